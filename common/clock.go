@@ -0,0 +1,50 @@
+package common
+
+import (
+	"sync"
+	"time"
+)
+
+// source of current time. use FakeClock in tests instead of system time.
+type Clock interface {
+	// current time on store location
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func NewSystemClock() Clock {
+	return &systemClock{}
+}
+
+func (s *systemClock) Now() time.Time {
+	return time.Now().In(storeLocation)
+}
+
+// clock which returns fixed time until Set or Advance is called
+type FakeClock struct {
+	mu  sync.RWMutex
+	now time.Time
+}
+
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+func (f *FakeClock) Now() time.Time {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.now.In(storeLocation)
+}
+
+func (f *FakeClock) Set(now time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = now
+}
+
+func (f *FakeClock) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+}
@@ -4,15 +4,22 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	AppPort    string
-	Db         DbConfig
-	Mail       MailConfig
-	GoogleJson string
+	AppPort     string
+	Db          DbConfig
+	Mail        MailConfig
+	RateLimit   RateLimitConfig
+	Order       OrderConfig
+	Idempotency IdempotencyConfig
+	GoogleJson  string
+	// IANA time zone name (ex:Asia/Tokyo)
+	StoreTimeZone string
 }
 
 type DbConfig struct {
@@ -34,6 +41,47 @@ type MailConfig struct {
 	Mailer      string
 }
 
+type RateLimitConfig struct {
+	Store             string
+	UserRatePerMinute float64
+	UserBurst         int
+	IpRatePerMinute   float64
+	IpBurst           int
+}
+
+type OrderConfig struct {
+	// 0 means no limit
+	MaxDailyOrdersPerContact int
+	// user can cancel until this minutes before pick up time. 0 means until pick up time
+	CancelCutoffMinutes int
+	// key to sign access token of guest order. empty means guest order is disabled
+	GuestTokenSecret string
+	// access token of guest order is valid until this hours after pick up time
+	GuestTokenValidHours int
+	// page url of guest order. access token is appended (ex:https://example.com/guest/order/)
+	GuestAccessUrl string
+	// time to send digest of item alerts of previous day (ex:07:00)
+	AlertDigestTime string
+	// time to save suggested quotas of demand forecast (ex:05:00). empty means auto apply is disabled
+	ForecastApplyTime string
+}
+
+type IdempotencyConfig struct {
+	Store string
+}
+
+const (
+	defaultUserRatePerMinute        = 3
+	defaultUserBurst                = 5
+	defaultIpRatePerMinute          = 10
+	defaultIpBurst                  = 20
+	defaultMaxDailyOrdersPerContact = 3
+	defaultCancelCutoffMinutes      = 60
+	defaultGuestTokenValidHours     = 24
+	defaultAlertDigestTime          = "07:00"
+	DefaultStoreTimeZone            = "Asia/Tokyo"
+)
+
 var config = Config{}
 
 func InitConfig(skipFile bool) error {
@@ -61,6 +109,23 @@ func InitConfig(skipFile bool) error {
 
 	config.Db = newDbConfig()
 	config.Mail = newMailConfig()
+	rateLimit, err := newRateLimitConfig()
+	if err != nil {
+		return err
+	}
+	config.RateLimit = *rateLimit
+	order, err := newOrderConfig()
+	if err != nil {
+		return err
+	}
+	config.Order = *order
+	config.Idempotency = IdempotencyConfig{Store: os.Getenv("IDEMPOTENCY_STORE")}
+	loc, err := loadStoreLocation()
+	if err != nil {
+		return err
+	}
+	config.StoreTimeZone = loc.String()
+	SetStoreLocation(loc)
 
 	return nil
 }
@@ -93,3 +158,96 @@ func newMailConfig() MailConfig {
 	}
 	return config
 }
+
+func newRateLimitConfig() (*RateLimitConfig, error) {
+	userRate, err := getEnvFloat("RATE_LIMIT_USER_PER_MINUTE", defaultUserRatePerMinute)
+	if err != nil {
+		return nil, err
+	}
+	userBurst, err := getEnvInt("RATE_LIMIT_USER_BURST", defaultUserBurst)
+	if err != nil {
+		return nil, err
+	}
+	ipRate, err := getEnvFloat("RATE_LIMIT_IP_PER_MINUTE", defaultIpRatePerMinute)
+	if err != nil {
+		return nil, err
+	}
+	ipBurst, err := getEnvInt("RATE_LIMIT_IP_BURST", defaultIpBurst)
+	if err != nil {
+		return nil, err
+	}
+	config := RateLimitConfig{
+		Store:             os.Getenv("RATE_LIMIT_STORE"),
+		UserRatePerMinute: userRate,
+		UserBurst:         userBurst,
+		IpRatePerMinute:   ipRate,
+		IpBurst:           ipBurst,
+	}
+	return &config, nil
+}
+
+func newOrderConfig() (*OrderConfig, error) {
+	maxDaily, err := getEnvInt("ORDER_MAX_DAILY_PER_CONTACT", defaultMaxDailyOrdersPerContact)
+	if err != nil {
+		return nil, err
+	}
+	cancelCutoff, err := getEnvInt("ORDER_CANCEL_CUTOFF_MINUTES", defaultCancelCutoffMinutes)
+	if err != nil {
+		return nil, err
+	}
+	guestTokenValid, err := getEnvInt("ORDER_GUEST_TOKEN_VALID_HOURS", defaultGuestTokenValidHours)
+	if err != nil {
+		return nil, err
+	}
+	alertDigestTime := os.Getenv("ORDER_ALERT_DIGEST_TIME")
+	if alertDigestTime == "" {
+		alertDigestTime = defaultAlertDigestTime
+	}
+	config := OrderConfig{
+		MaxDailyOrdersPerContact: maxDaily,
+		CancelCutoffMinutes:      cancelCutoff,
+		GuestTokenSecret:         os.Getenv("ORDER_GUEST_TOKEN_SECRET"),
+		GuestTokenValidHours:     guestTokenValid,
+		GuestAccessUrl:           os.Getenv("ORDER_GUEST_ACCESS_URL"),
+		AlertDigestTime:          alertDigestTime,
+		ForecastApplyTime:        os.Getenv("ORDER_FORECAST_APPLY_TIME"),
+	}
+	return &config, nil
+}
+
+func loadStoreLocation() (*time.Location, error) {
+	name := os.Getenv("STORE_TIMEZONE")
+	if name == "" {
+		name = DefaultStoreTimeZone
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("STORE_TIMEZONE is not valid time zone. value:%s %v", name, err)
+	}
+	return loc, nil
+}
+
+// empty value returns default value
+func getEnvInt(key string, defaultValue int) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	converted, err := strconv.Atoi(value)
+	if err != nil || converted < 0 {
+		return 0, fmt.Errorf("%s should be positive integer. value:%s", key, value)
+	}
+	return converted, nil
+}
+
+func getEnvFloat(key string, defaultValue float64) (float64, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	converted, err := strconv.ParseFloat(value, 64)
+	if err != nil || converted < 0 {
+		return 0, fmt.Errorf("%s should be positive number. value:%s", key, value)
+	}
+	return converted, nil
+}
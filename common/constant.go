@@ -1,8 +0,0 @@
-package common
-
-// minutes user can order before this
-// this information is used for list up orderable time
-const OffsetMinutesOrderableBefore = 120
-// minutes user can order
-// this value is used for check for actual order submit
-const OffsetMinutesOrderableCheckBefore = 60
@@ -8,6 +8,7 @@ const (
 	authTokenKey ctxKey = iota
 	authIsAdminKey
 	authUserIdKey
+	authUserEmailKey
 )
 
 func SetIsAdmin(isAdmin bool, ctx context.Context) context.Context {
@@ -47,4 +48,17 @@ func GetUserId(ctx context.Context) string {
 		return ""
 	}
 	return userId
-}
\ No newline at end of file
+}
+// only verified email is set
+func SetUserEmail(email string, ctx context.Context) context.Context {
+	return context.WithValue(ctx, authUserEmailKey, email)
+}
+
+func GetUserEmail(ctx context.Context) string {
+	v := ctx.Value(authUserEmailKey)
+	email, ok := v.(string)
+	if !ok {
+		return ""
+	}
+	return email
+}
@@ -1,6 +1,9 @@
 package common
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 type ValidationError struct {
 	name string
@@ -15,6 +18,35 @@ func (v *ValidationError) Error() string {
 	return fmt.Sprintf("Validation Error. Name:%s, Message:%s", v.name, v.msg)
 }
 
+// validation errors of multiple targets (ex: each ordered item)
+type ValidationErrors struct {
+	errors []ValidationError
+}
+
+func NewValidationErrors() *ValidationErrors {
+	return &ValidationErrors{errors: []ValidationError{}}
+}
+
+func (v *ValidationErrors) Add(name, msg string) {
+	v.errors = append(v.errors, ValidationError{name: name, msg: msg})
+}
+
+func (v *ValidationErrors) GetErrors() []ValidationError {
+	return v.errors
+}
+
+func (v *ValidationErrors) HasError() bool {
+	return len(v.errors) > 0
+}
+
+func (v *ValidationErrors) Error() string {
+	msgs := []string{}
+	for _, err := range v.errors {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "\n")
+}
+
 type UpdateTargetNotFoundError struct {
 	id   string
 }
@@ -61,4 +93,16 @@ func NewNotFoundError(name string) *NotFoundError {
 
 func (v *NotFoundError) Error() string {
 	return fmt.Sprintf("Not Found. Name:%s", v.name)
-}
\ No newline at end of file
+}
+type ConflictError struct {
+	name string
+	msg  string
+}
+
+func NewConflictError(name, msg string) *ConflictError {
+	return &ConflictError{name: name, msg: msg}
+}
+
+func (v *ConflictError) Error() string {
+	return fmt.Sprintf("Conflict Error. Name:%s, Message:%s", v.name, v.msg)
+}
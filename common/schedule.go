@@ -10,9 +10,10 @@ type DailySchedularTask struct {
 	start time.Time
 	last  *time.Time
 	task  func()
+	clock Clock
 }
 
-func NewDailySchedularTask(startStr string, task func()) (*DailySchedularTask, error) {
+func NewDailySchedularTask(startStr string, task func(), clock Clock) (*DailySchedularTask, error) {
 	if task == nil {
 		return nil, NewValidationError("task", "should not nil")
 	}
@@ -24,12 +25,13 @@ func NewDailySchedularTask(startStr string, task func()) (*DailySchedularTask, e
 		start: *time,
 		last:  nil,
 		task:  task,
+		clock: clock,
 	}, nil
 }
 
 func (d *DailySchedularTask) CheckAndExecTask() {
 	end := GetDateWithOffset(d.start, offsetMinutes)
-	now := GetNowDate()
+	now := GetDateUntilMinutes(d.clock.Now())
 	if IsInRangeTime(d.start, *end, *now) {
 		if d.last == nil || !DateEqual(*d.last, *now) {
 			d.task()
@@ -41,9 +43,10 @@ func (d *DailySchedularTask) CheckAndExecTask() {
 type TimerScheduleTask struct {
 	intervalMinutes int
 	task func(time.Time)
+	clock Clock
 }
 
-func NewTimerScheduleTask(intervalMinutes int, task func(time.Time)) (*TimerScheduleTask, error) {
+func NewTimerScheduleTask(intervalMinutes int, task func(time.Time), clock Clock) (*TimerScheduleTask, error) {
 	if task == nil {
 		return nil, NewValidationError("task", "should not nil")
 	}
@@ -53,6 +56,7 @@ func NewTimerScheduleTask(intervalMinutes int, task func(time.Time)) (*TimerSche
 	return &TimerScheduleTask{
 		intervalMinutes: intervalMinutes,
 		task:  task,
+		clock: clock,
 	}, nil
 }
 
@@ -63,7 +67,7 @@ func (t *TimerScheduleTask) Start() {
 	for {
 		select {
 		case <-timer.C:
-			t.task(*GetNowDateUntilMinutes())
+			t.task(*GetDateUntilMinutes(t.clock.Now()))
 		}
 	}
 }
\ No newline at end of file
@@ -8,21 +8,17 @@ import (
 )
 
 func TestDailySchedularTask_DoTaskOnSchedule(t *testing.T) {
+	t.Parallel()
 
 	// mock now (2022/10/5 10:20.30)
-	MockNow(func() time.Time {
-		return time.Date(2022, 10, 5, 10, 20, 30, 0, time.Local)
-	})
-	t.Cleanup(func() {
-		ResetNow()
-	})
+	clock := NewFakeClock(time.Date(2022, 10, 5, 10, 20, 30, 0, storeLocation))
 
 	counter := 0
 	task := func() {
 		counter++
 	}
 
-	sch, err := NewDailySchedularTask("10:20", task)
+	sch, err := NewDailySchedularTask("10:20", task, clock)
 	assert.NoError(t, err)
 
 	// act
@@ -32,21 +28,17 @@ func TestDailySchedularTask_DoTaskOnSchedule(t *testing.T) {
 }
 
 func TestDailySchedularTask_NotTaskAgain_UntilNextDay(t *testing.T) {
+	t.Parallel()
 
 	// mock now (2022/10/5 10:20.30)
-	MockNow(func() time.Time {
-		return time.Date(2022, 10, 5, 10, 20, 30, 0, time.Local)
-	})
-	t.Cleanup(func() {
-		ResetNow()
-	})
+	clock := NewFakeClock(time.Date(2022, 10, 5, 10, 20, 30, 0, storeLocation))
 
 	counter := 0
 	task := func() {
 		counter++
 	}
 
-	sch, err := NewDailySchedularTask("10:20", task)
+	sch, err := NewDailySchedularTask("10:20", task, clock)
 	assert.NoError(t, err)
 
 	// call first time
@@ -58,30 +50,24 @@ func TestDailySchedularTask_NotTaskAgain_UntilNextDay(t *testing.T) {
 	assert.Equal(t, 1, counter, "Should not be called task again.")
 
 	// increment a day
-	MockNow(func() time.Time {
-		return time.Date(2022, 10, 6, 10, 20, 30, 0, time.Local)
-	})
+	clock.Set(time.Date(2022, 10, 6, 10, 20, 30, 0, storeLocation))
 	// call
 	sch.CheckAndExecTask()
 	assert.Equal(t, 2, counter, "Should be called task.")
 }
 
 func TestDailySchedularTask_DoNotTaskBeforeSchedule(t *testing.T) {
+	t.Parallel()
 
 	// mock now (2022/10/5 10:20.30)
-	MockNow(func() time.Time {
-		return time.Date(2022, 10, 5, 10, 20, 30, 0, time.Local)
-	})
-	t.Cleanup(func() {
-		ResetNow()
-	})
+	clock := NewFakeClock(time.Date(2022, 10, 5, 10, 20, 30, 0, storeLocation))
 
 	counter := 0
 	task := func() {
 		counter++
 	}
 
-	sch, err := NewDailySchedularTask("10:23", task)
+	sch, err := NewDailySchedularTask("10:23", task, clock)
 	assert.NoError(t, err)
 
 	// act
@@ -91,14 +77,10 @@ func TestDailySchedularTask_DoNotTaskBeforeSchedule(t *testing.T) {
 }
 
 func TestDailySchedularTask_DoNotTaskAfterSchedule(t *testing.T) {
+	t.Parallel()
 
 	// mock now (2022/10/5 10:20.30)
-	MockNow(func() time.Time {
-		return time.Date(2022, 10, 5, 10, 20, 30, 0, time.Local)
-	})
-	t.Cleanup(func() {
-		ResetNow()
-	})
+	clock := NewFakeClock(time.Date(2022, 10, 5, 10, 20, 30, 0, storeLocation))
 
 	counter := 0
 	task := func() {
@@ -106,7 +88,7 @@ func TestDailySchedularTask_DoNotTaskAfterSchedule(t *testing.T) {
 	}
 
 	// 1 hour later
-	sch, err := NewDailySchedularTask("9:19", task)
+	sch, err := NewDailySchedularTask("9:19", task, clock)
 	assert.NoError(t, err)
 
 	// act
@@ -114,3 +96,51 @@ func TestDailySchedularTask_DoNotTaskAfterSchedule(t *testing.T) {
 
 	assert.Equal(t, 0, counter, "Should not be called task.")
 }
+
+// process time zone (ex:UTC on container) should not affect the schedule
+func TestDailySchedularTask_DoTaskOnSchedule_OtherProcessTimeZone(t *testing.T) {
+	orgLocal := time.Local
+	time.Local = time.FixedZone("UTC-8", -8*60*60)
+	t.Cleanup(func() {
+		time.Local = orgLocal
+	})
+
+	// mock now (2022/10/5 10:20.30 JST as UTC)
+	clock := NewFakeClock(time.Date(2022, 10, 5, 1, 20, 30, 0, time.UTC))
+
+	counter := 0
+	task := func() {
+		counter++
+	}
+
+	sch, err := NewDailySchedularTask("10:20", task, clock)
+	assert.NoError(t, err)
+	sch.CheckAndExecTask()
+	assert.Equal(t, 1, counter, "Should be called task.")
+
+	// 10:20 on process time zone is not scheduled time
+	clock.Set(time.Date(2022, 10, 6, 10, 20, 30, 0, time.Local))
+	sch.CheckAndExecTask()
+	assert.Equal(t, 1, counter, "Should not be called task.")
+}
+
+func TestDailySchedularTask_DoTaskOnSchedule_OtherStoreTimeZone(t *testing.T) {
+	orgStore := storeLocation
+	SetStoreLocation(time.FixedZone("UTC-5", -5*60*60))
+	t.Cleanup(func() {
+		SetStoreLocation(orgStore)
+	})
+
+	// mock now (2022/10/5 10:20.30 UTC-5 as UTC)
+	clock := NewFakeClock(time.Date(2022, 10, 5, 15, 20, 30, 0, time.UTC))
+
+	counter := 0
+	task := func() {
+		counter++
+	}
+
+	sch, err := NewDailySchedularTask("10:20", task, clock)
+	assert.NoError(t, err)
+	sch.CheckAndExecTask()
+	assert.Equal(t, 1, counter, "Should be called task.")
+}
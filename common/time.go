@@ -5,10 +5,9 @@ import (
 	"time"
 )
 
-var jst = time.FixedZone("Asia/Tokyo", 9*60*60)
-
-// for mock
-var now = time.Now
+// every date and time of store (business hours, pickup time and so on) is handled in this location.
+// default is JST and changed by config(STORE_TIMEZONE)
+var storeLocation = time.FixedZone(DefaultStoreTimeZone, 9*60*60)
 
 const dateHyphenLayout = "2006-01-02"
 const dateLayout = "2006/01/02"
@@ -16,57 +15,35 @@ const dateTimeLayout = "2006/01/02 15:04"
 const timeLayout = "15:04"
 const monthLayout = "2006/01"
 
-// for UT
-func MockNow(mock func() time.Time) {
-	now = mock
-}
-func ResetNow() {
-	now = time.Now
-}
+// date for time only value. it should not have DST change
+const timeReferenceDate = "2000/01/01"
 
-// get until from minutes (ex:2023-02-12 12:15)
-func GetNowDate() *time.Time {
-	now := now().In(jst)
-	t := time.Date(now.Year(), now.Month(), now.Day(), now.Hour(), now.Minute(), 0, 0, jst)
-	return &t
+func SetStoreLocation(loc *time.Location) {
+	storeLocation = loc
 }
-// currently same GetNowDate
-func GetNowDateUntilMinutes() *time.Time {
-	now := now().In(jst)
-	t := time.Date(now.Year(), now.Month(), now.Day(), now.Hour(), now.Minute(), 0, 0, jst)
-	return &t
+
+func GetStoreLocation() *time.Location {
+	return storeLocation
 }
 
-func GetNowDateUntilDay() *time.Time {
-	now := now().In(jst)
-	t := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, jst)
-	return &t
+// truncated until minutes (ex:2023-02-12 12:15)
+func GetDateUntilMinutes(base time.Time) *time.Time {
+	return GetDateWithOffset(base, 0)
 }
 
-func GetNowDateWithOffset(offsetMinutes int) *time.Time {
-	now := now().In(jst)
-	t := time.Date(now.Year(), now.Month(), now.Day(), now.Hour(), now.Minute(), 0, 0, jst)
-	t = t.Add(time.Duration(offsetMinutes) * time.Minute)
+func GetDateUntilDay(base time.Time) *time.Time {
+	base = base.In(storeLocation)
+	t := time.Date(base.Year(), base.Month(), base.Day(), 0, 0, 0, 0, storeLocation)
 	return &t
 }
 
 func GetDateWithOffset(base time.Time, offsetMinutes int) *time.Time {
-	t := time.Date(base.Year(), base.Month(), base.Day(), base.Hour(), base.Minute(), 0, 0, jst)
+	base = base.In(storeLocation)
+	t := time.Date(base.Year(), base.Month(), base.Day(), base.Hour(), base.Minute(), 0, 0, storeLocation)
 	t = t.Add(time.Duration(offsetMinutes) * time.Minute)
 	return &t
 }
 
-func GetNowTimeStr(offsetMinutes int) string {
-	now := GetNowDate()
-	afterNow := now.Add(time.Duration(offsetMinutes) * time.Minute)
-	return ConvertTimeToTimeStr(afterNow)
-}
-
-func GetNowTime() *time.Time {
-	now := now().In(jst)
-	return &now
-}
-
 func GetRound(t time.Time, roundMinutes int) time.Time {
 	r := t.Round(time.Duration(roundMinutes) * time.Minute)
 
@@ -76,13 +53,11 @@ func GetRound(t time.Time, roundMinutes int) time.Time {
 	return r
 }
 
+// date of converted time is fixed (only hour and minutes are meaningful)
 func ConvertStrToTime(timeStr string) (*time.Time, error) {
 	timeLayout := "2006/01/02T15:04"
-	currentTime := now().In(jst)
-	currentDate := currentTime.Format(dateLayout)
-
-	startDateStr := currentDate + "T" + timeStr
-	actualTime, err := time.ParseInLocation(timeLayout, startDateStr, jst)
+	startDateStr := timeReferenceDate + "T" + timeStr
+	actualTime, err := time.ParseInLocation(timeLayout, startDateStr, storeLocation)
 	if err != nil {
 		return nil, err
 	}
@@ -91,15 +66,15 @@ func ConvertStrToTime(timeStr string) (*time.Time, error) {
 }
 
 func ConvertTimeToTimeStr(target time.Time) string {
-	return target.Format(timeLayout)
+	return target.In(storeLocation).Format(timeLayout)
 }
 
 func ConvertTimeToMonthStr(target time.Time) string {
-	return target.Format(monthLayout)
+	return target.In(storeLocation).Format(monthLayout)
 }
 
 func ConvertStrToDate(dateStr string) (*time.Time, error) {
-	actualTime, err := time.ParseInLocation(dateLayout, dateStr, jst)
+	actualTime, err := time.ParseInLocation(dateLayout, dateStr, storeLocation)
 	if err != nil {
 		return nil, err
 	}
@@ -108,7 +83,7 @@ func ConvertStrToDate(dateStr string) (*time.Time, error) {
 }
 
 func ConvertHyphenStrToDate(dateStr string) (*time.Time, error) {
-	actualTime, err := time.ParseInLocation(dateHyphenLayout, dateStr, jst)
+	actualTime, err := time.ParseInLocation(dateHyphenLayout, dateStr, storeLocation)
 	if err != nil {
 		return nil, err
 	}
@@ -126,7 +101,7 @@ func ConvertDateTimeStrToDateStr(dateTimeStr string) (string, error) {
 }
 
 func ConvertStrToDateTime(dateTimeStr string) (*time.Time, error) {
-	actualTime, err := time.ParseInLocation(dateTimeLayout, dateTimeStr, jst)
+	actualTime, err := time.ParseInLocation(dateTimeLayout, dateTimeStr, storeLocation)
 	if err != nil {
 		return nil, err
 	}
@@ -135,7 +110,7 @@ func ConvertStrToDateTime(dateTimeStr string) (*time.Time, error) {
 }
 
 func ConvertStrToMonth(monthStr string) (*time.Time, error) {
-	actualTime, err := time.ParseInLocation(monthLayout, monthStr, jst)
+	actualTime, err := time.ParseInLocation(monthLayout, monthStr, storeLocation)
 	if err != nil {
 		return nil, err
 	}
@@ -144,11 +119,11 @@ func ConvertStrToMonth(monthStr string) (*time.Time, error) {
 }
 
 func ConvertTimeToDateTimeStr(target time.Time) string {
-	return target.Format(dateTimeLayout)
+	return target.In(storeLocation).Format(dateTimeLayout)
 }
 
 func ConvertTimeToDateStr(target time.Time) string {
-	return target.Format(dateLayout)
+	return target.In(storeLocation).Format(dateLayout)
 }
 
 func StartIsBeforeEnd(start, end time.Time, offsetMinutes float64) bool {
@@ -199,6 +174,7 @@ func IsInRange(startDate, endDate, targetDateTime time.Time) bool {
 }
 
 func IsInRangeTime(startTime, endTime, targetDateTime time.Time) bool {
+	startTime, endTime, targetDateTime = startTime.In(storeLocation), endTime.In(storeLocation), targetDateTime.In(storeLocation)
 	// compare as same date
 	acStDate := time.Date(2020, time.December, 1, startTime.Hour(), startTime.Minute()-1, 0, 0, time.UTC)
 	acEdDate := time.Date(2020, time.December, 1, endTime.Hour(), endTime.Minute()+1, 0, 0, time.UTC)
@@ -226,13 +202,8 @@ func IsInRangeTimeStr(startTime, endTime, targetTime string) (bool, error) {
 	return IsInRangeTime(*start, *end, *target), nil
 }
 
-func IsAfterFromNow(target time.Time, offsetHour int) bool {
-	now := now().In(jst)
-	actualTime := now.Add(time.Duration(offsetHour) * time.Hour)
-	return target.After(actualTime)
-}
-
 func ListUpDates(startDate, endDate time.Time, loc *time.Location) ([]time.Time, error) {
+	startDate, endDate = startDate.In(loc), endDate.In(loc)
 	actStart := time.Date(startDate.Year(), startDate.Month(), startDate.Day(), 0, 0, 0, 0, loc)
 	actEnd := time.Date(endDate.Year(), endDate.Month(), endDate.Day(), 1, 0, 0, 0, loc) // add 1minutes to compare
 
@@ -271,8 +242,9 @@ func ListUpMonths(startMonth, endMonth time.Time) ([]time.Time, error) {
 	return months, nil
 }
 
+// compare as date of store location
 func DateEqual(date1, date2 time.Time) bool {
-	y1, m1, d1 := date1.Date()
-	y2, m2, d2 := date2.Date()
+	y1, m1, d1 := date1.In(storeLocation).Date()
+	y2, m2, d2 := date2.In(storeLocation).Date()
 	return y1 == y2 && m1 == m2 && d1 == d2
 }
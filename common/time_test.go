@@ -148,3 +148,24 @@ func Test_IsInRange_Out(t *testing.T) {
 		assert.Equal(t, false, result, fmt.Sprintf("%v:case should be false", input.name))
 	}
 }
+
+func Test_ConvertTime_OtherProcessTimeZone(t *testing.T) {
+	orgLocal := time.Local
+	time.Local = time.FixedZone("UTC-8", -8*60*60)
+	t.Cleanup(func() {
+		time.Local = orgLocal
+	})
+
+	// values from db are returned as process local time zone
+	target := time.Date(2022, 10, 5, 1, 30, 0, 0, time.UTC).In(time.Local)
+	assert.Equal(t, "2022/10/05", ConvertTimeToDateStr(target))
+	assert.Equal(t, "10:30", ConvertTimeToTimeStr(target))
+	assert.Equal(t, "2022/10/05 10:30", ConvertTimeToDateTimeStr(target))
+
+	date, _ := ConvertStrToDate("2022/10/05")
+	assert.True(t, DateEqual(*date, target))
+
+	start, _ := ConvertStrToTime("10:00")
+	end, _ := ConvertStrToTime("11:00")
+	assert.True(t, IsInRangeTime(*start, *end, target))
+}
@@ -0,0 +1,142 @@
+package idempotency
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"chico/takeout/common"
+)
+
+const (
+	RecordExpireHours = 24
+	// in-progress record older than this is taken over (crashed instance or lost release)
+	ReservationLeaseMinutes = 5
+	KeyMaxLength            = 255
+)
+
+type RecordRepository interface {
+	Find(key string) (*Record, error)
+	// create record if not exists, expired or in progress over lease. return false if active record already exists
+	Reserve(record *Record, now time.Time) (bool, error)
+	Update(record *Record) error
+	Delete(key string) error
+	DeleteExpired(now time.Time) error
+}
+
+type Record struct {
+	key          string
+	requestHash  string
+	completed    bool
+	statusCode   int
+	contentType  string
+	responseBody []byte
+	responseHash string
+	created      time.Time
+}
+
+func NewRecord(key, requestHash string, now time.Time) (*Record, error) {
+	if strings.TrimSpace(key) == "" {
+		return nil, common.NewValidationError("Idempotency-Key", "required")
+	}
+	if len(key) > KeyMaxLength {
+		return nil, common.NewValidationError("Idempotency-Key", "too long")
+	}
+	return &Record{
+		key:          key,
+		requestHash:  requestHash,
+		completed:    false,
+		responseBody: []byte{},
+		created:      now,
+	}, nil
+}
+
+func NewRecordForOrm(key, requestHash string, completed bool, statusCode int, contentType string, responseBody []byte, responseHash string, created time.Time) *Record {
+	return &Record{
+		key:          key,
+		requestHash:  requestHash,
+		completed:    completed,
+		statusCode:   statusCode,
+		contentType:  contentType,
+		responseBody: responseBody,
+		responseHash: responseHash,
+		created:      created,
+	}
+}
+
+func HashBytes(values ...[]byte) string {
+	hash := sha256.New()
+	for _, value := range values {
+		hash.Write(value)
+		// separator to avoid same hash of different split
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func (r *Record) GetKey() string {
+	return r.key
+}
+
+func (r *Record) GetRequestHash() string {
+	return r.requestHash
+}
+
+func (r *Record) IsCompleted() bool {
+	return r.completed
+}
+
+func (r *Record) GetStatusCode() int {
+	return r.statusCode
+}
+
+func (r *Record) GetContentType() string {
+	return r.contentType
+}
+
+func (r *Record) GetResponseBody() []byte {
+	return r.responseBody
+}
+
+func (r *Record) GetResponseHash() string {
+	return r.responseHash
+}
+
+func (r *Record) GetCreated() time.Time {
+	return r.created
+}
+
+func (r *Record) IsSameRequest(requestHash string) bool {
+	return r.requestHash == requestHash
+}
+
+func (r *Record) IsExpired(now time.Time) bool {
+	return !r.created.After(GetExpiredBefore(now))
+}
+
+// true if the record can be reserved again
+func (r *Record) IsReservable(now time.Time) bool {
+	if r.IsExpired(now) {
+		return true
+	}
+	return !r.completed && !r.created.After(GetLeaseExpiredBefore(now))
+}
+
+func (r *Record) Complete(statusCode int, contentType string, responseBody []byte) {
+	r.completed = true
+	r.statusCode = statusCode
+	r.contentType = contentType
+	r.responseBody = responseBody
+	r.responseHash = HashBytes(responseBody)
+}
+
+// records created before this are expired
+func GetExpiredBefore(now time.Time) time.Time {
+	return now.Add(-RecordExpireHours * time.Hour)
+}
+
+// in-progress records created before this are stale
+func GetLeaseExpiredBefore(now time.Time) time.Time {
+	return now.Add(-ReservationLeaseMinutes * time.Minute)
+}
@@ -0,0 +1,70 @@
+package idempotency
+
+import (
+	"chico/takeout/common"
+)
+
+type IdempotencyService struct {
+	repo  RecordRepository
+	clock common.Clock
+}
+
+func NewIdempotencyService(repo RecordRepository, clock common.Clock) *IdempotencyService {
+	return &IdempotencyService{
+		repo:  repo,
+		clock: clock,
+	}
+}
+
+// returns completed record to replay. nil means the request should be processed.
+func (i *IdempotencyService) Start(key, requestHash string) (*Record, error) {
+	now := i.clock.Now()
+	record, err := NewRecord(key, requestHash, now)
+	if err != nil {
+		return nil, err
+	}
+	reserved, err := i.repo.Reserve(record, now)
+	if err != nil {
+		return nil, err
+	}
+	if reserved {
+		return nil, nil
+	}
+
+	existing, err := i.repo.Find(key)
+	if err != nil {
+		return nil, err
+	}
+	// released by other request just now
+	if existing == nil {
+		return nil, common.NewConflictError("Idempotency-Key", "request with same key is in progress")
+	}
+	if !existing.IsSameRequest(requestHash) {
+		return nil, common.NewConflictError("Idempotency-Key", "key is already used for different request")
+	}
+	if !existing.IsCompleted() {
+		return nil, common.NewConflictError("Idempotency-Key", "request with same key is in progress")
+	}
+	return existing, nil
+}
+
+func (i *IdempotencyService) Finish(key string, statusCode int, contentType string, responseBody []byte) error {
+	record, err := i.repo.Find(key)
+	if err != nil {
+		return err
+	}
+	if record == nil {
+		return common.NewUpdateTargetNotFoundError(key)
+	}
+	record.Complete(statusCode, contentType, responseBody)
+	return i.repo.Update(record)
+}
+
+// release key so that client can retry
+func (i *IdempotencyService) Release(key string) error {
+	return i.repo.Delete(key)
+}
+
+func (i *IdempotencyService) DeleteExpired() error {
+	return i.repo.DeleteExpired(i.clock.Now())
+}
@@ -0,0 +1,103 @@
+package idempotency_test
+
+import (
+	"testing"
+	"time"
+
+	"chico/takeout/common"
+	"chico/takeout/domains/idempotency"
+	"chico/takeout/infrastructures/memory"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotencyService_Start(t *testing.T) {
+	clock := common.NewFakeClock(time.Date(2050, 1, 1, 10, 0, 0, 0, time.UTC))
+	repo := memory.NewIdempotencyRecordMemoryRepository()
+	service := idempotency.NewIdempotencyService(repo, clock)
+
+	// first request is processed
+	record, err := service.Start("user1:key1", "hash1")
+	assert.NoError(t, err)
+	assert.Nil(t, record)
+
+	// same request while processing
+	_, err = service.Start("user1:key1", "hash1")
+	assert.IsType(t, common.NewConflictError("", ""), err)
+
+	err = service.Finish("user1:key1", 200, "application/json", []byte(`{"id":"1"}`))
+	assert.NoError(t, err)
+
+	// replay completed response
+	record, err = service.Start("user1:key1", "hash1")
+	assert.NoError(t, err)
+	assert.NotNil(t, record)
+	assert.Equal(t, 200, record.GetStatusCode())
+	assert.Equal(t, "application/json", record.GetContentType())
+	assert.Equal(t, []byte(`{"id":"1"}`), record.GetResponseBody())
+	assert.Equal(t, idempotency.HashBytes([]byte(`{"id":"1"}`)), record.GetResponseHash())
+
+	// different payload with same key
+	_, err = service.Start("user1:key1", "hash2")
+	assert.IsType(t, common.NewConflictError("", ""), err)
+
+	// key is available again after expired
+	clock.Advance(idempotency.RecordExpireHours * time.Hour)
+	record, err = service.Start("user1:key1", "hash2")
+	assert.NoError(t, err)
+	assert.Nil(t, record)
+}
+
+func TestIdempotencyService_Start_StaleReservation(t *testing.T) {
+	clock := common.NewFakeClock(time.Date(2050, 1, 1, 10, 0, 0, 0, time.UTC))
+	repo := memory.NewIdempotencyRecordMemoryRepository()
+	service := idempotency.NewIdempotencyService(repo, clock)
+
+	// reserved but never finished nor released
+	_, err := service.Start("user1:key1", "hash1")
+	assert.NoError(t, err)
+	clock.Advance(idempotency.ReservationLeaseMinutes*time.Minute - time.Second)
+	_, err = service.Start("user1:key1", "hash1")
+	assert.IsType(t, common.NewConflictError("", ""), err)
+
+	// taken over after lease
+	clock.Advance(time.Second)
+	record, err := service.Start("user1:key1", "hash1")
+	assert.NoError(t, err)
+	assert.Nil(t, record)
+
+	// completed record is kept after lease
+	assert.NoError(t, service.Finish("user1:key1", 200, "application/json", []byte(`{}`)))
+	clock.Advance(idempotency.ReservationLeaseMinutes * time.Minute)
+	record, err = service.Start("user1:key1", "hash1")
+	assert.NoError(t, err)
+	assert.NotNil(t, record)
+}
+
+func TestIdempotencyService_Release(t *testing.T) {
+	repo := memory.NewIdempotencyRecordMemoryRepository()
+	service := idempotency.NewIdempotencyService(repo, common.NewSystemClock())
+
+	_, err := service.Start("user1:key1", "hash1")
+	assert.NoError(t, err)
+	assert.NoError(t, service.Release("user1:key1"))
+
+	// can retry with other payload after released
+	record, err := service.Start("user1:key1", "hash2")
+	assert.NoError(t, err)
+	assert.Nil(t, record)
+}
+
+func TestIdempotencyService_Start_InvalidKey(t *testing.T) {
+	repo := memory.NewIdempotencyRecordMemoryRepository()
+	service := idempotency.NewIdempotencyService(repo, common.NewSystemClock())
+
+	longKey := make([]byte, idempotency.KeyMaxLength+1)
+	for i := range longKey {
+		longKey[i] = 'a'
+	}
+	for _, key := range []string{" ", string(longKey)} {
+		_, err := service.Start(key, "hash1")
+		assert.IsType(t, common.NewValidationError("", ""), err)
+	}
+}
@@ -0,0 +1,215 @@
+package item
+
+import (
+	"chico/takeout/common"
+	"chico/takeout/domains/shared"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ItemAlertType string
+
+const (
+	// remain is less than or equal to low stock threshold
+	ItemAlertLowStock ItemAlertType = "low_stock"
+	// stock remain reaches zero or food quota of the day is sold out
+	ItemAlertSoldOut ItemAlertType = "sold_out"
+)
+
+type ItemAlertRepository interface {
+	Create(alert *ItemAlert) error
+	// same alert of item is not created twice on same target date
+	Exists(itemId string, alertType ItemAlertType, targetDate string) (bool, error)
+	FindByTargetDate(targetDate string) ([]ItemAlert, error)
+}
+
+// target date is the date of alerted remain.
+// stock item is the date of change, food item is the pick up date of sold out quota.
+type ItemAlert struct {
+	id         string
+	itemId     string
+	itemType   string
+	itemName   string
+	alertType  ItemAlertType
+	targetDate shared.Date
+	remain     int
+	threshold  int
+	createdAt  time.Time
+}
+
+func NewItemAlert(itemId, itemType, itemName string, alertType ItemAlertType, targetDate string, remain, threshold int, now time.Time) (*ItemAlert, error) {
+	return NewItemAlertForOrm(uuid.NewString(), itemId, itemType, itemName, string(alertType), targetDate, remain, threshold, now)
+}
+
+// only for orm
+func NewItemAlertForOrm(id, itemId, itemType, itemName, alertType, targetDate string, remain, threshold int, createdAt time.Time) (*ItemAlert, error) {
+	alertTypeV := ItemAlertType(alertType)
+	if alertTypeV != ItemAlertLowStock && alertTypeV != ItemAlertSoldOut {
+		return nil, common.NewValidationError("alertType", fmt.Sprintf("unknown type:%s", alertType))
+	}
+	targetDateV, err := shared.NewDate(targetDate)
+	if err != nil {
+		return nil, err
+	}
+	return &ItemAlert{
+		id:         id,
+		itemId:     itemId,
+		itemType:   itemType,
+		itemName:   itemName,
+		alertType:  alertTypeV,
+		targetDate: *targetDateV,
+		remain:     remain,
+		threshold:  threshold,
+		createdAt:  createdAt,
+	}, nil
+}
+
+func (a *ItemAlert) GetId() string {
+	return a.id
+}
+
+func (a *ItemAlert) GetItemId() string {
+	return a.itemId
+}
+
+// stock or food
+func (a *ItemAlert) GetItemType() string {
+	return a.itemType
+}
+
+func (a *ItemAlert) GetItemName() string {
+	return a.itemName
+}
+
+func (a *ItemAlert) GetAlertType() ItemAlertType {
+	return a.alertType
+}
+
+func (a *ItemAlert) GetTargetDate() string {
+	return a.targetDate.GetValue()
+}
+
+func (a *ItemAlert) GetRemain() int {
+	return a.remain
+}
+
+func (a *ItemAlert) GetThreshold() int {
+	return a.threshold
+}
+
+func (a *ItemAlert) GetCreatedAt() time.Time {
+	return a.createdAt
+}
+
+// alert fires only when remain crosses threshold or reaches zero by the change.
+// nil means no alert
+func FindStockAlertType(before, after, threshold int) *ItemAlertType {
+	if after >= before {
+		return nil
+	}
+	var alertType ItemAlertType
+	if after == 0 {
+		alertType = ItemAlertSoldOut
+		return &alertType
+	}
+	if threshold > 0 && after <= threshold && before > threshold {
+		alertType = ItemAlertLowStock
+		return &alertType
+	}
+	return nil
+}
+
+type ItemAlertService struct {
+	alertRepo ItemAlertRepository
+}
+
+func NewItemAlertService(alertRepo ItemAlertRepository) *ItemAlertService {
+	return &ItemAlertService{
+		alertRepo: alertRepo,
+	}
+}
+
+// returns false if same alert is already raised on target date
+func (s *ItemAlertService) Raise(alert *ItemAlert) (bool, error) {
+	exists, err := s.alertRepo.Exists(alert.GetItemId(), alert.GetAlertType(), alert.GetTargetDate())
+	if err != nil {
+		return false, err
+	}
+	if exists {
+		return false, nil
+	}
+	err = s.alertRepo.Create(alert)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// alerts of stock items by change of remain (ex: order, waste, adjustment)
+type StockAlertChecker struct {
+	stockRepo     StockItemRepository
+	ledgerService StockLedgerService
+	alertService  ItemAlertService
+	clock         common.Clock
+}
+
+func NewStockAlertChecker(stockRepo StockItemRepository, batchRepo StockBatchRepository, movementRepo StockMovementRepository, alertRepo ItemAlertRepository, clock common.Clock) *StockAlertChecker {
+	return &StockAlertChecker{
+		stockRepo:     stockRepo,
+		ledgerService: *NewStockLedgerService(stockRepo, batchRepo, movementRepo),
+		alertService:  *NewItemAlertService(alertRepo),
+		clock:         clock,
+	}
+}
+
+// remain per stock item id before the change, to compare with remain after the change
+func (c *StockAlertChecker) SnapshotRemains() (map[string]int, error) {
+	stocks, err := c.stockRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	remains := map[string]int{}
+	for i := range stocks {
+		remain, err := c.ledgerService.CurrentRemain(&stocks[i])
+		if err != nil {
+			return nil, err
+		}
+		remains[stocks[i].GetId()] = remain
+	}
+	return remains, nil
+}
+
+// returns alerts which are raised for the first time on the date
+func (c *StockAlertChecker) Check(before map[string]int) ([]ItemAlert, error) {
+	now := c.clock.Now()
+	alerts := []ItemAlert{}
+	stocks, err := c.stockRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	for i := range stocks {
+		stock := &stocks[i]
+		remain, err := c.ledgerService.CurrentRemain(stock)
+		if err != nil {
+			return nil, err
+		}
+		alertType := FindStockAlertType(before[stock.GetId()], remain, stock.GetLowStockThreshold())
+		if alertType == nil {
+			continue
+		}
+		alert, err := NewItemAlert(stock.GetId(), "stock", stock.GetName(), *alertType, common.ConvertTimeToDateStr(now), remain, stock.GetLowStockThreshold(), now)
+		if err != nil {
+			return nil, err
+		}
+		raised, err := c.alertService.Raise(alert)
+		if err != nil {
+			return nil, err
+		}
+		if raised {
+			alerts = append(alerts, *alert)
+		}
+	}
+	return alerts, nil
+}
@@ -0,0 +1,65 @@
+package item_test
+
+import (
+	"testing"
+	"time"
+
+	"chico/takeout/common"
+	"chico/takeout/domains/item"
+	"chico/takeout/infrastructures/memory"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindStockAlertType(t *testing.T) {
+	tests := []struct {
+		name      string
+		before    int
+		after     int
+		threshold int
+		want      *item.ItemAlertType
+	}{
+		{name: "over threshold", before: 5, after: 3, threshold: 2, want: nil},
+		{name: "crosses threshold", before: 3, after: 2, threshold: 2, want: alertTypeOf(item.ItemAlertLowStock)},
+		{name: "already under threshold", before: 2, after: 1, threshold: 2, want: nil},
+		{name: "reaches zero", before: 1, after: 0, threshold: 2, want: alertTypeOf(item.ItemAlertSoldOut)},
+		{name: "no threshold", before: 5, after: 1, threshold: 0, want: nil},
+		{name: "increased", before: 0, after: 3, threshold: 5, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, item.FindStockAlertType(tt.before, tt.after, tt.threshold))
+		})
+	}
+}
+
+func alertTypeOf(alertType item.ItemAlertType) *item.ItemAlertType {
+	return &alertType
+}
+
+func TestItemAlertService_Raise(t *testing.T) {
+	repo := memory.NewItemAlertMemoryRepository()
+	service := item.NewItemAlertService(repo)
+	now := time.Date(2051, 1, 10, 12, 0, 0, 0, common.GetStoreLocation())
+
+	_, err := item.NewItemAlertForOrm("a1", "stock1", "stock", "stock", "unknown", "2051/01/10", 1, 2, now)
+	assert.IsType(t, common.NewValidationError("", ""), err)
+
+	alert, _ := item.NewItemAlert("stock1", "stock", "stock", item.ItemAlertLowStock, "2051/01/10", 1, 2, now)
+	raised, err := service.Raise(alert)
+	assert.NoError(t, err)
+	assert.True(t, raised)
+
+	// same item, type and date
+	alert, _ = item.NewItemAlert("stock1", "stock", "stock", item.ItemAlertLowStock, "2051/01/10", 0, 2, now)
+	raised, err = service.Raise(alert)
+	assert.NoError(t, err)
+	assert.False(t, raised)
+
+	// next day is raised again
+	alert, _ = item.NewItemAlert("stock1", "stock", "stock", item.ItemAlertLowStock, "2051/01/11", 1, 2, now)
+	raised, err = service.Raise(alert)
+	assert.NoError(t, err)
+	assert.True(t, raised)
+	assert.Len(t, repo.GetMemory(), 2)
+}
@@ -20,6 +20,7 @@ type FoodItem struct {
 	scheduleIds    []string
 	maxOrderPerDay MaxOrderPerDay
 	allowDates     AllowDates
+	leadTimeHours  LeadTimeHours
 }
 
 type AllowDates struct {
@@ -55,22 +56,22 @@ func NewAllowDates(dates []string) (*AllowDates, error) {
 	return &AllowDates{values: dateV}, nil
 }
 
-func NewFoodItem(name, description string, priority, maxOrder, maxOrderPerDay, price int, kindId string, scheduleIds []string, enabled bool, imageUrl string, allowDates []string) (*FoodItem, error) {
+func NewFoodItem(name, description string, priority, maxOrder, maxOrderPerDay, price int, kindId string, scheduleIds []string, enabled bool, imageUrl string, allowDates []string, leadTimeHours uint) (*FoodItem, error) {
 	common, err := newCommonItem(name, description, priority, maxOrder, price, kindId, enabled, imageUrl)
 	if err != nil {
 		return nil, err
 	}
 
 	item := FoodItem{commonItem: *common}
-	err = item.set(maxOrderPerDay, scheduleIds, allowDates)
+	err = item.set(maxOrderPerDay, scheduleIds, allowDates, leadTimeHours)
 	if err != nil {
 		return nil, err
 	}
 	return &item, nil
 }
 
-func NewFoodItemForOrm(id, name, description string, priority, maxOrder, maxOrderPerDay, price int, kindId string, scheduleIds []string, enabled bool, imageUrl string, allowDates []string) (*FoodItem, error) {
-	item, err := NewFoodItem(name, description, priority, maxOrder, maxOrderPerDay, price, kindId, scheduleIds, enabled, imageUrl, allowDates)
+func NewFoodItemForOrm(id, name, description string, priority, maxOrder, maxOrderPerDay, price int, kindId string, scheduleIds []string, enabled bool, imageUrl string, allowDates []string, leadTimeHours uint) (*FoodItem, error) {
+	item, err := NewFoodItem(name, description, priority, maxOrder, maxOrderPerDay, price, kindId, scheduleIds, enabled, imageUrl, allowDates, leadTimeHours)
 	if err != nil {
 		return nil, err
 	}
@@ -78,20 +79,20 @@ func NewFoodItemForOrm(id, name, description string, priority, maxOrder, maxOrde
 	return item, nil
 }
 
-func (f *FoodItem) Set(name, description string, priority, maxOrder, maxOrderPerDay, price int, kindId string, scheduleIds []string, enabled bool, imageUrl string, allowDates []string) error {
+func (f *FoodItem) Set(name, description string, priority, maxOrder, maxOrderPerDay, price int, kindId string, scheduleIds []string, enabled bool, imageUrl string, allowDates []string, leadTimeHours uint) error {
 	err := f.commonItem.Set(name, description, priority, maxOrder, price, kindId, enabled, imageUrl)
 	// common, err := newCommonItem(name, description, priority, maxOrder, price, kindId, enabled)
 	if err != nil {
 		return err
 	}
-	err = f.set(maxOrderPerDay, scheduleIds, allowDates)
+	err = f.set(maxOrderPerDay, scheduleIds, allowDates, leadTimeHours)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (f *FoodItem) set(maxOrderPerDay int, scheduleIds, allowDates []string) error {
+func (f *FoodItem) set(maxOrderPerDay int, scheduleIds, allowDates []string, leadTimeHours uint) error {
 	maxOrderPValue, err := NewMaxOrderPerDay(maxOrderPerDay, f.maxOrder)
 	if err != nil {
 		return err
@@ -104,9 +105,14 @@ func (f *FoodItem) set(maxOrderPerDay int, scheduleIds, allowDates []string) err
 	if err != nil {
 		return err
 	}
+	leadTime, err := NewLeadTimeHours(leadTimeHours)
+	if err != nil {
+		return err
+	}
 	f.maxOrderPerDay = *maxOrderPValue
 	f.scheduleIds = scheduleIds
 	f.allowDates = *dates
+	f.leadTimeHours = *leadTime
 	return nil
 }
 
@@ -141,6 +147,30 @@ func (s *FoodItem) GetScheduleIds() []string {
 	return s.scheduleIds
 }
 
+func (s *FoodItem) HasScheduleId(scheduleId string) bool {
+	for _, id := range s.scheduleIds {
+		if id == scheduleId {
+			return true
+		}
+	}
+	return false
+}
+
+// at least 1 schedule is needed
+func (s *FoodItem) RemoveScheduleId(scheduleId string) error {
+	ids := []string{}
+	for _, id := range s.scheduleIds {
+		if id != scheduleId {
+			ids = append(ids, id)
+		}
+	}
+	if err := validateScheduleIds(ids); err != nil {
+		return err
+	}
+	s.scheduleIds = ids
+	return nil
+}
+
 func (s *FoodItem) GetAllowDates() []string {
 	return s.allowDates.GetDates()
 }
@@ -149,6 +179,10 @@ func (s *FoodItem) GetAllowDatesAsTime() []time.Time {
 	return s.allowDates.GetDatesAsTime()
 }
 
+func (s *FoodItem) GetLeadTimeHours() uint {
+	return s.leadTimeHours.GetValue()
+}
+
 func (s *FoodItem) HasSameId(id string) bool {
 	return s.id == id
 }
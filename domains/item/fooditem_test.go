@@ -31,6 +31,7 @@ type foodItemTestArgs struct {
 	maxOrderPerDay int
 	imageUrl       string
 	allowDates     []string
+	leadTimeHours  uint
 }
 
 var foodItemInputs = []foodItemTest{
@@ -59,6 +60,20 @@ var foodItemInputs = []foodItemTest{
 		want:             foodItemTestArgs{name: "test1", priority: 1, maxOrder: 1, price: 1, description: maxDescStr, kindId: "123", enabled: true, scheduleIds: []string{"1", "2"}, maxOrderPerDay: 1, imageUrl: "http://google.com", allowDates: []string{"2022/12/11", "2022/12/13"}},
 		hasValidationErr: false,
 	},
+	{name: "normal case4(lead time hours)",
+		args:             foodItemTestArgs{name: "test1", priority: 1, maxOrder: 1, price: 1, description: maxDescStr, kindId: "123", enabled: true, scheduleIds: []string{"1", "2"}, maxOrderPerDay: 1, imageUrl: "http://google.com", allowDates: []string{}, leadTimeHours: 24},
+		want:             foodItemTestArgs{name: "test1", priority: 1, maxOrder: 1, price: 1, description: maxDescStr, kindId: "123", enabled: true, scheduleIds: []string{"1", "2"}, maxOrderPerDay: 1, imageUrl: "http://google.com", allowDates: []string{}, leadTimeHours: 24},
+		hasValidationErr: false,
+	},
+	{name: "normal case5(max lead time hours)",
+		args:             foodItemTestArgs{name: "test1", priority: 1, maxOrder: 1, price: 1, description: maxDescStr, kindId: "123", enabled: true, scheduleIds: []string{"1", "2"}, maxOrderPerDay: 1, imageUrl: "http://google.com", allowDates: []string{}, leadTimeHours: 168},
+		want:             foodItemTestArgs{name: "test1", priority: 1, maxOrder: 1, price: 1, description: maxDescStr, kindId: "123", enabled: true, scheduleIds: []string{"1", "2"}, maxOrderPerDay: 1, imageUrl: "http://google.com", allowDates: []string{}, leadTimeHours: 168},
+		hasValidationErr: false,
+	},
+	{name: "error: irregular lead time hours(169)",
+		args:             foodItemTestArgs{name: "test1", priority: 1, maxOrder: 1, price: 1, description: maxDescStr, kindId: "123", enabled: true, scheduleIds: []string{"1", "2"}, maxOrderPerDay: 1, imageUrl: "http://google.com", allowDates: []string{}, leadTimeHours: 169},
+		hasValidationErr: true,
+	},
 	{name: "error:empty name",
 		args:             foodItemTestArgs{name: "", priority: 3, maxOrder: 4, price: 140, description: "ttt", kindId: "abc", enabled: false, scheduleIds: []string{"1", "2"}, maxOrderPerDay: 30, imageUrl: "https://google.com", allowDates: []string{}},
 		want:             foodItemTestArgs{},
@@ -163,7 +178,7 @@ var foodItemInputs = []foodItemTest{
 func TestNewFoodItem(t *testing.T) {
 	for _, tt := range foodItemInputs {
 		fmt.Println("name:", tt.name)
-		got, err := item.NewFoodItem(tt.args.name, tt.args.description, tt.args.priority, tt.args.maxOrder, tt.args.maxOrderPerDay, tt.args.price, tt.args.kindId, tt.args.scheduleIds, tt.args.enabled, tt.args.imageUrl, tt.args.allowDates)
+		got, err := item.NewFoodItem(tt.args.name, tt.args.description, tt.args.priority, tt.args.maxOrder, tt.args.maxOrderPerDay, tt.args.price, tt.args.kindId, tt.args.scheduleIds, tt.args.enabled, tt.args.imageUrl, tt.args.allowDates, tt.args.leadTimeHours)
 		if err != nil {
 			fmt.Println(err)
 			var vErr *common.ValidationError
@@ -191,6 +206,7 @@ func TestNewFoodItem(t *testing.T) {
 		assert.Equal(t, expect.maxOrderPerDay, got.GetMaxOrderPerDay())
 		assert.Equal(t, expect.imageUrl, got.GetImageUrl())
 		assert.ElementsMatch(t, expect.allowDates, got.GetAllowDates())
+		assert.Equal(t, expect.leadTimeHours, got.GetLeadTimeHours())
 	}
 }
 
@@ -200,13 +216,13 @@ func TestFoodItem_Update(t *testing.T) {
 
 		// arrange
 		init := foodItemTestArgs{name: "test1", priority: 1, maxOrder: 2, price: 1, description: maxDescStr, kindId: "123", enabled: true, scheduleIds: []string{"1", "2"}, maxOrderPerDay: 4, imageUrl: "http://ho.com", allowDates: []string{}}
-		got, err := item.NewFoodItem(init.name, init.description, init.priority, init.maxOrder, init.maxOrderPerDay, init.price, init.kindId, init.scheduleIds, init.enabled, init.imageUrl, init.allowDates)
+		got, err := item.NewFoodItem(init.name, init.description, init.priority, init.maxOrder, init.maxOrderPerDay, init.price, init.kindId, init.scheduleIds, init.enabled, init.imageUrl, init.allowDates, init.leadTimeHours)
 		if err != nil {
 			assert.Fail(t, "failed to initialize", err)
 			continue
 		}
 		// act
-		err = got.Set(tt.args.name, tt.args.description, tt.args.priority, tt.args.maxOrder, tt.args.maxOrderPerDay, tt.args.price, tt.args.kindId, tt.args.scheduleIds, tt.args.enabled, tt.args.imageUrl, tt.args.allowDates)
+		err = got.Set(tt.args.name, tt.args.description, tt.args.priority, tt.args.maxOrder, tt.args.maxOrderPerDay, tt.args.price, tt.args.kindId, tt.args.scheduleIds, tt.args.enabled, tt.args.imageUrl, tt.args.allowDates, tt.args.leadTimeHours)
 		if err != nil {
 			fmt.Println(err)
 			var vErr *common.ValidationError
@@ -235,5 +251,6 @@ func TestFoodItem_Update(t *testing.T) {
 		assert.Equal(t, expect.maxOrderPerDay, got.GetMaxOrderPerDay())
 		assert.Equal(t, expect.imageUrl, got.GetImageUrl())
 		assert.ElementsMatch(t, expect.allowDates, got.GetAllowDates())
+		assert.Equal(t, expect.leadTimeHours, got.GetLeadTimeHours())
 	}
 }
@@ -0,0 +1,369 @@
+package item
+
+import (
+	"chico/takeout/common"
+	"chico/takeout/domains/shared"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+const (
+	IngredientNameMaxLength = 50
+	IngredientUnitMaxLength = 10
+)
+
+type IngredientRepository interface {
+	Find(id string) (*Ingredient, error)
+	FindAll() ([]Ingredient, error)
+	Create(item *Ingredient) (string, error)
+	Update(item *Ingredient) error
+	Delete(id string) error
+}
+
+type RecipeRepository interface {
+	// nil if item has no recipe
+	FindByItemId(itemId string) (*Recipe, error)
+	FindAll() ([]Recipe, error)
+	// ingredients of item are replaced
+	Save(recipe *Recipe) error
+	Delete(itemId string) error
+}
+
+type IngredientUsageRepository interface {
+	FindByDate(date string) ([]IngredientUsage, error)
+	// created if not exists
+	Save(usage *IngredientUsage) error
+}
+
+// ingredient shared by items. daily quantity is available every day
+type Ingredient struct {
+	id            string
+	name          Name
+	unit          string
+	dailyQuantity int
+}
+
+func NewIngredient(name, unit string, dailyQuantity int) (*Ingredient, error) {
+	return NewIngredientForOrm(uuid.NewString(), name, unit, dailyQuantity)
+}
+
+// only for orm
+func NewIngredientForOrm(id, name, unit string, dailyQuantity int) (*Ingredient, error) {
+	ingredient := &Ingredient{id: id}
+	err := ingredient.Set(name, unit, dailyQuantity)
+	if err != nil {
+		return nil, err
+	}
+	return ingredient, nil
+}
+
+func (i *Ingredient) GetId() string {
+	return i.id
+}
+
+func (i *Ingredient) GetName() string {
+	return i.name.GetValue()
+}
+
+// unit of quantity (ex:g, piece)
+func (i *Ingredient) GetUnit() string {
+	return i.unit
+}
+
+func (i *Ingredient) GetDailyQuantity() int {
+	return i.dailyQuantity
+}
+
+func (i *Ingredient) Set(name, unit string, dailyQuantity int) error {
+	nameV, err := NewName(name, IngredientNameMaxLength)
+	if err != nil {
+		return err
+	}
+	if len([]rune(unit)) > IngredientUnitMaxLength {
+		return common.NewValidationError("unit", fmt.Sprintf("MaxLength:%d", IngredientUnitMaxLength))
+	}
+	if dailyQuantity < 0 {
+		return common.NewValidationError("dailyQuantity", "should be 0 or more")
+	}
+	i.name = *nameV
+	i.unit = unit
+	i.dailyQuantity = dailyQuantity
+	return nil
+}
+
+// quantity of ingredient consumed by one unit of item
+type RecipeIngredient struct {
+	ingredientId string
+	quantity     int
+}
+
+func NewRecipeIngredient(ingredientId string, quantity int) (*RecipeIngredient, error) {
+	if ingredientId == "" {
+		return nil, common.NewValidationError("ingredientId", "Not allowed to be empty")
+	}
+	if quantity < 1 {
+		return nil, common.NewValidationError("quantity", "should be greater than 0")
+	}
+	return &RecipeIngredient{
+		ingredientId: ingredientId,
+		quantity:     quantity,
+	}, nil
+}
+
+func (r *RecipeIngredient) GetIngredientId() string {
+	return r.ingredientId
+}
+
+func (r *RecipeIngredient) GetQuantity() int {
+	return r.quantity
+}
+
+// links stock item or food item to ingredients
+type Recipe struct {
+	itemId      string
+	itemType    string
+	ingredients []RecipeIngredient
+}
+
+func NewRecipe(itemId, itemType string, ingredients []RecipeIngredient) (*Recipe, error) {
+	if itemId == "" {
+		return nil, common.NewValidationError("itemId", "Not allowed to be empty")
+	}
+	if itemType != "stock" && itemType != "food" {
+		return nil, common.NewValidationError("itemType", fmt.Sprintf("unknown type:%s", itemType))
+	}
+	if len(ingredients) == 0 {
+		return nil, common.NewValidationError("ingredients", "at least one ingredient is needed")
+	}
+	ids := map[string]bool{}
+	for _, ingredient := range ingredients {
+		if ids[ingredient.GetIngredientId()] {
+			return nil, common.NewValidationError("ingredients", fmt.Sprintf("duplicated ingredient:%s", ingredient.GetIngredientId()))
+		}
+		ids[ingredient.GetIngredientId()] = true
+	}
+	return &Recipe{
+		itemId:      itemId,
+		itemType:    itemType,
+		ingredients: ingredients,
+	}, nil
+}
+
+func (r *Recipe) GetItemId() string {
+	return r.itemId
+}
+
+// stock or food
+func (r *Recipe) GetItemType() string {
+	return r.itemType
+}
+
+func (r *Recipe) GetIngredients() []RecipeIngredient {
+	return r.ingredients
+}
+
+func (r *Recipe) Uses(ingredientId string) bool {
+	for _, ingredient := range r.ingredients {
+		if ingredient.GetIngredientId() == ingredientId {
+			return true
+		}
+	}
+	return false
+}
+
+// max quantity of item made from remain of ingredients
+func (r *Recipe) AvailableQuantity(remains map[string]int) int {
+	available := -1
+	for _, ingredient := range r.ingredients {
+		quantity := remains[ingredient.GetIngredientId()] / ingredient.GetQuantity()
+		if available < 0 || quantity < available {
+			available = quantity
+		}
+	}
+	if available < 0 {
+		return 0
+	}
+	return available
+}
+
+// used quantity of ingredient on the date
+type IngredientUsage struct {
+	ingredientId string
+	date         shared.Date
+	used         int
+}
+
+func NewIngredientUsage(ingredientId, date string) (*IngredientUsage, error) {
+	return NewIngredientUsageForOrm(ingredientId, date, 0)
+}
+
+// only for orm
+func NewIngredientUsageForOrm(ingredientId, date string, used int) (*IngredientUsage, error) {
+	dateV, err := shared.NewDate(date)
+	if err != nil {
+		return nil, err
+	}
+	return &IngredientUsage{
+		ingredientId: ingredientId,
+		date:         *dateV,
+		used:         used,
+	}, nil
+}
+
+func (u *IngredientUsage) GetIngredientId() string {
+	return u.ingredientId
+}
+
+func (u *IngredientUsage) GetDate() string {
+	return u.date.GetValue()
+}
+
+func (u *IngredientUsage) GetUsed() int {
+	return u.used
+}
+
+func (u *IngredientUsage) Consume(quantity, dailyQuantity int) error {
+	if u.used+quantity > dailyQuantity {
+		return common.NewValidationError("ingredients", fmt.Sprintf("ingredient remain is insufficient at %s", u.GetDate()))
+	}
+	u.used += quantity
+	return nil
+}
+
+func (u *IngredientUsage) Restore(quantity int) {
+	u.used -= quantity
+	if u.used < 0 {
+		u.used = 0
+	}
+}
+
+type IngredientService struct {
+	ingredientRepo IngredientRepository
+	recipeRepo     RecipeRepository
+	usageRepo      IngredientUsageRepository
+}
+
+func NewIngredientService(ingredientRepo IngredientRepository, recipeRepo RecipeRepository, usageRepo IngredientUsageRepository) *IngredientService {
+	return &IngredientService{
+		ingredientRepo: ingredientRepo,
+		recipeRepo:     recipeRepo,
+		usageRepo:      usageRepo,
+	}
+}
+
+// quantities of ingredient needed for quantities of item. items without recipe need nothing
+func (s *IngredientService) Needs(itemQuantities map[string]int) (map[string]int, error) {
+	needs := map[string]int{}
+	if len(itemQuantities) == 0 {
+		return needs, nil
+	}
+	recipes, err := s.recipeRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	for _, recipe := range recipes {
+		quantity := itemQuantities[recipe.GetItemId()]
+		if quantity == 0 {
+			continue
+		}
+		for _, ingredient := range recipe.GetIngredients() {
+			needs[ingredient.GetIngredientId()] += ingredient.GetQuantity() * quantity
+		}
+	}
+	return needs, nil
+}
+
+// daily quantity minus used quantity per ingredient id
+func (s *IngredientService) RemainsAt(date string) (map[string]int, error) {
+	ingredients, err := s.ingredientRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	usages, err := s.usageRepo.FindByDate(date)
+	if err != nil {
+		return nil, err
+	}
+	remains := map[string]int{}
+	for _, ingredient := range ingredients {
+		remains[ingredient.GetId()] = ingredient.GetDailyQuantity()
+	}
+	for _, usage := range usages {
+		if _, ok := remains[usage.GetIngredientId()]; ok {
+			remains[usage.GetIngredientId()] -= usage.GetUsed()
+		}
+	}
+	return remains, nil
+}
+
+// needs are restored at first, then consumed. all needs are checked before saved
+func (s *IngredientService) Replace(restoreDate string, restore map[string]int, consumeDate string, consume map[string]int) error {
+	if len(restore) == 0 && len(consume) == 0 {
+		return nil
+	}
+	ingredients, err := s.ingredientRepo.FindAll()
+	if err != nil {
+		return err
+	}
+	dailyQuantities := map[string]int{}
+	for _, ingredient := range ingredients {
+		dailyQuantities[ingredient.GetId()] = ingredient.GetDailyQuantity()
+	}
+	usages := map[string]*IngredientUsage{}
+	keys := []string{}
+	find := func(ingredientId, date string) (*IngredientUsage, error) {
+		key := ingredientId + "_" + date
+		if usage, ok := usages[key]; ok {
+			return usage, nil
+		}
+		all, err := s.usageRepo.FindByDate(date)
+		if err != nil {
+			return nil, err
+		}
+		var usage *IngredientUsage
+		for i := range all {
+			if all[i].GetIngredientId() == ingredientId {
+				usage = &all[i]
+				break
+			}
+		}
+		if usage == nil {
+			usage, err = NewIngredientUsage(ingredientId, date)
+			if err != nil {
+				return nil, err
+			}
+		}
+		usages[key] = usage
+		keys = append(keys, key)
+		return usage, nil
+	}
+	for ingredientId, quantity := range restore {
+		usage, err := find(ingredientId, restoreDate)
+		if err != nil {
+			return err
+		}
+		usage.Restore(quantity)
+	}
+	for ingredientId, quantity := range consume {
+		// deleted ingredient is not limited
+		dailyQuantity, ok := dailyQuantities[ingredientId]
+		if !ok {
+			continue
+		}
+		usage, err := find(ingredientId, consumeDate)
+		if err != nil {
+			return err
+		}
+		err = usage.Consume(quantity, dailyQuantity)
+		if err != nil {
+			return err
+		}
+	}
+	for _, key := range keys {
+		err = s.usageRepo.Save(usages[key])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package item_test
+
+import (
+	"testing"
+
+	"chico/takeout/common"
+	"chico/takeout/domains/item"
+	"chico/takeout/infrastructures/memory"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRecipe(t *testing.T) {
+	rice, _ := item.NewRecipeIngredient("rice", 150)
+	chicken, _ := item.NewRecipeIngredient("chicken", 100)
+
+	_, err := item.NewRecipeIngredient("rice", 0)
+	assert.IsType(t, common.NewValidationError("", ""), err)
+	_, err = item.NewRecipe("food1", "option", []item.RecipeIngredient{*rice})
+	assert.IsType(t, common.NewValidationError("", ""), err)
+	_, err = item.NewRecipe("food1", "food", []item.RecipeIngredient{})
+	assert.IsType(t, common.NewValidationError("", ""), err)
+	_, err = item.NewRecipe("food1", "food", []item.RecipeIngredient{*rice, *rice})
+	assert.IsType(t, common.NewValidationError("", ""), err)
+
+	recipe, err := item.NewRecipe("food1", "food", []item.RecipeIngredient{*rice, *chicken})
+	assert.NoError(t, err)
+	// min over ingredients
+	assert.Equal(t, 3, recipe.AvailableQuantity(map[string]int{"rice": 1000, "chicken": 350}))
+	assert.Equal(t, 0, recipe.AvailableQuantity(map[string]int{"rice": 1000}))
+}
+
+func TestIngredientService_Replace(t *testing.T) {
+	ingredientRepo := memory.NewIngredientMemoryRepository()
+	recipeRepo := memory.NewRecipeMemoryRepository()
+	usageRepo := memory.NewIngredientUsageMemoryRepository()
+	service := item.NewIngredientService(ingredientRepo, recipeRepo, usageRepo)
+
+	rice, _ := item.NewIngredient("rice", "g", 1000)
+	ingredientRepo.Create(rice)
+	line, _ := item.NewRecipeIngredient(rice.GetId(), 150)
+	curry, _ := item.NewRecipe("curry", "food", []item.RecipeIngredient{*line})
+	recipeRepo.Save(curry)
+	biryani, _ := item.NewRecipe("biryani", "food", []item.RecipeIngredient{*line})
+	recipeRepo.Save(biryani)
+
+	// shared ingredient is summed
+	needs, err := service.Needs(map[string]int{"curry": 2, "biryani": 3, "other": 5})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{rice.GetId(): 750}, needs)
+
+	err = service.Replace("", nil, "2051/01/10", needs)
+	assert.NoError(t, err)
+	remains, _ := service.RemainsAt("2051/01/10")
+	assert.Equal(t, 250, remains[rice.GetId()])
+	// other date is not used
+	remains, _ = service.RemainsAt("2051/01/11")
+	assert.Equal(t, 1000, remains[rice.GetId()])
+
+	// insufficient
+	err = service.Replace("", nil, "2051/01/10", needs)
+	assert.IsType(t, common.NewValidationError("", ""), err)
+	remains, _ = service.RemainsAt("2051/01/10")
+	assert.Equal(t, 250, remains[rice.GetId()])
+
+	// moved to other date
+	err = service.Replace("2051/01/10", needs, "2051/01/11", needs)
+	assert.NoError(t, err)
+	remains, _ = service.RemainsAt("2051/01/10")
+	assert.Equal(t, 1000, remains[rice.GetId()])
+	remains, _ = service.RemainsAt("2051/01/11")
+	assert.Equal(t, 250, remains[rice.GetId()])
+}
@@ -21,6 +21,7 @@ type ItemKind struct {
 	name          string
 	priority      Priority
 	optionItemIds []string
+	optionGroups  []OptionGroup
 }
 
 const (
@@ -74,7 +75,7 @@ func (i *ItemKind) Set(name string, priority int, optionItemIds []string) error
 		return err
 	}
 
-	err = i.validateOptionIds(optionItemIds)
+	err = validateDuplicatedIds("optionItemIds", optionItemIds)
 	if err != nil {
 		return err
 	}
@@ -85,21 +86,48 @@ func (i *ItemKind) Set(name string, priority int, optionItemIds []string) error
 	return nil
 }
 
-func (i *ItemKind) validateOptionIds(optionItemIds []string) error {
-	duplicated := false
-	duplicatedId := ""
-	encountered := map[string]bool{}
-	for i := 0; i < len(optionItemIds); i++ {
-		if !encountered[optionItemIds[i]] {
-			encountered[optionItemIds[i]] = true
-		} else {
-			duplicatedId = optionItemIds[i]
-			duplicated = true
-			break
+// each option of group should be option of kind and belongs to one group at most
+func (i *ItemKind) SetOptionGroups(groups []OptionGroup) error {
+	names := map[string]bool{}
+	grouped := map[string]bool{}
+	for _, group := range groups {
+		if names[group.GetName()] {
+			return common.NewValidationError("optionGroups", fmt.Sprintf("duplicate name are not allowed:%s", group.GetName()))
+		}
+		names[group.GetName()] = true
+		for _, id := range group.GetOptionItemIds() {
+			if !i.HasOption(id) {
+				return common.NewValidationError("optionGroups", fmt.Sprintf("Not exists in kind:%s", id))
+			}
+			if grouped[id] {
+				return common.NewValidationError("optionGroups", fmt.Sprintf("option belongs to multiple groups:%s", id))
+			}
+			grouped[id] = true
+		}
+	}
+	i.optionGroups = groups
+	return nil
+}
+
+func (i *ItemKind) GetOptionGroups() []OptionGroup {
+	return i.optionGroups
+}
+
+func (i *ItemKind) HasOption(optionItemId string) bool {
+	for _, id := range i.optionItemIds {
+		if id == optionItemId {
+			return true
 		}
 	}
-	if duplicated {
-		return common.NewValidationError("optionItemIds", fmt.Sprintf("duplicate Id are not allowed:%s", duplicatedId))
+	return false
+}
+
+// nil if option is not grouped
+func (i *ItemKind) FindOptionGroup(optionItemId string) *OptionGroup {
+	for idx := range i.optionGroups {
+		if i.optionGroups[idx].HasOption(optionItemId) {
+			return &i.optionGroups[idx]
+		}
 	}
 	return nil
-}
\ No newline at end of file
+}
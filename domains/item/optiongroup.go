@@ -0,0 +1,152 @@
+package item
+
+import (
+	"chico/takeout/common"
+	"fmt"
+)
+
+const (
+	OptionGroupNameMaxLength = 15
+	// max quantity of same option in one item (ex: extra topping x3)
+	OptionQuantityMaxValue = 10
+)
+
+// group of options of kind (ex: "choose exactly one spice level", "up to 3 toppings").
+// selected count is total quantity of options in the group
+type OptionGroup struct {
+	name             Name
+	optionItemIds    []string
+	required         bool
+	minSelect        uint
+	maxSelect        uint
+	maxQuantity      uint
+	defaultOptionIds []string
+}
+
+// 0 maxSelect means no limit. required group needs at least one selection
+func NewOptionGroup(name string, optionItemIds []string, required bool, minSelect, maxSelect, maxQuantity uint, defaultOptionIds []string) (*OptionGroup, error) {
+	nameV, err := NewName(name, OptionGroupNameMaxLength)
+	if err != nil {
+		return nil, err
+	}
+	if len(optionItemIds) == 0 {
+		return nil, common.NewValidationError("optionItemIds", fmt.Sprintf("option group needs option items:%s", name))
+	}
+	if err := validateDuplicatedIds("optionItemIds", optionItemIds); err != nil {
+		return nil, err
+	}
+	if required && minSelect == 0 {
+		minSelect = 1
+	}
+	if maxSelect != 0 && maxSelect < minSelect {
+		return nil, common.NewValidationError("maxSelect", fmt.Sprintf("Need to be greater than minSelect:%d", minSelect))
+	}
+	if maxQuantity == 0 || maxQuantity > OptionQuantityMaxValue {
+		return nil, common.NewValidationError("maxQuantity", fmt.Sprintf("Need to be in range 1-%d:%d", OptionQuantityMaxValue, maxQuantity))
+	}
+	if err := validateDuplicatedIds("defaultOptionIds", defaultOptionIds); err != nil {
+		return nil, err
+	}
+	group := &OptionGroup{
+		name:             *nameV,
+		optionItemIds:    optionItemIds,
+		required:         required,
+		minSelect:        minSelect,
+		maxSelect:        maxSelect,
+		maxQuantity:      maxQuantity,
+		defaultOptionIds: defaultOptionIds,
+	}
+	for _, id := range defaultOptionIds {
+		if !group.HasOption(id) {
+			return nil, common.NewValidationError("defaultOptionIds", fmt.Sprintf("Not exists in group:%s", id))
+		}
+	}
+	// defaults should be valid selection
+	if len(defaultOptionIds) > 0 {
+		if err := group.CheckSelection(group.GetDefaultSelection()); err != nil {
+			return nil, err
+		}
+	}
+	return group, nil
+}
+
+func (o *OptionGroup) GetName() string {
+	return o.name.GetValue()
+}
+
+func (o *OptionGroup) GetOptionItemIds() []string {
+	return o.optionItemIds
+}
+
+func (o *OptionGroup) IsRequired() bool {
+	return o.required
+}
+
+func (o *OptionGroup) GetMinSelect() uint {
+	return o.minSelect
+}
+
+func (o *OptionGroup) GetMaxSelect() uint {
+	return o.maxSelect
+}
+
+func (o *OptionGroup) GetMaxQuantity() uint {
+	return o.maxQuantity
+}
+
+func (o *OptionGroup) GetDefaultOptionIds() []string {
+	return o.defaultOptionIds
+}
+
+func (o *OptionGroup) HasOption(optionItemId string) bool {
+	for _, id := range o.optionItemIds {
+		if id == optionItemId {
+			return true
+		}
+	}
+	return false
+}
+
+// default options are selected by one
+func (o *OptionGroup) GetDefaultSelection() map[string]int {
+	selection := map[string]int{}
+	for _, id := range o.defaultOptionIds {
+		selection[id] = 1
+	}
+	return selection
+}
+
+// selection is quantity per option item id. options of other groups are ignored
+func (o *OptionGroup) CheckSelection(selection map[string]int) error {
+	total := 0
+	for id, quantity := range selection {
+		if !o.HasOption(id) {
+			continue
+		}
+		if quantity > int(o.maxQuantity) {
+			return common.NewValidationError("options", fmt.Sprintf("quantity of option is over limit(%d) in %s", o.maxQuantity, o.GetName()))
+		}
+		total += quantity
+	}
+	if total < int(o.minSelect) {
+		if o.required && total == 0 {
+			return common.NewValidationError("options", fmt.Sprintf("%s is required", o.GetName()))
+		}
+		return common.NewValidationError("options", fmt.Sprintf("select at least %d in %s", o.minSelect, o.GetName()))
+	}
+	if o.maxSelect != 0 && total > int(o.maxSelect) {
+		return common.NewValidationError("options", fmt.Sprintf("select up to %d in %s", o.maxSelect, o.GetName()))
+	}
+	return nil
+}
+
+func validateDuplicatedIds(name string, ids []string) error {
+	encountered := map[string]bool{}
+	for _, id := range ids {
+		if encountered[id] {
+			return common.NewValidationError(name, fmt.Sprintf("duplicate Id are not allowed:%s", id))
+		}
+		encountered[id] = true
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package item_test
+
+import (
+	"testing"
+
+	"chico/takeout/common"
+	"chico/takeout/domains/item"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewOptionGroup(t *testing.T) {
+	inputs := []struct {
+		name             string
+		optionItemIds    []string
+		required         bool
+		minSelect        uint
+		maxSelect        uint
+		maxQuantity      uint
+		defaultOptionIds []string
+		wantMinSelect    uint
+		hasErr           bool
+	}{
+		{name: "exactly one", optionItemIds: []string{"1", "2"}, required: true, minSelect: 1, maxSelect: 1, maxQuantity: 1, defaultOptionIds: []string{"1"}, wantMinSelect: 1},
+		{name: "required needs one at least", optionItemIds: []string{"1", "2"}, required: true, maxQuantity: 1, defaultOptionIds: []string{}, wantMinSelect: 1},
+		{name: "up to 3", optionItemIds: []string{"1", "2"}, maxSelect: 3, maxQuantity: 3, defaultOptionIds: []string{}},
+		{name: "no options", optionItemIds: []string{}, maxQuantity: 1, defaultOptionIds: []string{}, hasErr: true},
+		{name: "duplicated options", optionItemIds: []string{"1", "1"}, maxQuantity: 1, defaultOptionIds: []string{}, hasErr: true},
+		{name: "max is less than min", optionItemIds: []string{"1", "2"}, minSelect: 2, maxSelect: 1, maxQuantity: 1, defaultOptionIds: []string{}, hasErr: true},
+		{name: "zero quantity", optionItemIds: []string{"1"}, maxQuantity: 0, defaultOptionIds: []string{}, hasErr: true},
+		{name: "default is not in group", optionItemIds: []string{"1"}, maxQuantity: 1, defaultOptionIds: []string{"2"}, hasErr: true},
+		{name: "defaults are over max", optionItemIds: []string{"1", "2"}, maxSelect: 1, maxQuantity: 1, defaultOptionIds: []string{"1", "2"}, hasErr: true},
+	}
+	for _, tt := range inputs {
+		group, err := item.NewOptionGroup("辛さ", tt.optionItemIds, tt.required, tt.minSelect, tt.maxSelect, tt.maxQuantity, tt.defaultOptionIds)
+		if tt.hasErr {
+			assert.IsType(t, common.NewValidationError("", ""), err, tt.name)
+			continue
+		}
+		assert.NoError(t, err, tt.name)
+		assert.Equal(t, tt.wantMinSelect, group.GetMinSelect(), tt.name)
+	}
+}
+
+func TestOptionGroup_CheckSelection(t *testing.T) {
+	group, _ := item.NewOptionGroup("トッピング", []string{"1", "2", "3"}, true, 1, 3, 2, []string{})
+	inputs := []struct {
+		name      string
+		selection map[string]int
+		hasErr    bool
+	}{
+		{name: "one", selection: map[string]int{"1": 1}},
+		{name: "options of other group are ignored", selection: map[string]int{"1": 1, "9": 5}},
+		{name: "max by quantity", selection: map[string]int{"1": 2, "2": 1}},
+		{name: "required", selection: map[string]int{}, hasErr: true},
+		{name: "over max", selection: map[string]int{"1": 2, "2": 2}, hasErr: true},
+		{name: "over quantity", selection: map[string]int{"1": 3}, hasErr: true},
+	}
+	for _, tt := range inputs {
+		err := group.CheckSelection(tt.selection)
+		if tt.hasErr {
+			assert.IsType(t, common.NewValidationError("", ""), err, tt.name)
+		} else {
+			assert.NoError(t, err, tt.name)
+		}
+	}
+}
+
+func TestItemKind_SetOptionGroups(t *testing.T) {
+	kind, _ := item.NewItemKind("kind", 1, []string{"1", "2", "3"})
+	group1, _ := item.NewOptionGroup("group1", []string{"1", "2"}, false, 0, 0, 1, []string{})
+	group2, _ := item.NewOptionGroup("group2", []string{"2", "3"}, false, 0, 0, 1, []string{})
+	group3, _ := item.NewOptionGroup("group3", []string{"4"}, false, 0, 0, 1, []string{})
+
+	assert.Error(t, kind.SetOptionGroups([]item.OptionGroup{*group1, *group2}), "option in multiple groups")
+	assert.Error(t, kind.SetOptionGroups([]item.OptionGroup{*group3}), "option not in kind")
+	assert.Error(t, kind.SetOptionGroups([]item.OptionGroup{*group1, *group1}), "duplicated name")
+	assert.NoError(t, kind.SetOptionGroups([]item.OptionGroup{*group1}))
+	assert.Equal(t, "group1", kind.FindOptionGroup("2").GetName())
+	assert.Nil(t, kind.FindOptionGroup("3"))
+}
@@ -0,0 +1,136 @@
+package item
+
+import (
+	"chico/takeout/common"
+	"chico/takeout/domains/shared"
+	"fmt"
+)
+
+const (
+	FoodQuotaMaxValue = 1000
+)
+
+type FoodQuotaSource string
+
+const (
+	// saved by admin. it is not overwritten by forecast
+	FoodQuotaManual FoodQuotaSource = "manual"
+	// saved by applying demand forecast
+	FoodQuotaForecast FoodQuotaSource = "forecast"
+)
+
+type FoodQuotaRepository interface {
+	FindByDate(date string) ([]FoodQuota, error)
+	// start and end are included
+	FindByRange(startDate, endDate string) ([]FoodQuota, error)
+	// replaced if quota of the item, date and hour type exists
+	Save(quota *FoodQuota) error
+	// all quotas are saved or none
+	SaveAll(quotas []FoodQuota) error
+	Delete(itemId, date, hourTypeId string) error
+	// quotas of the item and hour type on the dates. all are deleted or none
+	DeleteAll(itemId, hourTypeId string, dates []string) error
+}
+
+// quota of food item on the date. it overrides max order per day.
+// quota with hour type limits the item in the business hour of the date in addition
+type FoodQuota struct {
+	itemId string
+	date   shared.Date
+	// empty means whole day
+	hourTypeId string
+	quantity   int
+	source     FoodQuotaSource
+}
+
+// quota set by admin. 0 means the item is sold out on the date (or in the business hour)
+func NewFoodQuota(itemId, date, hourTypeId string, quantity int) (*FoodQuota, error) {
+	return newFoodQuota(itemId, date, hourTypeId, quantity, FoodQuotaManual)
+}
+
+// whole day quota suggested by demand forecast
+func NewForecastFoodQuota(itemId, date string, quantity int) (*FoodQuota, error) {
+	return newFoodQuota(itemId, date, "", quantity, FoodQuotaForecast)
+}
+
+// empty source (saved before source is stored) is manual so that it is not overwritten by forecast
+func NewFoodQuotaForOrm(itemId, date, hourTypeId string, quantity int, source string) (*FoodQuota, error) {
+	switch FoodQuotaSource(source) {
+	case "", FoodQuotaManual:
+		return newFoodQuota(itemId, date, hourTypeId, quantity, FoodQuotaManual)
+	case FoodQuotaForecast:
+		return newFoodQuota(itemId, date, hourTypeId, quantity, FoodQuotaForecast)
+	}
+	return nil, common.NewValidationError("source", fmt.Sprintf("unknown source:%s", source))
+}
+
+func newFoodQuota(itemId, date, hourTypeId string, quantity int, source FoodQuotaSource) (*FoodQuota, error) {
+	if itemId == "" {
+		return nil, common.NewValidationError("itemId", "Not allowed to be empty")
+	}
+	dateV, err := shared.NewDate(date)
+	if err != nil {
+		return nil, err
+	}
+	if quantity < 0 || quantity > FoodQuotaMaxValue {
+		return nil, common.NewValidationError("quantity", fmt.Sprintf("should be 0 ~ %d", FoodQuotaMaxValue))
+	}
+	return &FoodQuota{
+		itemId:     itemId,
+		date:       *dateV,
+		hourTypeId: hourTypeId,
+		quantity:   quantity,
+		source:     source,
+	}, nil
+}
+
+func (q *FoodQuota) GetItemId() string {
+	return q.itemId
+}
+
+func (q *FoodQuota) GetDate() string {
+	return q.date.GetValue()
+}
+
+func (q *FoodQuota) GetHourTypeId() string {
+	return q.hourTypeId
+}
+
+func (q *FoodQuota) GetQuantity() int {
+	return q.quantity
+}
+
+func (q *FoodQuota) GetSource() FoodQuotaSource {
+	return q.source
+}
+
+func (q *FoodQuota) IsWholeDay() bool {
+	return q.hourTypeId == ""
+}
+
+func (q *FoodQuota) IsManual() bool {
+	return q.source == FoodQuotaManual
+}
+
+// quota of the date if exists, otherwise max order per day
+func (f *FoodItem) GetQuotaAt(date string, quotas []FoodQuota) int {
+	for _, quota := range quotas {
+		if quota.IsWholeDay() && quota.GetItemId() == f.GetId() && quota.GetDate() == date {
+			return quota.GetQuantity()
+		}
+	}
+	return f.GetMaxOrderPerDay()
+}
+
+// false if the business hour of the date has no quota
+func (f *FoodItem) GetHourQuotaAt(date, hourTypeId string, quotas []FoodQuota) (int, bool) {
+	if hourTypeId == "" {
+		return 0, false
+	}
+	for _, quota := range quotas {
+		if quota.GetHourTypeId() == hourTypeId && quota.GetItemId() == f.GetId() && quota.GetDate() == date {
+			return quota.GetQuantity(), true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,76 @@
+package item_test
+
+import (
+	"testing"
+
+	"chico/takeout/common"
+	"chico/takeout/domains/item"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewFoodQuota(t *testing.T) {
+	inputs := []struct {
+		name     string
+		itemId   string
+		date     string
+		quantity int
+		hasErr   bool
+	}{
+		{name: "sold out", itemId: "food1", date: "2051/01/11", quantity: 0},
+		{name: "max", itemId: "food1", date: "2051/01/11", quantity: item.FoodQuotaMaxValue},
+		{name: "over max", itemId: "food1", date: "2051/01/11", quantity: item.FoodQuotaMaxValue + 1, hasErr: true},
+		{name: "negative", itemId: "food1", date: "2051/01/11", quantity: -1, hasErr: true},
+		{name: "empty item", itemId: "", date: "2051/01/11", quantity: 1, hasErr: true},
+		{name: "invalid date", itemId: "food1", date: "2051-01-11", quantity: 1, hasErr: true},
+	}
+	for _, tt := range inputs {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := item.NewFoodQuota(tt.itemId, tt.date, "", tt.quantity)
+			if tt.hasErr {
+				_, ok := err.(*common.ValidationError)
+				assert.True(t, ok, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.quantity, got.GetQuantity())
+			assert.True(t, got.IsWholeDay())
+		})
+	}
+}
+
+func TestNewFoodQuotaForOrm_Source(t *testing.T) {
+	manual, err := item.NewFoodQuotaForOrm("food1", "2051/01/11", "", 1, "manual")
+	assert.NoError(t, err)
+	assert.True(t, manual.IsManual())
+	forecast, err := item.NewFoodQuotaForOrm("food1", "2051/01/11", "", 1, "forecast")
+	assert.NoError(t, err)
+	assert.False(t, forecast.IsManual())
+	// saved before source is stored
+	empty, err := item.NewFoodQuotaForOrm("food1", "2051/01/11", "", 1, "")
+	assert.NoError(t, err)
+	assert.Equal(t, item.FoodQuotaManual, empty.GetSource())
+	_, err = item.NewFoodQuotaForOrm("food1", "2051/01/11", "", 1, "other")
+	_, ok := err.(*common.ValidationError)
+	assert.True(t, ok, err)
+}
+
+func TestFoodItem_GetQuotaAt(t *testing.T) {
+	food, _ := item.NewFoodItem("food1", "food", 1, 4, 10, 100, "kind1", []string{"morning", "lunch"}, true, "", []string{}, 0)
+	day, _ := item.NewFoodQuota(food.GetId(), "2051/01/11", "", 30)
+	lunch, _ := item.NewFoodQuota(food.GetId(), "2051/01/11", "lunch", 5)
+	other, _ := item.NewFoodQuota("other", "2051/01/12", "", 1)
+	quotas := []item.FoodQuota{*lunch, *day, *other}
+
+	assert.Equal(t, 30, food.GetQuotaAt("2051/01/11", quotas))
+	assert.Equal(t, 10, food.GetQuotaAt("2051/01/12", quotas))
+
+	quantity, ok := food.GetHourQuotaAt("2051/01/11", "lunch", quotas)
+	assert.True(t, ok)
+	assert.Equal(t, 5, quantity)
+	_, ok = food.GetHourQuotaAt("2051/01/11", "morning", quotas)
+	assert.False(t, ok)
+	// whole day quota is not hour quota
+	_, ok = food.GetHourQuotaAt("2051/01/11", "", quotas)
+	assert.False(t, ok)
+}
@@ -0,0 +1,241 @@
+package item
+
+import (
+	"chico/takeout/common"
+	"chico/takeout/domains/shared"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type StockBatchRepository interface {
+	Find(id string) (*StockBatch, error)
+	FindByStockItemId(stockItemId string) ([]StockBatch, error)
+	Create(batch *StockBatch) (string, error)
+	Update(batch *StockBatch) error
+}
+
+// inventory of stock item received at once (ex: delivery, production batch).
+// batch is usable for pick up date from available date until expiry date
+type StockBatch struct {
+	id            string
+	stockItemId   string
+	availableFrom shared.Date
+	expiresAt     shared.Date
+	received      StockRemain
+	remain        StockRemain
+	wasted        StockRemain
+}
+
+func NewStockBatch(stockItemId, availableFrom, expiresAt string, quantity int) (*StockBatch, error) {
+	if quantity < 1 {
+		return nil, common.NewValidationError("quantity", "Need to be greater than 1")
+	}
+	return newStockBatch(uuid.NewString(), stockItemId, availableFrom, expiresAt, quantity, quantity, 0)
+}
+
+// only for orm
+func NewStockBatchForOrm(id, stockItemId, availableFrom, expiresAt string, received, remain, wasted int) (*StockBatch, error) {
+	return newStockBatch(id, stockItemId, availableFrom, expiresAt, received, remain, wasted)
+}
+
+func newStockBatch(id, stockItemId, availableFrom, expiresAt string, received, remain, wasted int) (*StockBatch, error) {
+	availableFromV, err := shared.NewDate(availableFrom)
+	if err != nil {
+		return nil, err
+	}
+	expiresAtV, err := shared.NewDate(expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	if expiresAtV.GetValue() < availableFromV.GetValue() {
+		return nil, common.NewValidationError("expiresAt", fmt.Sprintf("Need to be after available date:%s", availableFrom))
+	}
+	receivedV, err := NewStockRemain(received, StockItemMaxRemain)
+	if err != nil {
+		return nil, err
+	}
+	remainV, err := NewStockRemain(remain, received)
+	if err != nil {
+		return nil, err
+	}
+	wastedV, err := NewStockRemain(wasted, received-remain)
+	if err != nil {
+		return nil, err
+	}
+	return &StockBatch{
+		id:            id,
+		stockItemId:   stockItemId,
+		availableFrom: *availableFromV,
+		expiresAt:     *expiresAtV,
+		received:      *receivedV,
+		remain:        *remainV,
+		wasted:        *wastedV,
+	}, nil
+}
+
+func (s *StockBatch) GetId() string {
+	return s.id
+}
+
+func (s *StockBatch) GetStockItemId() string {
+	return s.stockItemId
+}
+
+func (s *StockBatch) GetAvailableFrom() string {
+	return s.availableFrom.GetValue()
+}
+
+func (s *StockBatch) GetExpiresAt() string {
+	return s.expiresAt.GetValue()
+}
+
+func (s *StockBatch) GetReceived() int {
+	return s.received.GetValue()
+}
+
+func (s *StockBatch) GetRemain() int {
+	return s.remain.GetValue()
+}
+
+func (s *StockBatch) GetWasted() int {
+	return s.wasted.GetValue()
+}
+
+// expiry date is included
+func (s *StockBatch) IsUsableAt(date time.Time) bool {
+	dateStr := common.ConvertTimeToDateStr(date)
+	return s.availableFrom.GetValue() <= dateStr && dateStr <= s.expiresAt.GetValue()
+}
+
+// quantity consumed by orders
+func (s *StockBatch) GetConsumed() int {
+	return s.received.GetValue() - s.remain.GetValue() - s.wasted.GetValue()
+}
+
+// wasted items (ex: expired, damaged) are removed from remain
+func (s *StockBatch) Waste(quantity int) error {
+	remain, err := s.remain.Consume(quantity)
+	if err != nil {
+		return err
+	}
+	wasted, err := NewStockRemain(s.wasted.GetValue()+quantity, s.received.GetValue())
+	if err != nil {
+		return err
+	}
+	s.remain = *remain
+	s.wasted = *wasted
+	return nil
+}
+
+func (s *StockBatch) consume(quantity int) error {
+	remain, err := s.remain.Consume(quantity)
+	if err != nil {
+		return err
+	}
+	s.remain = *remain
+	return nil
+}
+
+func (s *StockBatch) restore(quantity int) error {
+	if quantity > s.GetConsumed() {
+		return common.NewValidationError("stock remain", fmt.Sprintf("restore is over consumed. consumed:%d, request:%d", s.GetConsumed(), quantity))
+	}
+	remain, err := s.remain.Increase(quantity)
+	if err != nil {
+		return err
+	}
+	s.remain = *remain
+	return nil
+}
+
+// batches of one stock item. first expiring batch is consumed first (FEFO)
+type StockBatchInventory struct {
+	batches []StockBatch
+}
+
+func NewStockBatchInventory(batches []StockBatch) *StockBatchInventory {
+	sorted := append([]StockBatch{}, batches...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].GetExpiresAt() != sorted[j].GetExpiresAt() {
+			return sorted[i].GetExpiresAt() < sorted[j].GetExpiresAt()
+		}
+		return sorted[i].GetAvailableFrom() < sorted[j].GetAvailableFrom()
+	})
+	return &StockBatchInventory{batches: sorted}
+}
+
+func (s *StockBatchInventory) RemainAt(date time.Time) int {
+	remain := 0
+	for _, batch := range s.batches {
+		if batch.IsUsableAt(date) {
+			remain += batch.GetRemain()
+		}
+	}
+	return remain
+}
+
+// returns updated batches
+func (s *StockBatchInventory) Consume(date time.Time, quantity int) ([]StockBatch, error) {
+	remain := s.RemainAt(date)
+	if remain < quantity {
+		return nil, common.NewValidationError("stock remain", fmt.Sprintf("remain count is insufficient at %s. remain:%d , request:%d", common.ConvertTimeToDateStr(date), remain, quantity))
+	}
+	updated := []StockBatch{}
+	for i := range s.batches {
+		if quantity == 0 {
+			break
+		}
+		batch := &s.batches[i]
+		if !batch.IsUsableAt(date) || batch.GetRemain() == 0 {
+			continue
+		}
+		consumed := quantity
+		if batch.GetRemain() < consumed {
+			consumed = batch.GetRemain()
+		}
+		if err := batch.consume(consumed); err != nil {
+			return nil, err
+		}
+		quantity -= consumed
+		updated = append(updated, *batch)
+	}
+	return updated, nil
+}
+
+// canceled quantity is restored to batches which were consumed, in the same order as consumption.
+// error if consumed batches can not take all of the quantity (ex: batch was edited after consumed).
+// returns updated batches
+func (s *StockBatchInventory) Restore(date time.Time, quantity int) ([]StockBatch, error) {
+	consumed := 0
+	for _, batch := range s.batches {
+		if batch.IsUsableAt(date) {
+			consumed += batch.GetConsumed()
+		}
+	}
+	if consumed < quantity {
+		return nil, common.NewValidationError("stock remain", fmt.Sprintf("restore is over consumed at %s. consumed:%d, request:%d", common.ConvertTimeToDateStr(date), consumed, quantity))
+	}
+	updated := []StockBatch{}
+	for i := range s.batches {
+		if quantity == 0 {
+			break
+		}
+		batch := &s.batches[i]
+		if !batch.IsUsableAt(date) || batch.GetConsumed() == 0 {
+			continue
+		}
+		restored := quantity
+		if batch.GetConsumed() < restored {
+			restored = batch.GetConsumed()
+		}
+		if err := batch.restore(restored); err != nil {
+			return nil, err
+		}
+		quantity -= restored
+		updated = append(updated, *batch)
+	}
+	return updated, nil
+}
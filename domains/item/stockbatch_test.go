@@ -0,0 +1,101 @@
+package item_test
+
+import (
+	"testing"
+	"time"
+
+	"chico/takeout/common"
+	"chico/takeout/domains/item"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewStockBatch(t *testing.T) {
+	inputs := []struct {
+		name          string
+		availableFrom string
+		expiresAt     string
+		quantity      int
+		hasErr        bool
+	}{
+		{name: "valid", availableFrom: "2051/01/10", expiresAt: "2051/01/12", quantity: 10},
+		{name: "same day", availableFrom: "2051/01/10", expiresAt: "2051/01/10", quantity: 1},
+		{name: "expires before available", availableFrom: "2051/01/10", expiresAt: "2051/01/09", quantity: 10, hasErr: true},
+		{name: "zero quantity", availableFrom: "2051/01/10", expiresAt: "2051/01/12", quantity: 0, hasErr: true},
+	}
+	for _, tt := range inputs {
+		batch, err := item.NewStockBatch("stock1", tt.availableFrom, tt.expiresAt, tt.quantity)
+		if tt.hasErr {
+			assert.IsType(t, common.NewValidationError("", ""), err, tt.name)
+			continue
+		}
+		assert.NoError(t, err, tt.name)
+		assert.Equal(t, tt.quantity, batch.GetReceived(), tt.name)
+		assert.Equal(t, tt.quantity, batch.GetRemain(), tt.name)
+	}
+}
+
+func TestStockBatch_IsUsableAt(t *testing.T) {
+	batch, _ := item.NewStockBatch("stock1", "2051/01/10", "2051/01/12", 10)
+	date := func(d int) time.Time {
+		return time.Date(2051, 1, d, 12, 0, 0, 0, common.GetStoreLocation())
+	}
+	assert.False(t, batch.IsUsableAt(date(9)))
+	assert.True(t, batch.IsUsableAt(date(10)))
+	assert.True(t, batch.IsUsableAt(date(12)))
+	assert.False(t, batch.IsUsableAt(date(13)))
+}
+
+func TestStockBatch_Waste(t *testing.T) {
+	batch, _ := item.NewStockBatch("stock1", "2051/01/10", "2051/01/12", 10)
+	err := batch.Waste(3)
+	assert.NoError(t, err)
+	assert.Equal(t, 7, batch.GetRemain())
+	assert.Equal(t, 3, batch.GetWasted())
+	assert.Equal(t, 0, batch.GetConsumed())
+
+	err = batch.Waste(8)
+	assert.Error(t, err)
+	assert.Equal(t, 7, batch.GetRemain())
+}
+
+func TestStockBatchInventory_ConsumeAndRestore(t *testing.T) {
+	later, _ := item.NewStockBatch("stock1", "2051/01/10", "2051/01/15", 5)
+	sooner, _ := item.NewStockBatch("stock1", "2051/01/08", "2051/01/11", 3)
+	expired, _ := item.NewStockBatch("stock1", "2051/01/01", "2051/01/09", 10)
+	inventory := item.NewStockBatchInventory([]item.StockBatch{*later, *sooner, *expired})
+	date := time.Date(2051, 1, 10, 12, 0, 0, 0, common.GetStoreLocation())
+
+	assert.Equal(t, 8, inventory.RemainAt(date))
+
+	// insufficient
+	_, err := inventory.Consume(date, 9)
+	assert.IsType(t, common.NewValidationError("", ""), err)
+
+	// first expiring first
+	updated, err := inventory.Consume(date, 4)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(updated))
+	assert.Equal(t, sooner.GetId(), updated[0].GetId())
+	assert.Equal(t, 0, updated[0].GetRemain())
+	assert.Equal(t, later.GetId(), updated[1].GetId())
+	assert.Equal(t, 4, updated[1].GetRemain())
+	assert.Equal(t, 4, inventory.RemainAt(date))
+
+	updated, err = inventory.Restore(date, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(updated))
+	assert.Equal(t, sooner.GetId(), updated[0].GetId())
+	assert.Equal(t, 2, updated[0].GetRemain())
+	assert.Equal(t, 6, inventory.RemainAt(date))
+
+	// over consumed batches is not restored partially
+	_, err = inventory.Restore(date, 3)
+	assert.IsType(t, common.NewValidationError("", ""), err)
+	assert.Equal(t, 6, inventory.RemainAt(date))
+
+	updated, err = inventory.Restore(date, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(updated))
+	assert.Equal(t, 8, inventory.RemainAt(date))
+}
@@ -11,6 +11,10 @@ type StockItemRepository interface {
 type StockItem struct {
 	commonItem
 	remain StockRemain
+	// remain is managed per batch with expiry instead of single counter
+	trackByBatch bool
+	// alert is raised when remain is less than or equal to this. 0 means only sold out is alerted
+	lowStockThreshold StockRemain
 }
 
 const (
@@ -46,6 +50,27 @@ func NewStockItemForOrm(id, name, description string, priority, maxOrder, price,
 }
 
 
+func (s *StockItem) IsTrackedByBatch() bool {
+	return s.trackByBatch
+}
+
+func (s *StockItem) SetTrackByBatch(trackByBatch bool) {
+	s.trackByBatch = trackByBatch
+}
+
+func (s *StockItem) GetLowStockThreshold() int {
+	return s.lowStockThreshold.GetValue()
+}
+
+func (s *StockItem) SetLowStockThreshold(value int) error {
+	threshold, err := NewStockRemain(value, StockItemMaxRemain)
+	if err != nil {
+		return err
+	}
+	s.lowStockThreshold = *threshold
+	return nil
+}
+
 func (s *StockItem) GetRemain() int {
 	return s.remain.GetValue()
 }
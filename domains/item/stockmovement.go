@@ -0,0 +1,214 @@
+package item
+
+import (
+	"chico/takeout/common"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type StockMovementType string
+
+const (
+	// delivery or production of batch
+	StockMovementReceipt StockMovementType = "receipt"
+	// consumed by order (amended quantity is also recorded as order)
+	StockMovementOrder StockMovementType = "order"
+	// restored by canceled order
+	StockMovementCancel StockMovementType = "cancel"
+	// remain is changed by admin
+	StockMovementAdjustment StockMovementType = "adjustment"
+	StockMovementWaste      StockMovementType = "waste"
+)
+
+func NewStockMovementType(value string) (*StockMovementType, error) {
+	movementType := StockMovementType(value)
+	switch movementType {
+	case StockMovementReceipt, StockMovementOrder, StockMovementCancel, StockMovementAdjustment, StockMovementWaste:
+		return &movementType, nil
+	}
+	return nil, common.NewValidationError("movementType", fmt.Sprintf("unknown type:%s", value))
+}
+
+type StockMovementRepository interface {
+	Create(movement *StockMovement) error
+	// from and to are included
+	FindByStockItemId(stockItemId string, from, to time.Time) ([]StockMovement, error)
+	// sum of quantity per stock item id
+	SumQuantities() (map[string]int, error)
+}
+
+// append only record of stock remain change.
+// quantity is signed (negative means decreasing remain)
+type StockMovement struct {
+	id           string
+	stockItemId  string
+	batchId      string
+	movementType StockMovementType
+	quantity     int
+	orderId      string
+	userId       string
+	memo         string
+	createdAt    time.Time
+}
+
+// batchId is empty if stock item is not tracked by batch
+func NewStockMovement(stockItemId, batchId string, movementType StockMovementType, quantity int, orderId, userId, memo string, now time.Time) (*StockMovement, error) {
+	if quantity == 0 {
+		return nil, common.NewValidationError("quantity", "movement without quantity is not allowed")
+	}
+	return &StockMovement{
+		id:           uuid.NewString(),
+		stockItemId:  stockItemId,
+		batchId:      batchId,
+		movementType: movementType,
+		quantity:     quantity,
+		orderId:      orderId,
+		userId:       userId,
+		memo:         memo,
+		createdAt:    now,
+	}, nil
+}
+
+// only for orm
+func NewStockMovementForOrm(id, stockItemId, batchId, movementType string, quantity int, orderId, userId, memo string, createdAt time.Time) (*StockMovement, error) {
+	movementTypeV, err := NewStockMovementType(movementType)
+	if err != nil {
+		return nil, err
+	}
+	return &StockMovement{
+		id:           id,
+		stockItemId:  stockItemId,
+		batchId:      batchId,
+		movementType: *movementTypeV,
+		quantity:     quantity,
+		orderId:      orderId,
+		userId:       userId,
+		memo:         memo,
+		createdAt:    createdAt,
+	}, nil
+}
+
+func (s *StockMovement) GetId() string {
+	return s.id
+}
+
+func (s *StockMovement) GetStockItemId() string {
+	return s.stockItemId
+}
+
+func (s *StockMovement) GetBatchId() string {
+	return s.batchId
+}
+
+func (s *StockMovement) GetMovementType() StockMovementType {
+	return s.movementType
+}
+
+func (s *StockMovement) GetQuantity() int {
+	return s.quantity
+}
+
+func (s *StockMovement) GetOrderId() string {
+	return s.orderId
+}
+
+func (s *StockMovement) GetUserId() string {
+	return s.userId
+}
+
+func (s *StockMovement) GetMemo() string {
+	return s.memo
+}
+
+func (s *StockMovement) GetCreatedAt() time.Time {
+	return s.createdAt
+}
+
+// difference between current remain and balance of movements
+type StockDrift struct {
+	stockItemId string
+	remain      int
+	balance     int
+}
+
+func (s *StockDrift) GetStockItemId() string {
+	return s.stockItemId
+}
+
+func (s *StockDrift) GetRemain() int {
+	return s.remain
+}
+
+func (s *StockDrift) GetBalance() int {
+	return s.balance
+}
+
+// positive means remain is more than movements
+func (s *StockDrift) GetDrift() int {
+	return s.remain - s.balance
+}
+
+type StockLedgerService struct {
+	stockRepo    StockItemRepository
+	batchRepo    StockBatchRepository
+	movementRepo StockMovementRepository
+}
+
+func NewStockLedgerService(stockRepo StockItemRepository, batchRepo StockBatchRepository, movementRepo StockMovementRepository) *StockLedgerService {
+	return &StockLedgerService{
+		stockRepo:    stockRepo,
+		batchRepo:    batchRepo,
+		movementRepo: movementRepo,
+	}
+}
+
+// remain of stock item tracked by batch is sum of batches
+func (s *StockLedgerService) CurrentRemain(stock *StockItem) (int, error) {
+	if !stock.IsTrackedByBatch() {
+		return stock.GetRemain(), nil
+	}
+	batches, err := s.batchRepo.FindByStockItemId(stock.GetId())
+	if err != nil {
+		return 0, err
+	}
+	remain := 0
+	for _, batch := range batches {
+		remain += batch.GetRemain()
+	}
+	return remain, nil
+}
+
+// all stock items are compared with balance of movements
+func (s *StockLedgerService) CheckDrifts() ([]StockDrift, error) {
+	stocks, err := s.stockRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	balances, err := s.movementRepo.SumQuantities()
+	if err != nil {
+		return nil, err
+	}
+	drifts := []StockDrift{}
+	for i := range stocks {
+		remain, err := s.CurrentRemain(&stocks[i])
+		if err != nil {
+			return nil, err
+		}
+		drifts = append(drifts, StockDrift{stockItemId: stocks[i].GetId(), remain: remain, balance: balances[stocks[i].GetId()]})
+	}
+	return drifts, nil
+}
+
+func (s *StockLedgerService) CheckDrift(stock *StockItem) (*StockDrift, error) {
+	remain, err := s.CurrentRemain(stock)
+	if err != nil {
+		return nil, err
+	}
+	balances, err := s.movementRepo.SumQuantities()
+	if err != nil {
+		return nil, err
+	}
+	return &StockDrift{stockItemId: stock.GetId(), remain: remain, balance: balances[stock.GetId()]}, nil
+}
@@ -0,0 +1,60 @@
+package item_test
+
+import (
+	"testing"
+	"time"
+
+	"chico/takeout/common"
+	"chico/takeout/domains/item"
+	"chico/takeout/infrastructures/memory"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewStockMovement(t *testing.T) {
+	now := time.Date(2051, 1, 10, 12, 0, 0, 0, common.GetStoreLocation())
+	_, err := item.NewStockMovement("stock1", "", item.StockMovementAdjustment, 0, "", "user1", "", now)
+	assert.IsType(t, common.NewValidationError("", ""), err)
+
+	_, err = item.NewStockMovementForOrm("m1", "stock1", "", "unknown", 1, "", "user1", "", now)
+	assert.IsType(t, common.NewValidationError("", ""), err)
+	movement, err := item.NewStockMovementForOrm("m1", "stock1", "", "waste", -1, "", "user1", "", now)
+	assert.NoError(t, err)
+	assert.Equal(t, item.StockMovementWaste, movement.GetMovementType())
+}
+
+func TestStockLedgerService_CheckDrifts(t *testing.T) {
+	stockRepo := memory.NewStockItemMemoryRepository()
+	stockRepo.Reset()
+	batchRepo := memory.NewStockBatchMemoryRepository()
+	movementRepo := memory.NewStockMovementMemoryRepository()
+	now := time.Date(2051, 1, 10, 12, 0, 0, 0, common.GetStoreLocation())
+
+	stock, _ := item.NewStockItem("batch", "item", 3, 10, 100, "kind1", true, "")
+	stock.SetTrackByBatch(true)
+	stockRepo.Create(stock)
+	batch, _ := item.NewStockBatch(stock.GetId(), "2051/01/10", "2051/01/12", 5)
+	batchRepo.Create(batch)
+	receipt, _ := item.NewStockMovement(stock.GetId(), batch.GetId(), item.StockMovementReceipt, 5, "", "user1", "", now)
+	movementRepo.Create(receipt)
+
+	service := item.NewStockLedgerService(stockRepo, batchRepo, movementRepo)
+	drifts, err := service.CheckDrifts()
+	assert.NoError(t, err)
+	for _, drift := range drifts {
+		if drift.GetStockItemId() == stock.GetId() {
+			assert.Equal(t, 5, drift.GetRemain())
+			assert.Equal(t, 0, drift.GetDrift())
+			continue
+		}
+		// remain of initial data is not recorded
+		assert.Equal(t, drift.GetRemain(), drift.GetDrift())
+	}
+
+	// batch is changed without movement
+	batch.Waste(2)
+	batchRepo.Update(batch)
+	drift, err := service.CheckDrift(stock)
+	assert.NoError(t, err)
+	assert.Equal(t, -2, drift.GetDrift())
+}
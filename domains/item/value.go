@@ -125,6 +125,26 @@ func NewMaxOrderPerDay(value int, maxOrder MaxOrder) (*MaxOrderPerDay, error) {
 	return &MaxOrderPerDay{IntValue: shared.NewIntValue(value)}, nil
 }
 
+const (
+	// 1 week
+	LeadTimeHoursMaxValue = 168
+)
+
+// hours needed before pick up time to prepare item
+// 0 means lead time of business hour is used
+type LeadTimeHours struct {
+	shared.UintValue
+}
+
+var leadTimeHoursValidator = validator.NewRangeInteger("LeadTimeHours", 0, LeadTimeHoursMaxValue)
+
+func NewLeadTimeHours(value uint) (*LeadTimeHours, error) {
+	if err := leadTimeHoursValidator.Validate(int(value)); err != nil {
+		return nil, err
+	}
+	return &LeadTimeHours{UintValue: shared.NewUintValue(value)}, nil
+}
+
 type Description struct {
 	shared.StringValue
 }
@@ -41,31 +41,31 @@ func (s *StoreMessage) GetCreated() time.Time {
 	return s.created
 }
 
-func NewMessage(id, content string) (*StoreMessage, error) {
+func NewMessage(id, content string, now time.Time) (*StoreMessage, error) {
 	switch id {
 	case TopMessageId:
-		return NewStoreTopMessage(content)
+		return NewStoreTopMessage(content, now)
 	case MyPageMessageId:
-		return NewMyPageMessage(content)
+		return NewMyPageMessage(content, now)
 	}
 	return nil, common.NewValidationError("Id", "not supported type message")
 }
 
 func NewMessageForOrm(id, content string, edited, created time.Time) (*StoreMessage, error) {
 	msg := StoreMessage{id: id, edited: edited, created: created}
-	err := msg.Set(content)
+	err := msg.Set(content, edited)
 	return &msg, err
 }
 
-func NewStoreTopMessage(content string) (*StoreMessage, error) {
-	return newStoreMessage(TopMessageId, content)
+func NewStoreTopMessage(content string, now time.Time) (*StoreMessage, error) {
+	return newStoreMessage(TopMessageId, content, now)
 }
 
-func NewMyPageMessage(content string) (*StoreMessage, error) {
-	return newStoreMessage(MyPageMessageId, content)
+func NewMyPageMessage(content string, now time.Time) (*StoreMessage, error) {
+	return newStoreMessage(MyPageMessageId, content, now)
 }
 
-func newStoreMessage(id, content string) (*StoreMessage, error) {
+func newStoreMessage(id, content string, now time.Time) (*StoreMessage, error) {
 	contentVal, err := NewContent(content)
 	if err != nil {
 		return nil, err
@@ -73,7 +73,6 @@ func newStoreMessage(id, content string) (*StoreMessage, error) {
 	if strings.TrimSpace(id) == "" {
 		return nil, common.NewValidationError(id, "required")
 	}
-	now := *common.GetNowTime()
 	msg := &StoreMessage{
 		id:      id,
 		content: *contentVal,
@@ -83,12 +82,11 @@ func newStoreMessage(id, content string) (*StoreMessage, error) {
 	return msg, nil
 }
 
-func (s *StoreMessage) Set(content string) error {
+func (s *StoreMessage) Set(content string, now time.Time) error {
 	contentVal, err := NewContent(content)
 	if err != nil {
 		return err
 	}
-	now := *common.GetNowTime()
 
 	s.content = *contentVal
 	s.edited = now
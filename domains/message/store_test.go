@@ -30,9 +30,6 @@ var jst = time.FixedZone("Asia/Tokyo", 9*60*60)
 
 func TestNewStoreTopMessage(t *testing.T) {
 	mockedTime := time.Date(2023, 10, 5, 10, 20, 30, 0, jst)
-	common.MockNow(func() time.Time {
-		return mockedTime
-	})
 	inputs := []messageInfoTestInput{
 		{
 			name:             "normal",
@@ -49,7 +46,7 @@ func TestNewStoreTopMessage(t *testing.T) {
 	for _, tt := range inputs {
 		fmt.Println("name:", tt.name)
 
-		msg, err := message.NewMessage("1", tt.args.content)
+		msg, err := message.NewMessage("1", tt.args.content, tt.args.created)
 		if err != nil {
 			var vErr *common.ValidationError
 			if errors.As(err, &vErr) {
@@ -74,9 +71,6 @@ func TestNewStoreTopMessage(t *testing.T) {
 
 func TestNewMyPageMessage(t *testing.T) {
 	mockedTime := time.Date(2023, 10, 5, 10, 20, 30, 0, jst)
-	common.MockNow(func() time.Time {
-		return mockedTime
-	})
 	inputs := []messageInfoTestInput{
 		{
 			name:             "normal",
@@ -93,7 +87,7 @@ func TestNewMyPageMessage(t *testing.T) {
 	for _, tt := range inputs {
 		fmt.Println("name:", tt.name)
 
-		msg, err := message.NewMessage("2", tt.args.content)
+		msg, err := message.NewMessage("2", tt.args.content, tt.args.created)
 		if err != nil {
 			var vErr *common.ValidationError
 			if errors.As(err, &vErr) {
@@ -119,9 +113,6 @@ func TestNewMyPageMessage(t *testing.T) {
 
 func TestNotSupportedMessage(t *testing.T) {
 	mockedTime := time.Date(2023, 10, 5, 10, 20, 30, 0, jst)
-	common.MockNow(func() time.Time {
-		return mockedTime
-	})
 	inputs := []messageInfoTestInput{
 		{
 			name:             "not supported id",
@@ -132,7 +123,7 @@ func TestNotSupportedMessage(t *testing.T) {
 	for _, tt := range inputs {
 		fmt.Println("name:", tt.name)
 
-		msg, err := message.NewMessage(tt.args.id, tt.args.content)
+		msg, err := message.NewMessage(tt.args.id, tt.args.content, tt.args.created)
 		if err != nil {
 			var vErr *common.ValidationError
 			if errors.As(err, &vErr) {
@@ -0,0 +1,71 @@
+package order
+
+import (
+	"chico/takeout/common"
+	"chico/takeout/domains/item"
+	"chico/takeout/domains/store"
+)
+
+// alerts of stock items and food items by ordering
+type ItemAlertChecker struct {
+	stockAlertChecker item.StockAlertChecker
+	foodRemainChecker FoodItemRemainChecker
+	alertService      item.ItemAlertService
+	clock             common.Clock
+}
+
+func NewItemAlertChecker(stockRepo item.StockItemRepository, batchRepo item.StockBatchRepository, movementRepo item.StockMovementRepository,
+	orderRepo OrderInfoRepository, foodRepo item.FoodItemRepository, quotaRepo item.FoodQuotaRepository,
+	busRepo store.BusinessHoursRepository, spBusRepo store.SpecialBusinessHourRepository, spHolidayRepo store.SpecialHolidayRepository,
+	alertRepo item.ItemAlertRepository, clock common.Clock) *ItemAlertChecker {
+	return &ItemAlertChecker{
+		stockAlertChecker: *item.NewStockAlertChecker(stockRepo, batchRepo, movementRepo, alertRepo, clock),
+		foodRemainChecker: *NewFoodItemRemainChecker(orderRepo, foodRepo, quotaRepo, busRepo, spBusRepo, spHolidayRepo),
+		alertService:      *item.NewItemAlertService(alertRepo),
+		clock:             clock,
+	}
+}
+
+// remain per stock item id before ordering, to compare with remain after ordering
+func (c *ItemAlertChecker) SnapshotStockRemains() (map[string]int, error) {
+	return c.stockAlertChecker.SnapshotRemains()
+}
+
+// returns alerts which are raised for the first time on the date.
+// stock items are not checked if remains before ordering is nil
+func (c *ItemAlertChecker) Check(before map[string]int, order *OrderInfo) ([]item.ItemAlert, error) {
+	now := c.clock.Now()
+	alerts := []item.ItemAlert{}
+	if before != nil {
+		stockAlerts, err := c.stockAlertChecker.Check(before)
+		if err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, stockAlerts...)
+	}
+
+	// food quota is per pick up date
+	soldOut, err := c.foodRemainChecker.FindSoldOut(order.GetPickupDate(), order.GetFoodItems())
+	if err != nil {
+		return nil, err
+	}
+	for _, food := range soldOut {
+		alert, err := item.NewItemAlert(food.GetId(), "food", food.GetName(), item.ItemAlertSoldOut, order.GetPickupDate(), 0, 0, now)
+		if err != nil {
+			return nil, err
+		}
+		raised, err := c.alertService.Raise(alert)
+		if err != nil {
+			return nil, err
+		}
+		if raised {
+			alerts = append(alerts, *alert)
+		}
+	}
+	return alerts, nil
+}
+
+// only stock items, since food quota is not decreased by canceling
+func (c *ItemAlertChecker) CheckStock(before map[string]int) ([]item.ItemAlert, error) {
+	return c.stockAlertChecker.Check(before)
+}
@@ -3,7 +3,9 @@ package order
 import (
 	"chico/takeout/common"
 	"chico/takeout/domains/item"
+	"chico/takeout/domains/store"
 	"fmt"
+	"time"
 )
 
 type ItemOrder struct {
@@ -21,14 +23,19 @@ func (i *ItemOrder) GetOptionOderIds() []string {
 }
 
 type OptionOrder struct {
-	id string
+	id       string
+	quantity int
 }
 
-func NewItemOrder(id string, quantity int, optionIds []string) *ItemOrder {
-	options := []OptionOrder{}
-	for _, opt := range optionIds {
-		options = append(options, OptionOrder{id: opt})
+// 0 quantity means one
+func NewOptionOrder(id string, quantity int) *OptionOrder {
+	if quantity == 0 {
+		quantity = 1
 	}
+	return &OptionOrder{id: id, quantity: quantity}
+}
+
+func NewItemOrder(id string, quantity int, options []OptionOrder) *ItemOrder {
 	return &ItemOrder{
 		id:       id,
 		quantity: quantity,
@@ -41,32 +48,166 @@ type OrderInfoFactory struct {
 	foodRepo  item.FoodItemRepository
 	kindRepo  item.ItemKindRepository
 	optionRepo item.OptionItemRepository
+	busRepo       store.BusinessHoursRepository
+	spBusRepo     store.SpecialBusinessHourRepository
+	spHolidayRepo store.SpecialHolidayRepository
+	policy    OrderTimePolicy
+	clock     common.Clock
 }
 
-func NewOrderInfoFactory(stockRepo item.StockItemRepository, foodRepo item.FoodItemRepository, kindRepo item.ItemKindRepository, optionRepo item.OptionItemRepository) *OrderInfoFactory {
+func NewOrderInfoFactory(stockRepo item.StockItemRepository, foodRepo item.FoodItemRepository, kindRepo item.ItemKindRepository, optionRepo item.OptionItemRepository,
+	busRepo store.BusinessHoursRepository, spBusRepo store.SpecialBusinessHourRepository, spHolidayRepo store.SpecialHolidayRepository,
+	policy OrderTimePolicy, clock common.Clock) *OrderInfoFactory {
 	return &OrderInfoFactory{
 		stockRepo: stockRepo,
 		foodRepo:  foodRepo,
 		kindRepo:  kindRepo,
 		optionRepo: optionRepo,
+		busRepo:       busRepo,
+		spBusRepo:     spBusRepo,
+		spHolidayRepo: spHolidayRepo,
+		policy:    policy,
+		clock:     clock,
 	}
 }
 
 func (o *OrderInfoFactory) Create(userId, userName, userEmail, userTelNo, memo, pickupDateTime string, stockOrders, foodOrders []ItemOrder) (*OrderInfo, error) {
-	optionItems, err := o.optionRepo.FindAll()
+	stocks, foods, foodItems, err := o.createOrderItems(stockOrders, foodOrders)
 	if err != nil {
 		return nil, err
 	}
-	stocks, err := o.createOrderStockItems(stockOrders, optionItems)
+	now := o.clock.Now()
+	order, err := NewOrderInfo(userId, userName, userEmail, userTelNo, memo, pickupDateTime, stocks, foods, now)
+	if err != nil {
+		return nil, err
+	}
+	// schedule and lead time of business hour and food items
+	err = o.checkPickupTime(order, foodItems, now, false)
+	if err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// guest checkout is checked as same as user's order
+func (o *OrderInfoFactory) CreateGuest(userName, userEmail, userTelNo, memo, pickupDateTime string, stockOrders, foodOrders []ItemOrder) (*OrderInfo, error) {
+	stocks, foods, foodItems, err := o.createOrderItems(stockOrders, foodOrders)
+	if err != nil {
+		return nil, err
+	}
+	now := o.clock.Now()
+	order, err := NewGuestOrderInfo(userName, userEmail, userTelNo, memo, pickupDateTime, stocks, foods, now)
+	if err != nil {
+		return nil, err
+	}
+	err = o.checkPickupTime(order, foodItems, now, false)
 	if err != nil {
 		return nil, err
 	}
+	return order, nil
+}
+
+// proxy order is entered by admin for customer without account.
+// lead time can be ignored (ex:walk-in customer picks up soon), but pick up time should be in store business and food schedule
+func (o *OrderInfoFactory) CreateProxy(source, userName, userEmail, userTelNo, memo, pickupDateTime string, stockOrders, foodOrders []ItemOrder, ignoreLeadTime bool) (*OrderInfo, error) {
+	stocks, foods, foodItems, err := o.createOrderItems(stockOrders, foodOrders)
+	if err != nil {
+		return nil, err
+	}
+	now := o.clock.Now()
+	order, err := NewProxyOrderInfo(source, userName, userEmail, userTelNo, memo, pickupDateTime, stocks, foods, now)
+	if err != nil {
+		return nil, err
+	}
+	err = o.checkPickupTime(order, foodItems, now, ignoreLeadTime)
+	if err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// amended order is checked as same as new order
+func (o *OrderInfoFactory) Amend(order *OrderInfo, pickupDateTime string, stockOrders, foodOrders []ItemOrder) error {
+	stocks, foods, foodItems, err := o.createOrderItems(stockOrders, foodOrders)
+	if err != nil {
+		return err
+	}
+	now := o.clock.Now()
+	err = order.Amend(pickupDateTime, stocks, foods, now)
+	if err != nil {
+		return err
+	}
+	return o.checkPickupTime(order, foodItems, now, false)
+}
+
+func (o *OrderInfoFactory) createOrderItems(stockOrders, foodOrders []ItemOrder) ([]OrderStockItem, []OrderFoodItem, []item.FoodItem, error) {
+	optionItems, err := o.optionRepo.FindAll()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	stocks, err := o.createOrderStockItems(stockOrders, optionItems)
+	if err != nil {
+		return nil, nil, nil, err
+	}
 
-	foods, err := o.createOrderFoodItems(foodOrders, optionItems)
+	foodItems, err := o.foodRepo.FindAll()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	foods, err := o.createOrderFoodItems(foodOrders, foodItems, optionItems)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return stocks, foods, foodItems, nil
+}
+
+func (o *OrderInfoFactory) checkPickupTime(order *OrderInfo, foodItems []item.FoodItem, now time.Time, ignoreLeadTime bool) error {
+	shift, err := o.findShift(order)
+	if err != nil {
+		return err
+	}
+	err = NewFoodItemScheduleSpecification(foodItems).Check(order.GetFoodItems(), shift)
+	if err != nil {
+		return err
+	}
+	if ignoreLeadTime {
+		return nil
+	}
+
+	leadTimeHours := []uint{}
+	for _, food := range order.GetFoodItems() {
+		for _, item := range foodItems {
+			if item.HasSameId(food.GetItemId()) {
+				leadTimeHours = append(leadTimeHours, item.GetLeadTimeHours())
+				break
+			}
+		}
+	}
+	return o.policy.CheckOrderable(now, shift.Start, o.policy.LeadTime(shift.HourOffset, leadTimeHours...))
+}
+
+// error if pick up time is not in store business
+func (o *OrderInfoFactory) findShift(order *OrderInfo) (*store.ShiftInfo, error) {
+	schedules, err := o.busRepo.Fetch()
+	if err != nil {
+		return nil, err
+	}
+	spSchedules, err := o.spBusRepo.FindAll()
 	if err != nil {
 		return nil, err
 	}
-	return NewOrderInfo(userId, userName, userEmail, userTelNo, memo, pickupDateTime, stocks, foods)
+	spHolidays, err := o.spHolidayRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	shift, err := store.NewHolidaySpecification(*schedules, spSchedules, spHolidays).FindShiftAt(order.GetPickupDateTime())
+	if err != nil {
+		return nil, err
+	}
+	if shift == nil {
+		return nil, common.NewValidationError("PickupDateTime", "pickup time is not in store business")
+	}
+	return shift, nil
 }
 
 func (o *OrderInfoFactory) createOrderStockItems(stockOrders []ItemOrder, optionItems []item.OptionItem) ([]OrderStockItem, error) {
@@ -78,8 +219,8 @@ func (o *OrderInfoFactory) createOrderStockItems(stockOrders []ItemOrder, option
 	for _, stockOrder := range stockOrders {
 		for _, stock := range stocks {
 			if stock.HasSameId(stockOrder.id) {
-				// check option is exists
-				err := o.checkOptionItemExists(stockOrder, stock.GetKindId())
+				// check option is exists and selection of option group
+				options, err := o.checkOptionItemExists(stockOrder, stock.GetKindId())
 				if err != nil {
 					return nil, err
 				}
@@ -90,7 +231,7 @@ func (o *OrderInfoFactory) createOrderStockItems(stockOrders []ItemOrder, option
 					return nil, err
 				}
 
-				opts, err := o.creteOptionItems(stockOrder.GetOptionOderIds(), optionItems)
+				opts, err := o.creteOptionItems(options, optionItems)
 				if err != nil {
 					return nil, err
 				}
@@ -115,17 +256,13 @@ func (o *OrderInfoFactory) createOrderStockItems(stockOrders []ItemOrder, option
 	return stockItems, nil
 }
 
-func (o *OrderInfoFactory) createOrderFoodItems(foodOrders []ItemOrder, optionItems []item.OptionItem) ([]OrderFoodItem, error) {
-	foods, err := o.foodRepo.FindAll()
-	if err != nil {
-		return nil, err
-	}
+func (o *OrderInfoFactory) createOrderFoodItems(foodOrders []ItemOrder, foods []item.FoodItem, optionItems []item.OptionItem) ([]OrderFoodItem, error) {
 	foodItems := []OrderFoodItem{}
 	for _, foodOrder := range foodOrders {
 		for _, food := range foods {
 			if food.HasSameId(foodOrder.id) {
-				// check option is exists
-				err := o.checkOptionItemExists(foodOrder, food.GetKindId())
+				// check option is exists and selection of option group
+				options, err := o.checkOptionItemExists(foodOrder, food.GetKindId())
 				if err != nil {
 					return nil, err
 				}
@@ -136,7 +273,7 @@ func (o *OrderInfoFactory) createOrderFoodItems(foodOrders []ItemOrder, optionIt
 					return nil, err
 				}
 
-				opts, err := o.creteOptionItems(foodOrder.GetOptionOderIds(), optionItems)
+				opts, err := o.creteOptionItems(options, optionItems)
 				if err != nil {
 					return nil, err
 				}
@@ -156,35 +293,59 @@ func (o *OrderInfoFactory) createOrderFoodItems(foodOrders []ItemOrder, optionIt
 	return foodItems, nil
 }
 
-func (o *OrderInfoFactory) checkOptionItemExists(item ItemOrder, kindId string) error {
-	if len(item.options) == 0 {
-		return nil
-	}
+// returns ordered options with default options of group which is not selected
+func (o *OrderInfoFactory) checkOptionItemExists(item ItemOrder, kindId string) ([]OptionOrder, error) {
 	kind, err := o.kindRepo.Find(kindId)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if kind == nil {
+		if len(item.options) == 0 {
+			return item.options, nil
+		}
+		return nil, common.NewValidationError("optionItemId", fmt.Sprintf("Not exists kind:%s ", kindId))
 	}
+	selection := map[string]int{}
 	for _, opt := range item.options {
-		exists := false
-		for _, id := range kind.GetOptionItemIds() {
-			if opt.id == id {
-				exists = true
+		if !kind.HasOption(opt.id) {
+			return nil, common.NewValidationError("optionItemId", fmt.Sprintf("Not exists from kind:%s ", opt.id))
+		}
+		// option without group can not be ordered multiply
+		if kind.FindOptionGroup(opt.id) == nil && opt.quantity != 1 {
+			return nil, common.NewValidationError("options", fmt.Sprintf("quantity of option is over limit(1):%s", opt.id))
+		}
+		selection[opt.id] += opt.quantity
+	}
+
+	options := append([]OptionOrder{}, item.options...)
+	for _, group := range kind.GetOptionGroups() {
+		selected := false
+		for _, id := range group.GetOptionItemIds() {
+			if selection[id] > 0 {
+				selected = true
 				break
 			}
 		}
-		if !exists {
-			return common.NewValidationError("optionItemId", fmt.Sprintf("Not exists from kind:%s ", opt.id))
+		if !selected {
+			for _, id := range group.GetDefaultOptionIds() {
+				options = append(options, *NewOptionOrder(id, 1))
+				selection[id] = 1
+			}
+		}
+		err := group.CheckSelection(selection)
+		if err != nil {
+			return nil, err
 		}
 	}
-	return nil
+	return options, nil
 }
 
-func (o *OrderInfoFactory) creteOptionItems(optionIds []string, options []item.OptionItem) ([]OptionItemInfo, error) {
+func (o *OrderInfoFactory) creteOptionItems(optionOrders []OptionOrder, options []item.OptionItem) ([]OptionItemInfo, error) {
 	results := []OptionItemInfo{}
-	for _, id := range optionIds {
+	for _, order := range optionOrders {
 		for _, opt := range options {
-			if opt.HasSameId(id) {
-				op, err := NewOptionItemInfo(opt.GetId(), opt.GetName(), opt.GetPrice())
+			if opt.HasSameId(order.id) {
+				op, err := NewOptionItemInfo(opt.GetId(), opt.GetName(), opt.GetPrice(), order.quantity)
 				if err != nil {
 					return nil, err
 				}
@@ -193,4 +354,4 @@ func (o *OrderInfoFactory) creteOptionItems(optionIds []string, options []item.O
 		}
 	}
 	return results, nil
-}
\ No newline at end of file
+}
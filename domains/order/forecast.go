@@ -0,0 +1,80 @@
+package order
+
+import (
+	"chico/takeout/common"
+	"sort"
+	"time"
+)
+
+const (
+	DefaultSmoothingFactor = 0.5
+)
+
+// ordered quantities of items in a business hour of a date.
+// hours without order should be included as empty quantities
+type DemandRecord struct {
+	// yyyy/MM/dd
+	Date       string
+	Weekday    time.Weekday
+	HourTypeId string
+	// special business hour is set on the date
+	Special bool
+	// item id to quantity
+	Quantities map[string]int
+}
+
+// estimates demand of items by exponential smoothing of the same weekday and business hour
+type DemandForecaster struct {
+	records []DemandRecord
+	alpha   float64
+}
+
+// alpha is weight of latest record (0 < alpha <= 1). 1 means latest record is used as it is
+func NewDemandForecaster(records []DemandRecord, alpha float64) (*DemandForecaster, error) {
+	if alpha <= 0 || alpha > 1 {
+		return nil, common.NewValidationError("alpha", "should be 0 < alpha <= 1")
+	}
+	sorted := make([]DemandRecord, len(records))
+	copy(sorted, records)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Date < sorted[j].Date })
+	return &DemandForecaster{
+		records: sorted,
+		alpha:   alpha,
+	}, nil
+}
+
+// special hour is estimated by records of special hours at first,
+// and by records of the same weekday if there is no special record
+func (d *DemandForecaster) Estimate(itemId string, weekday time.Weekday, hourTypeId string, special bool) float64 {
+	if special {
+		if estimate, ok := d.smooth(itemId, func(r *DemandRecord) bool {
+			return r.Special && r.HourTypeId == hourTypeId
+		}); ok {
+			return estimate
+		}
+	}
+	estimate, _ := d.smooth(itemId, func(r *DemandRecord) bool {
+		return !r.Special && r.Weekday == weekday && r.HourTypeId == hourTypeId
+	})
+	return estimate
+}
+
+// false if no record matches
+func (d *DemandForecaster) smooth(itemId string, match func(r *DemandRecord) bool) (float64, bool) {
+	found := false
+	value := 0.0
+	for i := range d.records {
+		record := &d.records[i]
+		if !match(record) {
+			continue
+		}
+		quantity := float64(record.Quantities[itemId])
+		if !found {
+			value = quantity
+			found = true
+			continue
+		}
+		value = d.alpha*quantity + (1-d.alpha)*value
+	}
+	return value, found
+}
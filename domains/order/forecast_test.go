@@ -0,0 +1,46 @@
+package order_test
+
+import (
+	"testing"
+	"time"
+
+	"chico/takeout/common"
+	"chico/takeout/domains/order"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDemandForecaster_InvalidAlpha(t *testing.T) {
+	for _, alpha := range []float64{0, -0.1, 1.1} {
+		_, err := order.NewDemandForecaster([]order.DemandRecord{}, alpha)
+		_, ok := err.(*common.ValidationError)
+		assert.True(t, ok, alpha)
+	}
+}
+
+func TestDemandForecaster_Estimate(t *testing.T) {
+	records := []order.DemandRecord{
+		// not sorted
+		{Date: "2050/12/17", Weekday: time.Saturday, HourTypeId: "lunch", Quantities: map[string]int{"food1": 6}},
+		{Date: "2050/12/10", Weekday: time.Saturday, HourTypeId: "lunch", Quantities: map[string]int{"food1": 2, "food2": 1}},
+		{Date: "2050/12/10", Weekday: time.Saturday, HourTypeId: "dinner", Quantities: map[string]int{"food1": 10}},
+		{Date: "2050/12/14", Weekday: time.Wednesday, HourTypeId: "lunch", Quantities: map[string]int{"food1": 20}},
+		{Date: "2050/12/24", Weekday: time.Saturday, HourTypeId: "lunch", Special: true, Quantities: map[string]int{"food1": 30}},
+	}
+	forecaster, err := order.NewDemandForecaster(records, 0.5)
+	assert.Nil(t, err)
+
+	// 2 -> 0.5x6 + 0.5x2
+	assert.Equal(t, 4.0, forecaster.Estimate("food1", time.Saturday, "lunch", false))
+	// no order on 12/17 is treated as 0
+	assert.Equal(t, 0.5, forecaster.Estimate("food2", time.Saturday, "lunch", false))
+	assert.Equal(t, 10.0, forecaster.Estimate("food1", time.Saturday, "dinner", false))
+	assert.Equal(t, 20.0, forecaster.Estimate("food1", time.Wednesday, "lunch", false))
+	// no record
+	assert.Equal(t, 0.0, forecaster.Estimate("food1", time.Sunday, "lunch", false))
+
+	// special hour uses special records
+	assert.Equal(t, 30.0, forecaster.Estimate("food1", time.Sunday, "lunch", true))
+	// special hour falls back to the same weekday
+	assert.Equal(t, 10.0, forecaster.Estimate("food1", time.Saturday, "dinner", true))
+}
@@ -0,0 +1,62 @@
+package order
+
+import (
+	"chico/takeout/common"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// signer of access token of guest order. token is sent by complete mail instead of login.
+// format is "orderId.expiresAt(unix).signature"
+type GuestAccessTokenSigner struct {
+	secret     []byte
+	validHours int
+}
+
+func NewGuestAccessTokenSigner(secret string, validHours int) (*GuestAccessTokenSigner, error) {
+	if strings.TrimSpace(secret) == "" {
+		return nil, common.NewValidationError("secret", "required")
+	}
+	if validHours < 0 {
+		return nil, common.NewValidationError("validHours", fmt.Sprintf("Need to be greater than 0:%d", validHours))
+	}
+	return &GuestAccessTokenSigner{secret: []byte(secret), validHours: validHours}, nil
+}
+
+// token is valid until valid hours after pick up time
+func (g *GuestAccessTokenSigner) Sign(order *OrderInfo) string {
+	expiresAt := order.pickupDateTime.GetDateTime().Add(time.Duration(g.validHours) * time.Hour)
+	payload := fmt.Sprintf("%s.%d", order.GetId(), expiresAt.Unix())
+	return payload + "." + g.signature(payload)
+}
+
+// returns order id of the token
+func (g *GuestAccessTokenSigner) Verify(token string, now time.Time) (string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", common.NewValidationError("token", "invalid token")
+	}
+	payload := parts[0] + "." + parts[1]
+	if !hmac.Equal([]byte(g.signature(payload)), []byte(parts[2])) {
+		return "", common.NewValidationError("token", "invalid token")
+	}
+	expiresAt, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return "", common.NewValidationError("token", "invalid token")
+	}
+	if now.After(time.Unix(expiresAt, 0)) {
+		return "", common.NewValidationError("token", "token is expired")
+	}
+	return parts[0], nil
+}
+
+func (g *GuestAccessTokenSigner) signature(payload string) string {
+	mac := hmac.New(sha256.New, g.secret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
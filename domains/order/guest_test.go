@@ -0,0 +1,45 @@
+package order
+
+import (
+	"chico/takeout/common"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGuestAccessTokenSigner(t *testing.T) {
+	_, err := NewGuestAccessTokenSigner("", 24)
+	assert.Error(t, err)
+	_, err = NewGuestAccessTokenSigner("secret", -1)
+	assert.Error(t, err)
+
+	now := time.Date(2050, 12, 8, 10, 0, 0, 0, common.GetStoreLocation())
+	food1, _ := NewOrderFoodItem("f1", "food1", 100, 1, []OptionItemInfo{})
+	order, _ := NewGuestOrderInfo("ゲスト", "guest@hoge.com", "123456789", "", "2050/12/10 12:00", []OrderStockItem{}, []OrderFoodItem{*food1}, now)
+	signer, err := NewGuestAccessTokenSigner("secret", 24)
+	assert.NoError(t, err)
+	token := signer.Sign(order)
+
+	id, err := signer.Verify(token, now)
+	assert.NoError(t, err)
+	assert.Equal(t, order.GetId(), id)
+
+	inputs := []struct {
+		name  string
+		token string
+		now   time.Time
+	}{
+		{name: "tampered", token: token + "a", now: now},
+		{name: "malformed", token: order.GetId(), now: now},
+		{name: "expired", token: token, now: now.AddDate(0, 0, 4)},
+	}
+	for _, tt := range inputs {
+		_, err := signer.Verify(tt.token, tt.now)
+		assert.IsType(t, common.NewValidationError("", ""), err, tt.name)
+	}
+	// other secret
+	other, _ := NewGuestAccessTokenSigner("other", 24)
+	_, err = other.Verify(token, now)
+	assert.Error(t, err)
+}
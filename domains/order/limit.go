@@ -0,0 +1,71 @@
+package order
+
+const (
+	OrderLimitCountMaxValue = 20
+)
+
+type OrderLimitRepository interface {
+	// nil is returned if not created yet
+	Fetch() (*OrderLimit, error)
+	Update(target *OrderLimit) error
+	Create(target *OrderLimit) error
+}
+
+// limits of active orders, which are applied to non admin user
+type OrderLimit struct {
+	maxActivePerUser OrderLimitCount
+	maxPerPickupDate OrderLimitCount
+	maxPerHourSlot   OrderLimitCount
+}
+
+func NewOrderLimit(maxActivePerUser, maxPerPickupDate, maxPerHourSlot uint) (*OrderLimit, error) {
+	limit := &OrderLimit{}
+	if err := limit.Set(maxActivePerUser, maxPerPickupDate, maxPerHourSlot); err != nil {
+		return nil, err
+	}
+	return limit, nil
+}
+
+// default allows only one active order per user
+func NewDefaultOrderLimit() *OrderLimit {
+	limit, _ := NewOrderLimit(1, 0, 0)
+	return limit
+}
+
+func (o *OrderLimit) Set(maxActivePerUser, maxPerPickupDate, maxPerHourSlot uint) error {
+	active, err := NewOrderLimitCount("MaxActivePerUser", maxActivePerUser)
+	if err != nil {
+		return err
+	}
+	perDate, err := NewOrderLimitCount("MaxPerPickupDate", maxPerPickupDate)
+	if err != nil {
+		return err
+	}
+	perSlot, err := NewOrderLimitCount("MaxPerHourSlot", maxPerHourSlot)
+	if err != nil {
+		return err
+	}
+	o.maxActivePerUser = *active
+	o.maxPerPickupDate = *perDate
+	o.maxPerHourSlot = *perSlot
+	return nil
+}
+
+// max active orders of a user. 0 means no limit
+func (o *OrderLimit) GetMaxActivePerUser() uint {
+	return o.maxActivePerUser.GetValue()
+}
+
+// max active orders of a user on same pickup date. 0 means no limit
+func (o *OrderLimit) GetMaxPerPickupDate() uint {
+	return o.maxPerPickupDate.GetValue()
+}
+
+// max active orders of a user in same business hour shift. 0 means no limit
+func (o *OrderLimit) GetMaxPerHourSlot() uint {
+	return o.maxPerHourSlot.GetValue()
+}
+
+func isOverLimit(limit uint, count int) bool {
+	return limit > 0 && count >= int(limit)
+}
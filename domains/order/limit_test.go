@@ -0,0 +1,28 @@
+package order
+
+import (
+	"testing"
+
+	"chico/takeout/common"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewOrderLimit(t *testing.T) {
+	limit, err := NewOrderLimit(2, 1, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2), limit.GetMaxActivePerUser())
+	assert.Equal(t, uint(1), limit.GetMaxPerPickupDate())
+	assert.Equal(t, uint(0), limit.GetMaxPerHourSlot())
+
+	_, err = NewOrderLimit(OrderLimitCountMaxValue+1, 0, 0)
+	assert.IsType(t, &common.ValidationError{}, err)
+	_, err = NewOrderLimit(0, 0, OrderLimitCountMaxValue+1)
+	assert.IsType(t, &common.ValidationError{}, err)
+
+	// default keeps one active order per user
+	limit = NewDefaultOrderLimit()
+	assert.Equal(t, uint(1), limit.GetMaxActivePerUser())
+	assert.Equal(t, uint(0), limit.GetMaxPerPickupDate())
+	assert.Equal(t, uint(0), limit.GetMaxPerHourSlot())
+}
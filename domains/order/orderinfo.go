@@ -3,6 +3,7 @@ package order
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"chico/takeout/common"
 	"chico/takeout/domains/item"
@@ -13,18 +14,27 @@ import (
 type OrderInfoRepository interface {
 	Find(id string) (*OrderInfo, error)
 	FindByPickupDate(date string) ([]OrderInfo, error)
+	FindByOrderDate(date string) ([]OrderInfo, error)
 	FindByUserId(userId string) ([]OrderInfo, error)
-	FindActiveByUserId(userId string) ([]OrderInfo, error)
+	// orders until 30 minutes after pick up time from now are active
+	FindActiveByUserId(userId string, now time.Time) ([]OrderInfo, error)
 	FindAll() ([]OrderInfo, error)
 	Create(item *OrderInfo) (string, error)
 	UpdateOrderStatus(item *OrderInfo) error
 	UpdateUserInfo(item *OrderInfo) error
+	// pick up time and ordered items are replaced
+	UpdateItems(item *OrderInfo) error
+	// guest order is linked to user account
+	UpdateOwner(item *OrderInfo) error
 	Transact(fc func() error) error
 }
 
 const (
 	OrderInfoMaxMemoLength = 500
 	UserNameMaxLength      = 10
+	CancelReasonMaxLength  = 200
+	// user id of customer without account (guest checkout or order entered by admin)
+	GuestUserId = "guest"
 )
 
 type OrderInfo struct {
@@ -39,37 +49,71 @@ type OrderInfo struct {
 	stockItems     []OrderStockItem
 	foodItems      []OrderFoodItem
 	canceled       bool
+	cancelReason   Memo
+	source         OrderSource
+	// quantity per ingredient id consumed by the order. nil if not recorded (orders before recipe)
+	consumedIngredients map[string]int
 }
 
-func NewOrderInfo(userId, userName, userEmail, userTelNo, memo, pickupDateTime string, stockItems []OrderStockItem, foodItems []OrderFoodItem) (*OrderInfo, error) {
+func NewOrderInfo(userId, userName, userEmail, userTelNo, memo, pickupDateTime string, stockItems []OrderStockItem, foodItems []OrderFoodItem, now time.Time) (*OrderInfo, error) {
 	order := &OrderInfo{id: uuid.NewString(), canceled: false}
 	if err := order.validateUserId(userId); err != nil {
 		return nil, err
 	}
-	memoV, err := NewMemo(memo, OrderInfoMaxMemoLength)
+	userEmailV, err := NewEmail(userEmail)
 	if err != nil {
 		return nil, err
 	}
-	userNameV, err := NewUserName(userName, UserNameMaxLength)
+	sourceV, _ := NewOrderSource(OrderSourceWeb)
+	return order.init(userId, userName, *userEmailV, userTelNo, memo, pickupDateTime, *sourceV, stockItems, foodItems, now)
+}
+
+// order of customer without account is entered by admin (ex:phone, walk-in). email is optional
+func NewProxyOrderInfo(source, userName, userEmail, userTelNo, memo, pickupDateTime string, stockItems []OrderStockItem, foodItems []OrderFoodItem, now time.Time) (*OrderInfo, error) {
+	order := &OrderInfo{id: uuid.NewString(), canceled: false}
+	sourceV, err := NewOrderSource(source)
 	if err != nil {
 		return nil, err
 	}
-	userEmailV, err := NewEmail(userEmail)
+	if sourceV.GetValue() == OrderSourceWeb {
+		return nil, common.NewValidationError("Source", "web is not allowed for proxy order")
+	}
+	userEmailV, err := NewOptionalEmail(userEmail)
 	if err != nil {
 		return nil, err
 	}
-	userTelNoV, err := NewTelNo(userTelNo)
+	return order.init(GuestUserId, userName, *userEmailV, userTelNo, memo, pickupDateTime, *sourceV, stockItems, foodItems, now)
+}
+
+// guest checkout of customer without account. order is keyed by email and tel no
+func NewGuestOrderInfo(userName, userEmail, userTelNo, memo, pickupDateTime string, stockItems []OrderStockItem, foodItems []OrderFoodItem, now time.Time) (*OrderInfo, error) {
+	order := &OrderInfo{id: uuid.NewString(), canceled: false}
+	userEmailV, err := NewEmail(userEmail)
 	if err != nil {
 		return nil, err
 	}
+	sourceV, _ := NewOrderSource(OrderSourceWeb)
+	return order.init(GuestUserId, userName, *userEmailV, userTelNo, memo, pickupDateTime, *sourceV, stockItems, foodItems, now)
+}
 
-	// order date is current time
-	orderDate, err := NewOrderDateTime()
+func (o *OrderInfo) init(userId, userName string, userEmailV Email, userTelNo, memo, pickupDateTime string, sourceV OrderSource, stockItems []OrderStockItem, foodItems []OrderFoodItem, now time.Time) (*OrderInfo, error) {
+	memoV, err := NewMemo(memo, OrderInfoMaxMemoLength)
+	if err != nil {
+		return nil, err
+	}
+	userNameV, err := NewUserName(userName, UserNameMaxLength)
+	if err != nil {
+		return nil, err
+	}
+	userTelNoV, err := NewTelNo(userTelNo)
 	if err != nil {
 		return nil, err
 	}
+
+	// order date is current time
+	orderDate := NewOrderDateTime(now)
 	// pick up time is checked before specific time
-	pickupDate, err := NewPickupDateTime(pickupDateTime)
+	pickupDate, err := NewPickupDateTime(pickupDateTime, now)
 	if err != nil {
 		return nil, err
 	}
@@ -78,23 +122,30 @@ func NewOrderInfo(userId, userName, userEmail, userTelNo, memo, pickupDateTime s
 		return nil, common.NewValidationError("stockItems and foodItems", "both items are empty")
 	}
 
-	order.userId = userId
-	order.userName = *userNameV
-	order.memo = *memoV
-	order.userEmail = *userEmailV
-	order.userTelNo = *userTelNoV
-	order.orderDateTime = *orderDate
-	order.pickupDateTime = *pickupDate
-	order.stockItems = stockItems
-	order.foodItems = foodItems
-	return order, nil
+	o.userId = userId
+	o.userName = *userNameV
+	o.memo = *memoV
+	o.userEmail = userEmailV
+	o.userTelNo = *userTelNoV
+	o.orderDateTime = *orderDate
+	o.pickupDateTime = *pickupDate
+	o.stockItems = stockItems
+	o.foodItems = foodItems
+	o.source = sourceV
+	return o, nil
 }
 
-func NewOrderInfoForOrm(id, userId, userName, userEmail, userTelNo, memo, pickupDateTime, orderDateTime string, stockItems []OrderStockItem, foodItems []OrderFoodItem, canceled bool) (*OrderInfo, error) {
+// empty source is treated as web (orders before source is added)
+func NewOrderInfoForOrm(id, userId, userName, userEmail, userTelNo, memo string, pickupDateTime, orderDateTime time.Time, stockItems []OrderStockItem, foodItems []OrderFoodItem, canceled bool, cancelReason, source string) (*OrderInfo, error) {
 	memoVal, _ := NewMemo(memo, OrderInfoMaxMemoLength)
+	cancelReasonV, _ := NewCancelReason(cancelReason)
 	userNameV, _ := NewUserName(userName, UserNameMaxLength)
-	userEmailV, _ := NewEmail(userEmail)
+	userEmailV, _ := NewOptionalEmail(userEmail)
 	userTelNoV, _ := NewTelNo(userTelNo)
+	if source == "" {
+		source = OrderSourceWeb
+	}
+	sourceV, _ := NewOrderSource(source)
 	order := &OrderInfo{
 		id:             id,
 		userId:         userId,
@@ -102,18 +153,14 @@ func NewOrderInfoForOrm(id, userId, userName, userEmail, userTelNo, memo, pickup
 		userEmail:      *userEmailV,
 		userTelNo:      *userTelNoV,
 		memo:           *memoVal,
-		pickupDateTime: PickupDateTime{},
-		orderDateTime:  OrderDateTime{},
+		pickupDateTime: PickupDateTime{DateTime: *NewDateTimeFromTime(pickupDateTime)},
+		orderDateTime:  OrderDateTime{DateTime: *NewDateTimeFromTime(orderDateTime)},
 		stockItems:     stockItems,
 		foodItems:      foodItems,
 		canceled:       canceled,
+		cancelReason:   *cancelReasonV,
+		source:         *sourceV,
 	}
-	pD, _ := NewDateTime(pickupDateTime)
-	order.pickupDateTime.DateTime = *pD
-
-	oD, _ := NewDateTime(orderDateTime)
-	order.orderDateTime.DateTime = *oD
-
 	return order, nil
 }
 
@@ -170,22 +217,88 @@ func (o *OrderInfo) GetCanceled() bool {
 	return o.canceled
 }
 
+func (o *OrderInfo) GetCancelReason() string {
+	return o.cancelReason.GetValue()
+}
+
+func (o *OrderInfo) IsOwnedBy(userId string) bool {
+	return o.userId == userId
+}
+
+func (o *OrderInfo) GetSource() string {
+	return o.source.GetValue()
+}
+
+// customer without account (proxy order)
+func (o *OrderInfo) IsGuest() bool {
+	return o.userId == GuestUserId
+}
+
+// guest order of same email is linked when customer signs up
+func (o *OrderInfo) LinkUser(userId, email string) error {
+	if !o.IsGuest() {
+		return common.NewValidationError("userId", "only guest order can be linked")
+	}
+	if err := o.validateUserId(userId); err != nil {
+		return err
+	}
+	if !o.HasEmail() || !strings.EqualFold(o.GetUserEmail(), email) {
+		return common.NewValidationError("userEmail", "not match order email")
+	}
+	o.userId = userId
+	return nil
+}
+
+// email is absent for some proxy orders
+func (o *OrderInfo) HasEmail() bool {
+	return o.GetUserEmail() != ""
+}
+
 func (o *OrderInfo) GetPickupDateTime() string {
-	return o.pickupDateTime.value
+	return o.pickupDateTime.GetAsDateTime()
 }
 
 func (o *OrderInfo) GetOrderDateTime() string {
-	return o.orderDateTime.value
+	return o.orderDateTime.GetAsDateTime()
+}
+
+func (o *OrderInfo) GetPickupAt() time.Time {
+	return o.pickupDateTime.GetDateTime()
+}
+
+func (o *OrderInfo) GetOrderedAt() time.Time {
+	return o.orderDateTime.GetDateTime()
 }
 
 func (o *OrderInfo) GetPickupDate() string {
 	return o.pickupDateTime.GetAsDate()
 }
 
+func (o *OrderInfo) GetOrderDate() string {
+	return o.orderDateTime.GetAsDate()
+}
+
+// same email or tel no is treated as same contact
+func (o *OrderInfo) HasSameContact(other *OrderInfo) bool {
+	if strings.EqualFold(o.GetUserEmail(), other.GetUserEmail()) {
+		return true
+	}
+	return o.GetUserTelNo() == other.GetUserTelNo()
+}
+
 func (o *OrderInfo) GetFoodItems() []OrderFoodItem {
 	return o.foodItems
 }
 
+func (o *OrderInfo) GetConsumedIngredients() map[string]int {
+	return o.consumedIngredients
+}
+
+// set when ingredients are consumed, and by orm
+func (o *OrderInfo) SetConsumedIngredients(consumed map[string]int) {
+	o.consumedIngredients = consumed
+}
+
 func (o *OrderInfo) GetStockItems() []OrderStockItem {
 	return o.stockItems
 }
@@ -212,14 +325,49 @@ func (o *OrderInfo) GetTotalCost() int {
 	return total
 }
 
-func (o *OrderInfo) SetCancel() {
+// reason is optional
+func (o *OrderInfo) SetCancel(reason string) error {
+	if o.canceled {
+		return common.NewValidationError("canceled", "already canceled")
+	}
+	reasonV, err := NewCancelReason(reason)
+	if err != nil {
+		return err
+	}
 	o.canceled = true
+	o.cancelReason = *reasonV
+	return nil
+}
+
+// items and pick up time are replaced. user info and order date time are kept
+func (o *OrderInfo) Amend(pickupDateTime string, stockItems []OrderStockItem, foodItems []OrderFoodItem, now time.Time) error {
+	if o.canceled {
+		return common.NewValidationError("canceled", "canceled order can not be amended")
+	}
+	pickupDate, err := NewPickupDateTime(pickupDateTime, now)
+	if err != nil {
+		return err
+	}
+	// if both items are empty, it is error
+	if len(stockItems) == 0 && len(foodItems) == 0 {
+		return common.NewValidationError("stockItems and foodItems", "both items are empty")
+	}
+	if o.GetPickupAt().Equal(pickupDate.GetDateTime()) && sameStockItems(o.stockItems, stockItems) && sameFoodItems(o.foodItems, foodItems) {
+		return common.NewValidationError("stockItems and foodItems", "nothing is changed")
+	}
+	o.pickupDateTime = *pickupDate
+	o.stockItems = stockItems
+	o.foodItems = foodItems
+	return nil
 }
 
 func (o *OrderInfo) validateUserId(userId string) error {
 	if strings.TrimSpace(userId) == "" {
 		return common.NewValidationError("userId", "required")
 	}
+	if userId == GuestUserId {
+		return common.NewValidationError("userId", "reserved for guest order")
+	}
 	return nil
 }
 
@@ -260,9 +408,10 @@ type commonItemInfo struct {
 }
 
 type OptionItemInfo struct {
-	itemId string
-	name   item.Name
-	price  Price
+	itemId   string
+	name     item.Name
+	price    Price
+	quantity Quantity
 }
 
 func (o *OptionItemInfo) GetId() string {
@@ -277,7 +426,12 @@ func (o *OptionItemInfo) GetPrice() int {
 	return o.price.value
 }
 
-func NewOptionItemInfo(itemId, name string, price int) (*OptionItemInfo, error) {
+func (o *OptionItemInfo) GetQuantity() int {
+	return o.quantity.value
+}
+
+// quantity is per one ordered item
+func NewOptionItemInfo(itemId, name string, price, quantity int) (*OptionItemInfo, error) {
 	if strings.TrimSpace(itemId) == "" {
 		return nil, common.NewValidationError("itemId", "required")
 	}
@@ -290,13 +444,54 @@ func NewOptionItemInfo(itemId, name string, price int) (*OptionItemInfo, error)
 	if err != nil {
 		return nil, err
 	}
+	quantityV, err := NewQuantity(quantity)
+	if err != nil {
+		return nil, err
+	}
 	return &OptionItemInfo{
-		itemId: itemId,
-		name:   *nameV,
-		price:  *priceV,
+		itemId:   itemId,
+		name:     *nameV,
+		price:    *priceV,
+		quantity: *quantityV,
 	}, nil
 }
 
+func sameStockItems(items, others []OrderStockItem) bool {
+	if len(items) != len(others) {
+		return false
+	}
+	for i := range items {
+		if !items[i].equals(&others[i].commonItemInfo) {
+			return false
+		}
+	}
+	return true
+}
+
+func sameFoodItems(items, others []OrderFoodItem) bool {
+	if len(items) != len(others) {
+		return false
+	}
+	for i := range items {
+		if !items[i].equals(&others[i].commonItemInfo) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *commonItemInfo) equals(other *commonItemInfo) bool {
+	if c.itemId != other.itemId || c.quantity.value != other.quantity.value || len(c.options) != len(other.options) {
+		return false
+	}
+	for i := range c.options {
+		if c.options[i].itemId != other.options[i].itemId || c.options[i].quantity.value != other.options[i].quantity.value {
+			return false
+		}
+	}
+	return true
+}
+
 func (c *commonItemInfo) HasSameId(id string) bool {
 	return c.itemId == id
 }
@@ -324,7 +519,7 @@ func (c *commonItemInfo) GetPrice() int {
 func (c *commonItemInfo) GetTotalCost() int {
 	unitPrice := c.price.value
 	for _, opt := range c.GetOptionItems() {
-		unitPrice += opt.GetPrice()
+		unitPrice += opt.GetPrice() * opt.GetQuantity()
 	}
 	return unitPrice * c.quantity.value
 }
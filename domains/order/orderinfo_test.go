@@ -2,7 +2,9 @@ package order
 
 import (
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"chico/takeout/common"
 	"chico/takeout/tests"
@@ -10,6 +12,9 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
+// pick up time in tests is checked against this time
+var orderedAt = time.Date(2023, 10, 5, 10, 0, 0, 0, common.GetStoreLocation())
+
 type commonItemInfoArgs struct {
 	itemId   string
 	name     string
@@ -44,7 +49,7 @@ func (o *commonItemInfoArgs) toOptions() ([]OptionItemInfo, error) {
 }
 
 func (o *optionItemInfo) toOption() (*OptionItemInfo, error) {
-	return NewOptionItemInfo(o.itemId, o.name, o.price)
+	return NewOptionItemInfo(o.itemId, o.name, o.price, 1)
 }
 
 type commonItemInfoInput struct {
@@ -689,7 +694,7 @@ func TestNewOrderInfo(t *testing.T) {
 			}
 			sOrders = append(sOrders, *order)
 		}
-		got, err := NewOrderInfo(tt.args.userId, tt.args.userName, tt.args.userEmail, tt.args.userTelNo, tt.args.memo, tt.args.pickupDateTime, sOrders, fOrders)
+		got, err := NewOrderInfo(tt.args.userId, tt.args.userName, tt.args.userEmail, tt.args.userTelNo, tt.args.memo, tt.args.pickupDateTime, sOrders, fOrders, orderedAt)
 		assertOderInfoRoot(t, tt, got, err)
 	}
 }
@@ -753,10 +758,193 @@ func TestOrderInfoSetCancel(t *testing.T) {
 			}
 			sOrders = append(sOrders, *order)
 		}
-		got, err := NewOrderInfo(tt.args.userId, tt.args.userName, tt.args.userEmail, tt.args.userTelNo, tt.args.memo, tt.args.pickupDateTime, sOrders, foodOrders)
+		got, err := NewOrderInfo(tt.args.userId, tt.args.userName, tt.args.userEmail, tt.args.userTelNo, tt.args.memo, tt.args.pickupDateTime, sOrders, foodOrders, orderedAt)
 		if err == nil {
-			got.SetCancel()
+			err = got.SetCancel("")
 		}
 		assertOderInfoRoot(t, tt, got, err)
 	}
 }
+
+func TestOrderInfoSetCancel_Reason(t *testing.T) {
+	inputs := []struct {
+		name     string
+		canceled bool
+		reason   string
+		hasErr   bool
+	}{
+		{name: "empty reason", canceled: false, reason: "", hasErr: false},
+		{name: "with reason", canceled: false, reason: "材料が入荷できなかったため", hasErr: false},
+		{name: "max length reason", canceled: false, reason: strings.Repeat("a", CancelReasonMaxLength), hasErr: false},
+		{name: "too long reason", canceled: false, reason: strings.Repeat("a", CancelReasonMaxLength+1), hasErr: true},
+		{name: "already canceled", canceled: true, reason: "reason", hasErr: true},
+	}
+	for _, tt := range inputs {
+		fmt.Println("name:", tt.name)
+		order, err := NewOrderInfoForOrm("o1", "user1", "ユーザー1", "user1@hoge.com", "123456789", "", parseDateTime("2050/12/10 12:00"), parseDateTime("2050/12/08 12:00"), []OrderStockItem{}, []OrderFoodItem{}, tt.canceled, "", "")
+		assert.NoError(t, err)
+		err = order.SetCancel(tt.reason)
+		if tt.hasErr {
+			assert.Error(t, err)
+			assert.IsType(t, common.NewValidationError("", ""), err)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.True(t, order.GetCanceled())
+		assert.Equal(t, tt.reason, order.GetCancelReason())
+	}
+}
+
+func TestOrderInfoIsOwnedBy(t *testing.T) {
+	order, err := NewOrderInfoForOrm("o1", "user1", "ユーザー1", "user1@hoge.com", "123456789", "", parseDateTime("2050/12/10 12:00"), parseDateTime("2050/12/08 12:00"), []OrderStockItem{}, []OrderFoodItem{}, false, "", "")
+	assert.NoError(t, err)
+	assert.True(t, order.IsOwnedBy("user1"))
+	assert.False(t, order.IsOwnedBy("user2"))
+	assert.False(t, order.IsOwnedBy(""))
+}
+
+func TestOrderInfoAmend(t *testing.T) {
+	now := time.Date(2050, 12, 8, 10, 0, 0, 0, common.GetStoreLocation())
+	food1, _ := NewOrderFoodItem("f1", "food1", 100, 1, []OptionItemInfo{})
+	food2, _ := NewOrderFoodItem("f1", "food1", 100, 2, []OptionItemInfo{})
+	stock1, _ := NewOrderStockItem("s1", "stock1", 200, 1, []OptionItemInfo{})
+	inputs := []struct {
+		name           string
+		canceled       bool
+		pickupDateTime string
+		stockItems     []OrderStockItem
+		foodItems      []OrderFoodItem
+		hasErr         bool
+	}{
+		{name: "change quantity", pickupDateTime: "2050/12/10 12:00", stockItems: []OrderStockItem{}, foodItems: []OrderFoodItem{*food2}, hasErr: false},
+		{name: "change pickup time", pickupDateTime: "2050/12/11 12:00", stockItems: []OrderStockItem{}, foodItems: []OrderFoodItem{*food1}, hasErr: false},
+		{name: "add item", pickupDateTime: "2050/12/10 12:00", stockItems: []OrderStockItem{*stock1}, foodItems: []OrderFoodItem{*food1}, hasErr: false},
+		{name: "nothing is changed", pickupDateTime: "2050/12/10 12:00", stockItems: []OrderStockItem{}, foodItems: []OrderFoodItem{*food1}, hasErr: true},
+		{name: "past pickup time", pickupDateTime: "2050/12/08 09:00", stockItems: []OrderStockItem{}, foodItems: []OrderFoodItem{*food2}, hasErr: true},
+		{name: "empty items", pickupDateTime: "2050/12/10 12:00", stockItems: []OrderStockItem{}, foodItems: []OrderFoodItem{}, hasErr: true},
+		{name: "canceled", canceled: true, pickupDateTime: "2050/12/10 12:00", stockItems: []OrderStockItem{}, foodItems: []OrderFoodItem{*food2}, hasErr: true},
+	}
+	for _, tt := range inputs {
+		fmt.Println("name:", tt.name)
+		order, err := NewOrderInfoForOrm("o1", "user1", "ユーザー1", "user1@hoge.com", "123456789", "", parseDateTime("2050/12/10 12:00"), parseDateTime("2050/12/08 09:00"), []OrderStockItem{}, []OrderFoodItem{*food1}, tt.canceled, "", "")
+		assert.NoError(t, err)
+		err = order.Amend(tt.pickupDateTime, tt.stockItems, tt.foodItems, now)
+		if tt.hasErr {
+			assert.Error(t, err)
+			assert.IsType(t, common.NewValidationError("", ""), err)
+			// not changed
+			assert.Equal(t, "2050/12/10 12:00", order.GetPickupDateTime())
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, tt.pickupDateTime, order.GetPickupDateTime())
+		assert.Equal(t, len(tt.stockItems), len(order.GetStockItems()))
+		assert.Equal(t, len(tt.foodItems), len(order.GetFoodItems()))
+		// order date is kept
+		assert.Equal(t, "2050/12/08 09:00", order.GetOrderDateTime())
+	}
+}
+
+func TestNewPickupDateTime_Past(t *testing.T) {
+	now := time.Date(2050, 12, 10, 9, 0, 0, 0, common.GetStoreLocation())
+	inputs := []struct {
+		name           string
+		pickupDateTime string
+		hasErr         bool
+	}{
+		{name: "future", pickupDateTime: "2050/12/10 09:01", hasErr: false},
+		{name: "now", pickupDateTime: "2050/12/10 09:00", hasErr: true},
+		{name: "past", pickupDateTime: "2050/12/10 08:00", hasErr: true},
+	}
+	for _, tt := range inputs {
+		fmt.Println("name:", tt.name)
+		got, err := NewPickupDateTime(tt.pickupDateTime, now)
+		if tt.hasErr {
+			assert.IsType(t, &common.ValidationError{}, err)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, tt.pickupDateTime, got.GetAsDateTime())
+	}
+}
+
+func TestNewProxyOrderInfo(t *testing.T) {
+	now := time.Date(2050, 12, 8, 10, 0, 0, 0, common.GetStoreLocation())
+	food1, _ := NewOrderFoodItem("f1", "food1", 100, 1, []OptionItemInfo{})
+	inputs := []struct {
+		name   string
+		source string
+		email  string
+		hasErr bool
+	}{
+		{name: "phone with email", source: OrderSourcePhone, email: "guest@hoge.com", hasErr: false},
+		{name: "walk-in without email", source: OrderSourceWalkIn, email: "", hasErr: false},
+		{name: "web is not proxy", source: OrderSourceWeb, email: "", hasErr: true},
+		{name: "unknown source", source: "fax", email: "", hasErr: true},
+		{name: "invalid email", source: OrderSourcePhone, email: "guest", hasErr: true},
+	}
+	for _, tt := range inputs {
+		fmt.Println("name:", tt.name)
+		order, err := NewProxyOrderInfo(tt.source, "ゲスト", tt.email, "123456789", "", "2050/12/10 12:00", []OrderStockItem{}, []OrderFoodItem{*food1}, now)
+		if tt.hasErr {
+			assert.IsType(t, common.NewValidationError("", ""), err)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, GuestUserId, order.GetUserId())
+		assert.True(t, order.IsGuest())
+		assert.Equal(t, tt.source, order.GetSource())
+		assert.Equal(t, tt.email != "", order.HasEmail())
+	}
+
+	// normal order is from web
+	order, err := NewOrderInfo("user1", "ユーザー1", "user1@hoge.com", "123456789", "", "2050/12/10 12:00", []OrderStockItem{}, []OrderFoodItem{*food1}, now)
+	assert.NoError(t, err)
+	assert.Equal(t, OrderSourceWeb, order.GetSource())
+	assert.False(t, order.IsGuest())
+}
+
+func TestNewGuestOrderInfo(t *testing.T) {
+	now := time.Date(2050, 12, 8, 10, 0, 0, 0, common.GetStoreLocation())
+	food1, _ := NewOrderFoodItem("f1", "food1", 100, 1, []OptionItemInfo{})
+	// email is required to send access token
+	_, err := NewGuestOrderInfo("ゲスト", "", "123456789", "", "2050/12/10 12:00", []OrderStockItem{}, []OrderFoodItem{*food1}, now)
+	assert.IsType(t, common.NewValidationError("", ""), err)
+
+	order, err := NewGuestOrderInfo("ゲスト", "Guest@hoge.com", "123456789", "", "2050/12/10 12:00", []OrderStockItem{}, []OrderFoodItem{*food1}, now)
+	assert.NoError(t, err)
+	assert.True(t, order.IsGuest())
+	assert.Equal(t, OrderSourceWeb, order.GetSource())
+
+	// guest user id is reserved
+	_, err = NewOrderInfo(GuestUserId, "ゲスト", "guest@hoge.com", "123456789", "", "2050/12/10 12:00", []OrderStockItem{}, []OrderFoodItem{*food1}, now)
+	assert.IsType(t, common.NewValidationError("", ""), err)
+
+	// only same email can be linked
+	assert.Error(t, order.LinkUser("user1", "other@hoge.com"))
+	assert.True(t, order.IsGuest())
+	assert.NoError(t, order.LinkUser("user1", "guest@hoge.com"))
+	assert.Equal(t, "user1", order.GetUserId())
+	// already linked
+	assert.Error(t, order.LinkUser("user2", "guest@hoge.com"))
+}
+
+func TestOrderInfoGetTotalCost_OptionQuantity(t *testing.T) {
+	now := time.Date(2050, 12, 8, 10, 0, 0, 0, common.GetStoreLocation())
+	opt1, _ := NewOptionItemInfo("o1", "topping", 50, 3)
+	opt2, _ := NewOptionItemInfo("o2", "spice", 10, 1)
+	food1, _ := NewOrderFoodItem("f1", "food1", 100, 2, []OptionItemInfo{*opt1, *opt2})
+	order, err := NewOrderInfo("user1", "ユーザー1", "user1@hoge.com", "123456789", "", "2050/12/10 12:00", []OrderStockItem{}, []OrderFoodItem{*food1}, now)
+	assert.NoError(t, err)
+	// (100 + 50*3 + 10) * 2
+	assert.Equal(t, 520, order.GetTotalCost())
+
+	_, err = NewOptionItemInfo("o1", "topping", 50, 0)
+	assert.IsType(t, common.NewValidationError("", ""), err)
+}
+
+// fixture date time in store location
+func parseDateTime(value string) time.Time {
+	t, _ := common.ConvertStrToDateTime(value)
+	return *t
+}
@@ -0,0 +1,62 @@
+package order
+
+import (
+	"fmt"
+	"time"
+
+	"chico/takeout/common"
+)
+
+// earliest pick up time is rounded up by this minutes (ex:12:10 => 12:30)
+const pickupRoundMinutes = 30
+
+// rules of when order can be created and canceled.
+// same policy is used for listing orderable hours, creating and canceling orders.
+type OrderTimePolicy struct {
+	// 0 means order can be canceled until pick up time
+	cancelCutoffMinutes int
+}
+
+func NewOrderTimePolicy(cancelCutoffMinutes int) (*OrderTimePolicy, error) {
+	if cancelCutoffMinutes < 0 {
+		return nil, common.NewValidationError("cancelCutoffMinutes", fmt.Sprintf("Need to be greater than 0:%d", cancelCutoffMinutes))
+	}
+	return &OrderTimePolicy{cancelCutoffMinutes: cancelCutoffMinutes}, nil
+}
+
+// longest lead time of business hour and food items is applied
+func (p *OrderTimePolicy) LeadTime(hourOffset uint, foodLeadTimeHours ...uint) time.Duration {
+	hours := hourOffset
+	for _, foodHours := range foodLeadTimeHours {
+		if foodHours > hours {
+			hours = foodHours
+		}
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+// shifts started at this time or later can be ordered now
+func (p *OrderTimePolicy) EarliestShiftStart(now time.Time, leadTime time.Duration) time.Time {
+	return common.GetRound(*common.GetDateWithOffset(now, int(leadTime.Minutes())), pickupRoundMinutes)
+}
+
+func (p *OrderTimePolicy) IsOrderable(now, shiftStart time.Time, leadTime time.Duration) bool {
+	return !shiftStart.Before(p.EarliestShiftStart(now, leadTime))
+}
+
+// pick up time in the shift can be ordered until lead time before the shift starts
+func (p *OrderTimePolicy) CheckOrderable(now, shiftStart time.Time, leadTime time.Duration) error {
+	if !p.IsOrderable(now, shiftStart, leadTime) {
+		return common.NewValidationError("PickupDateTime", fmt.Sprintf("order is closed. shift(%s) needs %v hours before", common.ConvertTimeToDateTimeStr(shiftStart), leadTime.Hours()))
+	}
+	return nil
+}
+
+// order can be canceled until cutoff minutes before pick up time
+func (p *OrderTimePolicy) CheckCancelable(order *OrderInfo, now time.Time) error {
+	limit := order.pickupDateTime.GetDateTime().Add(-time.Duration(p.cancelCutoffMinutes) * time.Minute)
+	if now.After(limit) {
+		return common.NewValidationError("PickupDateTime", fmt.Sprintf("cancel is closed. limit:%s", common.ConvertTimeToDateTimeStr(limit)))
+	}
+	return nil
+}
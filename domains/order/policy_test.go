@@ -0,0 +1,91 @@
+package order
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"chico/takeout/common"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewOrderTimePolicy(t *testing.T) {
+	_, err := NewOrderTimePolicy(-1)
+	assert.IsType(t, &common.ValidationError{}, err)
+
+	policy, err := NewOrderTimePolicy(0)
+	assert.NoError(t, err)
+	assert.NotNil(t, policy)
+}
+
+func TestOrderTimePolicy_LeadTime(t *testing.T) {
+	policy, _ := NewOrderTimePolicy(60)
+	// business hour offset only
+	assert.Equal(t, 3*time.Hour, policy.LeadTime(3))
+	// food item which needs shorter time is ignored
+	assert.Equal(t, 3*time.Hour, policy.LeadTime(3, 0, 2))
+	// longest one is used
+	assert.Equal(t, 24*time.Hour, policy.LeadTime(3, 24, 5))
+}
+
+func TestOrderTimePolicy_IsOrderable(t *testing.T) {
+	loc := common.GetStoreLocation()
+	policy, _ := NewOrderTimePolicy(60)
+	now := time.Date(2050, 12, 10, 8, 10, 0, 0, loc)
+	inputs := []struct {
+		name       string
+		shiftStart time.Time
+		leadTime   time.Duration
+		want       bool
+	}{
+		// 8:10 + 3h = 11:10 => rounded 11:30
+		{name: "start is rounded limit", shiftStart: time.Date(2050, 12, 10, 11, 30, 0, 0, loc), leadTime: 3 * time.Hour, want: true},
+		{name: "start is before rounded limit", shiftStart: time.Date(2050, 12, 10, 11, 29, 0, 0, loc), leadTime: 3 * time.Hour, want: false},
+		{name: "next day", shiftStart: time.Date(2050, 12, 11, 7, 0, 0, 0, loc), leadTime: 3 * time.Hour, want: true},
+		// 8:10 + 24h = next day 8:10 => rounded 8:30
+		{name: "next day with 24 hours", shiftStart: time.Date(2050, 12, 11, 7, 0, 0, 0, loc), leadTime: 24 * time.Hour, want: false},
+		{name: "next day with 24 hours after limit", shiftStart: time.Date(2050, 12, 11, 11, 30, 0, 0, loc), leadTime: 24 * time.Hour, want: true},
+	}
+	for _, tt := range inputs {
+		fmt.Println("name:", tt.name)
+		assert.Equal(t, tt.want, policy.IsOrderable(now, tt.shiftStart, tt.leadTime))
+		err := policy.CheckOrderable(now, tt.shiftStart, tt.leadTime)
+		if tt.want {
+			assert.NoError(t, err)
+		} else {
+			assert.IsType(t, &common.ValidationError{}, err)
+		}
+	}
+}
+
+func TestOrderTimePolicy_CheckCancelable(t *testing.T) {
+	loc := common.GetStoreLocation()
+	orderedAt := time.Date(2050, 12, 1, 10, 0, 0, 0, loc)
+	food, _ := NewOrderFoodItem("1", "food1", 100, 1, []OptionItemInfo{})
+	order, err := NewOrderInfo("user1", "name", "user1@hoge.com", "123456789", "", "2050/12/10 12:00", []OrderStockItem{}, []OrderFoodItem{*food}, orderedAt)
+	assert.NoError(t, err)
+
+	inputs := []struct {
+		name          string
+		cutoffMinutes int
+		now           time.Time
+		hasErr        bool
+	}{
+		{name: "before cutoff", cutoffMinutes: 60, now: time.Date(2050, 12, 10, 10, 59, 0, 0, loc), hasErr: false},
+		{name: "just cutoff", cutoffMinutes: 60, now: time.Date(2050, 12, 10, 11, 0, 0, 0, loc), hasErr: false},
+		{name: "after cutoff", cutoffMinutes: 60, now: time.Date(2050, 12, 10, 11, 1, 0, 0, loc), hasErr: true},
+		{name: "no cutoff", cutoffMinutes: 0, now: time.Date(2050, 12, 10, 11, 59, 0, 0, loc), hasErr: false},
+		{name: "no cutoff after pickup", cutoffMinutes: 0, now: time.Date(2050, 12, 10, 12, 1, 0, 0, loc), hasErr: true},
+	}
+	for _, tt := range inputs {
+		fmt.Println("name:", tt.name)
+		policy, _ := NewOrderTimePolicy(tt.cutoffMinutes)
+		err := policy.CheckCancelable(order, tt.now)
+		if tt.hasErr {
+			assert.IsType(t, &common.ValidationError{}, err)
+		} else {
+			assert.NoError(t, err)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package order
+
+import (
+	"sort"
+	"strings"
+)
+
+// totals of option selected with item. quantity is per option unit of all ordered items
+type PrepOption struct {
+	optionId string
+	name     string
+	quantity int
+}
+
+func (p *PrepOption) GetOptionId() string {
+	return p.optionId
+}
+
+func (p *PrepOption) GetName() string {
+	return p.name
+}
+
+func (p *PrepOption) GetQuantity() int {
+	return p.quantity
+}
+
+type PrepItem struct {
+	itemId   string
+	itemType string
+	name     string
+	quantity int
+	options  []PrepOption
+}
+
+func (p *PrepItem) GetItemId() string {
+	return p.itemId
+}
+
+// stock or food
+func (p *PrepItem) GetItemType() string {
+	return p.itemType
+}
+
+func (p *PrepItem) GetName() string {
+	return p.name
+}
+
+func (p *PrepItem) GetQuantity() int {
+	return p.quantity
+}
+
+func (p *PrepItem) GetOptions() []PrepOption {
+	return p.options
+}
+
+func (p *PrepItem) add(quantity int, options []OptionItemInfo) {
+	p.quantity += quantity
+	for _, opt := range options {
+		found := false
+		for i := range p.options {
+			if p.options[i].optionId == opt.GetId() {
+				p.options[i].quantity += opt.GetQuantity() * quantity
+				found = true
+				break
+			}
+		}
+		if !found {
+			p.options = append(p.options, PrepOption{optionId: opt.GetId(), name: opt.GetName(), quantity: opt.GetQuantity() * quantity})
+		}
+	}
+}
+
+type PrepMemo struct {
+	pickupDateTime string
+	userName       string
+	memo           string
+}
+
+func (p *PrepMemo) GetPickupDateTime() string {
+	return p.pickupDateTime
+}
+
+func (p *PrepMemo) GetUserName() string {
+	return p.userName
+}
+
+func (p *PrepMemo) GetMemo() string {
+	return p.memo
+}
+
+// production plan of business hour. canceled orders should be excluded by caller
+type PrepList struct {
+	orderCount int
+	items      []PrepItem
+	memos      []PrepMemo
+}
+
+func NewPrepList(orders []OrderInfo) *PrepList {
+	prep := &PrepList{
+		orderCount: len(orders),
+		items:      []PrepItem{},
+		memos:      []PrepMemo{},
+	}
+	sorted := make([]OrderInfo, len(orders))
+	copy(sorted, orders)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].GetPickupDateTime() < sorted[j].GetPickupDateTime() })
+	for i := range sorted {
+		order := &sorted[i]
+		for _, food := range order.GetFoodItems() {
+			prep.findOrAdd(food.GetItemId(), "food", food.GetName()).add(food.GetQuantity(), food.GetOptionItems())
+		}
+		for _, stock := range order.GetStockItems() {
+			prep.findOrAdd(stock.GetItemId(), "stock", stock.GetName()).add(stock.GetQuantity(), stock.GetOptionItems())
+		}
+		if strings.TrimSpace(order.GetMemo()) != "" {
+			prep.memos = append(prep.memos, PrepMemo{pickupDateTime: order.GetPickupDateTime(), userName: order.GetUserName(), memo: order.GetMemo()})
+		}
+	}
+	return prep
+}
+
+func (p *PrepList) findOrAdd(itemId, itemType, name string) *PrepItem {
+	for i := range p.items {
+		if p.items[i].itemId == itemId {
+			return &p.items[i]
+		}
+	}
+	p.items = append(p.items, PrepItem{itemId: itemId, itemType: itemType, name: name, options: []PrepOption{}})
+	return &p.items[len(p.items)-1]
+}
+
+func (p *PrepList) GetOrderCount() int {
+	return p.orderCount
+}
+
+// food items first, then stock items in order of first appearance
+func (p *PrepList) GetItems() []PrepItem {
+	items := make([]PrepItem, len(p.items))
+	copy(items, p.items)
+	sort.SliceStable(items, func(i, j int) bool { return items[i].itemType == "food" && items[j].itemType != "food" })
+	return items
+}
+
+func (p *PrepList) GetMemos() []PrepMemo {
+	return p.memos
+}
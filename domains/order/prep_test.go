@@ -0,0 +1,46 @@
+package order_test
+
+import (
+	"testing"
+	"time"
+
+	"chico/takeout/domains/order"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPrepList(t *testing.T) {
+	orderedAt := time.Date(2050, 12, 1, 10, 0, 0, 0, time.FixedZone("Asia/Tokyo", 9*60*60))
+	spicy, _ := order.NewOptionItemInfo("opt1", "spicy", 0, 1)
+	cheese, _ := order.NewOptionItemInfo("opt2", "cheese", 100, 2)
+
+	curry1, _ := order.NewOrderFoodItem("food1", "curry", 800, 2, []order.OptionItemInfo{*spicy})
+	drink, _ := order.NewOrderStockItem("stock1", "lassi", 300, 1, []order.OptionItemInfo{})
+	order1, _ := order.NewOrderInfo("user1", "user1", "user1@hoge.com", "123456789", "no onion", "2050/12/10 9:10", []order.OrderStockItem{*drink}, []order.OrderFoodItem{*curry1}, orderedAt)
+
+	curry2, _ := order.NewOrderFoodItem("food1", "curry", 800, 3, []order.OptionItemInfo{*spicy, *cheese})
+	order2, _ := order.NewOrderInfo("user2", "user2", "user2@hoge.com", "123456789", "", "2050/12/10 8:00", []order.OrderStockItem{}, []order.OrderFoodItem{*curry2}, orderedAt)
+
+	prep := order.NewPrepList([]order.OrderInfo{*order1, *order2})
+	assert.Equal(t, 2, prep.GetOrderCount())
+	items := prep.GetItems()
+	assert.Equal(t, 2, len(items))
+	// food first
+	assert.Equal(t, "curry", items[0].GetName())
+	assert.Equal(t, 5, items[0].GetQuantity())
+	options := items[0].GetOptions()
+	assert.Equal(t, 2, len(options))
+	assert.Equal(t, "spicy", options[0].GetName())
+	assert.Equal(t, 5, options[0].GetQuantity())
+	// option quantity is per item unit
+	assert.Equal(t, "cheese", options[1].GetName())
+	assert.Equal(t, 6, options[1].GetQuantity())
+	assert.Equal(t, "stock", items[1].GetItemType())
+	assert.Equal(t, 1, items[1].GetQuantity())
+
+	// only non empty memo
+	memos := prep.GetMemos()
+	assert.Equal(t, 1, len(memos))
+	assert.Equal(t, "no onion", memos[0].GetMemo())
+	assert.Equal(t, "user1", memos[0].GetUserName())
+}
@@ -0,0 +1,97 @@
+package order
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OrderRevisionRepository interface {
+	// ordered by revision
+	FindByOrderId(orderId string) ([]OrderRevision, error)
+	Create(item *OrderRevision) error
+}
+
+// snapshot of order contents before amended
+type OrderRevision struct {
+	id             string
+	orderId        string
+	revision       int
+	pickupDateTime DateTime
+	stockItems     []OrderStockItem
+	foodItems      []OrderFoodItem
+	amendedAt      DateTime
+}
+
+// revision starts from 1
+func NewOrderRevision(order *OrderInfo, revision int, now time.Time) (*OrderRevision, error) {
+	amendedAt := NewOrderDateTime(now)
+	return &OrderRevision{
+		id:             uuid.NewString(),
+		orderId:        order.GetId(),
+		revision:       revision,
+		pickupDateTime: order.pickupDateTime.DateTime,
+		stockItems:     order.GetStockItems(),
+		foodItems:      order.GetFoodItems(),
+		amendedAt:      amendedAt.DateTime,
+	}, nil
+}
+
+func NewOrderRevisionForOrm(id, orderId string, revision int, pickupDateTime, amendedAt time.Time, stockItems []OrderStockItem, foodItems []OrderFoodItem) (*OrderRevision, error) {
+	return &OrderRevision{
+		id:             id,
+		orderId:        orderId,
+		revision:       revision,
+		pickupDateTime: *NewDateTimeFromTime(pickupDateTime),
+		stockItems:     stockItems,
+		foodItems:      foodItems,
+		amendedAt:      *NewDateTimeFromTime(amendedAt),
+	}, nil
+}
+
+func (o *OrderRevision) GetId() string {
+	return o.id
+}
+
+func (o *OrderRevision) GetOrderId() string {
+	return o.orderId
+}
+
+func (o *OrderRevision) GetRevision() int {
+	return o.revision
+}
+
+func (o *OrderRevision) GetPickupDateTime() string {
+	return o.pickupDateTime.GetAsDateTime()
+}
+
+func (o *OrderRevision) GetAmendedAt() string {
+	return o.amendedAt.GetAsDateTime()
+}
+
+func (o *OrderRevision) GetPickupAt() time.Time {
+	return o.pickupDateTime.GetDateTime()
+}
+
+func (o *OrderRevision) GetAmendedAtTime() time.Time {
+	return o.amendedAt.GetDateTime()
+}
+
+func (o *OrderRevision) GetStockItems() []OrderStockItem {
+	return o.stockItems
+}
+
+func (o *OrderRevision) GetFoodItems() []OrderFoodItem {
+	return o.foodItems
+}
+
+func (o *OrderRevision) GetTotalCost() int {
+	total := 0
+	for _, food := range o.foodItems {
+		total += food.GetTotalCost()
+	}
+	for _, stock := range o.stockItems {
+		total += stock.GetTotalCost()
+	}
+	return total
+}
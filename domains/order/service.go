@@ -3,28 +3,46 @@ package order
 import (
 	"chico/takeout/common"
 	"chico/takeout/domains/item"
+	"chico/takeout/domains/store"
+	"fmt"
 	"time"
 )
 
+// every change of remain is recorded as stock movement with reference to order
 type StockItemRemainCheckAndConsumer struct {
-	stockRepo item.StockItemRepository
+	stockRepo    item.StockItemRepository
+	batchRepo    item.StockBatchRepository
+	movementRepo item.StockMovementRepository
+	clock        common.Clock
 }
 
-func NewStockItemRemainCheckAndConsumer(stockRepo item.StockItemRepository) *StockItemRemainCheckAndConsumer {
+func NewStockItemRemainCheckAndConsumer(stockRepo item.StockItemRepository, batchRepo item.StockBatchRepository, movementRepo item.StockMovementRepository, clock common.Clock) *StockItemRemainCheckAndConsumer {
 	return &StockItemRemainCheckAndConsumer{
-		stockRepo: stockRepo,
+		stockRepo:    stockRepo,
+		batchRepo:    batchRepo,
+		movementRepo: movementRepo,
+		clock:        clock,
 	}
 }
 
-func (s *StockItemRemainCheckAndConsumer) ConsumeRemainStock(stockOrders []ItemOrder) error {
+// stock item tracked by batch is consumed from batches usable at pick up date
+func (s *StockItemRemainCheckAndConsumer) ConsumeRemainStock(order *OrderInfo) error {
 	allStocks, err := s.stockRepo.FindAll()
 	if err != nil {
 		return err
 	}
-	for _, order := range stockOrders {
+	pickupDateTime := order.pickupDateTime.GetDateTime()
+	for _, ordered := range order.GetStockItems() {
 		for _, stock := range allStocks {
-			if stock.HasSameId(order.id) {
-				err = stock.ConsumeRemain(order.quantity)
+			if stock.HasSameId(ordered.GetItemId()) {
+				if stock.IsTrackedByBatch() {
+					err = s.replaceBatches(order, item.StockMovementOrder, stock.GetId(), pickupDateTime, 0, pickupDateTime, ordered.GetQuantity())
+					if err != nil {
+						return err
+					}
+					break
+				}
+				err = stock.ConsumeRemain(ordered.GetQuantity())
 				// out of stock
 				if err != nil {
 					return err
@@ -34,6 +52,10 @@ func (s *StockItemRemainCheckAndConsumer) ConsumeRemainStock(stockOrders []ItemO
 				if err != nil {
 					return err
 				}
+				err = s.record(order, item.StockMovementOrder, stock.GetId(), "", -ordered.GetQuantity())
+				if err != nil {
+					return err
+				}
 				break
 			}
 		}
@@ -41,15 +63,23 @@ func (s *StockItemRemainCheckAndConsumer) ConsumeRemainStock(stockOrders []ItemO
 	return nil
 }
 
-func (s *StockItemRemainCheckAndConsumer) IncrementCanceledRemain(stockOrders []OrderStockItem) error {
+func (s *StockItemRemainCheckAndConsumer) IncrementCanceledRemain(order *OrderInfo) error {
 	allStocks, err := s.stockRepo.FindAll()
 	if err != nil {
 		return err
 	}
-	for _, order := range stockOrders {
+	pickupDateTime := order.pickupDateTime.GetDateTime()
+	for _, ordered := range order.GetStockItems() {
 		for _, stock := range allStocks {
-			if stock.HasSameId(order.GetItemId()) {
-				err = stock.IncreaseRemain(order.GetQuantity())
+			if stock.HasSameId(ordered.GetItemId()) {
+				if stock.IsTrackedByBatch() {
+					err = s.replaceBatches(order, item.StockMovementCancel, stock.GetId(), pickupDateTime, ordered.GetQuantity(), pickupDateTime, 0)
+					if err != nil {
+						return err
+					}
+					break
+				}
+				err = stock.IncreaseRemain(ordered.GetQuantity())
 				if err != nil {
 					return err
 				}
@@ -58,6 +88,10 @@ func (s *StockItemRemainCheckAndConsumer) IncrementCanceledRemain(stockOrders []
 				if err != nil {
 					return err
 				}
+				err = s.record(order, item.StockMovementCancel, stock.GetId(), "", ordered.GetQuantity())
+				if err != nil {
+					return err
+				}
 				break
 			}
 		}
@@ -65,44 +99,383 @@ func (s *StockItemRemainCheckAndConsumer) IncrementCanceledRemain(stockOrders []
 	return nil
 }
 
+// stock remain is changed by difference of quantity between before and after amended.
+// batches are restored at pick up date before amended and consumed at amended pick up date
+func (s *StockItemRemainCheckAndConsumer) ApplyAmendedRemain(before *OrderRevision, after *OrderInfo) error {
+	beforePickup := before.GetPickupAt()
+	deltas := newQuantityMap()
+	beforeQuantities := newQuantityMap()
+	afterQuantities := newQuantityMap()
+	for _, order := range after.GetStockItems() {
+		deltas.Add(order.GetItemId(), order.GetQuantity())
+		afterQuantities.Add(order.GetItemId(), order.GetQuantity())
+	}
+	for _, order := range before.GetStockItems() {
+		deltas.Add(order.GetItemId(), -order.GetQuantity())
+		beforeQuantities.Add(order.GetItemId(), order.GetQuantity())
+	}
+	allStocks, err := s.stockRepo.FindAll()
+	if err != nil {
+		return err
+	}
+	for i := range allStocks {
+		stock := &allStocks[i]
+		if stock.IsTrackedByBatch() {
+			err = s.replaceBatches(after, item.StockMovementOrder, stock.GetId(), beforePickup, beforeQuantities.GetQuantity(stock.GetId()), after.pickupDateTime.GetDateTime(), afterQuantities.GetQuantity(stock.GetId()))
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		delta := deltas.GetQuantity(stock.GetId())
+		if delta == 0 {
+			continue
+		}
+		if delta > 0 {
+			// out of stock
+			err = stock.ConsumeRemain(delta)
+		} else {
+			err = stock.IncreaseRemain(-delta)
+		}
+		if err != nil {
+			return err
+		}
+		// update stock db
+		err = s.stockRepo.Update(stock)
+		if err != nil {
+			return err
+		}
+		err = s.record(after, item.StockMovementOrder, stock.GetId(), "", -delta)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restored quantity is returned at first, then consumed
+func (s *StockItemRemainCheckAndConsumer) replaceBatches(order *OrderInfo, movementType item.StockMovementType, stockItemId string, restoreAt time.Time, restore int, consumeAt time.Time, consume int) error {
+	if restore == 0 && consume == 0 {
+		return nil
+	}
+	batches, err := s.batchRepo.FindByStockItemId(stockItemId)
+	if err != nil {
+		return err
+	}
+	remains := map[string]int{}
+	for _, batch := range batches {
+		remains[batch.GetId()] = batch.GetRemain()
+	}
+	inventory := item.NewStockBatchInventory(batches)
+	updated := []item.StockBatch{}
+	if restore > 0 {
+		restored, err := inventory.Restore(restoreAt, restore)
+		if err != nil {
+			return err
+		}
+		updated = append(updated, restored...)
+	}
+	if consume > 0 {
+		// out of stock
+		consumed, err := inventory.Consume(consumeAt, consume)
+		if err != nil {
+			return err
+		}
+		updated = append(updated, consumed...)
+	}
+	// update stock db (latest state of same batch is saved at last)
+	latest := map[string]item.StockBatch{}
+	batchIds := []string{}
+	for i := range updated {
+		err = s.batchRepo.Update(&updated[i])
+		if err != nil {
+			return err
+		}
+		if _, ok := latest[updated[i].GetId()]; !ok {
+			batchIds = append(batchIds, updated[i].GetId())
+		}
+		latest[updated[i].GetId()] = updated[i]
+	}
+	// movement is net change of each batch
+	for _, id := range batchIds {
+		batch := latest[id]
+		delta := batch.GetRemain() - remains[id]
+		if delta == 0 {
+			continue
+		}
+		err = s.record(order, movementType, stockItemId, id, delta)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *StockItemRemainCheckAndConsumer) record(order *OrderInfo, movementType item.StockMovementType, stockItemId, batchId string, quantity int) error {
+	movement, err := item.NewStockMovement(stockItemId, batchId, movementType, quantity, order.GetId(), order.GetUserId(), "", s.clock.Now())
+	if err != nil {
+		return err
+	}
+	return s.movementRepo.Create(movement)
+}
+
+// ingredients of ordered items are consumed at pick up date
+type IngredientConsumer struct {
+	service item.IngredientService
+}
+
+func NewIngredientConsumer(ingredientRepo item.IngredientRepository, recipeRepo item.RecipeRepository, usageRepo item.IngredientUsageRepository) *IngredientConsumer {
+	return &IngredientConsumer{
+		service: *item.NewIngredientService(ingredientRepo, recipeRepo, usageRepo),
+	}
+}
+
+// consumed quantities are kept in the order to restore same quantities even if recipe is changed
+func (c *IngredientConsumer) ConsumeIngredients(order *OrderInfo) error {
+	needs, err := c.service.Needs(itemQuantities(order.GetStockItems(), order.GetFoodItems()))
+	if err != nil {
+		return err
+	}
+	err = c.service.Replace("", nil, order.GetPickupDate(), needs)
+	if err != nil {
+		return err
+	}
+	order.SetConsumedIngredients(needs)
+	return nil
+}
+
+func (c *IngredientConsumer) RestoreCanceledIngredients(order *OrderInfo) error {
+	consumed, err := c.consumedIngredients(order.GetConsumedIngredients(), order.GetStockItems(), order.GetFoodItems())
+	if err != nil {
+		return err
+	}
+	return c.service.Replace(order.GetPickupDate(), consumed, "", nil)
+}
+
+// ingredients are restored at pick up date before amended and consumed at amended pick up date.
+// consumed ingredients of the order are not replaced yet, so they are the ones before amended
+func (c *IngredientConsumer) ApplyAmendedIngredients(before *OrderRevision, after *OrderInfo) error {
+	beforePickup := before.GetPickupAt()
+	restore, err := c.consumedIngredients(after.GetConsumedIngredients(), before.GetStockItems(), before.GetFoodItems())
+	if err != nil {
+		return err
+	}
+	consume, err := c.service.Needs(itemQuantities(after.GetStockItems(), after.GetFoodItems()))
+	if err != nil {
+		return err
+	}
+	err = c.service.Replace(common.ConvertTimeToDateStr(beforePickup), restore, after.GetPickupDate(), consume)
+	if err != nil {
+		return err
+	}
+	after.SetConsumedIngredients(consume)
+	return nil
+}
+
+// order which has no record of consumed ingredients uses current recipe
+func (c *IngredientConsumer) consumedIngredients(consumed map[string]int, stockOrders []OrderStockItem, foodOrders []OrderFoodItem) (map[string]int, error) {
+	if consumed != nil {
+		return consumed, nil
+	}
+	return c.service.Needs(itemQuantities(stockOrders, foodOrders))
+}
+
+func itemQuantities(stockOrders []OrderStockItem, foodOrders []OrderFoodItem) map[string]int {
+	quantities := map[string]int{}
+	for _, ordered := range stockOrders {
+		quantities[ordered.GetItemId()] += ordered.GetQuantity()
+	}
+	for _, ordered := range foodOrders {
+		quantities[ordered.GetItemId()] += ordered.GetQuantity()
+	}
+	return quantities
+}
+
 type FoodItemRemainChecker struct {
-	orderRepo OrderInfoRepository
-	foodRepo  item.FoodItemRepository
+	orderRepo     OrderInfoRepository
+	foodRepo      item.FoodItemRepository
+	quotaRepo     item.FoodQuotaRepository
+	busRepo       store.BusinessHoursRepository
+	spBusRepo     store.SpecialBusinessHourRepository
+	spHolidayRepo store.SpecialHolidayRepository
 }
 
-func NewFoodItemRemainChecker(orderRepo OrderInfoRepository, foodRepo item.FoodItemRepository) *FoodItemRemainChecker {
+func NewFoodItemRemainChecker(orderRepo OrderInfoRepository, foodRepo item.FoodItemRepository, quotaRepo item.FoodQuotaRepository,
+	busRepo store.BusinessHoursRepository, spBusRepo store.SpecialBusinessHourRepository, spHolidayRepo store.SpecialHolidayRepository) *FoodItemRemainChecker {
 	return &FoodItemRemainChecker{
-		orderRepo: orderRepo,
-		foodRepo:  foodRepo,
+		orderRepo:     orderRepo,
+		foodRepo:      foodRepo,
+		quotaRepo:     quotaRepo,
+		busRepo:       busRepo,
+		spBusRepo:     spBusRepo,
+		spHolidayRepo: spHolidayRepo,
 	}
 }
 
+// pickupDateTime is yyyy/MM/dd HH:mm
 func (f *FoodItemRemainChecker) CheckRemain(pickupDateTime string, foodOrders []OrderFoodItem) error {
+	return f.checkRemain(pickupDateTime, foodOrders, "")
+}
+
+// quantity of amended order itself is not counted as ordered
+func (f *FoodItemRemainChecker) CheckAmendedRemain(order *OrderInfo) error {
+	return f.checkRemain(order.GetPickupDateTime(), order.GetFoodItems(), order.GetId())
+}
+
+func (f *FoodItemRemainChecker) checkRemain(pickupDateTime string, foodOrders []OrderFoodItem, excludeOrderId string) error {
+	pickupDate, err := common.ConvertDateTimeStrToDateStr(pickupDateTime)
+	if err != nil {
+		return err
+	}
 	// step1 get same days food order and calc each quantity
-	sameDateOrders, err := f.orderRepo.FindByPickupDate(pickupDateTime)
+	sameDateOrders, err := f.orderRepo.FindByPickupDate(pickupDate)
 	if err != nil {
 		return err
 	}
-	spec := newFoodItemRemainQuantitySpecification(sameDateOrders)
+	otherOrders := []OrderInfo{}
+	for _, order := range sameDateOrders {
+		if order.id != excludeOrderId {
+			otherOrders = append(otherOrders, order)
+		}
+	}
+	spec := newFoodItemRemainQuantitySpecification(otherOrders)
 
-	// step2: check each order remain
+	// step2: check each order remain (quota of the date has priority)
 	foods, err := f.foodRepo.FindAll()
 	if err != nil {
 		return err
 	}
+	// hour crossing midnight starts at previous date, so quotas of previous date are loaded together
+	target, err := common.ConvertStrToDate(pickupDate)
+	if err != nil {
+		return err
+	}
+	quotas, err := f.quotaRepo.FindByRange(common.ConvertTimeToDateStr(target.AddDate(0, 0, -1)), pickupDate)
+	if err != nil {
+		return err
+	}
 	for _, foodOrder := range foodOrders {
 		for _, food := range foods {
 			if foodOrder.HasSameId(food.GetId()) {
-				if spec.IsOverRemain(food.GetId(), foodOrder.GetQuantity(), food.GetMaxOrderPerDay()) {
+				if spec.IsOverRemain(food.GetId(), foodOrder.GetQuantity(), food.GetQuotaAt(pickupDate, quotas)) {
 					return common.NewValidationError("foodOrders", "Food items remain count perDay is over limit.")
 				}
 				break
 			}
 		}
 	}
+
+	// step3: check quota of business hour with orders in the same shift
+	if !hasHourQuota(quotas) {
+		return nil
+	}
+	shift, sameShiftOrders, err := f.findSameShiftOrders(pickupDateTime, excludeOrderId)
+	if err != nil {
+		return err
+	}
+	// out of business is checked by factory
+	if shift == nil {
+		return nil
+	}
+	// quota of the hour is set on the start date of the shift
+	shiftDate := common.ConvertTimeToDateStr(shift.Start)
+	hourSpec := newFoodItemRemainQuantitySpecification(sameShiftOrders)
+	for _, foodOrder := range foodOrders {
+		for _, food := range foods {
+			if foodOrder.HasSameId(food.GetId()) {
+				quota, ok := food.GetHourQuotaAt(shiftDate, shift.HourTypeId, quotas)
+				if ok && hourSpec.IsOverRemain(food.GetId(), foodOrder.GetQuantity(), quota) {
+					return common.NewValidationError("foodOrders", "Food items remain count per business hour is over limit.")
+				}
+				break
+			}
+		}
+	}
 	return nil
 }
 
+func hasHourQuota(quotas []item.FoodQuota) bool {
+	for _, quota := range quotas {
+		if !quota.IsWholeDay() {
+			return true
+		}
+	}
+	return false
+}
+
+// shift which covers pick up time and orders picked up in the shift.
+// orders of start date and next date of the shift are checked because shift can cross midnight
+func (f *FoodItemRemainChecker) findSameShiftOrders(pickupDateTime string, excludeOrderId string) (*store.ShiftInfo, []OrderInfo, error) {
+	schedules, err := f.busRepo.Fetch()
+	if err != nil {
+		return nil, nil, err
+	}
+	spSchedules, err := f.spBusRepo.FindAll()
+	if err != nil {
+		return nil, nil, err
+	}
+	spHolidays, err := f.spHolidayRepo.FindAll()
+	if err != nil {
+		return nil, nil, err
+	}
+	spec := store.NewHolidaySpecification(*schedules, spSchedules, spHolidays)
+	shift, err := spec.FindShiftAt(pickupDateTime)
+	if err != nil || shift == nil {
+		return nil, nil, err
+	}
+	orders := []OrderInfo{}
+	for _, date := range []time.Time{shift.Start, shift.Start.AddDate(0, 0, 1)} {
+		dateOrders, err := f.orderRepo.FindByPickupDate(common.ConvertTimeToDateStr(date))
+		if err != nil {
+			return nil, nil, err
+		}
+		orders = append(orders, dateOrders...)
+	}
+	sameShift := []OrderInfo{}
+	for _, order := range orders {
+		if order.GetId() == excludeOrderId {
+			continue
+		}
+		other, err := spec.FindShiftAt(order.GetPickupDateTime())
+		if err != nil {
+			return nil, nil, err
+		}
+		if other != nil && other.HourTypeId == shift.HourTypeId && other.Start.Equal(shift.Start) {
+			sameShift = append(sameShift, order)
+		}
+	}
+	return shift, sameShift, nil
+}
+
+// ordered food items which quota of pick up date is used up
+func (f *FoodItemRemainChecker) FindSoldOut(pickupDate string, foodOrders []OrderFoodItem) ([]item.FoodItem, error) {
+	sameDateOrders, err := f.orderRepo.FindByPickupDate(pickupDate)
+	if err != nil {
+		return nil, err
+	}
+	spec := newFoodItemRemainQuantitySpecification(sameDateOrders)
+	foods, err := f.foodRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	quotas, err := f.quotaRepo.FindByDate(pickupDate)
+	if err != nil {
+		return nil, err
+	}
+	soldOut := []item.FoodItem{}
+	for _, foodOrder := range foodOrders {
+		for _, food := range foods {
+			if foodOrder.HasSameId(food.GetId()) {
+				if spec.qMap.GetQuantity(food.GetId()) >= food.GetQuotaAt(pickupDate, quotas) {
+					soldOut = append(soldOut, food)
+				}
+				break
+			}
+		}
+	}
+	return soldOut, nil
+}
+
 type OrderFilter struct {
 	orderRepo OrderInfoRepository
 }
@@ -135,37 +508,177 @@ func (o *OrderFilter) GetActiveOrderOfSpecifiedDayAndTime(startDate, startTime,
 	if err != nil {
 		return nil, err
 	}
-	// check active and in range time
+	// range over midnight (ex:20:00-01:00) is separated at 23:59
+	if common.ConvertTimeToTimeStr(endTime) >= common.ConvertTimeToTimeStr(startTime) {
+		return o.filterActiveInRangeTime(orders, startTime, endTime), nil
+	}
+	lastMinute := time.Date(startTime.Year(), startTime.Month(), startTime.Day(), 23, 59, 0, 0, startTime.Location())
+	target := o.filterActiveInRangeTime(orders, startTime, lastMinute)
+
+	// orders of next date until end
+	nextOrders, err := o.orderRepo.FindByPickupDate(common.ConvertTimeToDateStr(startDate.AddDate(0, 0, 1)))
+	if err != nil {
+		return nil, err
+	}
+	midnight := time.Date(startTime.Year(), startTime.Month(), startTime.Day(), 0, 0, 0, 0, startTime.Location())
+	return append(target, o.filterActiveInRangeTime(nextOrders, midnight, endTime)...), nil
+}
+
+func (o *OrderFilter) filterActiveInRangeTime(orders []OrderInfo, startTime, endTime time.Time) []OrderInfo {
 	target := []OrderInfo{}
 	for _, order := range orders {
 		if order.canceled {
 			continue
 		}
-		pickUpTime := order.pickupDateTime.GetDateTime()	
+		pickUpTime := order.pickupDateTime.GetDateTime()
 		if common.IsInRangeTime(startTime, endTime, pickUpTime) {
 			target = append(target, order)
 		}
 	}
-	return target, nil
+	return target
 }
 
-type OrderDuplicateChecker struct {
-	orderRepo OrderInfoRepository
+type OrderLimitChecker struct {
+	orderRepo     OrderInfoRepository
+	limitRepo     OrderLimitRepository
+	busRepo       store.BusinessHoursRepository
+	spBusRepo     store.SpecialBusinessHourRepository
+	spHolidayRepo store.SpecialHolidayRepository
+	clock         common.Clock
 }
 
-func NewOrderDuplicateChecker(orderRepo OrderInfoRepository) *OrderDuplicateChecker {
-	return &OrderDuplicateChecker{
-		orderRepo: orderRepo,
+func NewOrderLimitChecker(orderRepo OrderInfoRepository, limitRepo OrderLimitRepository,
+	busRepo store.BusinessHoursRepository, spBusRepo store.SpecialBusinessHourRepository, spHolidayRepo store.SpecialHolidayRepository,
+	clock common.Clock) *OrderLimitChecker {
+	return &OrderLimitChecker{
+		orderRepo:     orderRepo,
+		limitRepo:     limitRepo,
+		busRepo:       busRepo,
+		spBusRepo:     spBusRepo,
+		spHolidayRepo: spHolidayRepo,
+		clock:         clock,
+	}
+}
+
+// default limit is used if not created yet
+func (o *OrderLimitChecker) FetchLimit() (*OrderLimit, error) {
+	limit, err := o.limitRepo.Fetch()
+	if err != nil {
+		return nil, err
+	}
+	if limit == nil {
+		return NewDefaultOrderLimit(), nil
+	}
+	return limit, nil
+}
+
+// other active orders of same user are counted (order itself is excluded for amendment)
+// guest order is counted per same contact
+func (o *OrderLimitChecker) CheckLimit(order *OrderInfo) error {
+	limit, err := o.FetchLimit()
+	if err != nil {
+		return err
+	}
+	actives, err := o.orderRepo.FindActiveByUserId(order.GetUserId(), o.clock.Now())
+	if err != nil {
+		return err
+	}
+	others := []OrderInfo{}
+	for _, active := range actives {
+		// guest orders are distinguished by email or tel no
+		if active.id != order.id && (!order.IsGuest() || active.HasSameContact(order)) {
+			others = append(others, active)
+		}
+	}
+	if isOverLimit(limit.GetMaxActivePerUser(), len(others)) {
+		return common.NewValidationError("UserId", fmt.Sprintf("active orders per user is over limit(%d).", limit.GetMaxActivePerUser()))
+	}
+
+	sameDateCount := 0
+	for _, other := range others {
+		if other.GetPickupDate() == order.GetPickupDate() {
+			sameDateCount++
+		}
+	}
+	if isOverLimit(limit.GetMaxPerPickupDate(), sameDateCount) {
+		return common.NewValidationError("PickupDateTime", fmt.Sprintf("active orders per pickup date is over limit(%d).", limit.GetMaxPerPickupDate()))
+	}
+
+	if limit.GetMaxPerHourSlot() == 0 {
+		return nil
+	}
+	return o.checkHourSlotLimit(order, others, limit.GetMaxPerHourSlot())
+}
+
+// orders are in same slot if picked up in same shift of business hour
+func (o *OrderLimitChecker) checkHourSlotLimit(order *OrderInfo, others []OrderInfo, maxPerHourSlot uint) error {
+	schedules, err := o.busRepo.Fetch()
+	if err != nil {
+		return err
+	}
+	spSchedules, err := o.spBusRepo.FindAll()
+	if err != nil {
+		return err
+	}
+	spHolidays, err := o.spHolidayRepo.FindAll()
+	if err != nil {
+		return err
+	}
+	spec := store.NewHolidaySpecification(*schedules, spSchedules, spHolidays)
+	shift, err := spec.FindShiftAt(order.GetPickupDateTime())
+	if err != nil {
+		return err
+	}
+	// out of business is checked by factory
+	if shift == nil {
+		return nil
+	}
+	sameSlotCount := 0
+	for _, other := range others {
+		otherShift, err := spec.FindShiftAt(other.GetPickupDateTime())
+		if err != nil {
+			return err
+		}
+		if otherShift != nil && otherShift.HourTypeId == shift.HourTypeId && otherShift.Start.Equal(shift.Start) {
+			sameSlotCount++
+		}
+	}
+	if isOverLimit(maxPerHourSlot, sameSlotCount) {
+		return common.NewValidationError("PickupDateTime", fmt.Sprintf("active orders per business hour is over limit(%d).", maxPerHourSlot))
+	}
+	return nil
+}
+
+type OrderContactLimitChecker struct {
+	orderRepo       OrderInfoRepository
+	maxOrdersPerDay int
+}
+
+// 0 maxOrdersPerDay means no limit
+func NewOrderContactLimitChecker(orderRepo OrderInfoRepository, maxOrdersPerDay int) *OrderContactLimitChecker {
+	return &OrderContactLimitChecker{
+		orderRepo:       orderRepo,
+		maxOrdersPerDay: maxOrdersPerDay,
 	}
 }
 
-func (o *OrderDuplicateChecker) ActiveOrderExists(userId string) (bool, error) {
-	order, err := o.orderRepo.FindActiveByUserId(userId)
+// canceled orders are also counted to prevent ordering and canceling repeatedly
+func (o *OrderContactLimitChecker) CheckDailyLimit(order *OrderInfo) error {
+	if o.maxOrdersPerDay <= 0 {
+		return nil
+	}
+	sameDateOrders, err := o.orderRepo.FindByOrderDate(order.GetOrderDate())
 	if err != nil {
-		return false, err
+		return err
 	}
-	if len(order) > 0 {
-		return true, nil
+	count := 0
+	for _, ordered := range sameDateOrders {
+		if ordered.id != order.id && ordered.HasSameContact(order) {
+			count++
+		}
+	}
+	if count >= o.maxOrdersPerDay {
+		return common.NewValidationError("userTelNo", fmt.Sprintf("orders per day of same email or tel no is over limit(%d).", o.maxOrdersPerDay))
 	}
-	return false, nil
+	return nil
 }
@@ -0,0 +1,481 @@
+package order_test
+
+import (
+	"testing"
+	"time"
+
+	"chico/takeout/common"
+	"chico/takeout/domains/item"
+	domains "chico/takeout/domains/order"
+	"chico/takeout/domains/store"
+	"chico/takeout/infrastructures/memory"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderContactLimitChecker_CheckDailyLimit(t *testing.T) {
+	repo := memory.NewOrderInfoMemoryRepository()
+	repo.Reset()
+	// same email, different tel no
+	ordered1, _ := domains.NewOrderInfoForOrm("c1", "user11", "ユーザー11", "Same@hoge.com", "111111111", "", parseDateTime("2050/12/12 12:00"), parseDateTime("2050/12/09 10:00"), []domains.OrderStockItem{}, []domains.OrderFoodItem{}, false, "", "")
+	repo.Create(ordered1)
+	// different email, same tel no and canceled
+	ordered2, _ := domains.NewOrderInfoForOrm("c2", "user12", "ユーザー12", "other@hoge.com", "222222222", "", parseDateTime("2050/12/12 12:00"), parseDateTime("2050/12/09 11:00"), []domains.OrderStockItem{}, []domains.OrderFoodItem{}, true, "", "")
+	repo.Create(ordered2)
+	// same contact but other day
+	ordered3, _ := domains.NewOrderInfoForOrm("c3", "user13", "ユーザー13", "same@hoge.com", "222222222", "", parseDateTime("2050/12/12 12:00"), parseDateTime("2050/12/08 11:00"), []domains.OrderStockItem{}, []domains.OrderFoodItem{}, false, "", "")
+	repo.Create(ordered3)
+
+	inputs := []struct {
+		name             string
+		maxOrdersPerDay  int
+		email            string
+		telNo            string
+		hasValidationErr bool
+	}{
+		{name: "no limit", maxOrdersPerDay: 0, email: "same@hoge.com", telNo: "222222222", hasValidationErr: false},
+		{name: "under limit", maxOrdersPerDay: 2, email: "same@hoge.com", telNo: "333333333", hasValidationErr: false},
+		{name: "over limit by email(ignore case)", maxOrdersPerDay: 1, email: "same@hoge.com", telNo: "333333333", hasValidationErr: true},
+		{name: "over limit by tel no(canceled is counted)", maxOrdersPerDay: 1, email: "new@hoge.com", telNo: "222222222", hasValidationErr: true},
+		{name: "over limit by both", maxOrdersPerDay: 2, email: "same@hoge.com", telNo: "222222222", hasValidationErr: true},
+		{name: "other contact", maxOrdersPerDay: 1, email: "new@hoge.com", telNo: "333333333", hasValidationErr: false},
+	}
+	for _, tt := range inputs {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := domains.NewOrderContactLimitChecker(repo, tt.maxOrdersPerDay)
+			order, _ := domains.NewOrderInfoForOrm("new", "user14", "ユーザー14", tt.email, tt.telNo, "", parseDateTime("2050/12/12 12:00"), parseDateTime("2050/12/09 15:00"), []domains.OrderStockItem{}, []domains.OrderFoodItem{}, false, "", "")
+			err := checker.CheckDailyLimit(order)
+			if tt.hasValidationErr {
+				assert.Error(t, err)
+				assert.IsType(t, common.NewValidationError("", ""), err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestOrderFilter_GetActiveOrderOfSpecifiedDayAndTime_OverMidnight(t *testing.T) {
+	repo := memory.NewOrderInfoMemoryRepository()
+	repo.Reset()
+	pickups := []struct {
+		id       string
+		pickup   string
+		canceled bool
+	}{
+		{id: "m1", pickup: "2051/01/09 19:59"},
+		{id: "m2", pickup: "2051/01/09 20:00"},
+		{id: "m3", pickup: "2051/01/09 23:59"},
+		{id: "m4", pickup: "2051/01/10 00:00"},
+		{id: "m5", pickup: "2051/01/10 00:30", canceled: true},
+		{id: "m6", pickup: "2051/01/10 01:00"},
+		{id: "m7", pickup: "2051/01/10 01:01"},
+		{id: "m8", pickup: "2051/01/08 23:00"},
+	}
+	for _, p := range pickups {
+		order, err := domains.NewOrderInfoForOrm(p.id, "user1", "ユーザー1", "user1@hoge.com", "111111111", "", parseDateTime(p.pickup), parseDateTime("2051/01/01 10:00"), []domains.OrderStockItem{}, []domains.OrderFoodItem{}, p.canceled, "", "")
+		assert.NoError(t, err)
+		repo.Create(order)
+	}
+
+	filter := domains.NewOrderFilter(repo)
+	date, _ := common.ConvertStrToDate("2051/01/09")
+	start, _ := common.ConvertStrToTime("20:00")
+	end, _ := common.ConvertStrToTime("01:00")
+	orders, err := filter.GetActiveOrderOfSpecifiedDayAndTime(*date, *start, *end)
+	assert.NoError(t, err)
+	ids := []string{}
+	for _, order := range orders {
+		ids = append(ids, order.GetId())
+	}
+	assert.ElementsMatch(t, []string{"m2", "m3", "m4", "m6"}, ids)
+
+	// normal range is not changed
+	end, _ = common.ConvertStrToTime("23:59")
+	orders, err = filter.GetActiveOrderOfSpecifiedDayAndTime(*date, *start, *end)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(orders))
+}
+
+func TestStockItemRemainCheckAndConsumer_ApplyAmendedRemain(t *testing.T) {
+	stockRepo := memory.NewStockItemMemoryRepository()
+	stockRepo.Reset()
+	stock1, _ := item.NewStockItem("stock1", "item1", 1, 10, 100, "kind1", true, "")
+	stock1.SetRemain(5)
+	stockRepo.Create(stock1)
+	stock2, _ := item.NewStockItem("stock2", "item2", 2, 10, 200, "kind1", true, "")
+	stock2.SetRemain(5)
+	stockRepo.Create(stock2)
+	stock3, _ := item.NewStockItem("stock3", "item3", 3, 10, 300, "kind1", true, "")
+	stock3.SetRemain(5)
+	stockRepo.Create(stock3)
+
+	newStockItem := func(id string, quantity int) domains.OrderStockItem {
+		item, _ := domains.NewOrderStockItem(id, "item", 100, quantity, []domains.OptionItemInfo{})
+		return *item
+	}
+	// stock1 is increased, stock2 is removed, stock3 is added
+	now := time.Date(2051, 1, 1, 10, 0, 0, 0, common.GetStoreLocation())
+	order, _ := domains.NewOrderInfo("user1", "ユーザー1", "user1@hoge.com", "111111111", "", "2051/01/10 12:00",
+		[]domains.OrderStockItem{newStockItem(stock1.GetId(), 2), newStockItem(stock2.GetId(), 3)}, []domains.OrderFoodItem{}, now)
+	before, _ := domains.NewOrderRevision(order, 1, now)
+	err := order.Amend("2051/01/10 12:00", []domains.OrderStockItem{newStockItem(stock1.GetId(), 4), newStockItem(stock3.GetId(), 1)}, []domains.OrderFoodItem{}, now)
+	assert.NoError(t, err)
+
+	movementRepo := memory.NewStockMovementMemoryRepository()
+	consumer := domains.NewStockItemRemainCheckAndConsumer(stockRepo, memory.NewStockBatchMemoryRepository(), movementRepo, common.NewFakeClock(now))
+	err = consumer.ApplyAmendedRemain(before, order)
+	assert.NoError(t, err)
+	// difference is recorded as order movement
+	balances, _ := movementRepo.SumQuantities()
+	assert.Equal(t, -2, balances[stock1.GetId()])
+	assert.Equal(t, 3, balances[stock2.GetId()])
+	assert.Equal(t, -1, balances[stock3.GetId()])
+
+	stocks := stockRepo.GetMemory()
+	assert.Equal(t, 3, stocks[stock1.GetId()].GetRemain())
+	assert.Equal(t, 8, stocks[stock2.GetId()].GetRemain())
+	assert.Equal(t, 4, stocks[stock3.GetId()].GetRemain())
+
+	// out of stock by delta
+	after, _ := domains.NewOrderRevision(order, 2, now)
+	err = order.Amend("2051/01/10 12:00", []domains.OrderStockItem{newStockItem(stock1.GetId(), 10)}, []domains.OrderFoodItem{}, now)
+	assert.NoError(t, err)
+	err = consumer.ApplyAmendedRemain(after, order)
+	assert.Error(t, err)
+}
+
+func TestStockItemRemainCheckAndConsumer_Batch(t *testing.T) {
+	stockRepo := memory.NewStockItemMemoryRepository()
+	stockRepo.Reset()
+	batchRepo := memory.NewStockBatchMemoryRepository()
+	stock, _ := item.NewStockItem("stock1", "item1", 1, 10, 100, "kind1", true, "")
+	stock.SetTrackByBatch(true)
+	stockRepo.Create(stock)
+	// old batch expires on 1/11, new batch is available from 1/11
+	old, _ := item.NewStockBatch(stock.GetId(), "2051/01/08", "2051/01/11", 3)
+	batchRepo.Create(old)
+	fresh, _ := item.NewStockBatch(stock.GetId(), "2051/01/11", "2051/01/15", 5)
+	batchRepo.Create(fresh)
+
+	newOrder := func(pickupDateTime string, quantity int) *domains.OrderInfo {
+		stockItem, _ := domains.NewOrderStockItem(stock.GetId(), "item", 100, quantity, []domains.OptionItemInfo{})
+		order, _ := domains.NewOrderInfo("user1", "ユーザー1", "user1@hoge.com", "111111111", "", pickupDateTime,
+			[]domains.OrderStockItem{*stockItem}, []domains.OrderFoodItem{}, time.Date(2051, 1, 1, 10, 0, 0, 0, common.GetStoreLocation()))
+		return order
+	}
+	movementRepo := memory.NewStockMovementMemoryRepository()
+	consumer := domains.NewStockItemRemainCheckAndConsumer(stockRepo, batchRepo, movementRepo, common.NewFakeClock(time.Date(2051, 1, 2, 10, 0, 0, 0, common.GetStoreLocation())))
+	batches := batchRepo.GetMemory()
+
+	// only old batch is usable at 1/10
+	err := consumer.ConsumeRemainStock(newOrder("2051/01/10 12:00", 4))
+	assert.Error(t, err)
+	// first expiring batch is consumed first
+	order := newOrder("2051/01/11 12:00", 4)
+	err = consumer.ConsumeRemainStock(order)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, batches[old.GetId()].GetRemain())
+	assert.Equal(t, 4, batches[fresh.GetId()].GetRemain())
+	// stock remain is not changed
+	assert.Equal(t, 0, stockRepo.GetMemory()[stock.GetId()].GetRemain())
+	// movement per consumed batch
+	movements := movementRepo.GetMemory()
+	assert.Equal(t, 2, len(movements))
+	assert.Equal(t, item.StockMovementOrder, movements[0].GetMovementType())
+	assert.Equal(t, old.GetId(), movements[0].GetBatchId())
+	assert.Equal(t, -3, movements[0].GetQuantity())
+	assert.Equal(t, order.GetId(), movements[0].GetOrderId())
+	assert.Equal(t, fresh.GetId(), movements[1].GetBatchId())
+	assert.Equal(t, -1, movements[1].GetQuantity())
+
+	err = consumer.IncrementCanceledRemain(order)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, batches[old.GetId()].GetRemain())
+	assert.Equal(t, 5, batches[fresh.GetId()].GetRemain())
+	balances, _ := movementRepo.SumQuantities()
+	assert.Equal(t, 0, balances[stock.GetId()])
+	assert.Equal(t, item.StockMovementCancel, movementRepo.GetMemory()[3].GetMovementType())
+}
+
+func TestFoodItemRemainChecker_CheckAmendedRemain(t *testing.T) {
+	orderRepo := memory.NewOrderInfoMemoryRepository()
+	orderRepo.Reset()
+	foodRepo := memory.NewFoodItemMemoryRepository()
+	foodRepo.Reset()
+	foods, _ := foodRepo.FindAll()
+	food := foods[0]
+	max := food.GetMaxOrderPerDay()
+
+	newOrder := func(id string, quantity int) *domains.OrderInfo {
+		foodItem, _ := domains.NewOrderFoodItem(food.GetId(), food.GetName(), food.GetPrice(), quantity, []domains.OptionItemInfo{})
+		order, _ := domains.NewOrderInfoForOrm(id, "user1", "ユーザー1", "user1@hoge.com", "111111111", "", parseDateTime("2051/01/10 12:00"), parseDateTime("2051/01/01 10:00"), []domains.OrderStockItem{}, []domains.OrderFoodItem{*foodItem}, false, "", "")
+		return order
+	}
+	other := newOrder("a1", 1)
+	orderRepo.Create(other)
+	target := newOrder("a2", max-1)
+	orderRepo.Create(target)
+
+	checker := domains.NewFoodItemRemainChecker(orderRepo, foodRepo, memory.NewFoodQuotaMemoryRepository(),
+		memory.NewBusinessHoursMemoryRepository(), memory.NewSpecialBusinessHourMemoryRepository(), memory.NewSpecialHolidayMemoryRepository())
+	// quantity of order itself is not counted
+	err := checker.CheckAmendedRemain(newOrder("a2", max-1))
+	assert.NoError(t, err)
+	err = checker.CheckAmendedRemain(newOrder("a2", max))
+	assert.Error(t, err)
+	assert.IsType(t, common.NewValidationError("", ""), err)
+}
+
+func TestFoodItemRemainChecker_CheckRemain_Quota(t *testing.T) {
+	orderRepo := memory.NewOrderInfoMemoryRepository()
+	orderRepo.Reset()
+	foodRepo := memory.NewFoodItemMemoryRepository()
+	foodRepo.Reset()
+	quotaRepo := memory.NewFoodQuotaMemoryRepository()
+	foods, _ := foodRepo.FindAll()
+	food := foods[0]
+	max := food.GetMaxOrderPerDay()
+
+	foodItem, _ := domains.NewOrderFoodItem(food.GetId(), food.GetName(), food.GetPrice(), max+1, []domains.OptionItemInfo{})
+	checker := domains.NewFoodItemRemainChecker(orderRepo, foodRepo, quotaRepo,
+		memory.NewBusinessHoursMemoryRepository(), memory.NewSpecialBusinessHourMemoryRepository(), memory.NewSpecialHolidayMemoryRepository())
+	err := checker.CheckRemain("2051/01/11 12:00", []domains.OrderFoodItem{*foodItem})
+	assert.Error(t, err)
+
+	// quota of the date overrides max order per day
+	quota, _ := item.NewFoodQuota(food.GetId(), "2051/01/11", "", max+1)
+	quotaRepo.Save(quota)
+	err = checker.CheckRemain("2051/01/11 12:00", []domains.OrderFoodItem{*foodItem})
+	assert.NoError(t, err)
+	// other date is not changed
+	err = checker.CheckRemain("2051/01/12 12:00", []domains.OrderFoodItem{*foodItem})
+	assert.Error(t, err)
+}
+
+func TestFoodItemRemainChecker_CheckRemain_HourQuota(t *testing.T) {
+	// memory is replaced at reset, so repositories are created after reset
+	memory.NewOrderInfoMemoryRepository().Reset()
+	orderRepo := memory.NewOrderInfoMemoryRepository()
+	memory.NewBusinessHoursMemoryRepository().Reset()
+	busRepo := memory.NewBusinessHoursMemoryRepository()
+	memory.NewSpecialBusinessHourMemoryRepository().Reset()
+	spBusRepo := memory.NewSpecialBusinessHourMemoryRepository()
+	memory.NewSpecialHolidayMemoryRepository().Reset()
+	spHolidayRepo := memory.NewSpecialHolidayMemoryRepository()
+	foodRepo := memory.NewFoodItemMemoryRepository()
+	foodRepo.Reset()
+	quotaRepo := memory.NewFoodQuotaMemoryRepository()
+	foods, _ := foodRepo.FindAll()
+	food := foods[0]
+	hours, _ := busRepo.Fetch()
+	morning, lunch := hours.GetSchedules()[0], hours.GetSchedules()[1]
+
+	newFoodOrder := func(quantity int) []domains.OrderFoodItem {
+		foodItem, _ := domains.NewOrderFoodItem(food.GetId(), food.GetName(), food.GetPrice(), quantity, []domains.OptionItemInfo{})
+		return []domains.OrderFoodItem{*foodItem}
+	}
+	// wednesday morning and lunch
+	morningOrder, _ := domains.NewOrderInfoForOrm("h1", "user1", "ユーザー1", "user1@hoge.com", "111111111", "", parseDateTime("2051/01/11 08:00"), parseDateTime("2051/01/01 10:00"), []domains.OrderStockItem{}, newFoodOrder(3), false, "", "")
+	orderRepo.Create(morningOrder)
+	lunchOrder, _ := domains.NewOrderInfoForOrm("h2", "user1", "ユーザー1", "user1@hoge.com", "111111111", "", parseDateTime("2051/01/11 12:00"), parseDateTime("2051/01/01 10:00"), []domains.OrderStockItem{}, newFoodOrder(2), false, "", "")
+	orderRepo.Create(lunchOrder)
+	quota, _ := item.NewFoodQuota(food.GetId(), "2051/01/11", lunch.GetId(), 3)
+	quotaRepo.Save(quota)
+
+	checker := domains.NewFoodItemRemainChecker(orderRepo, foodRepo, quotaRepo, busRepo, spBusRepo, spHolidayRepo)
+	// only orders of lunch are counted
+	err := checker.CheckRemain("2051/01/11 13:00", newFoodOrder(1))
+	assert.NoError(t, err)
+	err = checker.CheckRemain("2051/01/11 13:00", newFoodOrder(2))
+	assert.IsType(t, common.NewValidationError("", ""), err)
+	// morning is limited only by the day
+	err = checker.CheckRemain("2051/01/11 08:30", newFoodOrder(food.GetMaxOrderPerDay()-5))
+	assert.NoError(t, err)
+	err = checker.CheckRemain("2051/01/11 08:30", newFoodOrder(food.GetMaxOrderPerDay()-4))
+	assert.Error(t, err)
+
+	// sold out in the business hour
+	quota, _ = item.NewFoodQuota(food.GetId(), "2051/01/11", morning.GetId(), 0)
+	quotaRepo.Save(quota)
+	err = checker.CheckRemain("2051/01/11 08:30", newFoodOrder(1))
+	assert.Error(t, err)
+	// quota of other date is not used
+	err = checker.CheckRemain("2051/01/18 08:30", newFoodOrder(1))
+	assert.NoError(t, err)
+}
+
+func TestFoodItemRemainChecker_CheckRemain_OvernightHourQuota(t *testing.T) {
+	// memory is replaced at reset, so repositories are created after reset
+	memory.NewOrderInfoMemoryRepository().Reset()
+	orderRepo := memory.NewOrderInfoMemoryRepository()
+	memory.NewBusinessHoursMemoryRepository().Reset()
+	busRepo := memory.NewBusinessHoursMemoryRepository()
+	memory.NewSpecialBusinessHourMemoryRepository().Reset()
+	spBusRepo := memory.NewSpecialBusinessHourMemoryRepository()
+	memory.NewSpecialHolidayMemoryRepository().Reset()
+	spHolidayRepo := memory.NewSpecialHolidayMemoryRepository()
+	foodRepo := memory.NewFoodItemMemoryRepository()
+	foodRepo.Reset()
+	quotaRepo := memory.NewFoodQuotaMemoryRepository()
+	foods, _ := foodRepo.FindAll()
+	food := foods[0]
+	hours, _ := busRepo.Fetch()
+	dinner := hours.GetSchedules()[2]
+
+	newFoodOrder := func(quantity int) []domains.OrderFoodItem {
+		foodItem, _ := domains.NewOrderFoodItem(food.GetId(), food.GetName(), food.GetPrice(), quantity, []domains.OptionItemInfo{})
+		return []domains.OrderFoodItem{*foodItem}
+	}
+	// wednesday night until 2:00 of thursday
+	late, _ := store.NewSpecialBusinessHour("late", "2051/01/11", "22:00", "02:00", dinner.GetId(), 1)
+	spBusRepo.Create(late)
+	before, _ := domains.NewOrderInfoForOrm("n1", "user1", "ユーザー1", "user1@hoge.com", "111111111", "", parseDateTime("2051/01/11 22:30"), parseDateTime("2051/01/01 10:00"), []domains.OrderStockItem{}, newFoodOrder(1), false, "", "")
+	orderRepo.Create(before)
+	after, _ := domains.NewOrderInfoForOrm("n2", "user1", "ユーザー1", "user1@hoge.com", "111111111", "", parseDateTime("2051/01/12 00:30"), parseDateTime("2051/01/01 10:00"), []domains.OrderStockItem{}, newFoodOrder(1), false, "", "")
+	orderRepo.Create(after)
+	// quota is set on the start date of the hour
+	quota, _ := item.NewFoodQuota(food.GetId(), "2051/01/11", dinner.GetId(), 3)
+	quotaRepo.Save(quota)
+
+	checker := domains.NewFoodItemRemainChecker(orderRepo, foodRepo, quotaRepo, busRepo, spBusRepo, spHolidayRepo)
+	// orders before and after midnight are counted in both dates
+	err := checker.CheckRemain("2051/01/12 01:30", newFoodOrder(1))
+	assert.NoError(t, err)
+	err = checker.CheckRemain("2051/01/12 01:30", newFoodOrder(2))
+	assert.IsType(t, common.NewValidationError("", ""), err)
+	err = checker.CheckRemain("2051/01/11 23:00", newFoodOrder(1))
+	assert.NoError(t, err)
+	err = checker.CheckRemain("2051/01/11 23:00", newFoodOrder(2))
+	assert.IsType(t, common.NewValidationError("", ""), err)
+
+	// amended order itself is not counted
+	amended, _ := domains.NewOrderInfoForOrm("n2", "user1", "ユーザー1", "user1@hoge.com", "111111111", "", parseDateTime("2051/01/12 00:30"), parseDateTime("2051/01/01 10:00"), []domains.OrderStockItem{}, newFoodOrder(2), false, "", "")
+	err = checker.CheckAmendedRemain(amended)
+	assert.NoError(t, err)
+}
+
+func TestOrderLimitChecker_CheckLimit(t *testing.T) {
+	// memory is replaced at reset, so repositories are created after reset
+	memory.NewOrderInfoMemoryRepository().Reset()
+	orderRepo := memory.NewOrderInfoMemoryRepository()
+	limitRepo := memory.NewOrderLimitMemoryRepository()
+	memory.NewBusinessHoursMemoryRepository().Reset()
+	busRepo := memory.NewBusinessHoursMemoryRepository()
+	memory.NewSpecialBusinessHourMemoryRepository().Reset()
+	spBusRepo := memory.NewSpecialBusinessHourMemoryRepository()
+	memory.NewSpecialHolidayMemoryRepository().Reset()
+	spHolidayRepo := memory.NewSpecialHolidayMemoryRepository()
+	clock := common.NewFakeClock(time.Date(2052, 12, 1, 10, 0, 0, 0, common.GetStoreLocation()))
+
+	newOrder := func(id, pickupDateTime string) *domains.OrderInfo {
+		order, _ := domains.NewOrderInfoForOrm(id, "user1", "ユーザー1", "user1@hoge.com", "111111111", "", parseDateTime(pickupDateTime), parseDateTime("2052/12/01 10:00"), []domains.OrderStockItem{}, []domains.OrderFoodItem{}, false, "", "")
+		return order
+	}
+	// tuesday morning and wednesday lunch
+	orderRepo.Create(newOrder("o1", "2052/12/10 09:00"))
+	orderRepo.Create(newOrder("o2", "2052/12/11 12:00"))
+	// other user and canceled orders are not counted
+	other, _ := domains.NewOrderInfoForOrm("o3", "user2", "ユーザー2", "user2@hoge.com", "222222222", "", parseDateTime("2052/12/10 09:00"), parseDateTime("2052/12/01 10:00"), []domains.OrderStockItem{}, []domains.OrderFoodItem{}, false, "", "")
+	orderRepo.Create(other)
+	canceled, _ := domains.NewOrderInfoForOrm("o4", "user1", "ユーザー1", "user1@hoge.com", "111111111", "", parseDateTime("2052/12/10 09:00"), parseDateTime("2052/12/01 10:00"), []domains.OrderStockItem{}, []domains.OrderFoodItem{}, true, "", "")
+	orderRepo.Create(canceled)
+
+	inputs := []struct {
+		name    string
+		limit   *domains.OrderLimit
+		order   *domains.OrderInfo
+		wantErr bool
+	}{
+		{name: "default allows only one", limit: nil, order: newOrder("new", "2052/12/13 12:00"), wantErr: true},
+		{name: "no limit", limit: newLimit(0, 0, 0), order: newOrder("new", "2052/12/10 12:00"), wantErr: false},
+		{name: "over active per user", limit: newLimit(2, 0, 0), order: newOrder("new", "2052/12/13 12:00"), wantErr: true},
+		{name: "within active per user", limit: newLimit(3, 0, 0), order: newOrder("new", "2052/12/13 12:00"), wantErr: false},
+		{name: "over per pickup date", limit: newLimit(0, 1, 0), order: newOrder("new", "2052/12/10 12:00"), wantErr: true},
+		{name: "within per pickup date", limit: newLimit(0, 1, 0), order: newOrder("new", "2052/12/13 12:00"), wantErr: false},
+		{name: "other slot of same date", limit: newLimit(0, 0, 1), order: newOrder("new", "2052/12/10 12:00"), wantErr: false},
+		{name: "over per hour slot", limit: newLimit(0, 0, 1), order: newOrder("new", "2052/12/10 08:00"), wantErr: true},
+		{name: "amended order itself is not counted", limit: newLimit(2, 1, 1), order: newOrder("o1", "2052/12/10 08:00"), wantErr: false},
+	}
+	for _, tt := range inputs {
+		limitRepo.Reset()
+		if tt.limit != nil {
+			limitRepo.Create(tt.limit)
+		}
+		checker := domains.NewOrderLimitChecker(orderRepo, limitRepo, busRepo, spBusRepo, spHolidayRepo, clock)
+		err := checker.CheckLimit(tt.order)
+		if tt.wantErr {
+			assert.Error(t, err, tt.name)
+			assert.IsType(t, common.NewValidationError("", ""), err, tt.name)
+		} else {
+			assert.NoError(t, err, tt.name)
+		}
+	}
+}
+
+func newLimit(maxActivePerUser, maxPerPickupDate, maxPerHourSlot uint) *domains.OrderLimit {
+	limit, _ := domains.NewOrderLimit(maxActivePerUser, maxPerPickupDate, maxPerHourSlot)
+	return limit
+}
+
+func TestIngredientConsumer_RestoreConsumedQuantity(t *testing.T) {
+	ingredientRepo := memory.NewIngredientMemoryRepository()
+	recipeRepo := memory.NewRecipeMemoryRepository()
+	usageRepo := memory.NewIngredientUsageMemoryRepository()
+	rice, _ := item.NewIngredient("rice", "g", 1000)
+	ingredientRepo.Create(rice)
+	saveRecipe := func(quantity int) {
+		line, _ := item.NewRecipeIngredient(rice.GetId(), quantity)
+		recipe, _ := item.NewRecipe("riceFood", "food", []item.RecipeIngredient{*line})
+		recipeRepo.Save(recipe)
+	}
+	used := func(date string) int {
+		usages, _ := usageRepo.FindByDate(date)
+		for _, usage := range usages {
+			if usage.GetIngredientId() == rice.GetId() {
+				return usage.GetUsed()
+			}
+		}
+		return 0
+	}
+	newOrder := func(pickupDateTime string, quantity int) *domains.OrderInfo {
+		foodItem, _ := domains.NewOrderFoodItem("riceFood", "rice food", 100, quantity, []domains.OptionItemInfo{})
+		order, _ := domains.NewOrderInfoForOrm("r1", "user1", "ユーザー1", "user1@hoge.com", "111111111", "", parseDateTime(pickupDateTime), parseDateTime("2051/01/01 10:00"), []domains.OrderStockItem{}, []domains.OrderFoodItem{*foodItem}, false, "", "")
+		return order
+	}
+	consumer := domains.NewIngredientConsumer(ingredientRepo, recipeRepo, usageRepo)
+
+	// recipe is changed after ordered
+	saveRecipe(150)
+	order := newOrder("2051/01/10 12:00", 2)
+	assert.NoError(t, consumer.ConsumeIngredients(order))
+	assert.Equal(t, 300, used("2051/01/10"))
+	assert.Equal(t, map[string]int{rice.GetId(): 300}, order.GetConsumedIngredients())
+	saveRecipe(100)
+	assert.NoError(t, consumer.RestoreCanceledIngredients(order))
+	assert.Equal(t, 0, used("2051/01/10"))
+
+	// amended order restores quantity before amended and consumes by current recipe
+	saveRecipe(150)
+	order = newOrder("2051/01/11 12:00", 2)
+	assert.NoError(t, consumer.ConsumeIngredients(order))
+	revision, _ := domains.NewOrderRevision(order, 1, time.Date(2051, 1, 2, 10, 0, 0, 0, common.GetStoreLocation()))
+	saveRecipe(100)
+	amended := newOrder("2051/01/12 12:00", 3)
+	amended.SetConsumedIngredients(order.GetConsumedIngredients())
+	assert.NoError(t, consumer.ApplyAmendedIngredients(revision, amended))
+	assert.Equal(t, 0, used("2051/01/11"))
+	assert.Equal(t, 300, used("2051/01/12"))
+	assert.Equal(t, map[string]int{rice.GetId(): 300}, amended.GetConsumedIngredients())
+
+	// order without record uses current recipe
+	legacy := newOrder("2051/01/12 12:00", 1)
+	assert.NoError(t, consumer.RestoreCanceledIngredients(legacy))
+	assert.Equal(t, 200, used("2051/01/12"))
+}
+
+// fixture date time in store location
+func parseDateTime(value string) time.Time {
+	t, _ := common.ConvertStrToDateTime(value)
+	return *t
+}
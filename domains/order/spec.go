@@ -1,5 +1,12 @@
 package order
 
+import (
+	"chico/takeout/common"
+	"chico/takeout/domains/item"
+	"chico/takeout/domains/store"
+	"fmt"
+)
+
 type FoodItemRemainQuantitySpecification struct {
 	qMap *quantityMap
 }
@@ -24,6 +31,53 @@ func (s *FoodItemRemainQuantitySpecification) IsOverRemain(id string, quantity,
 	return current+quantity > maxOrderPerDay
 }
 
+// food item is orderable only in its business hours (special hours are mapped to its business hour)
+// and on its allow dates if specified
+type FoodItemScheduleSpecification struct {
+	foods []item.FoodItem
+}
+
+func NewFoodItemScheduleSpecification(foods []item.FoodItem) *FoodItemScheduleSpecification {
+	return &FoodItemScheduleSpecification{foods: foods}
+}
+
+// errors of all items are returned together
+func (f *FoodItemScheduleSpecification) Check(foodOrders []OrderFoodItem, shift *store.ShiftInfo) error {
+	errs := common.NewValidationErrors()
+	for _, foodOrder := range foodOrders {
+		for _, food := range f.foods {
+			if !foodOrder.HasSameId(food.GetId()) {
+				continue
+			}
+			name := fmt.Sprintf("foodItems.%s", food.GetId())
+			if !food.HasScheduleId(shift.HourTypeId) {
+				errs.Add(name, fmt.Sprintf("%s is not served in the business hour of pickup time", food.GetName()))
+			} else if !f.isAllowedDate(&food, shift) {
+				errs.Add(name, fmt.Sprintf("%s is not served on the pickup date", food.GetName()))
+			}
+			break
+		}
+	}
+	if errs.HasError() {
+		return errs
+	}
+	return nil
+}
+
+// date of shift start is used for shift over midnight
+func (f *FoodItemScheduleSpecification) isAllowedDate(food *item.FoodItem, shift *store.ShiftInfo) bool {
+	dates := food.GetAllowDatesAsTime()
+	if len(dates) == 0 {
+		return true
+	}
+	for _, date := range dates {
+		if common.DateEqual(date, shift.Start) {
+			return true
+		}
+	}
+	return false
+}
+
 type quantityMap struct {
 	maps map[string]int
 }
@@ -0,0 +1,41 @@
+package order
+
+import (
+	"chico/takeout/common"
+	"chico/takeout/domains/item"
+	"chico/takeout/domains/store"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFoodItemScheduleSpecification_Check(t *testing.T) {
+	loc := common.GetStoreLocation()
+	lunch, _ := item.NewFoodItem("lunch", "lunch", 1, 5, 10, 100, "kind1", []string{"lunchId"}, true, "", []string{}, 0)
+	limited, _ := item.NewFoodItem("limited", "limited", 1, 5, 10, 100, "kind1", []string{"lunchId", "dinnerId"}, true, "", []string{"2050/12/10"}, 0)
+	spec := NewFoodItemScheduleSpecification([]item.FoodItem{*lunch, *limited})
+	lunchOrder, _ := NewOrderFoodItem(lunch.GetId(), "lunch", 100, 1, []OptionItemInfo{})
+	limitedOrder, _ := NewOrderFoodItem(limited.GetId(), "limited", 100, 1, []OptionItemInfo{})
+	orders := []OrderFoodItem{*lunchOrder, *limitedOrder}
+
+	inputs := []struct {
+		name     string
+		shift    store.ShiftInfo
+		errCount int
+	}{
+		{name: "lunch of allowed date", shift: store.ShiftInfo{HourTypeId: "lunchId", Start: time.Date(2050, 12, 10, 11, 0, 0, 0, loc)}},
+		{name: "dinner", shift: store.ShiftInfo{HourTypeId: "dinnerId", Start: time.Date(2050, 12, 10, 17, 0, 0, 0, loc)}, errCount: 1},
+		{name: "not allowed date", shift: store.ShiftInfo{HourTypeId: "lunchId", Start: time.Date(2050, 12, 11, 11, 0, 0, 0, loc)}, errCount: 1},
+		{name: "morning", shift: store.ShiftInfo{HourTypeId: "morningId", Start: time.Date(2050, 12, 10, 8, 0, 0, 0, loc)}, errCount: 2},
+	}
+	for _, tt := range inputs {
+		err := spec.Check(orders, &tt.shift)
+		if tt.errCount == 0 {
+			assert.NoError(t, err, tt.name)
+			continue
+		}
+		assert.IsType(t, common.NewValidationErrors(), err, tt.name)
+		assert.Equal(t, tt.errCount, len(err.(*common.ValidationErrors).GetErrors()), tt.name)
+	}
+}
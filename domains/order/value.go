@@ -47,9 +47,9 @@ func validateQuantity(value int) error {
 	return nil
 }
 
+// date time of store carried as zoned time. string is formatted only when it is output
 type DateTime struct {
-	value string
-	time  time.Time
+	time time.Time
 }
 
 func NewDateTime(value string) (*DateTime, error) {
@@ -57,55 +57,46 @@ func NewDateTime(value string) (*DateTime, error) {
 	if err != nil {
 		return nil, common.NewValidationError("date", fmt.Sprintf("can not convert dateTime:%s", value))
 	}
-	return &DateTime{value: value, time: *t}, nil
+	return &DateTime{time: *t}, nil
 }
 
-func (d *DateTime) GetAsDate() string {
-	val, _ := common.ConvertDateTimeStrToDateStr(d.value)
-	return val
+// truncated until minutes in store location
+func NewDateTimeFromTime(value time.Time) *DateTime {
+	return &DateTime{time: *common.GetDateUntilMinutes(value)}
 }
 
-func (d *DateTime) GetDateTime() time.Time {
-	return d.time
+func (d *DateTime) GetAsDate() string {
+	return common.ConvertTimeToDateStr(d.time)
 }
 
-// for testing
-var now = time.Now
-
-func MockNow(mock func() time.Time) {
-	now = mock
+func (d *DateTime) GetAsDateTime() string {
+	return common.ConvertTimeToDateTimeStr(d.time)
 }
-func ResetNow() {
-	now = time.Now
+
+func (d *DateTime) GetDateTime() time.Time {
+	return d.time
 }
 
 type OrderDateTime struct {
 	DateTime
 }
 
-func NewOrderDateTime() (*OrderDateTime, error) {
-	str := common.ConvertTimeToDateTimeStr(now())
-	item, err := NewDateTime(str)
-	if err != nil {
-		return nil, err
-	}
-	return &OrderDateTime{DateTime: *item}, nil
+func NewOrderDateTime(now time.Time) *OrderDateTime {
+	return &OrderDateTime{DateTime: *NewDateTimeFromTime(now)}
 }
 
 type PickupDateTime struct {
 	DateTime
 }
 
-func NewPickupDateTime(value string) (*PickupDateTime, error) {
+func NewPickupDateTime(value string, now time.Time) (*PickupDateTime, error) {
 	item, err := NewDateTime(value)
 	if err != nil {
 		return nil, err
 	}
-	date, _ := common.ConvertStrToDateTime(value)
-	// pick up time should be future from now + offset
-	now := now()
-	if !common.StartIsBeforeEnd(now, *date, common.OffsetMinutesOrderableCheckBefore) {
-		return nil, common.NewValidationError("PickupDateTime", fmt.Sprintf("not allowed time(%s). limit: now(%s) + minutes:(%v)", value, now, common.OffsetMinutesOrderableBefore))
+	// pick up time should be future. lead time is checked by OrderTimePolicy
+	if !item.GetDateTime().After(now) {
+		return nil, common.NewValidationError("PickupDateTime", fmt.Sprintf("not allowed past time(%s). now(%s)", value, common.ConvertTimeToDateTimeStr(now)))
 	}
 	return &PickupDateTime{DateTime: *item}, nil
 }
@@ -123,6 +114,16 @@ func NewMemo(value string, maxLength int) (*Memo, error) {
 	return &Memo{StringValue: shared.NewStringValue(value)}, nil
 }
 
+// cancel reason shares rule with memo. empty is allowed
+func NewCancelReason(value string) (*Memo, error) {
+	validator := validator.NewAllowEmptyStingLength("CancelReason", CancelReasonMaxLength)
+	if err := validator.Validate(value); err != nil {
+		return nil, err
+	}
+
+	return &Memo{StringValue: shared.NewStringValue(value)}, nil
+}
+
 type Email struct {
 	shared.StringValue
 }
@@ -136,6 +137,14 @@ func NewEmail(value string) (*Email, error) {
 	return &Email{StringValue: shared.NewStringValue(value)}, nil
 }
 
+// email is optional for order entered by admin (ex:phone order)
+func NewOptionalEmail(value string) (*Email, error) {
+	if value == "" {
+		return &Email{StringValue: shared.NewStringValue(value)}, nil
+	}
+	return NewEmail(value)
+}
+
 type TelNo struct {
 	shared.StringValue
 }
@@ -161,3 +170,36 @@ func NewUserName(value string, maxLength int) (*UserName, error) {
 
 	return &UserName{StringValue: shared.NewStringValue(value)}, nil
 }
+
+// count of orders allowed by limit. 0 means no limit
+type OrderLimitCount struct {
+	shared.UintValue
+}
+
+func NewOrderLimitCount(name string, value uint) (*OrderLimitCount, error) {
+	validator := validator.NewRangeInteger(name, 0, OrderLimitCountMaxValue)
+	if err := validator.Validate(int(value)); err != nil {
+		return nil, err
+	}
+
+	return &OrderLimitCount{UintValue: shared.NewUintValue(value)}, nil
+}
+
+const (
+	OrderSourceWeb    = "web"
+	OrderSourcePhone  = "phone"
+	OrderSourceWalkIn = "walk_in"
+)
+
+// channel which order comes from
+type OrderSource struct {
+	shared.StringValue
+}
+
+func NewOrderSource(value string) (*OrderSource, error) {
+	switch value {
+	case OrderSourceWeb, OrderSourcePhone, OrderSourceWalkIn:
+		return &OrderSource{StringValue: shared.NewStringValue(value)}, nil
+	}
+	return nil, common.NewValidationError("Source", fmt.Sprintf("not supported source:%s", value))
+}
@@ -0,0 +1,29 @@
+package ratelimit
+
+import (
+	"chico/takeout/common"
+)
+
+type RateLimiter struct {
+	repo   TokenBucketRepository
+	prefix string
+	limit  Limit
+	clock  common.Clock
+}
+
+// prefix separates buckets of each limiter in the same repository
+func NewRateLimiter(repo TokenBucketRepository, prefix string, limit Limit, clock common.Clock) *RateLimiter {
+	return &RateLimiter{
+		repo:   repo,
+		prefix: prefix,
+		limit:  limit,
+		clock:  clock,
+	}
+}
+
+func (r *RateLimiter) Allow(key string) (bool, error) {
+	if r.limit.IsUnlimited() {
+		return true, nil
+	}
+	return r.repo.Take(r.prefix+":"+key, r.limit, r.clock.Now())
+}
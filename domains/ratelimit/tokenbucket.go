@@ -0,0 +1,103 @@
+package ratelimit
+
+import (
+	"math"
+	"time"
+
+	"chico/takeout/common"
+)
+
+type TokenBucketRepository interface {
+	// refill and consume one token of the key atomically
+	Take(key string, limit Limit, now time.Time) (bool, error)
+}
+
+type Limit struct {
+	ratePerMinute float64
+	burst         int
+}
+
+// zero rate or burst means unlimited
+func NewLimit(ratePerMinute float64, burst int) (*Limit, error) {
+	if ratePerMinute < 0 {
+		return nil, common.NewValidationError("ratePerMinute", "should be positive")
+	}
+	if burst < 0 {
+		return nil, common.NewValidationError("burst", "should be positive")
+	}
+	return &Limit{ratePerMinute: ratePerMinute, burst: burst}, nil
+}
+
+func (l *Limit) IsUnlimited() bool {
+	return l.ratePerMinute == 0 || l.burst == 0
+}
+
+func (l *Limit) GetRatePerMinute() float64 {
+	return l.ratePerMinute
+}
+
+func (l *Limit) GetBurst() int {
+	return l.burst
+}
+
+type TokenBucket struct {
+	key        string
+	tokens     float64
+	refilledAt time.Time
+}
+
+// new bucket is full
+func NewTokenBucket(key string, limit Limit, now time.Time) *TokenBucket {
+	return &TokenBucket{
+		key:        key,
+		tokens:     float64(limit.burst),
+		refilledAt: now,
+	}
+}
+
+func NewTokenBucketForOrm(key string, tokens float64, refilledAt time.Time) *TokenBucket {
+	return &TokenBucket{
+		key:        key,
+		tokens:     tokens,
+		refilledAt: refilledAt,
+	}
+}
+
+func (t *TokenBucket) GetKey() string {
+	return t.key
+}
+
+func (t *TokenBucket) GetTokens() float64 {
+	return t.tokens
+}
+
+func (t *TokenBucket) GetRefilledAt() time.Time {
+	return t.refilledAt
+}
+
+// return false if no token is left
+func (t *TokenBucket) Take(limit Limit, now time.Time) bool {
+	t.refill(limit, now)
+	if t.tokens < 1 {
+		return false
+	}
+	t.tokens -= 1
+	return true
+}
+
+// full bucket has no state to keep
+func (t *TokenBucket) IsFull(limit Limit, now time.Time) bool {
+	copied := *t
+	copied.refill(limit, now)
+	return copied.tokens >= float64(limit.burst)
+}
+
+func (t *TokenBucket) refill(limit Limit, now time.Time) {
+	elapsed := now.Sub(t.refilledAt).Minutes()
+	// clock may go back
+	if elapsed <= 0 {
+		return
+	}
+	t.tokens = math.Min(float64(limit.burst), t.tokens+elapsed*limit.ratePerMinute)
+	t.refilledAt = now
+}
@@ -0,0 +1,74 @@
+package ratelimit_test
+
+import (
+	"testing"
+	"time"
+
+	"chico/takeout/common"
+	"chico/takeout/domains/ratelimit"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewLimit(t *testing.T) {
+	inputs := []struct {
+		name             string
+		ratePerMinute    float64
+		burst            int
+		unlimited        bool
+		hasValidationErr bool
+	}{
+		{name: "normal", ratePerMinute: 3, burst: 5, unlimited: false},
+		{name: "zero rate is unlimited", ratePerMinute: 0, burst: 5, unlimited: true},
+		{name: "zero burst is unlimited", ratePerMinute: 3, burst: 0, unlimited: true},
+		{name: "negative rate", ratePerMinute: -1, burst: 5, hasValidationErr: true},
+		{name: "negative burst", ratePerMinute: 3, burst: -1, hasValidationErr: true},
+	}
+	for _, tt := range inputs {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ratelimit.NewLimit(tt.ratePerMinute, tt.burst)
+			if tt.hasValidationErr {
+				assert.Error(t, err)
+				assert.IsType(t, common.NewValidationError("", ""), err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.unlimited, got.IsUnlimited())
+		})
+	}
+}
+
+func TestTokenBucket_Take(t *testing.T) {
+	limit, _ := ratelimit.NewLimit(2, 3)
+	now := time.Date(2050, 1, 1, 10, 0, 0, 0, time.UTC)
+	bucket := ratelimit.NewTokenBucket("user1", *limit, now)
+
+	// burst size is available at first
+	for i := 0; i < 3; i++ {
+		assert.True(t, bucket.Take(*limit, now), "take %d", i)
+	}
+	assert.False(t, bucket.Take(*limit, now))
+
+	// 2 tokens per minute => 1 token after 30 seconds
+	assert.False(t, bucket.Take(*limit, now.Add(29*time.Second)))
+	assert.True(t, bucket.Take(*limit, now.Add(30*time.Second)))
+	assert.False(t, bucket.Take(*limit, now.Add(30*time.Second)))
+
+	// refill never exceeds burst
+	later := now.Add(time.Hour)
+	assert.True(t, bucket.IsFull(*limit, later))
+	for i := 0; i < 3; i++ {
+		assert.True(t, bucket.Take(*limit, later), "take %d", i)
+	}
+	assert.False(t, bucket.Take(*limit, later))
+}
+
+func TestTokenBucket_Take_ClockGoesBack(t *testing.T) {
+	limit, _ := ratelimit.NewLimit(60, 1)
+	now := time.Date(2050, 1, 1, 10, 0, 0, 0, time.UTC)
+	bucket := ratelimit.NewTokenBucketForOrm("ip1", 0, now)
+
+	assert.False(t, bucket.Take(*limit, now.Add(-time.Minute)))
+	assert.Equal(t, now, bucket.GetRefilledAt())
+	assert.True(t, bucket.Take(*limit, now.Add(time.Second)))
+}
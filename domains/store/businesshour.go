@@ -10,14 +10,15 @@ import (
 )
 
 const (
-	BusinessHoursMaxSchedules = 3
-	DefaultHourOffset         = 3
+	DefaultHourOffset = 3
 )
 
 type BusinessHoursRepository interface {
 	Fetch() (*BusinessHours, error)
 	Update(target *BusinessHours) error
 	Create(target *BusinessHours) error
+	// removes the schedule from food items and deletes its special business hours at once
+	RemoveSchedule(target *BusinessHours, removedId string) error
 }
 
 type BusinessHours struct {
@@ -85,6 +86,26 @@ func (b *BusinessHours) IsInBusiness(targetDateTime time.Time) bool {
 	return false
 }
 
+// check the schedules started at the date
+func (b *BusinessHours) IsInBusinessOf(date, targetDateTime time.Time) bool {
+	return b.FindScheduleOf(date, targetDateTime) != nil
+}
+
+// find enabled schedule which covers target in the shift started at the date. nil if not found
+func (b *BusinessHours) FindScheduleOf(date, targetDateTime time.Time) *BusinessHour {
+	for _, bs := range b.schedules {
+		if !bs.enabled {
+			continue
+		}
+		if bs.IsInScheduleOf(date, targetDateTime) {
+			// return copy for immutable
+			found := bs
+			return &found
+		}
+	}
+	return nil
+}
+
 func (b *BusinessHours) FindByWeekday(weekday int) []BusinessHour {
 	result := []BusinessHour{}
 	for _, bs := range b.schedules {
@@ -100,23 +121,42 @@ func (b *BusinessHours) FindByWeekday(weekday int) []BusinessHour {
 	return result
 }
 
-// currently add is not needed.
-// func (b *BusinessHours) Add(name, start, end string, weekdays []Weekday) error {
-// 	hour, err := NewBusinessHour(name, start, end, weekdays)
-// 	if err != nil {
-// 		return err
-// 	}
-// 	tmp := append([]BusinessHour{}, b.schedules...)
-// 	tmp = append(tmp, *hour)
-// 	// check duplicate
-// 	_, err = NewBusinessHours(tmp)
-// 	if err != nil {
-// 		return err
-// 	}
+func (b *BusinessHours) Add(name, start, end string, weekdays []Weekday, hourOffset uint) (*BusinessHours, string, error) {
+	hour, err := NewBusinessHour(name, start, end, weekdays, hourOffset)
+	if err != nil {
+		return nil, "", err
+	}
+	selfCopy, err := b.Copy()
+	if err != nil {
+		return nil, "", fmt.Errorf("unexpected error at copy:%s", err)
+	}
+	selfCopy.schedules = append(selfCopy.schedules, *hour)
+	// check overWrap
+	err = selfCopy.validateSchedules()
+	if err != nil {
+		return nil, "", err
+	}
+	return selfCopy, hour.GetId(), nil
+}
 
-// 	b.schedules = append(b.schedules, *hour)
-// 	return nil
-// }
+func (b *BusinessHours) Remove(id string) (*BusinessHours, error) {
+	selfCopy, err := b.Copy()
+	if err != nil {
+		return nil, fmt.Errorf("unexpected error at copy:%s", err)
+	}
+
+	index, target := selfCopy.findSchedule(id)
+	if target == nil {
+		return nil, common.NewNotFoundError("id")
+	}
+	selfCopy.schedules = append(selfCopy.schedules[:index], selfCopy.schedules[index+1:]...)
+	// at least 1 schedule is needed
+	err = selfCopy.validateSchedules()
+	if err != nil {
+		return nil, err
+	}
+	return selfCopy, nil
+}
 
 func (b *BusinessHours) Update(id, name, start, end string, weekdays []Weekday, hourOffset uint) (*BusinessHours, error) {
 	selfCopy, err := b.Copy()
@@ -140,6 +180,28 @@ func (b *BusinessHours) Update(id, name, start, end string, weekdays []Weekday,
 	return selfCopy, nil
 }
 
+func (b *BusinessHours) UpdateWeekdayShifts(id string, shifts []WeekdayShift) (*BusinessHours, error) {
+	selfCopy, err := b.Copy()
+	if err != nil {
+		return nil, fmt.Errorf("unexpected error at copy:%s", err)
+	}
+
+	_, target := selfCopy.findSchedule(id)
+	if target == nil {
+		return nil, common.NewNotFoundError("id")
+	}
+	err = target.SetWeekdayShifts(shifts)
+	if err != nil {
+		return nil, err
+	}
+	// check overWrap
+	err = selfCopy.validateSchedules()
+	if err != nil {
+		return nil, err
+	}
+	return selfCopy, nil
+}
+
 func (b *BusinessHours) UpdateEnabled(id string, enabled bool) (*BusinessHours, error) {
 	selfCopy, err := b.Copy()
 	if err != nil {
@@ -168,10 +230,6 @@ func (b *BusinessHours) validateSchedules() error {
 	if len(b.schedules) == 0 {
 		return common.NewValidationError("schedules", "is empty or nil.")
 	}
-
-	if len(b.schedules) > BusinessHoursMaxSchedules {
-		return common.NewValidationError("schedules", fmt.Sprintf("MaxLength:%d", BusinessHoursMaxSchedules))
-	}
 	// check duplicate
 	return b.validateDuplicate()
 }
@@ -210,6 +268,8 @@ type BusinessHour struct {
 	weekdays   []Weekday
 	enabled    bool
 	hourOffset HourOffset
+	// overrides shift at the weekday
+	weekdayShifts []WeekdayShift
 }
 
 func NewBusinessHour(name, start, end string, weekdays []Weekday, hourOffset uint) (*BusinessHour, error) {
@@ -239,6 +299,7 @@ func (b *BusinessHour) Copy() *BusinessHour {
 	businessHour, _ := NewBusinessHour(b.name.GetValue(), b.shift.start, b.shift.end, b.weekdays, b.hourOffset.GetValue())
 	businessHour.id = b.id
 	businessHour.enabled = b.enabled
+	businessHour.weekdayShifts = append([]WeekdayShift{}, b.weekdayShifts...)
 	return businessHour
 }
 
@@ -275,6 +336,48 @@ func (b *BusinessHour) Set(name, start, end string, weekdays []Weekday, hourOffs
 	b.weekdays = weekdays
 	b.hourOffset = *hOffset
 
+	// shift of removed weekday is not needed
+	shifts := []WeekdayShift{}
+	for _, ws := range b.weekdayShifts {
+		if b.HasWeekday(ws.weekday) {
+			shifts = append(shifts, ws)
+		}
+	}
+	b.weekdayShifts = shifts
+
+	return b.validateSelfOverlap()
+}
+
+func (b *BusinessHour) SetWeekdayShifts(shifts []WeekdayShift) error {
+	encountered := map[Weekday]bool{}
+	for _, ws := range shifts {
+		if !b.HasWeekday(ws.weekday) {
+			return common.NewValidationError("weekdayShifts", fmt.Sprintf("weekday(%d) is not in weekdays", ws.weekday))
+		}
+		if encountered[ws.weekday] {
+			return common.NewValidationError("weekdayShifts", "duplicated weekday exists")
+		}
+		encountered[ws.weekday] = true
+	}
+	old := b.weekdayShifts
+	b.weekdayShifts = append([]WeekdayShift{}, shifts...)
+	if err := b.validateSelfOverlap(); err != nil {
+		b.weekdayShifts = old
+		return err
+	}
+	return nil
+}
+
+// shift crossing midnight can overlap with next weekday's shift
+func (b *BusinessHour) validateSelfOverlap() error {
+	periods := b.weeklyPeriods()
+	for i := range periods {
+		for j := i + 1; j < len(periods); j++ {
+			if isOverlapInWeek(periods[i], periods[j]) {
+				return common.NewValidationError("business hour", fmt.Sprintf("%s has overlapped time between weekdays", b.name.GetValue()))
+			}
+		}
+	}
 	return nil
 }
 
@@ -308,33 +411,72 @@ func validateDuplicatedWeekdays(weekdays []Weekday) bool {
 }
 
 func (b *BusinessHour) IsOverlap(other BusinessHour) bool {
-	isOverlap := false
-	for _, weekday := range b.weekdays {
-		for _, targetWeekday := range other.weekdays {
-			if weekday == targetWeekday {
-				// check time in overlap
-				if b.shift.IsOverlap(other.shift) {
-					isOverlap = true
-					break
-				}
+	for _, period := range b.weeklyPeriods() {
+		for _, otherPeriod := range other.weeklyPeriods() {
+			if isOverlapInWeek(period, otherPeriod) {
+				return true
 			}
 		}
 	}
-	return isOverlap
+	return false
+}
+
+// start and end minutes from sunday 00:00 for each weekday
+func (b *BusinessHour) weeklyPeriods() [][2]int {
+	periods := [][2]int{}
+	for _, weekday := range b.weekdays {
+		shift := b.GetShift(weekday)
+		start, end := shift.toMinutes()
+		base := int(weekday) * minutesPerDay
+		periods = append(periods, [2]int{base + start, base + end})
+	}
+	return periods
+}
+
+// saturday night shift can be overlapped with sunday morning
+func isOverlapInWeek(a, b [2]int) bool {
+	for _, offset := range []int{-minutesPerWeek, 0, minutesPerWeek} {
+		if b[0]+offset < a[1] && a[0] < b[1]+offset {
+			return true
+		}
+	}
+	return false
 }
 
 func (b *BusinessHour) IsInSchedule(targetDateTime time.Time) bool {
-	targetWk := targetDateTime.Weekday()
-	// check week day
-	for _, wk := range b.weekdays {
-		if int(wk) == int(targetWk) {
-			// check time shift is in range
-			return b.shift.IsInRange(targetDateTime)
+	// previous date's shift can cross midnight
+	for _, date := range []time.Time{targetDateTime, targetDateTime.AddDate(0, 0, -1)} {
+		if b.IsInScheduleOf(date, targetDateTime) {
+			return true
 		}
 	}
 	return false
 }
 
+// check target is in the shift started at the date
+func (b *BusinessHour) IsInScheduleOf(date, targetDateTime time.Time) bool {
+	weekday := Weekday(date.Weekday())
+	if !b.HasWeekday(weekday) {
+		return false
+	}
+	shift := b.GetShift(weekday)
+	return shift.IsInRange(date, targetDateTime)
+}
+
+// return weekday specific shift if exists
+func (b *BusinessHour) GetShift(weekday Weekday) TimeRange {
+	for _, ws := range b.weekdayShifts {
+		if ws.weekday == weekday {
+			return ws.shift
+		}
+	}
+	return b.shift
+}
+
+func (b *BusinessHour) GetWeekdayShifts() []WeekdayShift {
+	return append([]WeekdayShift{}, b.weekdayShifts...)
+}
+
 func (b *BusinessHour) GetId() string {
 	return b.id
 }
@@ -371,3 +513,31 @@ func (b *BusinessHour) HasWeekday(weekday Weekday) bool {
 	}
 	return false
 }
+
+type WeekdayShift struct {
+	weekday Weekday
+	shift   TimeRange
+}
+
+func NewWeekdayShift(weekday Weekday, start, end string) (*WeekdayShift, error) {
+	if weekday < Sunday || weekday > Saturday {
+		return nil, common.NewValidationError("weekday", fmt.Sprintf("not allowed value:%d", weekday))
+	}
+	shift, err := NewTimeRange(start, end)
+	if err != nil {
+		return nil, err
+	}
+	return &WeekdayShift{weekday: weekday, shift: *shift}, nil
+}
+
+func (w *WeekdayShift) GetWeekday() Weekday {
+	return w.weekday
+}
+
+func (w *WeekdayShift) GetStart() string {
+	return w.shift.start
+}
+
+func (w *WeekdayShift) GetEnd() string {
+	return w.shift.end
+}
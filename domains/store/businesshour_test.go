@@ -385,3 +385,227 @@ func TestBusinessHours_Update_Enabled(t *testing.T) {
 		}
 	}
 }
+
+func TestBusinessHours_Add(t *testing.T) {
+	defaults := []busHoursArgs{
+		{name: "morning", start: "07:00", end: "09:30", enabled: true, weekdays: []store.Weekday{store.Tuesday, store.Wednesday, store.Friday, store.Saturday, store.Sunday}, hourOffset: 3},
+		{name: "lunch", start: "11:30", end: "15:00", enabled: true, weekdays: []store.Weekday{store.Tuesday, store.Wednesday, store.Friday, store.Saturday, store.Sunday}, hourOffset: 3},
+		{name: "dinner", start: "18:00", end: "21:00", enabled: true, weekdays: []store.Weekday{store.Wednesday, store.Saturday}, hourOffset: 3},
+	}
+	inputs := []busHoursInput{
+		{name: "add night",
+			args: []busHoursArgs{
+				{name: "night", start: "21:00", end: "23:00", weekdays: []store.Weekday{store.Saturday}, hourOffset: 2},
+			},
+			want: append(append([]busHoursArgs{}, defaults...),
+				busHoursArgs{name: "night", start: "21:00", end: "23:00", enabled: true, weekdays: []store.Weekday{store.Saturday}, hourOffset: 2}),
+		},
+		{name: "add early morning",
+			args: []busHoursArgs{
+				{name: "early", start: "05:00", end: "06:30", weekdays: []store.Weekday{store.Monday}, hourOffset: 3},
+			},
+			want: append(append([]busHoursArgs{}, defaults...),
+				busHoursArgs{name: "early", start: "05:00", end: "06:30", enabled: true, weekdays: []store.Weekday{store.Monday}, hourOffset: 3}),
+		},
+		{name: "add overwrapped time on other weekday",
+			args: []busHoursArgs{
+				{name: "mon lunch", start: "11:00", end: "15:00", weekdays: []store.Weekday{store.Monday}, hourOffset: 3},
+			},
+			want: append(append([]busHoursArgs{}, defaults...),
+				busHoursArgs{name: "mon lunch", start: "11:00", end: "15:00", enabled: true, weekdays: []store.Weekday{store.Monday}, hourOffset: 3}),
+		},
+		{name: "overwrapped time on same weekday",
+			args: []busHoursArgs{
+				{name: "brunch", start: "09:00", end: "12:00", weekdays: []store.Weekday{store.Tuesday}, hourOffset: 3},
+			},
+			hasValidationErr: true,
+		},
+		{name: "irregular time",
+			args: []busHoursArgs{
+				{name: "night", start: "23:00", end: "22:00", weekdays: []store.Weekday{store.Saturday}, hourOffset: 3},
+			},
+			hasValidationErr: true,
+		},
+	}
+
+	for _, tt := range inputs {
+		fmt.Println("name:", tt.name)
+		bus, err := store.NewDefaultBusinessHours()
+		assert.NoError(t, err, "test initialize failed")
+
+		args := tt.args[0]
+		got, id, err := bus.Add(args.name, args.start, args.end, args.weekdays, args.hourOffset)
+		assertBusinessHoursRoot(t, tt, got, err)
+		if err == nil {
+			added := got.FindById(id)
+			assert.NotNil(t, added)
+			// original is not changed
+			assert.Equal(t, len(defaults), len(bus.GetSchedules()))
+		}
+	}
+}
+
+func TestBusinessHours_Remove(t *testing.T) {
+	bus, err := store.NewDefaultBusinessHours()
+	assert.NoError(t, err, "test initialize failed")
+	schedules := bus.GetSchedules()
+
+	got, err := bus.Remove(schedules[1].GetId())
+	assert.NoError(t, err)
+	assertBusinessHours(t, []busHoursArgs{
+		{name: "morning", start: "07:00", end: "09:30", enabled: true, weekdays: []store.Weekday{store.Tuesday, store.Wednesday, store.Friday, store.Saturday, store.Sunday}, hourOffset: 3},
+		{name: "dinner", start: "18:00", end: "21:00", enabled: true, weekdays: []store.Weekday{store.Wednesday, store.Saturday}, hourOffset: 3},
+	}, *got)
+	// original is not changed
+	assert.Equal(t, 3, len(bus.GetSchedules()))
+
+	_, err = bus.Remove("not exists")
+	assert.IsType(t, common.NewNotFoundError(""), err)
+
+	// last schedule can not be removed
+	got, _ = got.Remove(schedules[0].GetId())
+	_, err = got.Remove(schedules[2].GetId())
+	assert.IsType(t, common.NewValidationError("", ""), err)
+}
+
+func TestNewBusinessHour_OverMidnight(t *testing.T) {
+	inputs := []struct {
+		name             string
+		start            string
+		end              string
+		hasValidationErr bool
+	}{
+		{name: "over midnight", start: "20:00", end: "01:00"},
+		{name: "until midnight", start: "20:00", end: "00:00"},
+		{name: "edge time(start < end + 60)", start: "23:30", end: "00:30"},
+		{name: "irregular time(start < end + 59)", start: "23:30", end: "00:29", hasValidationErr: true},
+		{name: "edge time(12 hours)", start: "20:00", end: "08:00"},
+		{name: "irregular time(over 12 hours)", start: "20:00", end: "08:01", hasValidationErr: true},
+	}
+	for _, tt := range inputs {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := store.NewBusinessHour("late", tt.start, tt.end, []store.Weekday{store.Friday}, 3)
+			if tt.hasValidationErr {
+				assert.IsType(t, common.NewValidationError("", ""), err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestBusinessHour_IsInSchedule_OverMidnight(t *testing.T) {
+	// friday and saturday
+	hour, err := store.NewBusinessHour("late", "20:00", "01:00", []store.Weekday{store.Friday, store.Saturday}, 3)
+	assert.NoError(t, err)
+
+	inputs := []struct {
+		datetime string
+		want     bool
+	}{
+		{datetime: "2050/12/09 19:59", want: false},
+		{datetime: "2050/12/09 20:00", want: true},
+		{datetime: "2050/12/09 23:59", want: true},
+		{datetime: "2050/12/10 00:00", want: true},
+		{datetime: "2050/12/10 01:00", want: true},
+		{datetime: "2050/12/10 01:01", want: false},
+		// saturday night shift ends at sunday
+		{datetime: "2050/12/11 00:30", want: true},
+		// thursday is not business day
+		{datetime: "2050/12/09 00:30", want: false},
+		{datetime: "2050/12/12 00:30", want: false},
+	}
+	for _, tt := range inputs {
+		target, _ := common.ConvertStrToDateTime(tt.datetime)
+		assert.Equal(t, tt.want, hour.IsInSchedule(*target), tt.datetime)
+	}
+}
+
+func TestBusinessHours_Add_OverMidnight(t *testing.T) {
+	inputs := []busHoursInput{
+		{name: "late night on tuesday",
+			args:             []busHoursArgs{{name: "late", start: "21:00", end: "01:00", weekdays: []store.Weekday{store.Tuesday}, hourOffset: 3}},
+			hasValidationErr: false,
+		},
+		{name: "overlap with next morning",
+			args:             []busHoursArgs{{name: "late", start: "21:00", end: "07:30", weekdays: []store.Weekday{store.Tuesday}, hourOffset: 3}},
+			hasValidationErr: true,
+		},
+		{name: "next day is not business day",
+			args:             []busHoursArgs{{name: "late", start: "21:00", end: "07:30", weekdays: []store.Weekday{store.Sunday}, hourOffset: 3}},
+			hasValidationErr: false,
+		},
+		{name: "saturday night overlaps with sunday morning",
+			args:             []busHoursArgs{{name: "late", start: "22:00", end: "07:01", weekdays: []store.Weekday{store.Saturday}, hourOffset: 3}},
+			hasValidationErr: true,
+		},
+		{name: "saturday night just before sunday morning",
+			args:             []busHoursArgs{{name: "late", start: "22:00", end: "07:00", weekdays: []store.Weekday{store.Saturday}, hourOffset: 3}},
+			hasValidationErr: false,
+		},
+	}
+	for _, tt := range inputs {
+		t.Run(tt.name, func(t *testing.T) {
+			bus, _ := store.NewDefaultBusinessHours()
+			args := tt.args[0]
+			_, _, err := bus.Add(args.name, args.start, args.end, args.weekdays, args.hourOffset)
+			if tt.hasValidationErr {
+				assert.IsType(t, common.NewValidationError("", ""), err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestBusinessHours_UpdateWeekdayShifts(t *testing.T) {
+	bus, _ := store.NewDefaultBusinessHours()
+	schedules := bus.GetSchedules()
+	dinnerId := schedules[2].GetId()
+	lunchId := schedules[1].GetId()
+
+	// saturday only extended dinner
+	saturday, _ := store.NewWeekdayShift(store.Saturday, "18:00", "23:30")
+	got, err := bus.UpdateWeekdayShifts(dinnerId, []store.WeekdayShift{*saturday})
+	assert.NoError(t, err)
+	dinner := got.FindById(dinnerId)
+	satShift := dinner.GetShift(store.Saturday)
+	wedShift := dinner.GetShift(store.Wednesday)
+	assert.Equal(t, "23:30", satShift.GetEnd())
+	assert.Equal(t, "21:00", wedShift.GetEnd())
+	// original is not changed
+	assert.Equal(t, 0, len(bus.FindById(dinnerId).GetWeekdayShifts()))
+
+	sat, _ := common.ConvertStrToDateTime("2050/12/10 23:00")
+	wed, _ := common.ConvertStrToDateTime("2050/12/07 23:00")
+	assert.True(t, got.IsInBusiness(*sat))
+	assert.False(t, got.IsInBusiness(*wed))
+
+	// weekday is not in weekdays
+	monday, _ := store.NewWeekdayShift(store.Monday, "18:00", "23:30")
+	_, err = bus.UpdateWeekdayShifts(dinnerId, []store.WeekdayShift{*monday})
+	assert.IsType(t, common.NewValidationError("", ""), err)
+
+	// duplicated weekday
+	_, err = bus.UpdateWeekdayShifts(dinnerId, []store.WeekdayShift{*saturday, *saturday})
+	assert.IsType(t, common.NewValidationError("", ""), err)
+
+	// overlap with other schedule only on tuesday
+	tuesday, _ := store.NewWeekdayShift(store.Tuesday, "09:00", "15:00")
+	_, err = bus.UpdateWeekdayShifts(lunchId, []store.WeekdayShift{*tuesday})
+	assert.IsType(t, common.NewValidationError("", ""), err)
+
+	// overlap with own shift of next day
+	friday, _ := store.NewWeekdayShift(store.Friday, "20:00", "08:00")
+	_, err = bus.UpdateWeekdayShifts(lunchId, []store.WeekdayShift{*friday})
+	assert.IsType(t, common.NewValidationError("", ""), err)
+
+	// not found
+	_, err = bus.UpdateWeekdayShifts("not exists", []store.WeekdayShift{})
+	assert.IsType(t, common.NewNotFoundError(""), err)
+
+	// removed weekday's shift is also removed
+	got, err = got.Update(dinnerId, "dinner", "18:00", "21:00", []store.Weekday{store.Wednesday}, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(got.FindById(dinnerId).GetWeekdayShifts()))
+}
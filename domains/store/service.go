@@ -2,6 +2,9 @@ package store
 
 import (
 	"chico/takeout/common"
+	"chico/takeout/domains/item"
+	"fmt"
+	"strings"
 	"time"
 )
 
@@ -133,3 +136,117 @@ func (b *BusinessHourManagementService) GetSpecificDateHour(date time.Time) (*Bu
 	return spec.GetStoreBusinessHours(dateStr)
 }
 
+const CalendarMaxDays = 366
+
+// list up each date's business hours between start and end (includes both)
+func (b *BusinessHourManagementService) GetCalendar(start, end time.Time) ([]CalendarDayInfo, error) {
+	if start.After(end) {
+		return nil, common.NewValidationError("from", "should be before to")
+	}
+	if end.Sub(start).Hours()/24 >= CalendarMaxDays {
+		return nil, common.NewValidationError("to", fmt.Sprintf("range should be less than %d days", CalendarMaxDays))
+	}
+	holidays, err := b.specialHolidayRepository.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	spHours, err := b.specialBusinessHourRepository.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	bs, err := b.businessHoursRepository.Fetch()
+	if err != nil {
+		return nil, err
+	}
+
+	spec := NewBusinessHoursManagementSpecification(*bs, spHours, holidays)
+	dates, err := common.ListUpDates(start, end, start.Location())
+	if err != nil {
+		return nil, err
+	}
+	days := []CalendarDayInfo{}
+	for _, date := range dates {
+		day, err := spec.GetStoreCalendarDay(common.ConvertTimeToDateStr(date))
+		if err != nil {
+			return nil, err
+		}
+		days = append(days, *day)
+	}
+	return days, nil
+}
+
+type BusinessHourRemoveService struct {
+	businessHoursRepository       BusinessHoursRepository
+	specialBusinessHourRepository SpecialBusinessHourRepository
+	foodRepository                item.FoodItemRepository
+	clock                         common.Clock
+}
+
+func NewBusinessHourRemoveService(
+	businessHoursRepository BusinessHoursRepository,
+	specialBusinessHourRepository SpecialBusinessHourRepository,
+	foodRepository item.FoodItemRepository,
+	clock common.Clock) *BusinessHourRemoveService {
+	return &BusinessHourRemoveService{
+		businessHoursRepository:       businessHoursRepository,
+		specialBusinessHourRepository: specialBusinessHourRepository,
+		foodRepository:                foodRepository,
+		clock:                         clock,
+	}
+}
+
+// removing is blocked if upcoming special hours use the schedule or food item has only the schedule.
+// otherwise past special hours are deleted and the schedule is removed from food items.
+func (b *BusinessHourRemoveService) Remove(id string) error {
+	businessHours, err := b.businessHoursRepository.Fetch()
+	if err != nil {
+		return err
+	}
+	if businessHours == nil {
+		return common.NewUpdateTargetNotFoundError(id)
+	}
+	removed, err := businessHours.Remove(id)
+	if err != nil {
+		return err
+	}
+
+	// step1: check special hours
+	spHours, err := b.specialBusinessHourRepository.FindAll()
+	if err != nil {
+		return err
+	}
+	today := common.GetDateUntilDay(b.clock.Now())
+	upcomingNames := []string{}
+	for _, spHour := range spHours {
+		if spHour.GetBusinessHourId() != id {
+			continue
+		}
+		if !spHour.IsBeforeDate(*today) {
+			upcomingNames = append(upcomingNames, fmt.Sprintf("%s(%s)", spHour.GetName(), spHour.GetDate()))
+		}
+	}
+	if len(upcomingNames) > 0 {
+		return common.NewValidationError("id", fmt.Sprintf("special business hours use this schedule:%s", strings.Join(upcomingNames, ",")))
+	}
+
+	// step2: check food items
+	foods, err := b.foodRepository.FindAll()
+	if err != nil {
+		return err
+	}
+	onlyScheduleNames := []string{}
+	for _, food := range foods {
+		if !food.HasScheduleId(id) {
+			continue
+		}
+		if err := food.RemoveScheduleId(id); err != nil {
+			onlyScheduleNames = append(onlyScheduleNames, food.GetName())
+		}
+	}
+	if len(onlyScheduleNames) > 0 {
+		return common.NewValidationError("id", fmt.Sprintf("food items have only this schedule:%s", strings.Join(onlyScheduleNames, ",")))
+	}
+
+	// step3: update with references in a transaction
+	return b.businessHoursRepository.RemoveSchedule(removed, id)
+}
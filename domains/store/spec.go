@@ -3,6 +3,8 @@ package store
 import (
 	"chico/takeout/common"
 	"fmt"
+	"sort"
+	"time"
 )
 
 type SpecialBusinessHourSpecification struct {
@@ -80,17 +82,44 @@ func NewHolidaySpecification(normalSchedules BusinessHours,
 }
 
 func (h *HolidaySpecification) IsStoreInBusiness(datetime string) (bool, error) {
-	time, err := common.ConvertStrToDateTime(datetime)
+	shift, err := h.FindShiftAt(datetime)
 	if err != nil {
 		return false, err
 	}
+	return shift != nil, nil
+}
+
+// shift of business hour which covers a date time
+type ShiftInfo struct {
+	HourTypeId string
+	Start      time.Time
+	HourOffset uint
+}
+
+// find the shift which covers datetime. nil if store is not in business
+func (h *HolidaySpecification) FindShiftAt(datetime string) (*ShiftInfo, error) {
+	target, err := common.ConvertStrToDateTime(datetime)
+	if err != nil {
+		return nil, err
+	}
+	// shift of previous date can cross midnight
+	for _, date := range []time.Time{*target, target.AddDate(0, 0, -1)} {
+		if shift := h.findShiftOf(date, *target); shift != nil {
+			return shift, nil
+		}
+	}
+	return nil, nil
+}
+
+// find target in the shifts started at the date
+func (h *HolidaySpecification) findShiftOf(date, target time.Time) *ShiftInfo {
 	// step1 :specific date is available
 
 	// check special holiday (most high priority)
 	for _, sh := range h.specialHolidays {
-		if sh.shift.InRangeDate(*time) {
+		if sh.IsHolidayAt(date) {
 			// store is holiday. can not reserve
-			return false, nil
+			return nil
 		}
 	}
 
@@ -100,22 +129,27 @@ func (h *HolidaySpecification) IsStoreInBusiness(datetime string) (bool, error)
 
 	hasSameDate := false
 	for _, ss := range h.specialSchedules {
-		if ss.IsSameDate(*time) {
+		if ss.IsSameDate(date) {
 			hasSameDate = true
 			// check time is overlap
-			if ss.IsInRange(*time) {
+			if ss.IsInRange(target) {
 				// can reserve
-				return true, nil
+				return &ShiftInfo{HourTypeId: ss.GetBusinessHourId(), Start: ss.GetStartDateTime(), HourOffset: ss.GetHourOffset()}
 			}
 		}
 	}
 	// if same date's special schedule has, other normal schedule is ignored (can not reserve)
 	if hasSameDate {
-		return false, nil
+		return nil
 	}
 
 	// get normal schedules by day of week
-	return h.normalSchedules.IsInBusiness(*time), nil
+	schedule := h.normalSchedules.FindScheduleOf(date, target)
+	if schedule == nil {
+		return nil
+	}
+	shift := schedule.GetShift(Weekday(date.Weekday()))
+	return &ShiftInfo{HourTypeId: schedule.GetId(), Start: shift.StartAt(date), HourOffset: schedule.GetHourOffset()}
 }
 
 type BusinessHoursManagementSpecification struct {
@@ -128,6 +162,8 @@ type BusinessHourInfo struct {
 	Date  string
 	Hours []HourInfo
 }
+
+// EndTime before StartTime means crossing midnight
 type HourInfo struct {
 	HourTypeId string
 	Name       string
@@ -146,22 +182,43 @@ func NewBusinessHoursManagementSpecification(normalSchedules BusinessHours,
 }
 
 func (b *BusinessHoursManagementSpecification) GetStoreBusinessHours(dateStr string) (*BusinessHourInfo, error) {
+	day, err := b.GetStoreCalendarDay(dateStr)
+	if err != nil {
+		return nil, err
+	}
+	return &BusinessHourInfo{Date: day.Date, Hours: day.Hours}, nil
+}
+
+type CalendarDayInfo struct {
+	Date string
+	// empty if not holiday
+	HolidayName string
+	// special schedules override normal schedules
+	IsSpecial bool
+	Hours     []HourInfo
+}
+
+func (c *CalendarDayInfo) IsHoliday() bool {
+	return c.HolidayName != ""
+}
+
+func (b *BusinessHoursManagementSpecification) GetStoreCalendarDay(dateStr string) (*CalendarDayInfo, error) {
 	date, err := common.ConvertStrToDate(dateStr)
 	if err != nil {
 		return nil, err
 	}
-	result := BusinessHourInfo{Date: dateStr, Hours: []HourInfo{}}
+	result := CalendarDayInfo{Date: dateStr, Hours: []HourInfo{}}
 	// at first check special holiday
 	// check special holiday (most high priority)
 	for _, sh := range b.specialHolidays {
-		if sh.shift.InRangeDate(*date) {
-			// store is holiday. no information
+		if sh.IsHolidayAt(*date) {
+			// store is holiday. no hour information
+			result.HolidayName = sh.GetName()
 			return &result, nil
 		}
 	}
 
 	// check special schedule
-	hasSpecialSchedules := false
 	for _, sc := range b.specialSchedules {
 		if sc.IsSameDate(*date) {
 			hour := HourInfo{
@@ -172,23 +229,25 @@ func (b *BusinessHoursManagementSpecification) GetStoreBusinessHours(dateStr str
 			}
 			result.Hours = append(result.Hours, hour)
 
-			hasSpecialSchedules = true
+			result.IsSpecial = true
 		}
 	}
 
 	// if already has, skip
-	if hasSpecialSchedules {
+	if result.IsSpecial {
+		sort.Slice(result.Hours, func(i, j int) bool { return result.Hours[i].StartTime < result.Hours[j].StartTime })
 		return &result, nil
 	}
 
 	// get normal schedule
 	weekday := date.Weekday()
 	for _, sc := range b.normalSchedules.FindByWeekday(int(weekday)) {
+		shift := sc.GetShift(Weekday(weekday))
 		hour := HourInfo{
 			HourTypeId: sc.GetId(),
 			Name:       sc.GetName(),
-			StartTime:  sc.GetStart(),
-			EndTime:    sc.GetEnd(),
+			StartTime:  shift.GetStart(),
+			EndTime:    shift.GetEnd(),
 		}
 		result.Hours = append(result.Hours, hour)
 	}
@@ -0,0 +1,137 @@
+package store_test
+
+import (
+	"testing"
+	"time"
+
+	"chico/takeout/common"
+	"chico/takeout/domains/store"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBusinessHoursManagementSpecification_GetStoreCalendarDay(t *testing.T) {
+	hours, _ := store.NewDefaultBusinessHours()
+	schedules := hours.GetSchedules()
+	special1, _ := store.NewSpecialBusinessHour("特別ランチ", "2050/12/06", "12:00", "14:00", schedules[1].GetId(), 3)
+	special2, _ := store.NewSpecialBusinessHour("特別モーニング", "2050/12/06", "08:00", "10:00", schedules[0].GetId(), 3)
+	// special hour in holiday is ignored
+	special3, _ := store.NewSpecialBusinessHour("特別ディナー", "2050/12/20", "18:00", "20:00", schedules[2].GetId(), 3)
+	holiday, _ := store.NewSpecialHoliday("年末休み", "2050/12/19", "2050/12/21")
+	spec := store.NewBusinessHoursManagementSpecification(*hours, []store.SpecialBusinessHour{*special1, *special2, *special3}, []store.SpecialHoliday{*holiday})
+
+	inputs := []struct {
+		name        string
+		date        string
+		holidayName string
+		isSpecial   bool
+		hours       []store.HourInfo
+	}{
+		{name: "normal wednesday", date: "2050/12/07", hours: []store.HourInfo{
+			{HourTypeId: schedules[0].GetId(), Name: "morning", StartTime: "07:00", EndTime: "09:30"},
+			{HourTypeId: schedules[1].GetId(), Name: "lunch", StartTime: "11:30", EndTime: "15:00"},
+			{HourTypeId: schedules[2].GetId(), Name: "dinner", StartTime: "18:00", EndTime: "21:00"},
+		}},
+		{name: "normal monday(closed)", date: "2050/12/05", hours: []store.HourInfo{}},
+		{name: "special schedule overrides and sorted by start", date: "2050/12/06", isSpecial: true, hours: []store.HourInfo{
+			{HourTypeId: schedules[0].GetId(), Name: "特別モーニング", StartTime: "08:00", EndTime: "10:00"},
+			{HourTypeId: schedules[1].GetId(), Name: "特別ランチ", StartTime: "12:00", EndTime: "14:00"},
+		}},
+		{name: "holiday has priority", date: "2050/12/20", holidayName: "年末休み", hours: []store.HourInfo{}},
+	}
+	for _, tt := range inputs {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := spec.GetStoreCalendarDay(tt.date)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.date, got.Date)
+			assert.Equal(t, tt.holidayName, got.HolidayName)
+			assert.Equal(t, tt.holidayName != "", got.IsHoliday())
+			assert.Equal(t, tt.isSpecial, got.IsSpecial)
+			assert.Equal(t, tt.hours, got.Hours)
+		})
+	}
+
+	_, err := spec.GetStoreCalendarDay("2050-12-20")
+	assert.Error(t, err)
+}
+
+func TestHolidaySpecification_IsStoreInBusiness_OverMidnight(t *testing.T) {
+	late, _ := store.NewBusinessHour("late", "20:00", "01:00", []store.Weekday{store.Friday}, 3)
+	hours, _ := store.NewBusinessHours([]store.BusinessHour{*late})
+	// friday(2050/12/16) has special schedule
+	special, _ := store.NewSpecialBusinessHour("特別", "2050/12/16", "19:00", "00:30", late.GetId(), 3)
+	// friday(2050/12/23) is holiday, saturday(2050/12/31) is holiday
+	holiday1, _ := store.NewSpecialHoliday("休み1", "2050/12/23", "2050/12/23")
+	holiday2, _ := store.NewSpecialHoliday("休み2", "2050/12/31", "2050/12/31")
+	spec := store.NewHolidaySpecification(*hours, []store.SpecialBusinessHour{*special}, []store.SpecialHoliday{*holiday1, *holiday2})
+
+	inputs := []struct {
+		datetime string
+		want     bool
+	}{
+		{datetime: "2050/12/09 20:00", want: true},
+		{datetime: "2050/12/10 00:59", want: true},
+		{datetime: "2050/12/10 01:00", want: true},
+		{datetime: "2050/12/10 01:01", want: false},
+		{datetime: "2050/12/10 20:00", want: false},
+		// special schedule over midnight
+		{datetime: "2050/12/16 19:00", want: true},
+		{datetime: "2050/12/17 00:30", want: true},
+		{datetime: "2050/12/17 00:31", want: false},
+		// started at holiday
+		{datetime: "2050/12/23 21:00", want: false},
+		{datetime: "2050/12/24 00:30", want: false},
+		// started before holiday
+		{datetime: "2050/12/31 00:30", want: true},
+	}
+	for _, tt := range inputs {
+		got, err := spec.IsStoreInBusiness(tt.datetime)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.want, got, tt.datetime)
+	}
+}
+
+func TestHolidaySpecification_FindShiftAt(t *testing.T) {
+	loc := common.GetStoreLocation()
+	late, _ := store.NewBusinessHour("late", "20:00", "01:00", []store.Weekday{store.Friday}, 3)
+	hours, _ := store.NewBusinessHours([]store.BusinessHour{*late})
+	// friday(2050/12/16) has special schedule
+	special, _ := store.NewSpecialBusinessHour("特別", "2050/12/16", "19:00", "00:30", late.GetId(), 5)
+	spec := store.NewHolidaySpecification(*hours, []store.SpecialBusinessHour{*special}, []store.SpecialHoliday{})
+
+	inputs := []struct {
+		datetime string
+		want     *store.ShiftInfo
+	}{
+		{datetime: "2050/12/09 20:30", want: &store.ShiftInfo{HourTypeId: late.GetId(), Start: time.Date(2050, 12, 9, 20, 0, 0, 0, loc), HourOffset: 3}},
+		// shift started at previous date
+		{datetime: "2050/12/10 00:30", want: &store.ShiftInfo{HourTypeId: late.GetId(), Start: time.Date(2050, 12, 9, 20, 0, 0, 0, loc), HourOffset: 3}},
+		{datetime: "2050/12/17 00:10", want: &store.ShiftInfo{HourTypeId: late.GetId(), Start: time.Date(2050, 12, 16, 19, 0, 0, 0, loc), HourOffset: 5}},
+		{datetime: "2050/12/10 20:00", want: nil},
+	}
+	for _, tt := range inputs {
+		got, err := spec.FindShiftAt(tt.datetime)
+		assert.NoError(t, err)
+		if tt.want == nil {
+			assert.Nil(t, got, tt.datetime)
+			continue
+		}
+		assert.Equal(t, tt.want.HourTypeId, got.HourTypeId, tt.datetime)
+		assert.True(t, tt.want.Start.Equal(got.Start), tt.datetime)
+		assert.Equal(t, tt.want.HourOffset, got.HourOffset, tt.datetime)
+	}
+}
+
+func TestBusinessHoursManagementSpecification_GetStoreCalendarDay_WeekdayShift(t *testing.T) {
+	hours, _ := store.NewDefaultBusinessHours()
+	dinner := hours.GetSchedules()[2]
+	saturday, _ := store.NewWeekdayShift(store.Saturday, "18:00", "01:00")
+	hours, err := hours.UpdateWeekdayShifts(dinner.GetId(), []store.WeekdayShift{*saturday})
+	assert.NoError(t, err)
+	spec := store.NewBusinessHoursManagementSpecification(*hours, []store.SpecialBusinessHour{}, []store.SpecialHoliday{})
+
+	wed, _ := spec.GetStoreCalendarDay("2050/12/07")
+	assert.Equal(t, store.HourInfo{HourTypeId: dinner.GetId(), Name: "dinner", StartTime: "18:00", EndTime: "21:00"}, wed.Hours[2])
+	sat, _ := spec.GetStoreCalendarDay("2050/12/10")
+	assert.Equal(t, store.HourInfo{HourTypeId: dinner.GetId(), Name: "dinner", StartTime: "18:00", EndTime: "01:00"}, sat.Hours[2])
+}
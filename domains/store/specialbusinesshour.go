@@ -152,14 +152,20 @@ func (s *SpecialBusinessHour) IsSameDateAndHourId(other SpecialBusinessHour) boo
 	return s.HaveSameBusinessHourId(other) && s.HaveSameDate(other)
 }
 
+func (s *SpecialBusinessHour) IsBeforeDate(datetime time.Time) bool {
+	return s.date.GetAsDate().Before(datetime)
+}
+
 func (s *SpecialBusinessHour) IsSameDate(datetime time.Time) bool {
 	return s.date.IsSameDate(datetime)
 }
 
+// start date time of the shift
+func (s *SpecialBusinessHour) GetStartDateTime() time.Time {
+	return s.shift.StartAt(s.date.GetAsDate())
+}
+
 func (s *SpecialBusinessHour) IsInRange(datetime time.Time) bool {
-	// same date and within range time
-	if s.date.IsSameDate(datetime) {
-		return s.shift.IsInRange(datetime)
-	}
-	return false
+	// within range time from the date (end can be next date if it crosses midnight)
+	return s.shift.IsInRange(s.date.GetAsDate(), datetime)
 }
@@ -3,6 +3,7 @@ package store
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"chico/takeout/common"
 
@@ -17,10 +18,14 @@ type SpecialHolidayRepository interface {
 	Delete(id string) error
 }
 
+// shift is the holiday range if not recurring.
+// yearly: shift is the first range and it is repeated every year.
+// weekly, nthWeekday: shift is the period when the rule is applied.
 type SpecialHoliday struct {
-	id    string
-	name  string
-	shift DateRange
+	id         string
+	name       string
+	shift      DateRange
+	recurrence HolidayRecurrence
 }
 
 const (
@@ -38,11 +43,22 @@ func NewSpecialHoliday(name, start, end string) (*SpecialHoliday, error) {
 	return holiday, nil
 }
 
-func NewSpecialHolidayForOrm(id, name, start, end string) (*SpecialHoliday, error) {
+func NewRecurringSpecialHoliday(name, start, end string, recurrence HolidayRecurrence) (*SpecialHoliday, error) {
+	holiday := &SpecialHoliday{
+		id: uuid.NewString(),
+	}
+	err := holiday.SetWithRecurrence(name, start, end, recurrence)
+	if err != nil {
+		return nil, err
+	}
+	return holiday, nil
+}
+
+func NewSpecialHolidayForOrm(id, name, start, end string, recurrence HolidayRecurrence) (*SpecialHoliday, error) {
 	holiday := &SpecialHoliday{
 		id: id,
 	}
-	err := holiday.Set(name, start, end)
+	err := holiday.SetWithRecurrence(name, start, end, recurrence)
 	if err != nil {
 		return nil, err
 	}
@@ -61,6 +77,10 @@ func (h *SpecialHoliday) validateHolidayInfoName(name string) error {
 }
 
 func (h *SpecialHoliday) Set(name, start, end string) error {
+	return h.SetWithRecurrence(name, start, end, h.recurrence)
+}
+
+func (h *SpecialHoliday) SetWithRecurrence(name, start, end string, recurrence HolidayRecurrence) error {
 	if err := h.validateHolidayInfoName(name); err != nil {
 		return err
 	}
@@ -68,9 +88,13 @@ func (h *SpecialHoliday) Set(name, start, end string) error {
 	if err != nil {
 		return err
 	}
+	if err := recurrence.validateWith(*shift); err != nil {
+		return err
+	}
 
 	h.name = name
 	h.shift = *shift
+	h.recurrence = recurrence
 	return nil
 }
 
@@ -90,10 +114,188 @@ func (s *SpecialHoliday) GetEnd() string {
 	return s.shift.GetEnd()
 }
 
+func (s *SpecialHoliday) GetRecurrence() HolidayRecurrence {
+	return s.recurrence
+}
+
+func (s *SpecialHoliday) IsRecurring() bool {
+	return s.recurrence.recurrenceType != RecurrenceNone
+}
+
 func (s *SpecialHoliday) Equals(other SpecialHoliday) bool {
 	return s.GetId() == other.GetId()
 }
 
+func (s *SpecialHoliday) IsHolidayAt(datetime time.Time) bool {
+	switch s.recurrence.recurrenceType {
+	case RecurrenceYearly:
+		start, _ := common.ConvertStrToDate(s.shift.start)
+		end, _ := common.ConvertStrToDate(s.shift.end)
+		// range can be over the year end
+		for _, year := range []int{datetime.Year() - 1, datetime.Year()} {
+			offset := year - start.Year()
+			if offset < 0 {
+				continue
+			}
+			if common.IsInRange(start.AddDate(offset, 0, 0), end.AddDate(offset, 0, 0), datetime) {
+				return true
+			}
+		}
+		return false
+	case RecurrenceWeekly:
+		return s.shift.InRangeDate(datetime) && s.recurrence.hasWeekday(Weekday(datetime.Weekday()))
+	case RecurrenceNthWeekday:
+		return s.shift.InRangeDate(datetime) && s.recurrence.isNthWeekday(datetime)
+	default:
+		return s.shift.InRangeDate(datetime)
+	}
+}
+
+// compare expanded dates of recurring rule
 func (s *SpecialHoliday) IsOverlap(other SpecialHoliday) bool {
-	return s.shift.IsOverlap(other.shift)
+	if !s.IsRecurring() && !other.IsRecurring() {
+		return s.shift.IsOverlap(other.shift)
+	}
+	start, end := s.activePeriod()
+	oStart, oEnd := other.activePeriod()
+	if oStart.After(start) {
+		start = oStart
+	}
+	if end == nil || (oEnd != nil && oEnd.Before(*end)) {
+		end = oEnd
+	}
+	// both are yearly. 2 years are enough to check all patterns
+	if end == nil {
+		limit := start.AddDate(2, 0, 0)
+		end = &limit
+	}
+	for date := start; !date.After(*end); date = date.AddDate(0, 0, 1) {
+		if s.IsHolidayAt(date) && other.IsHolidayAt(date) {
+			return true
+		}
+	}
+	return false
+}
+
+// nil end means endless
+func (s *SpecialHoliday) activePeriod() (time.Time, *time.Time) {
+	start, _ := common.ConvertStrToDate(s.shift.start)
+	if s.recurrence.recurrenceType == RecurrenceYearly {
+		return *start, nil
+	}
+	end, _ := common.ConvertStrToDate(s.shift.end)
+	return *start, end
+}
+
+type HolidayRecurrenceType string
+
+const (
+	RecurrenceNone       HolidayRecurrenceType = ""
+	RecurrenceYearly     HolidayRecurrenceType = "yearly"
+	RecurrenceWeekly     HolidayRecurrenceType = "weekly"
+	RecurrenceNthWeekday HolidayRecurrenceType = "nthWeekday"
+)
+
+// nth for the last weekday of the month
+const LastWeekOfMonth = -1
+
+type HolidayRecurrence struct {
+	recurrenceType HolidayRecurrenceType
+	// weekly: closed weekdays, nthWeekday: only 1 weekday
+	weekdays []Weekday
+	// nthWeekday: 1-5 or LastWeekOfMonth
+	nth int
+	// nthWeekday: 1-12, 0 is every month
+	month int
+}
+
+func NewHolidayRecurrence(recurrenceType string, weekdays []Weekday, nth, month int) (*HolidayRecurrence, error) {
+	recurrence := HolidayRecurrence{recurrenceType: HolidayRecurrenceType(recurrenceType)}
+	switch recurrence.recurrenceType {
+	case RecurrenceNone, RecurrenceYearly:
+		return &recurrence, nil
+	case RecurrenceWeekly:
+		if len(weekdays) == 0 {
+			return nil, common.NewValidationError("weekdays", "required")
+		}
+		if validateDuplicatedWeekdays(weekdays) {
+			return nil, common.NewValidationError("weekdays", "duplicated value exists")
+		}
+		recurrence.weekdays = weekdays
+	case RecurrenceNthWeekday:
+		if len(weekdays) != 1 {
+			return nil, common.NewValidationError("weekdays", "only 1 weekday is allowed")
+		}
+		if (nth < 1 || nth > 5) && nth != LastWeekOfMonth {
+			return nil, common.NewValidationError("nth", fmt.Sprintf("not allowed value:%d", nth))
+		}
+		if month < 0 || month > 12 {
+			return nil, common.NewValidationError("month", fmt.Sprintf("not allowed value:%d", month))
+		}
+		recurrence.weekdays = weekdays
+		recurrence.nth = nth
+		recurrence.month = month
+	default:
+		return nil, common.NewValidationError("recurrenceType", fmt.Sprintf("not allowed value:%s", recurrenceType))
+	}
+	for _, weekday := range weekdays {
+		if weekday < Sunday || weekday > Saturday {
+			return nil, common.NewValidationError("weekdays", fmt.Sprintf("not allowed value:%d", weekday))
+		}
+	}
+	return &recurrence, nil
+}
+
+func (r *HolidayRecurrence) validateWith(shift DateRange) error {
+	if r.recurrenceType != RecurrenceYearly {
+		return nil
+	}
+	start, _ := common.ConvertStrToDate(shift.start)
+	end, _ := common.ConvertStrToDate(shift.end)
+	if !end.Before(start.AddDate(1, 0, 0)) {
+		return common.NewValidationError("start end", "yearly holiday should be shorter than 1 year")
+	}
+	// 02/29 does not exist every year
+	if (start.Month() == time.February && start.Day() == 29) || (end.Month() == time.February && end.Day() == 29) {
+		return common.NewValidationError("start end", "02/29 is not allowed for yearly holiday")
+	}
+	return nil
+}
+
+func (r *HolidayRecurrence) GetType() string {
+	return string(r.recurrenceType)
+}
+
+func (r *HolidayRecurrence) GetWeekdays() []Weekday {
+	return append([]Weekday{}, r.weekdays...)
+}
+
+func (r *HolidayRecurrence) GetNth() int {
+	return r.nth
+}
+
+func (r *HolidayRecurrence) GetMonth() int {
+	return r.month
+}
+
+func (r *HolidayRecurrence) hasWeekday(weekday Weekday) bool {
+	for _, wk := range r.weekdays {
+		if wk == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *HolidayRecurrence) isNthWeekday(date time.Time) bool {
+	if !r.hasWeekday(Weekday(date.Weekday())) {
+		return false
+	}
+	if r.month != 0 && int(date.Month()) != r.month {
+		return false
+	}
+	if r.nth == LastWeekOfMonth {
+		return date.AddDate(0, 0, 7).Month() != date.Month()
+	}
+	return (date.Day()-1)/7+1 == r.nth
 }
@@ -149,3 +149,119 @@ func TestSetSpecialHoliday(t *testing.T) {
 		assertSpecialHolidayRoot(t, tt, got, err)
 	}
 }
+
+func TestNewHolidayRecurrence(t *testing.T) {
+	tests := []struct {
+		name           string
+		recurrenceType string
+		weekdays       []store.Weekday
+		nth            int
+		month          int
+		hasErr         bool
+	}{
+		{name: "none", recurrenceType: ""},
+		{name: "yearly", recurrenceType: "yearly"},
+		{name: "weekly", recurrenceType: "weekly", weekdays: []store.Weekday{store.Monday, store.Tuesday}},
+		{name: "nth weekday", recurrenceType: "nthWeekday", weekdays: []store.Weekday{store.Monday}, nth: 2, month: 1},
+		{name: "last weekday", recurrenceType: "nthWeekday", weekdays: []store.Weekday{store.Friday}, nth: store.LastWeekOfMonth},
+		{name: "unknown type", recurrenceType: "daily", hasErr: true},
+		{name: "weekly without weekday", recurrenceType: "weekly", hasErr: true},
+		{name: "weekly duplicated weekday", recurrenceType: "weekly", weekdays: []store.Weekday{store.Monday, store.Monday}, hasErr: true},
+		{name: "nth weekday with 2 weekdays", recurrenceType: "nthWeekday", weekdays: []store.Weekday{store.Monday, store.Friday}, nth: 1, hasErr: true},
+		{name: "nth is 0", recurrenceType: "nthWeekday", weekdays: []store.Weekday{store.Monday}, nth: 0, hasErr: true},
+		{name: "nth is 6", recurrenceType: "nthWeekday", weekdays: []store.Weekday{store.Monday}, nth: 6, hasErr: true},
+		{name: "month is 13", recurrenceType: "nthWeekday", weekdays: []store.Weekday{store.Monday}, nth: 1, month: 13, hasErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := store.NewHolidayRecurrence(tt.recurrenceType, tt.weekdays, tt.nth, tt.month)
+			if tt.hasErr {
+				assert.IsType(t, common.NewValidationError("", ""), err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestNewRecurringSpecialHoliday_Validation(t *testing.T) {
+	yearly, _ := store.NewHolidayRecurrence("yearly", nil, 0, 0)
+	_, err := store.NewRecurringSpecialHoliday("over year", "2050/01/01", "2051/01/01", *yearly)
+	assert.Error(t, err)
+	_, err = store.NewRecurringSpecialHoliday("leap day", "2052/02/29", "2052/02/29", *yearly)
+	assert.Error(t, err)
+	_, err = store.NewRecurringSpecialHoliday("new year", "2050/12/30", "2051/01/03", *yearly)
+	assert.NoError(t, err)
+}
+
+func TestSpecialHoliday_IsHolidayAt_Recurring(t *testing.T) {
+	yearly, _ := store.NewHolidayRecurrence("yearly", nil, 0, 0)
+	weekly, _ := store.NewHolidayRecurrence("weekly", []store.Weekday{store.Tuesday, store.Wednesday}, 0, 0)
+	thirdMonday, _ := store.NewHolidayRecurrence("nthWeekday", []store.Weekday{store.Monday}, 3, 0)
+	lastFridayOfMarch, _ := store.NewHolidayRecurrence("nthWeekday", []store.Weekday{store.Friday}, store.LastWeekOfMonth, 3)
+
+	tests := []struct {
+		name       string
+		start      string
+		end        string
+		recurrence store.HolidayRecurrence
+		trues      []string
+		falses     []string
+	}{
+		{name: "yearly over new year", start: "2050/12/30", end: "2051/01/03", recurrence: *yearly,
+			trues:  []string{"2050/12/30", "2051/01/03", "2060/12/31", "2061/01/01"},
+			falses: []string{"2049/12/31", "2050/12/29", "2051/01/04", "2060/12/29"}},
+		{name: "weekly", start: "2050/12/01", end: "2050/12/31", recurrence: *weekly,
+			trues:  []string{"2050/12/06", "2050/12/07", "2050/12/27"},
+			falses: []string{"2050/12/05", "2050/12/08", "2051/01/03", "2050/11/29"}},
+		{name: "3rd monday", start: "2050/01/01", end: "2050/12/31", recurrence: *thirdMonday,
+			trues:  []string{"2050/12/19", "2050/01/17"},
+			falses: []string{"2050/12/12", "2050/12/26", "2051/01/16"}},
+		{name: "last friday of march", start: "2050/01/01", end: "2051/12/31", recurrence: *lastFridayOfMarch,
+			trues:  []string{"2050/03/25", "2051/03/31"},
+			falses: []string{"2050/03/18", "2050/04/29", "2051/03/24"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			holiday, err := store.NewRecurringSpecialHoliday("test", tt.start, tt.end, tt.recurrence)
+			assert.NoError(t, err)
+			for _, date := range tt.trues {
+				target, _ := common.ConvertStrToDate(date)
+				assert.True(t, holiday.IsHolidayAt(*target), date)
+			}
+			for _, date := range tt.falses {
+				target, _ := common.ConvertStrToDate(date)
+				assert.False(t, holiday.IsHolidayAt(*target), date)
+			}
+		})
+	}
+}
+
+func TestSpecialHoliday_IsOverlap_Recurring(t *testing.T) {
+	yearly, _ := store.NewHolidayRecurrence("yearly", nil, 0, 0)
+	weekly, _ := store.NewHolidayRecurrence("weekly", []store.Weekday{store.Tuesday}, 0, 0)
+	thirdMonday, _ := store.NewHolidayRecurrence("nthWeekday", []store.Weekday{store.Monday}, 3, 0)
+
+	newYear, _ := store.NewRecurringSpecialHoliday("new year", "2050/12/30", "2051/01/03", *yearly)
+	tuesday, _ := store.NewRecurringSpecialHoliday("tuesday", "2050/06/01", "2050/06/30", *weekly)
+	monday, _ := store.NewRecurringSpecialHoliday("monday", "2050/01/01", "2050/12/31", *thirdMonday)
+	single := func(date string) store.SpecialHoliday {
+		h, _ := store.NewSpecialHoliday("single", date, date)
+		return *h
+	}
+
+	assert.True(t, newYear.IsOverlap(single("2060/01/02")))
+	assert.False(t, newYear.IsOverlap(single("2060/01/05")))
+	assert.True(t, tuesday.IsOverlap(single("2050/06/21")))
+	assert.False(t, tuesday.IsOverlap(single("2050/06/22")))
+	assert.False(t, tuesday.IsOverlap(*monday))
+	assert.True(t, monday.IsOverlap(single("2050/05/16")))
+	// same single date is overlapped
+	sameDate := single("2050/05/16")
+	assert.True(t, sameDate.IsOverlap(single("2050/05/16")))
+
+	otherYearly, _ := store.NewRecurringSpecialHoliday("ny2", "2055/01/03", "2055/01/05", *yearly)
+	assert.True(t, newYear.IsOverlap(*otherYearly))
+	otherYearly, _ = store.NewRecurringSpecialHoliday("ny3", "2055/01/04", "2055/01/05", *yearly)
+	assert.False(t, newYear.IsOverlap(*otherYearly))
+}
@@ -10,9 +10,14 @@ import (
 )
 
 const (
-	offsetMinutes = 59
+	offsetMinutes  = 59
+	minutesPerDay  = 24 * 60
+	minutesPerWeek = 7 * minutesPerDay
+	// longer range over midnight is regarded as input mistake (ex:09:00-08:00)
+	overMidnightMaxMinutes = 12 * 60
 )
 
+// end before start means the range crosses midnight (ex:20:00-01:00)
 type TimeRange struct {
 	start string
 	end   string
@@ -28,27 +33,64 @@ func NewTimeRange(start, end string) (*TimeRange, error) {
 	if err != nil {
 		return nil, common.NewValidationError("end", fmt.Sprintf("can not convert time:%s", start))
 	}
+	if endTime.Before(*startTime) {
+		// end is next day
+		nextDay := endTime.AddDate(0, 0, 1)
+		endTime = &nextDay
+		if endTime.Sub(*startTime).Minutes() > overMidnightMaxMinutes {
+			return nil, common.NewValidationError("start end time", fmt.Sprintf("range over midnight(%s-%s) should be within %d hours", start, end, overMidnightMaxMinutes/60))
+		}
+	}
 	if !common.StartIsBeforeEnd(*startTime, *endTime, offsetMinutes) {
 		return nil, common.NewValidationError("start end time", fmt.Sprintf("start time(%s) should be greater than end time(%s) with offset(%d)", start, end, offsetMinutes))
 	}
 	return &TimeRange{start: start, end: end}, nil
 }
 
-func (t *TimeRange) IsOverlap(other TimeRange) bool {
+// minutes from 00:00 of start day. end is over 24h if range crosses midnight.
+func (t *TimeRange) toMinutes() (int, int) {
 	tStart, _ := common.ConvertStrToTime(t.start)
 	tEnd, _ := common.ConvertStrToTime(t.end)
+	start := tStart.Hour()*60 + tStart.Minute()
+	end := tEnd.Hour()*60 + tEnd.Minute()
+	if end < start {
+		end += minutesPerDay
+	}
+	return start, end
+}
 
-	oStart, _ := common.ConvertStrToTime(other.start)
-	oEnd, _ := common.ConvertStrToTime(other.end)
+func (t *TimeRange) IsOverMidnight() bool {
+	_, end := t.toMinutes()
+	return end > minutesPerDay
+}
 
-	return common.IsOverlap(*tStart, *tEnd, *oStart, *oEnd)
+// both ranges start at same date
+func (t *TimeRange) IsOverlap(other TimeRange) bool {
+	tStart, tEnd := t.toMinutes()
+	oStart, oEnd := other.toMinutes()
+	return oStart < tEnd && tStart < oEnd
 }
 
-func (t *TimeRange) IsInRange(target time.Time) bool {
-	tStart, _ := common.ConvertStrToTime(t.start)
-	tEnd, _ := common.ConvertStrToTime(t.end)
+// check target is in the range started at base date (start and end are included)
+func (t *TimeRange) IsInRange(baseDate, target time.Time) bool {
+	start, end := t.toMinutes()
+	base := startOfDate(baseDate)
+	// compare as minutes
+	current := target.Truncate(time.Minute)
+	return !current.Before(base.Add(time.Duration(start)*time.Minute)) &&
+		!current.After(base.Add(time.Duration(end)*time.Minute))
+}
+
+// start date time of the range started at base date
+func (t *TimeRange) StartAt(baseDate time.Time) time.Time {
+	start, _ := t.toMinutes()
+	return startOfDate(baseDate).Add(time.Duration(start) * time.Minute)
+}
 
-	return common.IsInRangeTime(*tStart, *tEnd, target)
+func startOfDate(date time.Time) time.Time {
+	loc := common.GetStoreLocation()
+	date = date.In(loc)
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc)
 }
 
 func (t *TimeRange) GetStart() string {
@@ -109,7 +151,8 @@ func (d *DateRange) IsOverlap(other DateRange) bool {
 	oStart, _ := common.ConvertStrToDate(other.start)
 	oEnd, _ := common.ConvertStrToDate(other.end)
 
-	return common.IsOverlap(*tStart, *tEnd, *oStart, *oEnd)
+	// end date is included (same as InRangeDate)
+	return common.IsOverlap(*tStart, tEnd.AddDate(0, 0, 1), *oStart, oEnd.AddDate(0, 0, 1))
 }
 
 func (d *DateRange) InRangeDate(datetime time.Time) bool {
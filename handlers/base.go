@@ -23,6 +23,11 @@ func (b *BaseHandler) HandleError(c *gin.Context, e error) {
 		c.String(http.StatusBadRequest, vErr.Error())
 		return
 	}
+	var vErrs *common.ValidationErrors
+	if errors.As(e, &vErrs) {
+		c.String(http.StatusBadRequest, vErrs.Error())
+		return
+	}
 	var rErr *common.RelatedItemNotFoundError
 	if errors.As(e, &rErr) {
 		c.String(http.StatusBadRequest, rErr.Error())
@@ -43,6 +48,11 @@ func (b *BaseHandler) HandleError(c *gin.Context, e error) {
 		c.String(http.StatusNotFound, nErr.Error())
 		return
 	}
+	var cErr *common.ConflictError
+	if errors.As(e, &cErr) {
+		c.String(http.StatusConflict, cErr.Error())
+		return
+	}
 	b.HandleServerError(c)
 }
 
@@ -0,0 +1,53 @@
+package item
+
+import (
+	"chico/takeout/handlers"
+	usecase "chico/takeout/usecase/item"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ItemAlertResponse struct {
+	Id         string `json:"id" binding:"required"`
+	ItemId     string `json:"itemId" binding:"required"`
+	ItemType   string `json:"itemType" binding:"required"`
+	ItemName   string `json:"itemName" binding:"required"`
+	AlertType  string `json:"alertType" binding:"required"`
+	TargetDate string `json:"targetDate" binding:"required"`
+	Remain     int    `json:"remain"`
+	Threshold  int    `json:"threshold"`
+	CreatedAt  string `json:"createdAt" binding:"required"`
+}
+
+type itemAlertHandler struct {
+	*handlers.BaseHandler
+	usecase usecase.ItemAlertUseCase
+}
+
+func NewItemAlertHandler(u usecase.ItemAlertUseCase) *itemAlertHandler {
+	return &itemAlertHandler{usecase: u}
+}
+
+// query date is yyyy/MM/dd
+func (i *itemAlertHandler) GetByDate(c *gin.Context) {
+	alerts, err := i.usecase.FindByDate(c.Query("date"))
+	if err != nil {
+		i.HandleError(c, err)
+		return
+	}
+	models := []ItemAlertResponse{}
+	for _, alert := range alerts {
+		models = append(models, ItemAlertResponse{
+			Id:         alert.Id,
+			ItemId:     alert.ItemId,
+			ItemType:   alert.ItemType,
+			ItemName:   alert.ItemName,
+			AlertType:  alert.AlertType,
+			TargetDate: alert.TargetDate,
+			Remain:     alert.Remain,
+			Threshold:  alert.Threshold,
+			CreatedAt:  alert.CreatedAt,
+		})
+	}
+	i.HandleOK(c, models)
+}
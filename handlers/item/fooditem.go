@@ -12,6 +12,7 @@ type FoodItemResponse struct {
 	ScheduleIds    []string `json:"scheduleIds" binding:"required"`
 	MaxOrderPerDay int      `json:"maxOrderPerDay" binding:"required"`
 	AllowDates     []string `json:"allowDates" binding:"required"`
+	LeadTimeHours  uint     `json:"leadTimeHours" binding:"required"`
 }
 
 func newFoodItemData(item *usecase.FoodItemModel) *FoodItemResponse {
@@ -35,6 +36,7 @@ func newFoodItemData(item *usecase.FoodItemModel) *FoodItemResponse {
 		ScheduleIds:    item.ScheduleIds,
 		MaxOrderPerDay: item.MaxOrderPerDay,
 		AllowDates:     item.AllowDates,
+		LeadTimeHours:  item.LeadTimeHours,
 	}
 }
 
@@ -43,6 +45,8 @@ type FoodItemCreateRequest struct {
 	ScheduleIds    []string `json:"scheduleIds" binding:"required"`
 	MaxOrderPerDay int      `json:"maxOrderPerDay" binding:"required"`
 	AllowDates     []string `json:"allowDates" binding:"required"`
+	// optional. 0 means lead time of business hour is used
+	LeadTimeHours uint `json:"leadTimeHours"`
 }
 
 type FoodItemCreateResponse struct {
@@ -57,7 +61,7 @@ func (s *FoodItemCreateRequest) toModel() *usecase.FoodItemCreateModel {
 				Name: s.Name, Priority: s.Priority, MaxOrder: s.MaxOrder, Price: *s.Price, Description: s.Description, Enabled: *s.Enabled, ImageUrl: *s.ImageUrl,
 			},
 		},
-		ScheduleIds: s.ScheduleIds, MaxOrderPerDay: s.MaxOrderPerDay, AllowDates: s.AllowDates,
+		ScheduleIds: s.ScheduleIds, MaxOrderPerDay: s.MaxOrderPerDay, AllowDates: s.AllowDates, LeadTimeHours: s.LeadTimeHours,
 	}
 }
 
@@ -66,6 +70,8 @@ type FoodItemUpdateRequest struct {
 	ScheduleIds    []string `json:"scheduleIds" binding:"required"`
 	MaxOrderPerDay int      `json:"maxOrderPerDay" binding:"required"`
 	AllowDates     []string `json:"allowDates" binding:"required"`
+	// optional. 0 means lead time of business hour is used
+	LeadTimeHours uint `json:"leadTimeHours"`
 }
 
 func (s *FoodItemUpdateRequest) toModel(id string) *usecase.FoodItemUpdateModel {
@@ -77,7 +83,7 @@ func (s *FoodItemUpdateRequest) toModel(id string) *usecase.FoodItemUpdateModel
 				Name: s.Name, Priority: s.Priority, MaxOrder: s.MaxOrder, Price: *s.Price, Description: s.Description, Enabled: *s.Enabled, ImageUrl: *s.ImageUrl,
 			},
 		},
-		ScheduleIds: s.ScheduleIds, MaxOrderPerDay: s.MaxOrderPerDay, AllowDates: s.AllowDates,
+		ScheduleIds: s.ScheduleIds, MaxOrderPerDay: s.MaxOrderPerDay, AllowDates: s.AllowDates, LeadTimeHours: s.LeadTimeHours,
 	}
 }
 
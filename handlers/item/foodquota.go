@@ -0,0 +1,131 @@
+package item
+
+import (
+	"chico/takeout/common"
+	"chico/takeout/handlers"
+	usecase "chico/takeout/usecase/item"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FoodQuotaData struct {
+	Date       string `json:"date" binding:"required"`
+	HourTypeId string `json:"hourTypeId"`
+	Quantity   int    `json:"quantity"`
+	// manual or forecast
+	Source string `json:"source"`
+}
+
+type FoodQuotaRequest struct {
+	Date string `json:"date" binding:"required"`
+	// empty means whole day
+	HourTypeId string `json:"hourTypeId"`
+	// 0 means sold out
+	Quantity *int `json:"quantity" binding:"required,number,gte=0"`
+}
+
+func (f *FoodQuotaRequest) toModel(itemId string) *usecase.FoodQuotaModel {
+	return &usecase.FoodQuotaModel{ItemId: itemId, Date: f.Date, HourTypeId: f.HourTypeId, Quantity: *f.Quantity}
+}
+
+type FoodQuotaRangeRequest struct {
+	Start      string `json:"start" binding:"required"`
+	End        string `json:"end" binding:"required"`
+	HourTypeId string `json:"hourTypeId"`
+	// 0:Sunday ~ 6:Saturday. empty means every day
+	Weekdays []int `json:"weekdays"`
+	Quantity *int  `json:"quantity" binding:"required,number,gte=0"`
+}
+
+func (f *FoodQuotaRangeRequest) toModel(itemId string) *usecase.FoodQuotaRangeModel {
+	return &usecase.FoodQuotaRangeModel{ItemId: itemId, Start: f.Start, End: f.End, HourTypeId: f.HourTypeId, Weekdays: f.Weekdays, Quantity: *f.Quantity}
+}
+
+// given by query (ex:?start=2051/01/01&end=2051/01/31&weekdays=2&weekdays=4)
+type FoodQuotaRangeDeleteRequest struct {
+	Start      string `form:"start" binding:"required"`
+	End        string `form:"end" binding:"required"`
+	HourTypeId string `form:"hourTypeId"`
+	Weekdays   []int  `form:"weekdays"`
+}
+
+func (f *FoodQuotaRangeDeleteRequest) toModel(itemId string) *usecase.FoodQuotaRangeModel {
+	return &usecase.FoodQuotaRangeModel{ItemId: itemId, Start: f.Start, End: f.End, HourTypeId: f.HourTypeId, Weekdays: f.Weekdays}
+}
+
+type FoodQuotaRangeResponse struct {
+	Count int `json:"count"`
+}
+
+type foodQuotaHandler struct {
+	*handlers.BaseHandler
+	usecase usecase.FoodQuotaUseCase
+}
+
+func NewFoodQuotaHandler(u usecase.FoodQuotaUseCase) *foodQuotaHandler {
+	return &foodQuotaHandler{usecase: u}
+}
+
+// query start and end (yyyy/MM/dd) are required
+func (f *foodQuotaHandler) GetAll(c *gin.Context) {
+	quotas, err := f.usecase.FindByItem(c.Param("id"), c.Query("start"), c.Query("end"))
+	if err != nil {
+		f.HandleError(c, err)
+		return
+	}
+	data := []FoodQuotaData{}
+	for _, quota := range quotas {
+		data = append(data, FoodQuotaData{Date: quota.Date, HourTypeId: quota.HourTypeId, Quantity: quota.Quantity, Source: quota.Source})
+	}
+	f.HandleOK(c, data)
+}
+
+func (f *foodQuotaHandler) Put(c *gin.Context) {
+	var req FoodQuotaRequest
+	if !f.ShouldBind(c, &req) {
+		return
+	}
+	err := f.usecase.Save(req.toModel(c.Param("id")))
+	if err != nil {
+		f.HandleError(c, err)
+		return
+	}
+	f.HandleOK(c, nil)
+}
+
+// query date (yyyy/MM/dd) is required. hourTypeId is empty for whole day
+func (f *foodQuotaHandler) Delete(c *gin.Context) {
+	err := f.usecase.Delete(c.Param("id"), c.Query("date"), c.Query("hourTypeId"))
+	if err != nil {
+		f.HandleError(c, err)
+		return
+	}
+	f.HandleOK(c, nil)
+}
+
+func (f *foodQuotaHandler) PutRange(c *gin.Context) {
+	var req FoodQuotaRangeRequest
+	if !f.ShouldBind(c, &req) {
+		return
+	}
+	count, err := f.usecase.SaveRange(req.toModel(c.Param("id")))
+	if err != nil {
+		f.HandleError(c, err)
+		return
+	}
+	f.HandleOK(c, FoodQuotaRangeResponse{Count: count})
+}
+
+func (f *foodQuotaHandler) DeleteRange(c *gin.Context) {
+	var req FoodQuotaRangeDeleteRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		f.HandleError(c, common.NewValidationError("query", err.Error()))
+		return
+	}
+	count, err := f.usecase.DeleteRange(req.toModel(c.Param("id")))
+	if err != nil {
+		f.HandleError(c, err)
+		return
+	}
+	f.HandleOK(c, FoodQuotaRangeResponse{Count: count})
+}
@@ -0,0 +1,206 @@
+package item
+
+import (
+	"chico/takeout/handlers"
+	usecase "chico/takeout/usecase/item"
+
+	"github.com/gin-gonic/gin"
+)
+
+type IngredientCreateRequest struct {
+	Name          string `json:"name" binding:"required"`
+	Unit          string `json:"unit"`
+	DailyQuantity *int   `json:"dailyQuantity" binding:"required,number,gte=0"`
+}
+
+type IngredientCreateResponse struct {
+	Id string `json:"id" binding:"required"`
+}
+
+func (i *IngredientCreateRequest) toModel() *usecase.IngredientCreateModel {
+	return &usecase.IngredientCreateModel{Name: i.Name, Unit: i.Unit, DailyQuantity: *i.DailyQuantity}
+}
+
+type IngredientUpdateRequest struct {
+	Name          string `json:"name" binding:"required"`
+	Unit          string `json:"unit"`
+	DailyQuantity *int   `json:"dailyQuantity" binding:"required,number,gte=0"`
+}
+
+func (i *IngredientUpdateRequest) toModel(id string) *usecase.IngredientUpdateModel {
+	return &usecase.IngredientUpdateModel{Id: id, Name: i.Name, Unit: i.Unit, DailyQuantity: *i.DailyQuantity}
+}
+
+type IngredientData struct {
+	Id            string `json:"id"`
+	Name          string `json:"name" binding:"required"`
+	Unit          string `json:"unit"`
+	DailyQuantity int    `json:"dailyQuantity"`
+}
+
+type IngredientRemainData struct {
+	Id            string `json:"id"`
+	Name          string `json:"name" binding:"required"`
+	Unit          string `json:"unit"`
+	DailyQuantity int    `json:"dailyQuantity"`
+	Remain        int    `json:"remain"`
+}
+
+type RecipeIngredientData struct {
+	IngredientId string `json:"ingredientId" binding:"required"`
+	Quantity     int    `json:"quantity" binding:"required,gte=1"`
+}
+
+type RecipeRequest struct {
+	Ingredients []RecipeIngredientData `json:"ingredients" binding:"required,min=1,dive"`
+}
+
+func (r *RecipeRequest) toModel(itemId string) *usecase.RecipeModel {
+	ingredients := []usecase.RecipeIngredientModel{}
+	for _, ingredient := range r.Ingredients {
+		ingredients = append(ingredients, usecase.RecipeIngredientModel{IngredientId: ingredient.IngredientId, Quantity: ingredient.Quantity})
+	}
+	return &usecase.RecipeModel{ItemId: itemId, Ingredients: ingredients}
+}
+
+type RecipeData struct {
+	ItemId      string                 `json:"itemId" binding:"required"`
+	ItemType    string                 `json:"itemType" binding:"required"`
+	Ingredients []RecipeIngredientData `json:"ingredients" binding:"required"`
+}
+
+func newIngredientData(item *usecase.IngredientModel) *IngredientData {
+	return &IngredientData{
+		Id:            item.Id,
+		Name:          item.Name,
+		Unit:          item.Unit,
+		DailyQuantity: item.DailyQuantity,
+	}
+}
+
+type ingredientHandler struct {
+	*handlers.BaseHandler
+	usecase usecase.IngredientUseCase
+}
+
+func NewIngredientHandler(u usecase.IngredientUseCase) *ingredientHandler {
+	return &ingredientHandler{usecase: u}
+}
+
+func (i *ingredientHandler) GetAll(c *gin.Context) {
+	items, err := i.usecase.FindAll()
+	if err != nil {
+		i.HandleError(c, err)
+		return
+	}
+	models := []IngredientData{}
+	for _, item := range items {
+		models = append(models, *newIngredientData(&item))
+	}
+	i.HandleOK(c, models)
+}
+
+func (i *ingredientHandler) Get(c *gin.Context) {
+	id := c.Param("id")
+	item, err := i.usecase.Find(id)
+	if err != nil {
+		i.HandleError(c, err)
+		return
+	}
+	i.HandleOK(c, newIngredientData(item))
+}
+
+// query date is yyyy/MM/dd
+func (i *ingredientHandler) GetRemains(c *gin.Context) {
+	items, err := i.usecase.FindRemains(c.Query("date"))
+	if err != nil {
+		i.HandleError(c, err)
+		return
+	}
+	models := []IngredientRemainData{}
+	for _, item := range items {
+		models = append(models, IngredientRemainData{
+			Id:            item.Id,
+			Name:          item.Name,
+			Unit:          item.Unit,
+			DailyQuantity: item.DailyQuantity,
+			Remain:        item.Remain,
+		})
+	}
+	i.HandleOK(c, models)
+}
+
+func (i *ingredientHandler) Post(c *gin.Context) {
+	var req IngredientCreateRequest
+	if !i.ShouldBind(c, &req) {
+		return
+	}
+	id, err := i.usecase.Create(req.toModel())
+	if err != nil {
+		i.HandleError(c, err)
+		return
+	}
+	i.HandleOK(c, IngredientCreateResponse{Id: id})
+}
+
+func (i *ingredientHandler) Put(c *gin.Context) {
+	id := c.Param("id")
+	var req IngredientUpdateRequest
+	if !i.ShouldBind(c, &req) {
+		return
+	}
+	err := i.usecase.Update(req.toModel(id))
+	if err != nil {
+		i.HandleError(c, err)
+		return
+	}
+	i.HandleOK(c, nil)
+}
+
+func (i *ingredientHandler) Delete(c *gin.Context) {
+	id := c.Param("id")
+	err := i.usecase.Delete(id)
+	if err != nil {
+		i.HandleError(c, err)
+		return
+	}
+	i.HandleOK(c, nil)
+}
+
+func (i *ingredientHandler) GetRecipe(c *gin.Context) {
+	itemId := c.Param("itemId")
+	recipe, err := i.usecase.FindRecipe(itemId)
+	if err != nil {
+		i.HandleError(c, err)
+		return
+	}
+	ingredients := []RecipeIngredientData{}
+	for _, ingredient := range recipe.Ingredients {
+		ingredients = append(ingredients, RecipeIngredientData{IngredientId: ingredient.IngredientId, Quantity: ingredient.Quantity})
+	}
+	i.HandleOK(c, RecipeData{ItemId: recipe.ItemId, ItemType: recipe.ItemType, Ingredients: ingredients})
+}
+
+func (i *ingredientHandler) PutRecipe(c *gin.Context) {
+	itemId := c.Param("itemId")
+	var req RecipeRequest
+	if !i.ShouldBind(c, &req) {
+		return
+	}
+	err := i.usecase.SaveRecipe(req.toModel(itemId))
+	if err != nil {
+		i.HandleError(c, err)
+		return
+	}
+	i.HandleOK(c, nil)
+}
+
+func (i *ingredientHandler) DeleteRecipe(c *gin.Context) {
+	itemId := c.Param("itemId")
+	err := i.usecase.DeleteRecipe(itemId)
+	if err != nil {
+		i.HandleError(c, err)
+		return
+	}
+	i.HandleOK(c, nil)
+}
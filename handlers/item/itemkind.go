@@ -8,9 +8,57 @@ import (
 )
 
 type ItemKindCreateRequest struct {
-	Name          string   `json:"name" binding:"required"`
-	Priority      int      `json:"priority" binding:"required"`
-	OptionItemIds []string `json:"optionItemIds" binding:"required"`
+	Name          string            `json:"name" binding:"required"`
+	Priority      int               `json:"priority" binding:"required"`
+	OptionItemIds []string          `json:"optionItemIds" binding:"required"`
+	OptionGroups  []OptionGroupData `json:"optionGroups"`
+}
+
+// options of kind are grouped (ex: choose one spice level). 0 maxSelect means no limit
+type OptionGroupData struct {
+	Name             string   `json:"name" binding:"required"`
+	OptionItemIds    []string `json:"optionItemIds" binding:"required"`
+	Required         bool     `json:"required"`
+	MinSelect        uint     `json:"minSelect"`
+	MaxSelect        uint     `json:"maxSelect"`
+	MaxQuantity      uint     `json:"maxQuantity"`
+	DefaultOptionIds []string `json:"defaultOptionIds"`
+}
+
+func toOptionGroupModels(groups []OptionGroupData) []usecase.OptionGroupModel {
+	models := []usecase.OptionGroupModel{}
+	for _, group := range groups {
+		defaults := group.DefaultOptionIds
+		if defaults == nil {
+			defaults = []string{}
+		}
+		models = append(models, usecase.OptionGroupModel{
+			Name:             group.Name,
+			OptionItemIds:    group.OptionItemIds,
+			Required:         group.Required,
+			MinSelect:        group.MinSelect,
+			MaxSelect:        group.MaxSelect,
+			MaxQuantity:      group.MaxQuantity,
+			DefaultOptionIds: defaults,
+		})
+	}
+	return models
+}
+
+func newOptionGroupDataList(models []usecase.OptionGroupModel) []OptionGroupData {
+	groups := []OptionGroupData{}
+	for _, model := range models {
+		groups = append(groups, OptionGroupData{
+			Name:             model.Name,
+			OptionItemIds:    model.OptionItemIds,
+			Required:         model.Required,
+			MinSelect:        model.MinSelect,
+			MaxSelect:        model.MaxSelect,
+			MaxQuantity:      model.MaxQuantity,
+			DefaultOptionIds: model.DefaultOptionIds,
+		})
+	}
+	return groups
 }
 
 type ItemKindCreateResponse struct {
@@ -18,24 +66,26 @@ type ItemKindCreateResponse struct {
 }
 
 func (i *ItemKindCreateRequest) toModel() *usecase.ItemKindCreateModel {
-	return &usecase.ItemKindCreateModel{Name: i.Name, Priority: i.Priority, OptionItemIds: i.OptionItemIds}
+	return &usecase.ItemKindCreateModel{Name: i.Name, Priority: i.Priority, OptionItemIds: i.OptionItemIds, OptionGroups: toOptionGroupModels(i.OptionGroups)}
 }
 
 type ItemKindUpdateRequest struct {
-	Name          string   `json:"name" binding:"required"`
-	Priority      int      `json:"priority" binding:"required"`
-	OptionItemIds []string `json:"optionItemIds" binding:"required"`
+	Name          string            `json:"name" binding:"required"`
+	Priority      int               `json:"priority" binding:"required"`
+	OptionItemIds []string          `json:"optionItemIds" binding:"required"`
+	OptionGroups  []OptionGroupData `json:"optionGroups"`
 }
 
 func (i *ItemKindUpdateRequest) toModel(id string) *usecase.ItemKindUpdateModel {
-	return &usecase.ItemKindUpdateModel{Id: id, Name: i.Name, Priority: i.Priority, OptionItemIds: i.OptionItemIds}
+	return &usecase.ItemKindUpdateModel{Id: id, Name: i.Name, Priority: i.Priority, OptionItemIds: i.OptionItemIds, OptionGroups: toOptionGroupModels(i.OptionGroups)}
 }
 
 type ItemKindData struct {
-	Id            string   `json:"id"`
-	Name          string   `json:"name" binding:"required"`
-	Priority      int      `json:"priority" binding:"required"`
-	OptionItemIds []string `json:"optionItemIds" binding:"required"`
+	Id            string            `json:"id"`
+	Name          string            `json:"name" binding:"required"`
+	Priority      int               `json:"priority" binding:"required"`
+	OptionItemIds []string          `json:"optionItemIds" binding:"required"`
+	OptionGroups  []OptionGroupData `json:"optionGroups" binding:"required"`
 }
 
 func newItemKindData(item *usecase.ItemKindModel) *ItemKindData {
@@ -44,6 +94,7 @@ func newItemKindData(item *usecase.ItemKindModel) *ItemKindData {
 		Name:          item.Name,
 		Priority:      item.Priority,
 		OptionItemIds: item.OptionItemIds,
+		OptionGroups:  newOptionGroupDataList(item.OptionGroups),
 	}
 }
 
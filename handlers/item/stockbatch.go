@@ -0,0 +1,119 @@
+package item
+
+import (
+	"context"
+
+	"chico/takeout/handlers"
+	usecase "chico/takeout/usecase/item"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StockBatchData struct {
+	Id            string `json:"id" binding:"required"`
+	AvailableFrom string `json:"availableFrom" binding:"required"`
+	ExpiresAt     string `json:"expiresAt" binding:"required"`
+	Received      int    `json:"received"`
+	Remain        int    `json:"remain"`
+	Wasted        int    `json:"wasted"`
+	Consumed      int    `json:"consumed"`
+}
+
+func newStockBatchData(batch *usecase.StockBatchModel) *StockBatchData {
+	return &StockBatchData{
+		Id:            batch.Id,
+		AvailableFrom: batch.AvailableFrom,
+		ExpiresAt:     batch.ExpiresAt,
+		Received:      batch.Received,
+		Remain:        batch.Remain,
+		Wasted:        batch.Wasted,
+		Consumed:      batch.Consumed,
+	}
+}
+
+type StockBatchReceiveRequest struct {
+	AvailableFrom string `json:"availableFrom" binding:"required"`
+	ExpiresAt     string `json:"expiresAt" binding:"required"`
+	Quantity      int    `json:"quantity" binding:"required"`
+}
+
+type StockBatchReceiveResponse struct {
+	Id string `json:"id" binding:"required"`
+}
+
+func (s *StockBatchReceiveRequest) toModel(stockItemId string) *usecase.StockBatchReceiveModel {
+	return &usecase.StockBatchReceiveModel{
+		StockItemId:   stockItemId,
+		AvailableFrom: s.AvailableFrom,
+		ExpiresAt:     s.ExpiresAt,
+		Quantity:      s.Quantity,
+	}
+}
+
+type StockBatchWasteRequest struct {
+	Quantity int `json:"quantity" binding:"required"`
+}
+
+func (s *StockBatchWasteRequest) toModel(stockItemId, batchId string) *usecase.StockBatchWasteModel {
+	return &usecase.StockBatchWasteModel{
+		StockItemId: stockItemId,
+		BatchId:     batchId,
+		Quantity:    s.Quantity,
+	}
+}
+
+type stockBatchHandler struct {
+	*handlers.BaseHandler
+	usecase usecase.StockBatchUseCase
+}
+
+func NewStockBatchHandler(u usecase.StockBatchUseCase) *stockBatchHandler {
+	return &stockBatchHandler{usecase: u}
+}
+
+func (s *stockBatchHandler) InitContext(ctx context.Context) {
+	s.usecase.InitContext(ctx)
+}
+
+func (s *stockBatchHandler) GetAll(c *gin.Context) {
+	id := c.Param("id")
+	batches, err := s.usecase.FindByStockItem(id)
+	if err != nil {
+		s.HandleError(c, err)
+		return
+	}
+	models := []StockBatchData{}
+	for i := range batches {
+		models = append(models, *newStockBatchData(&batches[i]))
+	}
+	s.HandleOK(c, models)
+}
+
+func (s *stockBatchHandler) Post(c *gin.Context) {
+	id := c.Param("id")
+	var req StockBatchReceiveRequest
+	if !s.ShouldBind(c, &req) {
+		return
+	}
+	batchId, err := s.usecase.Receive(req.toModel(id))
+	if err != nil {
+		s.HandleError(c, err)
+		return
+	}
+	s.HandleOK(c, StockBatchReceiveResponse{Id: batchId})
+}
+
+func (s *stockBatchHandler) PutWaste(c *gin.Context) {
+	id := c.Param("id")
+	batchId := c.Param("batchId")
+	var req StockBatchWasteRequest
+	if !s.ShouldBind(c, &req) {
+		return
+	}
+	err := s.usecase.Waste(req.toModel(id, batchId))
+	if err != nil {
+		s.HandleError(c, err)
+		return
+	}
+	s.HandleOK(c, nil)
+}
@@ -1,6 +1,8 @@
 package item
 
 import (
+	"context"
+
 	"chico/takeout/handlers"
 	usecase "chico/takeout/usecase/item"
 
@@ -9,7 +11,9 @@ import (
 
 type StockItemResponse struct {
 	CommonItemResponse
-	Remain int `json:"remain" binding:"required"`
+	Remain            int  `json:"remain" binding:"required"`
+	TrackByBatch      bool `json:"trackByBatch"`
+	LowStockThreshold int  `json:"lowStockThreshold"`
 }
 
 func newStockItemData(item *usecase.StockItemModel) *StockItemResponse {
@@ -30,12 +34,17 @@ func newStockItemData(item *usecase.StockItemModel) *StockItemResponse {
 				ImageUrl:    &imageUrl,
 			},
 		},
-		Remain: item.Remain,
+		Remain:            item.Remain,
+		TrackByBatch:      item.TrackByBatch,
+		LowStockThreshold: item.LowStockThreshold,
 	}
 }
 
 type StockItemCreateRequest struct {
 	CommonItemCreateRequest
+	TrackByBatch bool `json:"trackByBatch"`
+	// 0 means only sold out is alerted
+	LowStockThreshold int `json:"lowStockThreshold"`
 }
 
 type StockItemCreateResponse struct {
@@ -50,11 +59,16 @@ func (s *StockItemCreateRequest) toModel() *usecase.StockItemCreateModel {
 				Name: s.Name, Priority: s.Priority, MaxOrder: s.MaxOrder, Price: *s.Price, Description: s.Description, Enabled: *s.Enabled, ImageUrl: *s.ImageUrl,
 			},
 		},
+		TrackByBatch:      s.TrackByBatch,
+		LowStockThreshold: s.LowStockThreshold,
 	}
 }
 
 type StockItemUpdateRequest struct {
 	CommonItemUpdateRequest
+	TrackByBatch bool `json:"trackByBatch"`
+	// 0 means only sold out is alerted
+	LowStockThreshold int `json:"lowStockThreshold"`
 }
 
 func (s *StockItemUpdateRequest) toModel(id string) *usecase.StockItemUpdateModel {
@@ -66,6 +80,8 @@ func (s *StockItemUpdateRequest) toModel(id string) *usecase.StockItemUpdateMode
 				Name: s.Name, Priority: s.Priority, MaxOrder: s.MaxOrder, Price: *s.Price, Description: s.Description, Enabled: *s.Enabled, ImageUrl: *s.ImageUrl,
 			},
 		},
+		TrackByBatch:      s.TrackByBatch,
+		LowStockThreshold: s.LowStockThreshold,
 	}
 }
 
@@ -86,6 +102,10 @@ func NewStockItemHandler(u usecase.StockItemUseCase) *stockItemHandler {
 	return &stockItemHandler{usecase: u}
 }
 
+func (i *stockItemHandler) InitContext(ctx context.Context) {
+	i.usecase.InitContext(ctx)
+}
+
 func (i *stockItemHandler) GetAll(c *gin.Context) {
 	items, err := i.usecase.FindAll()
 	if err != nil {
@@ -0,0 +1,123 @@
+package item
+
+import (
+	"context"
+
+	"chico/takeout/handlers"
+	usecase "chico/takeout/usecase/item"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StockMovementData struct {
+	Id           string `json:"id" binding:"required"`
+	BatchId      string `json:"batchId"`
+	MovementType string `json:"movementType" binding:"required"`
+	Quantity     int    `json:"quantity" binding:"required"`
+	OrderId      string `json:"orderId"`
+	UserId       string `json:"userId"`
+	Memo         string `json:"memo"`
+	CreatedAt    string `json:"createdAt" binding:"required"`
+}
+
+type StockMovementReportResponse struct {
+	StockItemId string              `json:"stockItemId" binding:"required"`
+	From        string              `json:"from" binding:"required"`
+	To          string              `json:"to" binding:"required"`
+	Movements   []StockMovementData `json:"movements" binding:"required"`
+	Receipt     int                 `json:"receipt"`
+	Order       int                 `json:"order"`
+	Cancel      int                 `json:"cancel"`
+	Adjustment  int                 `json:"adjustment"`
+	Waste       int                 `json:"waste"`
+	Net         int                 `json:"net"`
+}
+
+type StockDriftResponse struct {
+	StockItemId string `json:"stockItemId" binding:"required"`
+	Name        string `json:"name" binding:"required"`
+	Remain      int    `json:"remain"`
+	Balance     int    `json:"balance"`
+	Drift       int    `json:"drift"`
+}
+
+func newStockMovementReportResponse(report *usecase.StockMovementReportModel) *StockMovementReportResponse {
+	movements := []StockMovementData{}
+	for _, movement := range report.Movements {
+		movements = append(movements, StockMovementData{
+			Id:           movement.Id,
+			BatchId:      movement.BatchId,
+			MovementType: movement.MovementType,
+			Quantity:     movement.Quantity,
+			OrderId:      movement.OrderId,
+			UserId:       movement.UserId,
+			Memo:         movement.Memo,
+			CreatedAt:    movement.CreatedAt,
+		})
+	}
+	return &StockMovementReportResponse{
+		StockItemId: report.StockItemId,
+		From:        report.From,
+		To:          report.To,
+		Movements:   movements,
+		Receipt:     report.Receipt,
+		Order:       report.Order,
+		Cancel:      report.Cancel,
+		Adjustment:  report.Adjustment,
+		Waste:       report.Waste,
+		Net:         report.Net,
+	}
+}
+
+type stockMovementHandler struct {
+	*handlers.BaseHandler
+	usecase usecase.StockMovementUseCase
+}
+
+func NewStockMovementHandler(u usecase.StockMovementUseCase) *stockMovementHandler {
+	return &stockMovementHandler{usecase: u}
+}
+
+func (s *stockMovementHandler) InitContext(ctx context.Context) {
+	s.usecase.InitContext(ctx)
+}
+
+// query from and to are date (yyyy/MM/dd)
+func (s *stockMovementHandler) GetByStockItem(c *gin.Context) {
+	id := c.Param("id")
+	report, err := s.usecase.FindByStockItem(id, c.Query("from"), c.Query("to"))
+	if err != nil {
+		s.HandleError(c, err)
+		return
+	}
+	s.HandleOK(c, newStockMovementReportResponse(report))
+}
+
+func (s *stockMovementHandler) GetDrifts(c *gin.Context) {
+	drifts, err := s.usecase.FindDrifts()
+	if err != nil {
+		s.HandleError(c, err)
+		return
+	}
+	models := []StockDriftResponse{}
+	for _, drift := range drifts {
+		models = append(models, StockDriftResponse{
+			StockItemId: drift.StockItemId,
+			Name:        drift.Name,
+			Remain:      drift.Remain,
+			Balance:     drift.Balance,
+			Drift:       drift.Drift,
+		})
+	}
+	s.HandleOK(c, models)
+}
+
+func (s *stockMovementHandler) PutReconcile(c *gin.Context) {
+	id := c.Param("id")
+	err := s.usecase.Reconcile(id)
+	if err != nil {
+		s.HandleError(c, err)
+		return
+	}
+	s.HandleOK(c, nil)
+}
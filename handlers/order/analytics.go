@@ -0,0 +1,160 @@
+package order
+
+import (
+	"chico/takeout/handlers"
+	queryUseCases "chico/takeout/usecase/order/query"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AnalyticsSummaryResponse struct {
+	StartDate           string  `json:"startDate" binding:"required"`
+	EndDate             string  `json:"endDate" binding:"required"`
+	Basis               string  `json:"basis" binding:"required"`
+	OrderTotal          int     `json:"orderTotal"`
+	CanceledTotal       int     `json:"canceledTotal"`
+	QuantityTotal       int     `json:"quantityTotal"`
+	MoneyTotal          int     `json:"moneyTotal"`
+	AverageOrderValue   float64 `json:"averageOrderValue"`
+	CancellationRate    float64 `json:"cancellationRate"`
+	CustomerTotal       int     `json:"customerTotal"`
+	RepeatCustomerTotal int     `json:"repeatCustomerTotal"`
+	RepeatCustomerRatio float64 `json:"repeatCustomerRatio"`
+}
+
+type ItemAnalyticsData struct {
+	ItemId     string `json:"itemId" binding:"required"`
+	ItemType   string `json:"itemType" binding:"required"`
+	Name       string `json:"name" binding:"required"`
+	KindId     string `json:"kindId"`
+	Quantity   int    `json:"quantity"`
+	MoneyTotal int    `json:"moneyTotal"`
+}
+
+type KindAnalyticsData struct {
+	KindId     string `json:"kindId"`
+	Name       string `json:"name"`
+	Quantity   int    `json:"quantity"`
+	MoneyTotal int    `json:"moneyTotal"`
+}
+
+type OptionAnalyticsData struct {
+	OptionId   string `json:"optionId" binding:"required"`
+	Name       string `json:"name" binding:"required"`
+	Quantity   int    `json:"quantity"`
+	MoneyTotal int    `json:"moneyTotal"`
+}
+
+type DailyAnalyticsData struct {
+	Date          string `json:"date" binding:"required"`
+	OrderTotal    int    `json:"orderTotal"`
+	CanceledTotal int    `json:"canceledTotal"`
+	QuantityTotal int    `json:"quantityTotal"`
+	MoneyTotal    int    `json:"moneyTotal"`
+}
+
+type HeatmapAnalyticsData struct {
+	Weekday       int    `json:"weekday"`
+	HourTypeId    string `json:"hourTypeId"`
+	HourName      string `json:"hourName"`
+	StartTime     string `json:"startTime"`
+	OrderTotal    int    `json:"orderTotal"`
+	QuantityTotal int    `json:"quantityTotal"`
+	MoneyTotal    int    `json:"moneyTotal"`
+}
+
+type analyticsHandler struct {
+	*handlers.BaseHandler
+	queryUseCase *queryUseCases.OrderAnalyticsUseCase
+}
+
+func NewAnalyticsHandler(queryUseCase *queryUseCases.OrderAnalyticsUseCase) *analyticsHandler {
+	return &analyticsHandler{
+		queryUseCase: queryUseCase,
+	}
+}
+
+func (a *analyticsHandler) GetSummary(c *gin.Context) {
+	a.handle(c, func(m *queryUseCases.AnalyticsData) interface{} {
+		return &AnalyticsSummaryResponse{
+			StartDate:           m.StartDate,
+			EndDate:             m.EndDate,
+			Basis:               m.Basis,
+			OrderTotal:          m.Summary.OrderTotal,
+			CanceledTotal:       m.Summary.CanceledTotal,
+			QuantityTotal:       m.Summary.QuantityTotal,
+			MoneyTotal:          m.Summary.MoneyTotal,
+			AverageOrderValue:   m.Summary.AverageOrderValue,
+			CancellationRate:    m.Summary.CancellationRate,
+			CustomerTotal:       m.Summary.CustomerTotal,
+			RepeatCustomerTotal: m.Summary.RepeatCustomerTotal,
+			RepeatCustomerRatio: m.Summary.RepeatCustomerRatio,
+		}
+	})
+}
+
+func (a *analyticsHandler) GetItems(c *gin.Context) {
+	a.handle(c, func(m *queryUseCases.AnalyticsData) interface{} {
+		data := []ItemAnalyticsData{}
+		for _, d := range m.Items {
+			data = append(data, ItemAnalyticsData{ItemId: d.ItemId, ItemType: d.ItemType, Name: d.Name, KindId: d.KindId, Quantity: d.Quantity, MoneyTotal: d.MoneyTotal})
+		}
+		return data
+	})
+}
+
+func (a *analyticsHandler) GetKinds(c *gin.Context) {
+	a.handle(c, func(m *queryUseCases.AnalyticsData) interface{} {
+		data := []KindAnalyticsData{}
+		for _, d := range m.Kinds {
+			data = append(data, KindAnalyticsData{KindId: d.KindId, Name: d.Name, Quantity: d.Quantity, MoneyTotal: d.MoneyTotal})
+		}
+		return data
+	})
+}
+
+func (a *analyticsHandler) GetOptions(c *gin.Context) {
+	a.handle(c, func(m *queryUseCases.AnalyticsData) interface{} {
+		data := []OptionAnalyticsData{}
+		for _, d := range m.Options {
+			data = append(data, OptionAnalyticsData{OptionId: d.OptionId, Name: d.Name, Quantity: d.Quantity, MoneyTotal: d.MoneyTotal})
+		}
+		return data
+	})
+}
+
+func (a *analyticsHandler) GetDaily(c *gin.Context) {
+	a.handle(c, func(m *queryUseCases.AnalyticsData) interface{} {
+		data := []DailyAnalyticsData{}
+		for _, d := range m.Daily {
+			data = append(data, DailyAnalyticsData{Date: d.Date, OrderTotal: d.OrderTotal, CanceledTotal: d.CanceledTotal, QuantityTotal: d.QuantityTotal, MoneyTotal: d.MoneyTotal})
+		}
+		return data
+	})
+}
+
+func (a *analyticsHandler) GetHeatmap(c *gin.Context) {
+	a.handle(c, func(m *queryUseCases.AnalyticsData) interface{} {
+		data := []HeatmapAnalyticsData{}
+		for _, d := range m.Heatmap {
+			data = append(data, HeatmapAnalyticsData{Weekday: d.Weekday, HourTypeId: d.HourTypeId, HourName: d.HourName, StartTime: d.StartTime,
+				OrderTotal: d.OrderTotal, QuantityTotal: d.QuantityTotal, MoneyTotal: d.MoneyTotal})
+		}
+		return data
+	})
+}
+
+// query start and end (yyyy/MM/dd) are required. basis is order (default) or pickup
+func (a *analyticsHandler) handle(c *gin.Context, toResponse func(m *queryUseCases.AnalyticsData) interface{}) {
+	req := queryUseCases.AnalyticsRequestModel{
+		Start: c.Query("start"),
+		End:   c.Query("end"),
+		Basis: c.Query("basis"),
+	}
+	model, err := a.queryUseCase.FetchAnalytics(req)
+	if err != nil {
+		a.HandleError(c, err)
+		return
+	}
+	a.HandleOK(c, toResponse(model))
+}
@@ -0,0 +1,101 @@
+package order
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"chico/takeout/common"
+	"chico/takeout/handlers"
+	"chico/takeout/infrastructures/export"
+	queryUseCases "chico/takeout/usecase/order/query"
+
+	"github.com/gin-gonic/gin"
+)
+
+type exportHandler struct {
+	*handlers.BaseHandler
+	queryUseCase *queryUseCases.OrderExportUseCase
+}
+
+func NewExportHandler(queryUseCase *queryUseCases.OrderExportUseCase) *exportHandler {
+	return &exportHandler{
+		queryUseCase: queryUseCase,
+	}
+}
+
+// query start and end (yyyy/MM/dd) are required. basis is order (default) or pickup. format is csv (default) or xlsx
+func (e *exportHandler) GetOrders(c *gin.Context) {
+	req := queryUseCases.ExportRequestModel{Start: c.Query("start"), End: c.Query("end"), Basis: c.Query("basis")}
+	e.export(c, "orders_"+dateRangeFileName(req.Start, req.End), func(writer queryUseCases.TableWriter) error {
+		return e.queryUseCase.ExportOrders(req, writer)
+	})
+}
+
+// query start and end (yyyy/MM/dd) are required. format is csv (default) or xlsx
+func (e *exportHandler) GetDailySales(c *gin.Context) {
+	req := queryUseCases.ExportRequestModel{Start: c.Query("start"), End: c.Query("end")}
+	e.export(c, "sales_"+dateRangeFileName(req.Start, req.End), func(writer queryUseCases.TableWriter) error {
+		return e.queryUseCase.ExportDailySales(req, writer)
+	})
+}
+
+// query start and end (yyyy/MM) are required. format is csv (default) or xlsx
+func (e *exportHandler) GetMonthly(c *gin.Context) {
+	req := queryUseCases.MonthlyStatisticRequestModel{Start: c.Query("start"), End: c.Query("end"), Basis: c.Query("basis")}
+	e.export(c, "monthly_"+dateRangeFileName(req.Start, req.End), func(writer queryUseCases.TableWriter) error {
+		return e.queryUseCase.ExportMonthlyStatistic(req, writer)
+	})
+}
+
+func (e *exportHandler) export(c *gin.Context, fileName string, write func(writer queryUseCases.TableWriter) error) {
+	format := c.DefaultQuery("format", "csv")
+	out := &exportResponseWriter{context: c}
+	var writer queryUseCases.TableWriter
+	switch format {
+	case "csv":
+		out.contentType = "text/csv; charset=utf-8"
+		out.fileName = fileName + ".csv"
+		writer = export.NewCsvTableWriter(out)
+	case "xlsx":
+		out.contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+		out.fileName = fileName + ".xlsx"
+		writer = export.NewXlsxTableWriter(out, strings.Split(fileName, "_")[0])
+	default:
+		e.HandleError(c, common.NewValidationError("format", "should be csv or xlsx"))
+		return
+	}
+	err := write(writer)
+	if err != nil {
+		// response can not be changed after output is started
+		if out.started {
+			log.Printf("failed to export %s:%s", out.fileName, err)
+			c.Abort()
+			return
+		}
+		e.HandleError(c, err)
+	}
+}
+
+func dateRangeFileName(start, end string) string {
+	return fmt.Sprintf("%s_%s", strings.ReplaceAll(start, "/", ""), strings.ReplaceAll(end, "/", ""))
+}
+
+// headers are written on first output, so that validation error can be returned as json
+type exportResponseWriter struct {
+	context     *gin.Context
+	contentType string
+	fileName    string
+	started     bool
+}
+
+func (e *exportResponseWriter) Write(p []byte) (int, error) {
+	if !e.started {
+		e.started = true
+		e.context.Header("Content-Type", e.contentType)
+		e.context.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", e.fileName))
+		e.context.Status(http.StatusOK)
+	}
+	return e.context.Writer.Write(p)
+}
@@ -0,0 +1,103 @@
+package order
+
+import (
+	"chico/takeout/handlers"
+	queryUseCases "chico/takeout/usecase/order/query"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DemandForecastResponse struct {
+	StartDate        string                        `json:"startDate" binding:"required"`
+	EndDate          string                        `json:"endDate" binding:"required"`
+	HistoryStartDate string                        `json:"historyStartDate" binding:"required"`
+	HistoryEndDate   string                        `json:"historyEndDate" binding:"required"`
+	Foods            []FoodDemandForecastResponse  `json:"foods" binding:"required"`
+	Stocks           []StockDemandForecastResponse `json:"stocks" binding:"required"`
+}
+
+type FoodDemandForecastResponse struct {
+	ItemId string                          `json:"itemId" binding:"required"`
+	Name   string                          `json:"name" binding:"required"`
+	Days   []FoodDemandForecastDayResponse `json:"days" binding:"required"`
+}
+
+type FoodDemandForecastDayResponse struct {
+	Date           string  `json:"date" binding:"required"`
+	Estimate       float64 `json:"estimate"`
+	CurrentQuota   int     `json:"currentQuota"`
+	SuggestedQuota int     `json:"suggestedQuota"`
+}
+
+type StockDemandForecastResponse struct {
+	ItemId         string                           `json:"itemId" binding:"required"`
+	Name           string                           `json:"name" binding:"required"`
+	Estimate       float64                          `json:"estimate"`
+	Remain         int                              `json:"remain"`
+	SuggestedStock int                              `json:"suggestedStock"`
+	Shortage       int                              `json:"shortage"`
+	Days           []StockDemandForecastDayResponse `json:"days" binding:"required"`
+}
+
+type StockDemandForecastDayResponse struct {
+	Date     string  `json:"date" binding:"required"`
+	Estimate float64 `json:"estimate"`
+}
+
+func newDemandForecastResponse(m *queryUseCases.DemandForecastData) *DemandForecastResponse {
+	foods := []FoodDemandForecastResponse{}
+	for _, food := range m.Foods {
+		days := []FoodDemandForecastDayResponse{}
+		for _, day := range food.Days {
+			days = append(days, FoodDemandForecastDayResponse{Date: day.Date, Estimate: day.Estimate, CurrentQuota: day.CurrentQuota, SuggestedQuota: day.SuggestedQuota})
+		}
+		foods = append(foods, FoodDemandForecastResponse{ItemId: food.ItemId, Name: food.Name, Days: days})
+	}
+	stocks := []StockDemandForecastResponse{}
+	for _, stock := range m.Stocks {
+		days := []StockDemandForecastDayResponse{}
+		for _, day := range stock.Days {
+			days = append(days, StockDemandForecastDayResponse{Date: day.Date, Estimate: day.Estimate})
+		}
+		stocks = append(stocks, StockDemandForecastResponse{ItemId: stock.ItemId, Name: stock.Name, Estimate: stock.Estimate,
+			Remain: stock.Remain, SuggestedStock: stock.SuggestedStock, Shortage: stock.Shortage, Days: days})
+	}
+	return &DemandForecastResponse{
+		StartDate:        m.StartDate,
+		EndDate:          m.EndDate,
+		HistoryStartDate: m.HistoryStartDate,
+		HistoryEndDate:   m.HistoryEndDate,
+		Foods:            foods,
+		Stocks:           stocks,
+	}
+}
+
+type forecastHandler struct {
+	*handlers.BaseHandler
+	useCase *queryUseCases.DemandForecastUseCase
+}
+
+func NewForecastHandler(useCase *queryUseCases.DemandForecastUseCase) *forecastHandler {
+	return &forecastHandler{
+		useCase: useCase,
+	}
+}
+
+func (f *forecastHandler) Get(c *gin.Context) {
+	model, err := f.useCase.FetchForecast()
+	if err != nil {
+		f.HandleError(c, err)
+		return
+	}
+	f.HandleOK(c, newDemandForecastResponse(model))
+}
+
+// suggested quotas are saved as per-date quotas of food items
+func (f *forecastHandler) PostApply(c *gin.Context) {
+	model, err := f.useCase.ApplyForecast()
+	if err != nil {
+		f.HandleError(c, err)
+		return
+	}
+	f.HandleOK(c, newDemandForecastResponse(model))
+}
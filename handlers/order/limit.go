@@ -0,0 +1,62 @@
+package order
+
+import (
+	"chico/takeout/handlers"
+	usecase "chico/takeout/usecase/order"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 0 means no limit
+type OrderLimitData struct {
+	MaxActivePerUser uint `json:"maxActivePerUser"`
+	MaxPerPickupDate uint `json:"maxPerPickupDate"`
+	MaxPerHourSlot   uint `json:"maxPerHourSlot"`
+}
+
+func newOrderLimitData(item *usecase.OrderLimitModel) *OrderLimitData {
+	return &OrderLimitData{
+		MaxActivePerUser: item.MaxActivePerUser,
+		MaxPerPickupDate: item.MaxPerPickupDate,
+		MaxPerHourSlot:   item.MaxPerHourSlot,
+	}
+}
+
+func (o *OrderLimitData) toModel() *usecase.OrderLimitModel {
+	return &usecase.OrderLimitModel{
+		MaxActivePerUser: o.MaxActivePerUser,
+		MaxPerPickupDate: o.MaxPerPickupDate,
+		MaxPerHourSlot:   o.MaxPerHourSlot,
+	}
+}
+
+type orderLimitHandler struct {
+	*handlers.BaseHandler
+	usecase usecase.OrderLimitUseCase
+}
+
+func NewOrderLimitHandler(u usecase.OrderLimitUseCase) *orderLimitHandler {
+	return &orderLimitHandler{usecase: u}
+}
+
+func (o *orderLimitHandler) Get(c *gin.Context) {
+	item, err := o.usecase.Fetch()
+	if err != nil {
+		o.HandleError(c, err)
+		return
+	}
+	o.HandleOK(c, newOrderLimitData(item))
+}
+
+func (o *orderLimitHandler) Put(c *gin.Context) {
+	var req OrderLimitData
+	if !o.ShouldBind(c, &req) {
+		return
+	}
+	err := o.usecase.Update(req.toModel())
+	if err != nil {
+		o.HandleError(c, err)
+		return
+	}
+	o.HandleOK(c, nil)
+}
@@ -21,6 +21,8 @@ type OrderInfoData struct {
 	StockItems     []CommonItemOrderData `json:"stockItems" binding:"required"`
 	FoodItems      []CommonItemOrderData `json:"foodItems" binding:"required"`
 	Canceled       bool                  `json:"canceled" binding:"required"`
+	CancelReason   string                `json:"cancelReason" binding:"required"`
+	Source         string                `json:"source" binding:"required"`
 }
 
 type CommonItemOrderData struct {
@@ -32,9 +34,10 @@ type CommonItemOrderData struct {
 }
 
 type OptionItemOrderData struct {
-	ItemId string `json:"itemId" binding:"required"`
-	Name   string `json:"name" binding:"required"`
-	Price  int    `json:"price" binding:"required"`
+	ItemId   string `json:"itemId" binding:"required"`
+	Name     string `json:"name" binding:"required"`
+	Price    int    `json:"price" binding:"required"`
+	Quantity int    `json:"quantity" binding:"required"`
 }
 
 func newCommonItemOrderData(itemId, name string, price, quantity int, options []OptionItemOrderData) *CommonItemOrderData {
@@ -47,11 +50,12 @@ func newCommonItemOrderData(itemId, name string, price, quantity int, options []
 	}
 }
 
-func newOptionItemOrderData(itemId, name string, price int) *OptionItemOrderData {
+func newOptionItemOrderData(itemId, name string, price, quantity int) *OptionItemOrderData {
 	return &OptionItemOrderData{
 		ItemId:   itemId,
 		Name:     name,
 		Price:    price,
+		Quantity: quantity,
 	}
 }
 
@@ -60,7 +64,7 @@ func newOrderInfoData(item *usecases.OrderInfoModel) *OrderInfoData {
 	for _, stock := range item.StockItems {
 		options := []OptionItemOrderData{}
 		for _, opt := range stock.Options {
-			options = append(options, *newOptionItemOrderData(opt.ItemId, opt.Name, opt.Price))
+			options = append(options, *newOptionItemOrderData(opt.ItemId, opt.Name, opt.Price, opt.Quantity))
 		}
 		stocks = append(stocks, *newCommonItemOrderData(stock.ItemId, stock.Name, stock.Price, stock.Quantity, options))
 	}
@@ -68,7 +72,7 @@ func newOrderInfoData(item *usecases.OrderInfoModel) *OrderInfoData {
 	for _, food := range item.FoodItems {
 		options := []OptionItemOrderData{}
 		for _, opt := range food.Options {
-			options = append(options, *newOptionItemOrderData(opt.ItemId, opt.Name, opt.Price))
+			options = append(options, *newOptionItemOrderData(opt.ItemId, opt.Name, opt.Price, opt.Quantity))
 		}
 		foods = append(foods, *newCommonItemOrderData(food.ItemId, food.Name, food.Price, food.Quantity, options))
 	}
@@ -82,6 +86,8 @@ func newOrderInfoData(item *usecases.OrderInfoModel) *OrderInfoData {
 		OrderDateTime:  item.OrderDateTime,
 		PickupDateTime: item.PickupDateTime,
 		Canceled:       item.Canceled,
+		CancelReason:   item.CancelReason,
+		Source:         item.Source,
 		StockItems:     stocks,
 		FoodItems:      foods,
 	}
@@ -119,6 +125,90 @@ func (o *OrderInfoCreateRequest) toModel() *usecases.OrderInfoCreateModel {
 	}
 }
 
+// email is optional. mail is not sent if empty
+type OrderInfoProxyCreateRequest struct {
+	Source         string                   `json:"source" binding:"required"`
+	UserName       string                   `json:"userName" binding:"required"`
+	UserEmail      string                   `json:"userEmail"`
+	UserTelNo      string                   `json:"userTelNo" binding:"required"`
+	Memo           string                   `json:"memo"`
+	PickupDateTime string                   `json:"pickupDateTime" binding:"required"`
+	IgnoreLeadTime bool                     `json:"ignoreLeadTime"`
+	StockItems     []CommonItemOrderRequest `json:"stockItems" binding:"required"`
+	FoodItems      []CommonItemOrderRequest `json:"foodItems" binding:"required"`
+}
+
+func (o *OrderInfoProxyCreateRequest) toModel() *usecases.OrderProxyCreateModel {
+	stocks := []usecases.CommonItemOrderCreateModel{}
+	for _, stock := range o.StockItems {
+		stocks = append(stocks, *stock.toModel())
+	}
+	foods := []usecases.CommonItemOrderCreateModel{}
+	for _, food := range o.FoodItems {
+		foods = append(foods, *food.toModel())
+	}
+	return &usecases.OrderProxyCreateModel{
+		Source:         o.Source,
+		UserName:       o.UserName,
+		UserEmail:      o.UserEmail,
+		UserTelNo:      o.UserTelNo,
+		Memo:           o.Memo,
+		PickupDateTime: o.PickupDateTime,
+		IgnoreLeadTime: o.IgnoreLeadTime,
+		StockItems:     stocks,
+		FoodItems:      foods,
+	}
+}
+
+// guest checkout without account. email and tel no are required
+type OrderInfoGuestCreateRequest struct {
+	UserName       string                   `json:"userName" binding:"required"`
+	UserEmail      string                   `json:"userEmail" binding:"required"`
+	UserTelNo      string                   `json:"userTelNo" binding:"required"`
+	Memo           string                   `json:"memo"`
+	PickupDateTime string                   `json:"pickupDateTime" binding:"required"`
+	StockItems     []CommonItemOrderRequest `json:"stockItems" binding:"required"`
+	FoodItems      []CommonItemOrderRequest `json:"foodItems" binding:"required"`
+}
+
+func (o *OrderInfoGuestCreateRequest) toModel() *usecases.OrderGuestCreateModel {
+	stocks := []usecases.CommonItemOrderCreateModel{}
+	for _, stock := range o.StockItems {
+		stocks = append(stocks, *stock.toModel())
+	}
+	foods := []usecases.CommonItemOrderCreateModel{}
+	for _, food := range o.FoodItems {
+		foods = append(foods, *food.toModel())
+	}
+	return &usecases.OrderGuestCreateModel{
+		UserName:       o.UserName,
+		UserEmail:      o.UserEmail,
+		UserTelNo:      o.UserTelNo,
+		Memo:           o.Memo,
+		PickupDateTime: o.PickupDateTime,
+		StockItems:     stocks,
+		FoodItems:      foods,
+	}
+}
+
+type OrderInfoGuestCreateResponse struct {
+	Id    string `json:"id" binding:"required"`
+	Token string `json:"token" binding:"required"`
+}
+
+type OrderInfoGuestCancelRequest struct {
+	Token  string
+	Reason string `json:"reason"`
+}
+
+func (o *OrderInfoGuestCancelRequest) toModel() *usecases.OrderGuestCancelModel {
+	return &usecases.OrderGuestCancelModel{Token: o.Token, Reason: o.Reason}
+}
+
+type OrderGuestLinkResponse struct {
+	Linked int `json:"linked"`
+}
+
 type OrderInfoCreateResponse struct {
 	Id string `json:"id" binding:"required"`
 }
@@ -131,16 +221,77 @@ type CommonItemOrderRequest struct {
 
 type OptionItemOrderRequest struct {
 	ItemId string `json:"itemId" binding:"required"`
+	// optional. one if not specified
+	Quantity int `json:"quantity"`
+}
+
+type OrderInfoAmendRequest struct {
+	Id             string
+	PickupDateTime string                   `json:"pickupDateTime" binding:"required"`
+	StockItems     []CommonItemOrderRequest `json:"stockItems" binding:"required"`
+	FoodItems      []CommonItemOrderRequest `json:"foodItems" binding:"required"`
+}
+
+func (o *OrderInfoAmendRequest) toModel() *usecases.OrderAmendModel {
+	stocks := []usecases.CommonItemOrderCreateModel{}
+	for _, stock := range o.StockItems {
+		stocks = append(stocks, *stock.toModel())
+	}
+	foods := []usecases.CommonItemOrderCreateModel{}
+	for _, food := range o.FoodItems {
+		foods = append(foods, *food.toModel())
+	}
+	return &usecases.OrderAmendModel{
+		OrderId:        o.Id,
+		PickupDateTime: o.PickupDateTime,
+		StockItems:     stocks,
+		FoodItems:      foods,
+	}
+}
+
+type OrderRevisionData struct {
+	Revision       int                   `json:"revision" binding:"required"`
+	PickupDateTime string                `json:"pickupDateTime" binding:"required"`
+	AmendedAt      string                `json:"amendedAt" binding:"required"`
+	StockItems     []CommonItemOrderData `json:"stockItems" binding:"required"`
+	FoodItems      []CommonItemOrderData `json:"foodItems" binding:"required"`
+}
+
+func newCommonItemOrderDataList(items []usecases.CommonItemOrderModel) []CommonItemOrderData {
+	results := []CommonItemOrderData{}
+	for _, item := range items {
+		options := []OptionItemOrderData{}
+		for _, opt := range item.Options {
+			options = append(options, *newOptionItemOrderData(opt.ItemId, opt.Name, opt.Price, opt.Quantity))
+		}
+		results = append(results, *newCommonItemOrderData(item.ItemId, item.Name, item.Price, item.Quantity, options))
+	}
+	return results
+}
+
+func newOrderRevisionData(model *usecases.OrderRevisionModel) *OrderRevisionData {
+	return &OrderRevisionData{
+		Revision:       model.Revision,
+		PickupDateTime: model.PickupDateTime,
+		AmendedAt:      model.AmendedAt,
+		StockItems:     newCommonItemOrderDataList(model.StockItems),
+		FoodItems:      newCommonItemOrderDataList(model.FoodItems),
+	}
 }
 
 type OrderInfoCancelRequest struct {
-	Id string
+	Id     string
+	Reason string `json:"reason"`
+}
+
+func (o *OrderInfoCancelRequest) toModel() *usecases.OrderCancelModel {
+	return usecases.NewOrderCancelModel(o.Id, o.Reason)
 }
 
 func (c *CommonItemOrderRequest) toModel() *usecases.CommonItemOrderCreateModel {
 	options := []usecases.OptionItemOrderCreateModel{}
 	for _, opt := range c.Options {
-		options = append(options, usecases.OptionItemOrderCreateModel{ItemId: opt.ItemId})
+		options = append(options, usecases.OptionItemOrderCreateModel{ItemId: opt.ItemId, Quantity: opt.Quantity})
 	}
 	return &usecases.CommonItemOrderCreateModel{
 		ItemId:   c.ItemId,
@@ -269,10 +420,74 @@ func (s *orderInfoHandler) PostCreate(c *gin.Context) {
 	s.HandleOK(c, OrderInfoCreateResponse{Id: id})
 }
 
+func (s *orderInfoHandler) PostProxyCreate(c *gin.Context) {
+	var req OrderInfoProxyCreateRequest
+	if !s.ShouldBind(c, &req) {
+		return
+	}
+	id, err := s.usecase.CreateProxy(req.toModel())
+	if err != nil {
+		s.HandleError(c, err)
+		return
+	}
+	s.HandleOK(c, OrderInfoCreateResponse{Id: id})
+}
+
+func (s *orderInfoHandler) PostGuestCreate(c *gin.Context) {
+	var req OrderInfoGuestCreateRequest
+	if !s.ShouldBind(c, &req) {
+		return
+	}
+	model, err := s.usecase.CreateGuest(req.toModel())
+	if err != nil {
+		s.HandleError(c, err)
+		return
+	}
+	s.HandleOK(c, OrderInfoGuestCreateResponse{Id: model.Id, Token: model.Token})
+}
+
+func (s *orderInfoHandler) GetByGuestToken(c *gin.Context) {
+	model, err := s.usecase.FindByGuestToken(c.Param("token"))
+	if err != nil {
+		s.HandleError(c, err)
+		return
+	}
+	s.HandleOK(c, newOrderInfoData(model))
+}
+
+func (s *orderInfoHandler) PutGuestCancel(c *gin.Context) {
+	req := OrderInfoGuestCancelRequest{}
+	// body is optional
+	if c.Request.ContentLength != 0 && !s.ShouldBind(c, &req) {
+		return
+	}
+	req.Token = c.Param("token")
+	err := s.usecase.CancelByGuestToken(req.toModel())
+	if err != nil {
+		s.HandleError(c, err)
+		return
+	}
+	s.HandleOK(c, nil)
+}
+
+func (s *orderInfoHandler) PutLinkGuest(c *gin.Context) {
+	linked, err := s.usecase.LinkGuestOrders()
+	if err != nil {
+		s.HandleError(c, err)
+		return
+	}
+	s.HandleOK(c, OrderGuestLinkResponse{Linked: linked})
+}
+
 func (s *orderInfoHandler) PutCancel(c *gin.Context) {
 	id := c.Param("id")
-	req := OrderInfoCancelRequest{Id: id}
-	err := s.usecase.Cancel(req.Id)
+	req := OrderInfoCancelRequest{}
+	// body is optional (reason is not needed for user's cancel)
+	if c.Request.ContentLength != 0 && !s.ShouldBind(c, &req) {
+		return
+	}
+	req.Id = id
+	err := s.usecase.Cancel(req.toModel())
 	if err != nil {
 		s.HandleError(c, err)
 		return
@@ -280,6 +495,33 @@ func (s *orderInfoHandler) PutCancel(c *gin.Context) {
 	s.HandleOK(c, nil)
 }
 
+func (s *orderInfoHandler) PutAmend(c *gin.Context) {
+	var req OrderInfoAmendRequest
+	if !s.ShouldBind(c, &req) {
+		return
+	}
+	req.Id = c.Param("id")
+	err := s.usecase.Amend(req.toModel())
+	if err != nil {
+		s.HandleError(c, err)
+		return
+	}
+	s.HandleOK(c, nil)
+}
+
+func (s *orderInfoHandler) GetRevisions(c *gin.Context) {
+	models, err := s.usecase.FindRevisions(c.Param("id"))
+	if err != nil {
+		s.HandleError(c, err)
+		return
+	}
+	revisions := []OrderRevisionData{}
+	for _, model := range models {
+		revisions = append(revisions, *newOrderRevisionData(&model))
+	}
+	s.HandleOK(c, revisions)
+}
+
 func (s *orderInfoHandler) PutUpdateUserInfo(c *gin.Context) {
 	userId := c.Param("userId")
 	orderId := c.Param("orderId")
@@ -0,0 +1,202 @@
+package order
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+
+	"chico/takeout/common"
+	"chico/takeout/handlers"
+	"chico/takeout/infrastructures/export"
+	usecase "chico/takeout/usecase/order"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PrepOptionData struct {
+	OptionId string `json:"optionId" binding:"required"`
+	Name     string `json:"name" binding:"required"`
+	Quantity int    `json:"quantity" binding:"required"`
+}
+
+type PrepItemData struct {
+	ItemId   string           `json:"itemId" binding:"required"`
+	ItemType string           `json:"itemType" binding:"required"`
+	Name     string           `json:"name" binding:"required"`
+	Quantity int              `json:"quantity" binding:"required"`
+	Options  []PrepOptionData `json:"options" binding:"required"`
+}
+
+type PrepMemoData struct {
+	PickupDateTime string `json:"pickupDateTime" binding:"required"`
+	UserName       string `json:"userName" binding:"required"`
+	Memo           string `json:"memo" binding:"required"`
+}
+
+type PrepListResponse struct {
+	Date       string         `json:"date" binding:"required"`
+	HourTypeId string         `json:"hourTypeId" binding:"required"`
+	HourName   string         `json:"hourName" binding:"required"`
+	StartTime  string         `json:"startTime" binding:"required"`
+	EndTime    string         `json:"endTime" binding:"required"`
+	OrderCount int            `json:"orderCount"`
+	Items      []PrepItemData `json:"items" binding:"required"`
+	Memos      []PrepMemoData `json:"memos" binding:"required"`
+}
+
+func newPrepListResponse(model *usecase.PrepListModel) *PrepListResponse {
+	items := []PrepItemData{}
+	for _, item := range model.Items {
+		options := []PrepOptionData{}
+		for _, opt := range item.Options {
+			options = append(options, PrepOptionData{OptionId: opt.OptionId, Name: opt.Name, Quantity: opt.Quantity})
+		}
+		items = append(items, PrepItemData{
+			ItemId:   item.ItemId,
+			ItemType: item.ItemType,
+			Name:     item.Name,
+			Quantity: item.Quantity,
+			Options:  options,
+		})
+	}
+	memos := []PrepMemoData{}
+	for _, memo := range model.Memos {
+		memos = append(memos, PrepMemoData{PickupDateTime: memo.PickupDateTime, UserName: memo.UserName, Memo: memo.Memo})
+	}
+	return &PrepListResponse{
+		Date:       model.Date,
+		HourTypeId: model.HourTypeId,
+		HourName:   model.HourName,
+		StartTime:  model.StartTime,
+		EndTime:    model.EndTime,
+		OrderCount: model.OrderCount,
+		Items:      items,
+		Memos:      memos,
+	}
+}
+
+type prepReportHandler struct {
+	*handlers.BaseHandler
+	usecase usecase.PrepReportUseCase
+}
+
+func NewPrepReportHandler(u usecase.PrepReportUseCase) *prepReportHandler {
+	return &prepReportHandler{usecase: u}
+}
+
+// query date (yyyy/MM/dd) and hourId are required.
+// format is json (default), csv or html
+func (p *prepReportHandler) Get(c *gin.Context) {
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" && format != "html" {
+		p.HandleError(c, common.NewValidationError("format", "should be json, csv or html"))
+		return
+	}
+	model, err := p.usecase.FindPrepList(c.Query("date"), c.Query("hourId"))
+	if err != nil {
+		p.HandleError(c, err)
+		return
+	}
+	fileName := "prep_" + strings.ReplaceAll(model.Date, "/", "")
+	switch format {
+	case "csv":
+		data, err := writePrepCsv(model)
+		if err != nil {
+			p.HandleError(c, err)
+			return
+		}
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s.csv", fileName))
+		c.Data(http.StatusOK, "text/csv; charset=utf-8", data)
+	case "html":
+		data, err := writePrepHtml(model)
+		if err != nil {
+			p.HandleError(c, err)
+			return
+		}
+		c.Data(http.StatusOK, "text/html; charset=utf-8", data)
+	default:
+		p.HandleOK(c, newPrepListResponse(model))
+	}
+}
+
+// prep list is sent to admin by mail
+func (p *prepReportHandler) PostMail(c *gin.Context) {
+	err := p.usecase.SendPrepList(c.Query("date"), c.Query("hourId"))
+	if err != nil {
+		p.HandleError(c, err)
+		return
+	}
+	p.HandleOK(c, nil)
+}
+
+// item rows are followed by option rows of the item, memo rows are last.
+// written by the same writer as order export, so customer text is escaped and excel reads it as utf-8
+func writePrepCsv(model *usecase.PrepListModel) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	writer := export.NewCsvTableWriter(buf)
+	rows := [][]interface{}{{"type", "name", "option", "quantity", "pickupDateTime", "userName", "memo"}}
+	for _, item := range model.Items {
+		rows = append(rows, []interface{}{item.ItemType, item.Name, "", item.Quantity, "", "", ""})
+		for _, opt := range item.Options {
+			rows = append(rows, []interface{}{"option", item.Name, opt.Name, opt.Quantity, "", "", ""})
+		}
+	}
+	for _, memo := range model.Memos {
+		rows = append(rows, []interface{}{"memo", "", "", "", memo.PickupDateTime, memo.UserName, memo.Memo})
+	}
+	for _, row := range rows {
+		if err := writer.WriteRow(row); err != nil {
+			return nil, err
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+var prepHtmlTemplate = template.Must(template.New("prep").Parse(`<!DOCTYPE html>
+<html lang="ja">
+<head>
+<meta charset="utf-8">
+<title>仕込みリスト {{.Date}} {{.StartTime}} ~ {{.EndTime}}</title>
+<style>
+body { font-family: sans-serif; margin: 16px; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 16px; }
+th, td { border: 1px solid #333; padding: 4px 8px; text-align: left; }
+td.quantity { text-align: right; width: 6em; }
+tr.option td { padding-left: 24px; }
+@media print { body { margin: 0; } }
+</style>
+</head>
+<body>
+<h1>仕込みリスト</h1>
+<p>{{.Date}} {{.HourName}} ({{.StartTime}} ~ {{.EndTime}}) 注文数:{{.OrderCount}}</p>
+<table>
+<thead><tr><th>商品</th><th>数量</th></tr></thead>
+<tbody>
+{{range .Items}}<tr><td>{{.Name}}</td><td class="quantity">{{.Quantity}}</td></tr>
+{{range .Options}}<tr class="option"><td>{{.Name}}</td><td class="quantity">{{.Quantity}}</td></tr>
+{{end}}{{end}}</tbody>
+</table>
+{{if .Memos}}<h2>要望やメッセージ</h2>
+<table>
+<thead><tr><th>受取日時</th><th>氏名</th><th>メッセージ</th></tr></thead>
+<tbody>
+{{range .Memos}}<tr><td>{{.PickupDateTime}}</td><td>{{.UserName}}</td><td>{{.Memo}}</td></tr>
+{{end}}</tbody>
+</table>
+{{end}}</body>
+</html>
+`))
+
+func writePrepHtml(model *usecase.PrepListModel) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	err := prepHtmlTemplate.Execute(buf, model)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -12,7 +12,15 @@ type MonthlyStatisticResponse struct {
 }
 
 type MonthlyData struct {
-	Month         string `json:"month" binding:"required"`
+	Month         string              `json:"month" binding:"required"`
+	OrderTotal    int                 `json:"orderTotal" binding:"required"`
+	QuantityTotal int                 `json:"quantityTotal" binding:"required"`
+	MoneyTotal    int                 `json:"moneyTotal" binding:"required"`
+	Sources       []MonthlySourceData `json:"sources" binding:"required"`
+}
+
+type MonthlySourceData struct {
+	Source        string `json:"source" binding:"required"`
 	OrderTotal    int    `json:"orderTotal" binding:"required"`
 	QuantityTotal int    `json:"quantityTotal" binding:"required"`
 	MoneyTotal    int    `json:"moneyTotal" binding:"required"`
@@ -31,11 +39,21 @@ func newMonthlyStatisticResponse(m queryUseCases.MonthlyStatisticData) *MonthlyS
 }
 
 func newMonthlyData(d queryUseCases.MonthlyData) *MonthlyData {
+	sources := []MonthlySourceData{}
+	for _, s := range d.Sources {
+		sources = append(sources, MonthlySourceData{
+			Source:        s.Source,
+			OrderTotal:    s.OrderTotal,
+			QuantityTotal: s.QuantityTotal,
+			MoneyTotal:    s.MoneyTotal,
+		})
+	}
 	return &MonthlyData{
 		Month:         d.Month,
 		OrderTotal:    d.OrderTotal,
 		QuantityTotal: d.QuantityTotal,
 		MoneyTotal:    d.MoneyTotal,
+		Sources:       sources,
 	}
 }
 
@@ -56,6 +74,7 @@ func (s *statisticInfoHandler) GetMonthly(c *gin.Context) {
 	req := queryUseCases.MonthlyStatisticRequestModel{
 		Start: start,
 		End:   end,
+		Basis: c.Query("basis"),
 	}
 	model, err := s.queryUseCase.FetchMonthlyData(req)
 	if err != nil {
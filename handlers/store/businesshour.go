@@ -21,47 +21,94 @@ func newBusinessHoursData(model usecases.BusinessHoursModel) *BusinessHoursData
 	}
 }
 
+type BusinessHoursCreateData struct {
+	Name          string             `json:"name" binding:"required"`
+	Start         string             `json:"start" binding:"required"`
+	End           string             `json:"end" binding:"required"`
+	Weekdays      []usecases.Weekday `json:"weekdays" binding:"required"`
+	OffsetHour    uint               `json:"offsetHour" binding:"required"`
+	WeekdayShifts []WeekdayShiftData `json:"weekdayShifts"`
+}
+
+func (b *BusinessHoursCreateData) toModel() *usecases.BusinessHoursCreateModel {
+	return &usecases.BusinessHoursCreateModel{
+		Name:          b.Name,
+		Start:         b.Start,
+		End:           b.End,
+		Weekdays:      b.Weekdays,
+		OffsetHour:    b.OffsetHour,
+		WeekdayShifts: toWeekdayShiftModels(b.WeekdayShifts),
+	}
+}
+
+type BusinessHoursCreateResponse struct {
+	Id string `json:"id" binding:"required"`
+}
+
 type BusinessHoursUpdateData struct {
-	Name       string             `json:"name" binding:"required"`
-	Start      string             `json:"start" binding:"required"`
-	End        string             `json:"end" binding:"required"`
-	Weekdays   []usecases.Weekday `json:"weekdays" binding:"required"`
-	OffsetHour uint               `json:"offsetHour" binding:"required"`
+	Name          string             `json:"name" binding:"required"`
+	Start         string             `json:"start" binding:"required"`
+	End           string             `json:"end" binding:"required"`
+	Weekdays      []usecases.Weekday `json:"weekdays" binding:"required"`
+	OffsetHour    uint               `json:"offsetHour" binding:"required"`
+	WeekdayShifts []WeekdayShiftData `json:"weekdayShifts"`
 }
 
 func (b *BusinessHoursUpdateData) toModel(id string) *usecases.BusinessHoursUpdateModel {
 	return &usecases.BusinessHoursUpdateModel{
-		Id:         id,
-		Name:       b.Name,
-		Start:      b.Start,
-		End:        b.End,
-		Weekdays:   b.Weekdays,
-		OffsetHour: b.OffsetHour,
+		Id:            id,
+		Name:          b.Name,
+		Start:         b.Start,
+		End:           b.End,
+		Weekdays:      b.Weekdays,
+		OffsetHour:    b.OffsetHour,
+		WeekdayShifts: toWeekdayShiftModels(b.WeekdayShifts),
 	}
 }
 
 type BusinessHourData struct {
-	Id         string             `json:"id" binding:"required"`
-	Name       string             `json:"name" binding:"required"`
-	Start      string             `json:"start" binding:"required"`
-	End        string             `json:"end" binding:"required"`
-	Weekdays   []usecases.Weekday `json:"weekdays" binding:"required"`
-	Enabled    *bool              `json:"enabled" binding:"required"`
-	OffsetHour uint               `json:"offsetHour" binding:"required"`
+	Id            string             `json:"id" binding:"required"`
+	Name          string             `json:"name" binding:"required"`
+	Start         string             `json:"start" binding:"required"`
+	End           string             `json:"end" binding:"required"`
+	Weekdays      []usecases.Weekday `json:"weekdays" binding:"required"`
+	Enabled       *bool              `json:"enabled" binding:"required"`
+	OffsetHour    uint               `json:"offsetHour" binding:"required"`
+	WeekdayShifts []WeekdayShiftData `json:"weekdayShifts" binding:"required"`
 }
 
 func newBusinessHourData(model usecases.BusinessHourModel) *BusinessHourData {
+	shifts := []WeekdayShiftData{}
+	for _, ws := range model.WeekdayShifts {
+		shifts = append(shifts, WeekdayShiftData{Weekday: ws.Weekday, Start: ws.Start, End: ws.End})
+	}
 	return &BusinessHourData{
-		Id:         model.Id,
-		Name:       model.Name,
-		Start:      model.Start,
-		End:        model.End,
-		Weekdays:   model.Weekdays,
-		Enabled:    &model.Enabled,
-		OffsetHour: model.OffsetHour,
+		Id:            model.Id,
+		Name:          model.Name,
+		Start:         model.Start,
+		End:           model.End,
+		Weekdays:      model.Weekdays,
+		Enabled:       &model.Enabled,
+		OffsetHour:    model.OffsetHour,
+		WeekdayShifts: shifts,
 	}
 }
 
+// end before start means crossing midnight
+type WeekdayShiftData struct {
+	Weekday usecases.Weekday `json:"weekday"`
+	Start   string           `json:"start" binding:"required"`
+	End     string           `json:"end" binding:"required"`
+}
+
+func toWeekdayShiftModels(data []WeekdayShiftData) []usecases.WeekdayShiftModel {
+	models := []usecases.WeekdayShiftModel{}
+	for _, ws := range data {
+		models = append(models, usecases.WeekdayShiftModel{Weekday: ws.Weekday, Start: ws.Start, End: ws.End})
+	}
+	return models
+}
+
 type BusinessHoursEnabledUpdateData struct {
 	Enabled *bool `json:"enabled" binding:"required"`
 }
@@ -93,6 +140,19 @@ func (b *businessHoursHandler) Get(c *gin.Context) {
 	b.HandleOK(c, newBusinessHoursData(*model))
 }
 
+func (b *businessHoursHandler) Post(c *gin.Context) {
+	var req BusinessHoursCreateData
+	if !b.ShouldBind(c, &req) {
+		return
+	}
+	id, err := b.usecase.Create(req.toModel())
+	if err != nil {
+		b.HandleError(c, err)
+		return
+	}
+	b.HandleOK(c, BusinessHoursCreateResponse{Id: id})
+}
+
 func (b *businessHoursHandler) Put(c *gin.Context) {
 	id := c.Param("id")
 	var req BusinessHoursUpdateData
@@ -120,3 +180,13 @@ func (b *businessHoursHandler) PutEnabled(c *gin.Context) {
 	}
 	b.HandleOK(c, nil)
 }
+
+func (b *businessHoursHandler) Delete(c *gin.Context) {
+	id := c.Param("id")
+	err := b.usecase.Delete(id)
+	if err != nil {
+		b.HandleError(c, err)
+		return
+	}
+	b.HandleOK(c, nil)
+}
@@ -0,0 +1,192 @@
+package store
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"chico/takeout/common"
+	"chico/takeout/handlers"
+	usecases "chico/takeout/usecase/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StoreCalendarData struct {
+	From string            `json:"from" binding:"required"`
+	To   string            `json:"to" binding:"required"`
+	Days []CalendarDayData `json:"days" binding:"required"`
+}
+
+type CalendarDayData struct {
+	Date        string             `json:"date" binding:"required"`
+	IsHoliday   bool               `json:"isHoliday" binding:"required"`
+	HolidayName string             `json:"holidayName" binding:"required"`
+	IsSpecial   bool               `json:"isSpecial" binding:"required"`
+	Hours       []CalendarHourData `json:"hours" binding:"required"`
+}
+
+type CalendarHourData struct {
+	HourTypeId string `json:"hourTypeId" binding:"required"`
+	Name       string `json:"name" binding:"required"`
+	StartTime  string `json:"startTime" binding:"required"`
+	EndTime    string `json:"endTime" binding:"required"`
+}
+
+func newStoreCalendarData(model *usecases.StoreCalendarModel) *StoreCalendarData {
+	days := []CalendarDayData{}
+	for _, day := range model.Days {
+		hours := []CalendarHourData{}
+		for _, hour := range day.Hours {
+			hours = append(hours, CalendarHourData{
+				HourTypeId: hour.HourTypeId,
+				Name:       hour.Name,
+				StartTime:  hour.StartTime,
+				EndTime:    hour.EndTime,
+			})
+		}
+		days = append(days, CalendarDayData{
+			Date:        day.Date,
+			IsHoliday:   day.IsHoliday,
+			HolidayName: day.HolidayName,
+			IsSpecial:   day.IsSpecial,
+			Hours:       hours,
+		})
+	}
+	return &StoreCalendarData{
+		From: model.From,
+		To:   model.To,
+		Days: days,
+	}
+}
+
+type storeCalendarHandler struct {
+	*handlers.BaseHandler
+	usecase usecases.StoreCalendarUseCase
+	clock   common.Clock
+}
+
+func NewStoreCalendarHandler(usecase usecases.StoreCalendarUseCase, clock common.Clock) *storeCalendarHandler {
+	return &storeCalendarHandler{
+		usecase: usecase,
+		clock:   clock,
+	}
+}
+
+func (s *storeCalendarHandler) Get(c *gin.Context) {
+	model, err := s.usecase.Fetch(c.Query("from"), c.Query("to"))
+	if err != nil {
+		s.HandleError(c, err)
+		return
+	}
+	s.HandleOK(c, newStoreCalendarData(model))
+}
+
+func (s *storeCalendarHandler) GetIcs(c *gin.Context) {
+	model, err := s.usecase.Fetch(c.Query("from"), c.Query("to"))
+	if err != nil {
+		s.HandleError(c, err)
+		return
+	}
+	ics, err := newIcsWriter(s.clock.Now()).write(model)
+	if err != nil {
+		s.HandleError(c, err)
+		return
+	}
+	c.Header("Content-Disposition", "inline; filename=calendar.ics")
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", []byte(ics))
+}
+
+const (
+	icsUidDomain      = "chico-takeout"
+	icsDateLayout     = "20060102"
+	icsDateTimeLayout = "20060102T150405Z"
+	// octets per line without line break
+	icsMaxLineLength = 75
+)
+
+// render calendar as iCalendar(RFC5545)
+type icsWriter struct {
+	builder strings.Builder
+	stamp   string
+}
+
+func newIcsWriter(now time.Time) *icsWriter {
+	return &icsWriter{stamp: now.UTC().Format(icsDateTimeLayout)}
+}
+
+func (w *icsWriter) write(model *usecases.StoreCalendarModel) (string, error) {
+	w.writeLine("BEGIN:VCALENDAR")
+	w.writeLine("VERSION:2.0")
+	w.writeLine("PRODID:-//chico takeout//store calendar//JA")
+	w.writeLine("CALSCALE:GREGORIAN")
+	w.writeLine("METHOD:PUBLISH")
+	w.writeLine("X-WR-CALNAME:" + escapeIcsText("営業カレンダー"))
+	for _, day := range model.Days {
+		date, err := common.ConvertStrToDate(day.Date)
+		if err != nil {
+			return "", err
+		}
+		if day.IsHoliday {
+			w.writeLine("BEGIN:VEVENT")
+			w.writeLine(fmt.Sprintf("UID:holiday-%s@%s", date.Format(icsDateLayout), icsUidDomain))
+			w.writeLine("DTSTAMP:" + w.stamp)
+			w.writeLine("DTSTART;VALUE=DATE:" + date.Format(icsDateLayout))
+			w.writeLine("DTEND;VALUE=DATE:" + date.AddDate(0, 0, 1).Format(icsDateLayout))
+			w.writeLine("SUMMARY:" + escapeIcsText(fmt.Sprintf("休業日(%s)", day.HolidayName)))
+			w.writeLine("TRANSP:TRANSPARENT")
+			w.writeLine("END:VEVENT")
+			continue
+		}
+		for _, hour := range day.Hours {
+			start, err := common.ConvertStrToDateTime(day.Date + " " + hour.StartTime)
+			if err != nil {
+				return "", err
+			}
+			end, err := common.ConvertStrToDateTime(day.Date + " " + hour.EndTime)
+			if err != nil {
+				return "", err
+			}
+			// end is next day if the hour crosses midnight
+			if !end.After(*start) {
+				*end = end.AddDate(0, 0, 1)
+			}
+			summary := fmt.Sprintf("営業(%s)", hour.Name)
+			if day.IsSpecial {
+				summary = fmt.Sprintf("特別営業(%s)", hour.Name)
+			}
+			w.writeLine("BEGIN:VEVENT")
+			w.writeLine(fmt.Sprintf("UID:%s-%s@%s", date.Format(icsDateLayout), hour.HourTypeId, icsUidDomain))
+			w.writeLine("DTSTAMP:" + w.stamp)
+			w.writeLine("DTSTART:" + start.UTC().Format(icsDateTimeLayout))
+			w.writeLine("DTEND:" + end.UTC().Format(icsDateTimeLayout))
+			w.writeLine("SUMMARY:" + escapeIcsText(summary))
+			w.writeLine("END:VEVENT")
+		}
+	}
+	w.writeLine("END:VCALENDAR")
+	return w.builder.String(), nil
+}
+
+// long line is folded by CRLF and space without breaking utf8 character
+func (w *icsWriter) writeLine(line string) {
+	length := 0
+	for _, r := range line {
+		size := utf8.RuneLen(r)
+		if length+size > icsMaxLineLength {
+			w.builder.WriteString("\r\n ")
+			// space is counted
+			length = 1
+		}
+		w.builder.WriteRune(r)
+		length += size
+	}
+	w.builder.WriteString("\r\n")
+}
+
+func escapeIcsText(text string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+	return replacer.Replace(text)
+}
@@ -0,0 +1,262 @@
+package store
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"chico/takeout/common"
+	"chico/takeout/handlers"
+	usecases "chico/takeout/usecase/store"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/text/encoding/japanese"
+)
+
+const holidayImportMaxFileSize = 1 << 20
+
+// multipart form. file is iCalendar(.ics) or CSV(date,name)
+type HolidayImportRequest struct {
+	File           *multipart.FileHeader `form:"file" binding:"required"`
+	Mode           string                `form:"mode" binding:"required,oneof=closed special"`
+	BusinessHourId string                `form:"businessHourId"`
+	Start          string                `form:"start"`
+	End            string                `form:"end"`
+	OffsetHour     uint                  `form:"offsetHour"`
+}
+
+func (h *HolidayImportRequest) toModel(entries []usecases.HolidayImportEntryModel) *usecases.HolidayImportModel {
+	return &usecases.HolidayImportModel{
+		Entries:        entries,
+		Mode:           h.Mode,
+		BusinessHourId: h.BusinessHourId,
+		Start:          h.Start,
+		End:            h.End,
+		OffsetHour:     h.OffsetHour,
+	}
+}
+
+type HolidayImportEntryData struct {
+	Date string `json:"date"`
+	Name string `json:"name"`
+}
+
+type HolidayImportSkippedData struct {
+	Date   string `json:"date"`
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+type HolidayImportResponse struct {
+	Imported []HolidayImportEntryData   `json:"imported"`
+	Skipped  []HolidayImportSkippedData `json:"skipped"`
+}
+
+func newHolidayImportResponse(model usecases.HolidayImportResultModel) *HolidayImportResponse {
+	res := HolidayImportResponse{Imported: []HolidayImportEntryData{}, Skipped: []HolidayImportSkippedData{}}
+	for _, item := range model.Imported {
+		res.Imported = append(res.Imported, HolidayImportEntryData{Date: item.Date, Name: item.Name})
+	}
+	for _, item := range model.Skipped {
+		res.Skipped = append(res.Skipped, HolidayImportSkippedData{Date: item.Date, Name: item.Name, Reason: item.Reason})
+	}
+	return &res
+}
+
+type holidayImportHandler struct {
+	*handlers.BaseHandler
+	usecase usecases.HolidayImportUseCase
+}
+
+func NewHolidayImportHandler(usecase usecases.HolidayImportUseCase) *holidayImportHandler {
+	return &holidayImportHandler{
+		usecase: usecase,
+	}
+}
+
+func (h *holidayImportHandler) Post(c *gin.Context) {
+	var req HolidayImportRequest
+	if !h.ShouldBind(c, &req) {
+		return
+	}
+	entries, err := parseHolidayFile(req.File)
+	if err != nil {
+		h.HandleError(c, err)
+		return
+	}
+	result, err := h.usecase.Import(req.toModel(entries))
+	if err != nil {
+		h.HandleError(c, err)
+		return
+	}
+	h.HandleOK(c, newHolidayImportResponse(*result))
+}
+
+func parseHolidayFile(header *multipart.FileHeader) ([]usecases.HolidayImportEntryModel, error) {
+	if header.Size > holidayImportMaxFileSize {
+		return nil, common.NewValidationError("file", "file size is too large")
+	}
+	file, err := header.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	data, err := io.ReadAll(io.LimitReader(file, holidayImportMaxFileSize))
+	if err != nil {
+		return nil, err
+	}
+	// files from japanese government are often Shift_JIS
+	if !utf8.Valid(data) {
+		data, err = japanese.ShiftJIS.NewDecoder().Bytes(data)
+		if err != nil {
+			return nil, common.NewValidationError("file", "failed to decode file")
+		}
+	}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	ext := strings.ToLower(filepath.Ext(header.Filename))
+	if ext == ".ics" || bytes.HasPrefix(bytes.TrimSpace(data), []byte("BEGIN:VCALENDAR")) {
+		return parseHolidayIcs(data)
+	}
+	return parseHolidayCsv(data)
+}
+
+// reads DTSTART, DTEND(exclusive) and SUMMARY of each VEVENT
+func parseHolidayIcs(data []byte) ([]usecases.HolidayImportEntryModel, error) {
+	entries := []usecases.HolidayImportEntryModel{}
+	inEvent := false
+	var start, end *time.Time
+	name := ""
+	for _, line := range unfoldIcsLines(data) {
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		// remove parameters (e.g. DTSTART;VALUE=DATE)
+		key := strings.SplitN(strings.ToUpper(parts[0]), ";", 2)[0]
+		value := strings.TrimSpace(parts[1])
+		switch key {
+		case "BEGIN":
+			if value == "VEVENT" {
+				inEvent = true
+				start, end, name = nil, nil, ""
+			}
+		case "DTSTART", "DTEND":
+			if !inEvent {
+				continue
+			}
+			date, err := parseIcsDate(value)
+			if err != nil {
+				return nil, common.NewValidationError("file", fmt.Sprintf("not allowed date format:%s", value))
+			}
+			if key == "DTSTART" {
+				start = date
+			} else {
+				end = date
+			}
+		case "SUMMARY":
+			if inEvent {
+				name = unescapeIcsText(value)
+			}
+		case "END":
+			if value != "VEVENT" || !inEvent {
+				continue
+			}
+			inEvent = false
+			if start == nil {
+				continue
+			}
+			// all day event has exclusive end
+			last := *start
+			if end != nil && end.After(*start) {
+				last = end.AddDate(0, 0, -1)
+			}
+			for date := *start; !date.After(last); date = date.AddDate(0, 0, 1) {
+				entries = append(entries, usecases.HolidayImportEntryModel{Date: common.ConvertTimeToDateStr(date), Name: name})
+			}
+		}
+	}
+	if len(entries) == 0 {
+		return nil, common.NewValidationError("file", "no holiday exists in file")
+	}
+	return entries, nil
+}
+
+func unfoldIcsLines(data []byte) []string {
+	lines := []string{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// only date is used even if date-time is specified
+func parseIcsDate(value string) (*time.Time, error) {
+	if len(value) >= 8 {
+		value = value[:8]
+	}
+	date, err := time.ParseInLocation("20060102", value, common.GetStoreLocation())
+	if err != nil {
+		return nil, err
+	}
+	return &date, nil
+}
+
+func unescapeIcsText(value string) string {
+	replacer := strings.NewReplacer(`\n`, " ", `\N`, " ", `\,`, ",", `\;`, ";", `\\`, `\`)
+	return strings.TrimSpace(replacer.Replace(value))
+}
+
+// each row is date and name. header row is skipped
+func parseHolidayCsv(data []byte) ([]usecases.HolidayImportEntryModel, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, common.NewValidationError("file", fmt.Sprintf("failed to read csv:%s", err))
+	}
+	entries := []usecases.HolidayImportEntryModel{}
+	for i, row := range rows {
+		if len(row) == 0 || strings.TrimSpace(row[0]) == "" {
+			continue
+		}
+		date, err := parseCsvDate(row[0])
+		if err != nil {
+			if i == 0 {
+				continue
+			}
+			return nil, common.NewValidationError("file", fmt.Sprintf("not allowed date format at line %d:%s", i+1, row[0]))
+		}
+		name := ""
+		if len(row) > 1 {
+			name = strings.TrimSpace(row[1])
+		}
+		entries = append(entries, usecases.HolidayImportEntryModel{Date: common.ConvertTimeToDateStr(*date), Name: name})
+	}
+	if len(entries) == 0 {
+		return nil, common.NewValidationError("file", "no holiday exists in file")
+	}
+	return entries, nil
+}
+
+func parseCsvDate(value string) (*time.Time, error) {
+	value = strings.ReplaceAll(strings.TrimSpace(value), "-", "/")
+	date, err := time.ParseInLocation("2006/1/2", value, common.GetStoreLocation())
+	if err != nil {
+		return nil, err
+	}
+	return &date, nil
+}
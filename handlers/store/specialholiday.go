@@ -8,10 +8,11 @@ import (
 )
 
 type SpecialHolidayData struct {
-	Id    string `json:"id" binding:"required"`
-	Name  string `json:"name" binding:"required"`
-	Start string `json:"start" binding:"required"`
-	End   string `json:"end" binding:"required"`
+	Id         string                `json:"id" binding:"required"`
+	Name       string                `json:"name" binding:"required"`
+	Start      string                `json:"start" binding:"required"`
+	End        string                `json:"end" binding:"required"`
+	Recurrence HolidayRecurrenceData `json:"recurrence" binding:"required"`
 }
 
 func newSpecialHolidayData(model usecases.SpecialHolidayModel) *SpecialHolidayData {
@@ -20,20 +21,49 @@ func newSpecialHolidayData(model usecases.SpecialHolidayModel) *SpecialHolidayDa
 		Name:  model.Name,
 		Start: model.Start,
 		End:   model.End,
+		Recurrence: HolidayRecurrenceData{
+			Type:     model.Recurrence.Type,
+			Weekdays: model.Recurrence.Weekdays,
+			Nth:      model.Recurrence.Nth,
+			Month:    model.Recurrence.Month,
+		},
+	}
+}
+
+// type is empty(not recurring), yearly, weekly or nthWeekday
+type HolidayRecurrenceData struct {
+	Type     string             `json:"type"`
+	Weekdays []usecases.Weekday `json:"weekdays"`
+	Nth      int                `json:"nth"`
+	Month    int                `json:"month"`
+}
+
+func (h *HolidayRecurrenceData) toModel() usecases.HolidayRecurrenceModel {
+	// not specified is not recurring
+	if h == nil {
+		return usecases.HolidayRecurrenceModel{}
+	}
+	return usecases.HolidayRecurrenceModel{
+		Type:     h.Type,
+		Weekdays: h.Weekdays,
+		Nth:      h.Nth,
+		Month:    h.Month,
 	}
 }
 
 type SpecialHolidayCreateData struct {
-	Name  string `json:"name" binding:"required"`
-	Start string `json:"start" binding:"required"`
-	End   string `json:"end" binding:"required"`
+	Name       string                 `json:"name" binding:"required"`
+	Start      string                 `json:"start" binding:"required"`
+	End        string                 `json:"end" binding:"required"`
+	Recurrence *HolidayRecurrenceData `json:"recurrence"`
 }
 
 func (b *SpecialHolidayCreateData) toModel() *usecases.SpecialHolidayCreateModel {
 	return &usecases.SpecialHolidayCreateModel{
-		Name:  b.Name,
-		Start: b.Start,
-		End:   b.End,
+		Name:       b.Name,
+		Start:      b.Start,
+		End:        b.End,
+		Recurrence: b.Recurrence.toModel(),
 	}
 }
 
@@ -42,17 +72,19 @@ type SpecialHolidayCreateResponse struct {
 }
 
 type SpecialHolidayUpdateData struct {
-	Name  string `json:"name" binding:"required"`
-	Start string `json:"start" binding:"required"`
-	End   string `json:"end" binding:"required"`
+	Name       string                 `json:"name" binding:"required"`
+	Start      string                 `json:"start" binding:"required"`
+	End        string                 `json:"end" binding:"required"`
+	Recurrence *HolidayRecurrenceData `json:"recurrence"`
 }
 
 func (b *SpecialHolidayUpdateData) toModel(id string) *usecases.SpecialHolidayUpdateModel {
 	return &usecases.SpecialHolidayUpdateModel{
-		Id:    id,
-		Name:  b.Name,
-		Start: b.Start,
-		End:   b.End,
+		Id:         id,
+		Name:       b.Name,
+		Start:      b.Start,
+		End:        b.End,
+		Recurrence: b.Recurrence.toModel(),
 	}
 }
 
@@ -0,0 +1,55 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// excel detects utf-8 by bom
+const utf8Bom = "\xEF\xBB\xBF"
+
+type CsvTableWriter struct {
+	out     io.Writer
+	writer  *csv.Writer
+	started bool
+}
+
+func NewCsvTableWriter(out io.Writer) *CsvTableWriter {
+	return &CsvTableWriter{
+		out:    out,
+		writer: csv.NewWriter(out),
+	}
+}
+
+func (c *CsvTableWriter) WriteRow(values []interface{}) error {
+	if !c.started {
+		c.started = true
+		if _, err := io.WriteString(c.out, utf8Bom); err != nil {
+			return err
+		}
+	}
+	record := []string{}
+	for _, value := range values {
+		record = append(record, toCsvValue(value))
+	}
+	return c.writer.Write(record)
+}
+
+func (c *CsvTableWriter) Flush() error {
+	c.writer.Flush()
+	return c.writer.Error()
+}
+
+// text beginning with formula character is escaped not to be evaluated by spreadsheet
+func toCsvValue(value interface{}) string {
+	text, ok := value.(string)
+	if !ok {
+		return fmt.Sprint(value)
+	}
+	if text != "" && strings.ContainsAny(text[:1], "=+-@") {
+		return "'" + text
+	}
+	return text
+}
@@ -0,0 +1,54 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCsvTableWriter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	writer := NewCsvTableWriter(buf)
+	assert.Nil(t, writer.WriteRow([]interface{}{"name", "quantity", "canceled"}))
+	assert.Nil(t, writer.WriteRow([]interface{}{"カレー, 大盛り", 3, false}))
+	assert.Nil(t, writer.WriteRow([]interface{}{"=1+1", -1, true}))
+	assert.Nil(t, writer.Flush())
+
+	assert.Equal(t, utf8Bom+"name,quantity,canceled\n\"カレー, 大盛り\",3,false\n'=1+1,-1,true\n", buf.String())
+}
+
+func TestXlsxTableWriter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	writer := NewXlsxTableWriter(buf, "orders")
+	assert.Nil(t, writer.WriteRow([]interface{}{"name", "quantity", "canceled"}))
+	assert.Nil(t, writer.WriteRow([]interface{}{"<curry> & rice", 3, true}))
+	assert.Nil(t, writer.Flush())
+
+	reader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.Nil(t, err)
+	files := map[string]string{}
+	for _, file := range reader.File {
+		r, err := file.Open()
+		assert.Nil(t, err)
+		content, err := io.ReadAll(r)
+		assert.Nil(t, err)
+		files[file.Name] = string(content)
+	}
+	assert.Equal(t, 5, len(files))
+	assert.True(t, strings.Contains(files["xl/workbook.xml"], `<sheet name="orders"`))
+	sheet := files["xl/worksheets/sheet1.xml"]
+	assert.True(t, strings.Contains(sheet, `<row r="1"><c r="A1" t="inlineStr"><is><t xml:space="preserve">name</t></is></c>`))
+	assert.True(t, strings.Contains(sheet, `<c r="A2" t="inlineStr"><is><t xml:space="preserve">&lt;curry&gt; &amp; rice</t></is></c><c r="B2"><v>3</v></c><c r="C2" t="b"><v>1</v></c></row>`))
+	assert.True(t, strings.HasSuffix(sheet, `</sheetData></worksheet>`))
+}
+
+func TestColumnName(t *testing.T) {
+	assert.Equal(t, "A", columnName(0))
+	assert.Equal(t, "Z", columnName(25))
+	assert.Equal(t, "AA", columnName(26))
+	assert.Equal(t, "BA", columnName(52))
+}
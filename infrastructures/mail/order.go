@@ -20,6 +20,14 @@ func (s *SendGridSendOrderMail) SendCancel(data order.OrderCancelMailData) error
 	return s.mailer.sendMail(data.Title, data.Message, data.SendFrom, data.Cc, data.SendTo)
 }
 
+func (s *SendGridSendOrderMail) SendChange(data order.OrderChangeMailData) error {
+	return s.mailer.sendMail(data.Title, data.Message, data.SendFrom, data.Cc, data.SendTo)
+}
+
 func (s *SendGridSendOrderMail) SendDailySummary(data order.ReservationSummaryMailData) error {
 	return s.mailer.sendMail(data.Title, data.Message, data.SendFrom, data.Cc, data.SendTo)
+}
+
+func (s *SendGridSendOrderMail) SendItemAlert(data order.ItemAlertMailData) error {
+	return s.mailer.sendMail(data.Title, data.Message, data.SendFrom, data.Cc, data.SendTo)
 }
\ No newline at end of file
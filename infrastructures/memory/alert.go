@@ -0,0 +1,50 @@
+package memory
+
+import (
+	"sort"
+
+	domains "chico/takeout/domains/item"
+)
+
+type ItemAlertMemoryRepository struct {
+	inMemory []domains.ItemAlert
+}
+
+func NewItemAlertMemoryRepository() *ItemAlertMemoryRepository {
+	return &ItemAlertMemoryRepository{
+		inMemory: []domains.ItemAlert{},
+	}
+}
+
+func (s *ItemAlertMemoryRepository) Reset() {
+	s.inMemory = []domains.ItemAlert{}
+}
+
+func (s *ItemAlertMemoryRepository) GetMemory() []domains.ItemAlert {
+	return s.inMemory
+}
+
+func (s *ItemAlertMemoryRepository) Create(alert *domains.ItemAlert) error {
+	s.inMemory = append(s.inMemory, *alert)
+	return nil
+}
+
+func (s *ItemAlertMemoryRepository) Exists(itemId string, alertType domains.ItemAlertType, targetDate string) (bool, error) {
+	for _, alert := range s.inMemory {
+		if alert.GetItemId() == itemId && alert.GetAlertType() == alertType && alert.GetTargetDate() == targetDate {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *ItemAlertMemoryRepository) FindByTargetDate(targetDate string) ([]domains.ItemAlert, error) {
+	alerts := []domains.ItemAlert{}
+	for _, alert := range s.inMemory {
+		if alert.GetTargetDate() == targetDate {
+			alerts = append(alerts, alert)
+		}
+	}
+	sort.SliceStable(alerts, func(i, j int) bool { return alerts[i].GetCreatedAt().Before(alerts[j].GetCreatedAt()) })
+	return alerts, nil
+}
@@ -53,3 +53,21 @@ func (b *BusinessHoursMemoryRepository) Create(target *domains.BusinessHours) er
 	*b.inMemory = *target
 	return nil
 }
+
+func (b *BusinessHoursMemoryRepository) RemoveSchedule(target *domains.BusinessHours, removedId string) error {
+	for _, food := range foodMemory {
+		if !food.HasScheduleId(removedId) {
+			continue
+		}
+		if err := food.RemoveScheduleId(removedId); err != nil {
+			return err
+		}
+	}
+	for id, spHour := range specialBusinessHourMemory {
+		if spHour.GetBusinessHourId() == removedId {
+			delete(specialBusinessHourMemory, id)
+		}
+	}
+	*b.inMemory = *target
+	return nil
+}
@@ -0,0 +1,77 @@
+package memory
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	domains "chico/takeout/domains/idempotency"
+)
+
+type IdempotencyRecordMemoryRepository struct {
+	mutex    sync.Mutex
+	inMemory map[string]*domains.Record
+}
+
+func NewIdempotencyRecordMemoryRepository() *IdempotencyRecordMemoryRepository {
+	return &IdempotencyRecordMemoryRepository{
+		inMemory: map[string]*domains.Record{},
+	}
+}
+
+func (i *IdempotencyRecordMemoryRepository) Reset() {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	i.inMemory = map[string]*domains.Record{}
+}
+
+func (i *IdempotencyRecordMemoryRepository) Find(key string) (*domains.Record, error) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	if val, ok := i.inMemory[key]; ok {
+		// need copy to protect
+		duplicated := *val
+		return &duplicated, nil
+	}
+	return nil, nil
+}
+
+func (i *IdempotencyRecordMemoryRepository) Reserve(record *domains.Record, now time.Time) (bool, error) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	if val, ok := i.inMemory[record.GetKey()]; ok && !val.IsReservable(now) {
+		return false, nil
+	}
+	duplicated := *record
+	i.inMemory[record.GetKey()] = &duplicated
+	return true, nil
+}
+
+func (i *IdempotencyRecordMemoryRepository) Update(record *domains.Record) error {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	if _, ok := i.inMemory[record.GetKey()]; ok {
+		duplicated := *record
+		i.inMemory[record.GetKey()] = &duplicated
+		return nil
+	}
+	return fmt.Errorf("update target not exists")
+}
+
+func (i *IdempotencyRecordMemoryRepository) Delete(key string) error {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	delete(i.inMemory, key)
+	return nil
+}
+
+func (i *IdempotencyRecordMemoryRepository) DeleteExpired(now time.Time) error {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	for key, record := range i.inMemory {
+		if record.IsExpired(now) {
+			delete(i.inMemory, key)
+		}
+	}
+	return nil
+}
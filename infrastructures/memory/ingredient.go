@@ -0,0 +1,147 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+
+	domains "chico/takeout/domains/item"
+)
+
+type IngredientMemoryRepository struct {
+	inMemory map[string]*domains.Ingredient
+}
+
+func NewIngredientMemoryRepository() *IngredientMemoryRepository {
+	return &IngredientMemoryRepository{
+		inMemory: map[string]*domains.Ingredient{},
+	}
+}
+
+func (i *IngredientMemoryRepository) Reset() {
+	i.inMemory = map[string]*domains.Ingredient{}
+}
+
+func (i *IngredientMemoryRepository) GetMemory() map[string]*domains.Ingredient {
+	return i.inMemory
+}
+
+func (i *IngredientMemoryRepository) Find(id string) (*domains.Ingredient, error) {
+	if val, ok := i.inMemory[id]; ok {
+		// need copy to protect
+		duplicated := *val
+		return &duplicated, nil
+	}
+	return nil, nil
+}
+
+func (i *IngredientMemoryRepository) FindAll() ([]domains.Ingredient, error) {
+	items := []domains.Ingredient{}
+	for _, item := range i.inMemory {
+		items = append(items, *item)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].GetName() < items[j].GetName() })
+	return items, nil
+}
+
+func (i *IngredientMemoryRepository) Create(item *domains.Ingredient) (string, error) {
+	i.inMemory[item.GetId()] = item
+	return item.GetId(), nil
+}
+
+func (i *IngredientMemoryRepository) Update(item *domains.Ingredient) error {
+	if _, ok := i.inMemory[item.GetId()]; ok {
+		duplicated := *item
+		i.inMemory[item.GetId()] = &duplicated
+		return nil
+	}
+	return fmt.Errorf("update target not exists")
+}
+
+func (i *IngredientMemoryRepository) Delete(id string) error {
+	if _, ok := i.inMemory[id]; ok {
+		delete(i.inMemory, id)
+		return nil
+	}
+	return fmt.Errorf("delete target not exists")
+}
+
+type RecipeMemoryRepository struct {
+	inMemory map[string]*domains.Recipe
+}
+
+func NewRecipeMemoryRepository() *RecipeMemoryRepository {
+	return &RecipeMemoryRepository{
+		inMemory: map[string]*domains.Recipe{},
+	}
+}
+
+func (r *RecipeMemoryRepository) Reset() {
+	r.inMemory = map[string]*domains.Recipe{}
+}
+
+func (r *RecipeMemoryRepository) GetMemory() map[string]*domains.Recipe {
+	return r.inMemory
+}
+
+func (r *RecipeMemoryRepository) FindByItemId(itemId string) (*domains.Recipe, error) {
+	if val, ok := r.inMemory[itemId]; ok {
+		duplicated := *val
+		return &duplicated, nil
+	}
+	return nil, nil
+}
+
+func (r *RecipeMemoryRepository) FindAll() ([]domains.Recipe, error) {
+	recipes := []domains.Recipe{}
+	for _, recipe := range r.inMemory {
+		recipes = append(recipes, *recipe)
+	}
+	sort.Slice(recipes, func(i, j int) bool { return recipes[i].GetItemId() < recipes[j].GetItemId() })
+	return recipes, nil
+}
+
+func (r *RecipeMemoryRepository) Save(recipe *domains.Recipe) error {
+	duplicated := *recipe
+	r.inMemory[recipe.GetItemId()] = &duplicated
+	return nil
+}
+
+func (r *RecipeMemoryRepository) Delete(itemId string) error {
+	delete(r.inMemory, itemId)
+	return nil
+}
+
+type IngredientUsageMemoryRepository struct {
+	inMemory map[string]*domains.IngredientUsage
+}
+
+func NewIngredientUsageMemoryRepository() *IngredientUsageMemoryRepository {
+	return &IngredientUsageMemoryRepository{
+		inMemory: map[string]*domains.IngredientUsage{},
+	}
+}
+
+func (u *IngredientUsageMemoryRepository) Reset() {
+	u.inMemory = map[string]*domains.IngredientUsage{}
+}
+
+// key is ingredient id and date
+func (u *IngredientUsageMemoryRepository) GetMemory() map[string]*domains.IngredientUsage {
+	return u.inMemory
+}
+
+func (u *IngredientUsageMemoryRepository) FindByDate(date string) ([]domains.IngredientUsage, error) {
+	usages := []domains.IngredientUsage{}
+	for _, usage := range u.inMemory {
+		if usage.GetDate() == date {
+			usages = append(usages, *usage)
+		}
+	}
+	return usages, nil
+}
+
+func (u *IngredientUsageMemoryRepository) Save(usage *domains.IngredientUsage) error {
+	duplicated := *usage
+	u.inMemory[usage.GetIngredientId()+"_"+usage.GetDate()] = &duplicated
+	return nil
+}
@@ -10,7 +10,6 @@ import (
 	domains "chico/takeout/domains/order"
 )
 
-var orderMemory map[string]*domains.OrderInfo
 var orderStockItems []item.StockItem
 var orderFoodItems []item.FoodItem
 
@@ -20,16 +19,14 @@ type OrderInfoMemoryRepository struct {
 	foodItems  []item.FoodItem
 }
 
+// each repository has its own orders, so tests using different repositories do not affect each other
 func NewOrderInfoMemoryRepository() *OrderInfoMemoryRepository {
-	if orderMemory == nil {
-		resetOrderInfoMemory()
-	}
 	return &OrderInfoMemoryRepository{
-		inMemory: orderMemory,
+		inMemory: newOrderInfoMemory(),
 	}
 }
 
-func resetOrderInfoMemory() {
+func newOrderInfoMemory() map[string]*domains.OrderInfo {
 	NewItemKindMemoryRepository()
 	NewBusinessHoursMemoryRepository()
 
@@ -39,7 +36,7 @@ func resetOrderInfoMemory() {
 	foodItemRepos := NewFoodItemMemoryRepository()
 	allFoods, _ := foodItemRepos.FindAll()
 
-	orderMemory = map[string]*domains.OrderInfo{}
+	orderMemory := map[string]*domains.OrderInfo{}
 
 	// order1
 	foodOrders1 := []domains.OrderFoodItem{}
@@ -57,7 +54,7 @@ func resetOrderInfoMemory() {
 	foodOrders1 = append(foodOrders1, *foodOrder2)
 
 	stockOrders1 := []domains.OrderStockItem{}
-	order1, err := domains.NewOrderInfoForOrm("o1", "user1", "ユーザー1", "user1@hoge.com", "123456789", "memo1", "2050/12/10 12:00", "2050/12/08 12:00", stockOrders1, foodOrders1, true)
+	order1, err := domains.NewOrderInfoForOrm("o1", "user1", "ユーザー1", "user1@hoge.com", "123456789", "memo1", newFixtureDateTime(2050, 12, 10, 12, 0), newFixtureDateTime(2050, 12, 8, 12, 0), stockOrders1, foodOrders1, true, "", "")
 	if err != nil {
 		fmt.Println(err)
 		panic("failed to create stock order")
@@ -80,12 +77,17 @@ func resetOrderInfoMemory() {
 		panic("failed to create food order")
 	}
 	stockOrders2 = append(stockOrders2, *stockOrder1)
-	order2, err := domains.NewOrderInfoForOrm("o2", "user2", "ユーザー2", "user2@hoge.com", "987654321", "memo2", "2050/12/14 12:00", "2050/12/11 10:00", stockOrders2, foodOrders2, true)
+	order2, err := domains.NewOrderInfoForOrm("o2", "user2", "ユーザー2", "user2@hoge.com", "987654321", "memo2", newFixtureDateTime(2050, 12, 14, 12, 0), newFixtureDateTime(2050, 12, 11, 10, 0), stockOrders2, foodOrders2, true, "", "")
 	if err != nil {
 		fmt.Println(err)
 		panic("failed to create food order")
 	}
 	orderMemory[order2.GetId()] = order2
+	return orderMemory
+}
+
+func newFixtureDateTime(year int, month time.Month, day, hour, min int) time.Time {
+	return time.Date(year, month, day, hour, min, 0, 0, common.GetStoreLocation())
 }
 
 func (o *OrderInfoMemoryRepository) GetMemory() map[string]*domains.OrderInfo {
@@ -93,7 +95,7 @@ func (o *OrderInfoMemoryRepository) GetMemory() map[string]*domains.OrderInfo {
 }
 
 func (o *OrderInfoMemoryRepository) Reset() {
-	resetOrderInfoMemory()
+	o.inMemory = newOrderInfoMemory()
 }
 
 func (o *OrderInfoMemoryRepository) FindAll() ([]domains.OrderInfo, error) {
@@ -124,6 +126,16 @@ func (o *OrderInfoMemoryRepository) FindByPickupDate(date string) ([]domains.Ord
 	return items, nil
 }
 
+func (o *OrderInfoMemoryRepository) FindByOrderDate(date string) ([]domains.OrderInfo, error) {
+	items := []domains.OrderInfo{}
+	for _, item := range o.inMemory {
+		if item.GetOrderDate() == date {
+			items = append(items, *item)
+		}
+	}
+	return items, nil
+}
+
 func (o *OrderInfoMemoryRepository) FindByUserId(userId string) ([]domains.OrderInfo, error) {
 	items := []domains.OrderInfo{}
 	for _, item := range o.inMemory {
@@ -135,18 +147,14 @@ func (o *OrderInfoMemoryRepository) FindByUserId(userId string) ([]domains.Order
 	return items, nil
 }
 
-func (o *OrderInfoMemoryRepository) FindActiveByUserId(userId string) ([]domains.OrderInfo, error) {
+func (o *OrderInfoMemoryRepository) FindActiveByUserId(userId string, now time.Time) ([]domains.OrderInfo, error) {
 	items := []domains.OrderInfo{}
 	for _, item := range o.inMemory {
 		// not canceled
 		if item.GetUserId() == userId && !item.GetCanceled() {
 			// if time is future, add.
-			pickUpDateTime, err := common.ConvertStrToDateTime(item.GetPickupDateTime())
-			if err != nil {
-				return nil, err
-			}
 			// until 30 minutes after active.
-			if common.StartIsBeforeEnd(*pickUpDateTime, time.Now(), -30) {
+			if common.StartIsBeforeEnd(now, item.GetPickupAt(), -30) {
 				items = append(items, *item)
 			}
 		}
@@ -176,6 +184,22 @@ func (o *OrderInfoMemoryRepository) UpdateUserInfo(item *domains.OrderInfo) erro
 	return fmt.Errorf("update target not exists")
 }
 
+func (o *OrderInfoMemoryRepository) UpdateOwner(item *domains.OrderInfo) error {
+	if _, ok := o.inMemory[item.GetId()]; ok {
+		o.inMemory[item.GetId()] = item
+		return nil
+	}
+	return fmt.Errorf("update target not exists")
+}
+
+func (o *OrderInfoMemoryRepository) UpdateItems(item *domains.OrderInfo) error {
+	if _, ok := o.inMemory[item.GetId()]; ok {
+		o.inMemory[item.GetId()] = item
+		return nil
+	}
+	return fmt.Errorf("update target not exists")
+}
+
 func (o *OrderInfoMemoryRepository) Transact(fc func() error) error {
 	return fc()
 }
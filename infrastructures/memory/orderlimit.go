@@ -0,0 +1,55 @@
+package memory
+
+import (
+	"fmt"
+	"sync"
+
+	domains "chico/takeout/domains/order"
+)
+
+var orderLimitMux sync.Mutex
+var orderLimitMemory *domains.OrderLimit
+
+type OrderLimitMemoryRepository struct {
+}
+
+func NewOrderLimitMemoryRepository() *OrderLimitMemoryRepository {
+	return &OrderLimitMemoryRepository{}
+}
+
+// limit is not created at reset (default is used)
+func (o *OrderLimitMemoryRepository) Reset() {
+	orderLimitMux.Lock()
+	defer orderLimitMux.Unlock()
+	orderLimitMemory = nil
+}
+
+func (o *OrderLimitMemoryRepository) Fetch() (*domains.OrderLimit, error) {
+	orderLimitMux.Lock()
+	defer orderLimitMux.Unlock()
+	if orderLimitMemory == nil {
+		return nil, nil
+	}
+	// need copy to protect
+	duplicated := *orderLimitMemory
+	return &duplicated, nil
+}
+
+func (o *OrderLimitMemoryRepository) Update(target *domains.OrderLimit) error {
+	orderLimitMux.Lock()
+	defer orderLimitMux.Unlock()
+	if orderLimitMemory == nil {
+		return fmt.Errorf("update target not exists")
+	}
+	duplicated := *target
+	orderLimitMemory = &duplicated
+	return nil
+}
+
+func (o *OrderLimitMemoryRepository) Create(target *domains.OrderLimit) error {
+	orderLimitMux.Lock()
+	defer orderLimitMux.Unlock()
+	duplicated := *target
+	orderLimitMemory = &duplicated
+	return nil
+}
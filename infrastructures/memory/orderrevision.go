@@ -0,0 +1,41 @@
+package memory
+
+import (
+	"sort"
+
+	domains "chico/takeout/domains/order"
+)
+
+type OrderRevisionMemoryRepository struct {
+	inMemory map[string]*domains.OrderRevision
+}
+
+func NewOrderRevisionMemoryRepository() *OrderRevisionMemoryRepository {
+	return &OrderRevisionMemoryRepository{
+		inMemory: map[string]*domains.OrderRevision{},
+	}
+}
+
+func (o *OrderRevisionMemoryRepository) Reset() {
+	o.inMemory = map[string]*domains.OrderRevision{}
+}
+
+func (o *OrderRevisionMemoryRepository) GetMemory() map[string]*domains.OrderRevision {
+	return o.inMemory
+}
+
+func (o *OrderRevisionMemoryRepository) FindByOrderId(orderId string) ([]domains.OrderRevision, error) {
+	items := []domains.OrderRevision{}
+	for _, item := range o.inMemory {
+		if item.GetOrderId() == orderId {
+			items = append(items, *item)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].GetRevision() < items[j].GetRevision() })
+	return items, nil
+}
+
+func (o *OrderRevisionMemoryRepository) Create(item *domains.OrderRevision) error {
+	o.inMemory[item.GetId()] = item
+	return nil
+}
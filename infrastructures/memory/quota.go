@@ -0,0 +1,75 @@
+package memory
+
+import (
+	"sort"
+
+	domains "chico/takeout/domains/item"
+)
+
+type FoodQuotaMemoryRepository struct {
+	inMemory map[string]*domains.FoodQuota
+}
+
+func NewFoodQuotaMemoryRepository() *FoodQuotaMemoryRepository {
+	return &FoodQuotaMemoryRepository{
+		inMemory: map[string]*domains.FoodQuota{},
+	}
+}
+
+func (q *FoodQuotaMemoryRepository) Reset() {
+	q.inMemory = map[string]*domains.FoodQuota{}
+}
+
+// key is item id, date and hour type id
+func (q *FoodQuotaMemoryRepository) GetMemory() map[string]*domains.FoodQuota {
+	return q.inMemory
+}
+
+func (q *FoodQuotaMemoryRepository) FindByDate(date string) ([]domains.FoodQuota, error) {
+	return q.FindByRange(date, date)
+}
+
+func (q *FoodQuotaMemoryRepository) FindByRange(startDate, endDate string) ([]domains.FoodQuota, error) {
+	quotas := []domains.FoodQuota{}
+	for _, quota := range q.inMemory {
+		// yyyy/MM/dd is comparable as string
+		if startDate <= quota.GetDate() && quota.GetDate() <= endDate {
+			quotas = append(quotas, *quota)
+		}
+	}
+	sort.Slice(quotas, func(i, j int) bool {
+		if quotas[i].GetDate() != quotas[j].GetDate() {
+			return quotas[i].GetDate() < quotas[j].GetDate()
+		}
+		if quotas[i].GetItemId() != quotas[j].GetItemId() {
+			return quotas[i].GetItemId() < quotas[j].GetItemId()
+		}
+		return quotas[i].GetHourTypeId() < quotas[j].GetHourTypeId()
+	})
+	return quotas, nil
+}
+
+func (q *FoodQuotaMemoryRepository) Save(quota *domains.FoodQuota) error {
+	duplicated := *quota
+	q.inMemory[quota.GetItemId()+"_"+quota.GetDate()+"_"+quota.GetHourTypeId()] = &duplicated
+	return nil
+}
+
+func (q *FoodQuotaMemoryRepository) SaveAll(quotas []domains.FoodQuota) error {
+	for i := range quotas {
+		q.Save(&quotas[i])
+	}
+	return nil
+}
+
+func (q *FoodQuotaMemoryRepository) Delete(itemId, date, hourTypeId string) error {
+	delete(q.inMemory, itemId+"_"+date+"_"+hourTypeId)
+	return nil
+}
+
+func (q *FoodQuotaMemoryRepository) DeleteAll(itemId, hourTypeId string, dates []string) error {
+	for _, date := range dates {
+		q.Delete(itemId, date, hourTypeId)
+	}
+	return nil
+}
@@ -41,6 +41,7 @@ func resetSpecialBusinessHour() {
 
 func (i *SpecialBusinessHourMemoryRepository) Reset() {
 	resetSpecialBusinessHour()
+	i.inMemory = specialBusinessHourMemory
 }
 
 func (i *SpecialBusinessHourMemoryRepository) GetMemory() map[string]*domains.SpecialBusinessHour {
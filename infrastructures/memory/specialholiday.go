@@ -42,6 +42,7 @@ func (i *SpecialHolidayMemoryRepository) GetMemory() map[string]*domains.Special
 
 func (i *SpecialHolidayMemoryRepository) Reset() {
 	resetSpecialHolidayMemory()
+	i.inMemory = specialHolidayMemory
 }
 
 func (i *SpecialHolidayMemoryRepository) Find(id string) (*domains.SpecialHoliday, error) {
@@ -0,0 +1,60 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+
+	domains "chico/takeout/domains/item"
+)
+
+type StockBatchMemoryRepository struct {
+	inMemory map[string]*domains.StockBatch
+}
+
+func NewStockBatchMemoryRepository() *StockBatchMemoryRepository {
+	return &StockBatchMemoryRepository{
+		inMemory: map[string]*domains.StockBatch{},
+	}
+}
+
+func (s *StockBatchMemoryRepository) Reset() {
+	s.inMemory = map[string]*domains.StockBatch{}
+}
+
+func (s *StockBatchMemoryRepository) GetMemory() map[string]*domains.StockBatch {
+	return s.inMemory
+}
+
+func (s *StockBatchMemoryRepository) Find(id string) (*domains.StockBatch, error) {
+	if val, ok := s.inMemory[id]; ok {
+		// need copy to protect
+		duplicated := *val
+		return &duplicated, nil
+	}
+	return nil, nil
+}
+
+func (s *StockBatchMemoryRepository) FindByStockItemId(stockItemId string) ([]domains.StockBatch, error) {
+	items := []domains.StockBatch{}
+	for _, item := range s.inMemory {
+		if item.GetStockItemId() == stockItemId {
+			items = append(items, *item)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].GetExpiresAt() < items[j].GetExpiresAt() })
+	return items, nil
+}
+
+func (s *StockBatchMemoryRepository) Create(item *domains.StockBatch) (string, error) {
+	s.inMemory[item.GetId()] = item
+	return item.GetId(), nil
+}
+
+func (s *StockBatchMemoryRepository) Update(item *domains.StockBatch) error {
+	if _, ok := s.inMemory[item.GetId()]; ok {
+		duplicated := *item
+		s.inMemory[item.GetId()] = &duplicated
+		return nil
+	}
+	return fmt.Errorf("update target not exists")
+}
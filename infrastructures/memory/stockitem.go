@@ -5,7 +5,6 @@ import (
 	"sort"
 
 	domains "chico/takeout/domains/item"
-	"github.com/jinzhu/copier"
 )
 
 var stockMemory map[string]*domains.StockItem
@@ -46,8 +45,7 @@ func (s *StockItemMemoryRepository) Reset() {
 func (s *StockItemMemoryRepository) Find(id string) (*domains.StockItem, error) {
 	if val, ok := s.inMemory[id]; ok {
 		// need copy to protect
-		duplicated := domains.StockItem{}
-		copier.Copy(&duplicated, &val)
+		duplicated := *val
 		return &duplicated, nil
 	}
 	return nil, nil
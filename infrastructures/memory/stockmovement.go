@@ -0,0 +1,54 @@
+package memory
+
+import (
+	"sort"
+	"time"
+
+	domains "chico/takeout/domains/item"
+)
+
+type StockMovementMemoryRepository struct {
+	inMemory []domains.StockMovement
+}
+
+func NewStockMovementMemoryRepository() *StockMovementMemoryRepository {
+	return &StockMovementMemoryRepository{
+		inMemory: []domains.StockMovement{},
+	}
+}
+
+func (s *StockMovementMemoryRepository) Reset() {
+	s.inMemory = []domains.StockMovement{}
+}
+
+func (s *StockMovementMemoryRepository) GetMemory() []domains.StockMovement {
+	return s.inMemory
+}
+
+func (s *StockMovementMemoryRepository) Create(movement *domains.StockMovement) error {
+	s.inMemory = append(s.inMemory, *movement)
+	return nil
+}
+
+func (s *StockMovementMemoryRepository) FindByStockItemId(stockItemId string, from, to time.Time) ([]domains.StockMovement, error) {
+	movements := []domains.StockMovement{}
+	for _, movement := range s.inMemory {
+		if movement.GetStockItemId() != stockItemId {
+			continue
+		}
+		if movement.GetCreatedAt().Before(from) || movement.GetCreatedAt().After(to) {
+			continue
+		}
+		movements = append(movements, movement)
+	}
+	sort.SliceStable(movements, func(i, j int) bool { return movements[i].GetCreatedAt().Before(movements[j].GetCreatedAt()) })
+	return movements, nil
+}
+
+func (s *StockMovementMemoryRepository) SumQuantities() (map[string]int, error) {
+	sums := map[string]int{}
+	for _, movement := range s.inMemory {
+		sums[movement.GetStockItemId()] += movement.GetQuantity()
+	}
+	return sums, nil
+}
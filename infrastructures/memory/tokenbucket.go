@@ -0,0 +1,52 @@
+package memory
+
+import (
+	"sync"
+	"time"
+
+	domains "chico/takeout/domains/ratelimit"
+)
+
+// remove full buckets when size is over
+const tokenBucketSweepSize = 10000
+
+// buckets are swept by the limit of the caller, so the repository should not be shared by limiters
+type TokenBucketMemoryRepository struct {
+	mutex    sync.Mutex
+	inMemory map[string]*domains.TokenBucket
+}
+
+func NewTokenBucketMemoryRepository() *TokenBucketMemoryRepository {
+	return &TokenBucketMemoryRepository{
+		inMemory: map[string]*domains.TokenBucket{},
+	}
+}
+
+func (t *TokenBucketMemoryRepository) Reset() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.inMemory = map[string]*domains.TokenBucket{}
+}
+
+func (t *TokenBucketMemoryRepository) Take(key string, limit domains.Limit, now time.Time) (bool, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	bucket, ok := t.inMemory[key]
+	if !ok {
+		if len(t.inMemory) >= tokenBucketSweepSize {
+			t.sweep(limit, now)
+		}
+		bucket = domains.NewTokenBucket(key, limit, now)
+		t.inMemory[key] = bucket
+	}
+	return bucket.Take(limit, now), nil
+}
+
+func (t *TokenBucketMemoryRepository) sweep(limit domains.Limit, now time.Time) {
+	for key, bucket := range t.inMemory {
+		if bucket.IsFull(limit, now) {
+			delete(t.inMemory, key)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package idempotency
+
+import (
+	"errors"
+	"time"
+
+	domains "chico/takeout/domains/idempotency"
+	"chico/takeout/infrastructures/rdbms"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IdempotencyRecordRepository struct {
+	rdbms.BaseRepository
+}
+
+func NewIdempotencyRecordRepository(db *gorm.DB) *IdempotencyRecordRepository {
+	return &IdempotencyRecordRepository{
+		BaseRepository: rdbms.BaseRepository{Db: db},
+	}
+}
+
+type IdempotencyRecordModel struct {
+	RecordKey    string `gorm:"primaryKey"`
+	RequestHash  string
+	Completed    bool
+	StatusCode   int
+	ContentType  string
+	ResponseBody []byte
+	ResponseHash string
+	CreatedAt    time.Time `gorm:"index"`
+}
+
+func newIdempotencyRecordModel(record *domains.Record) *IdempotencyRecordModel {
+	return &IdempotencyRecordModel{
+		RecordKey:    record.GetKey(),
+		RequestHash:  record.GetRequestHash(),
+		Completed:    record.IsCompleted(),
+		StatusCode:   record.GetStatusCode(),
+		ContentType:  record.GetContentType(),
+		ResponseBody: record.GetResponseBody(),
+		ResponseHash: record.GetResponseHash(),
+		CreatedAt:    record.GetCreated(),
+	}
+}
+
+func (i *IdempotencyRecordModel) toDomain() *domains.Record {
+	return domains.NewRecordForOrm(i.RecordKey, i.RequestHash, i.Completed, i.StatusCode, i.ContentType, i.ResponseBody, i.ResponseHash, i.CreatedAt)
+}
+
+func (i *IdempotencyRecordRepository) Find(key string) (*domains.Record, error) {
+	model := IdempotencyRecordModel{}
+	err := i.Db.First(&model, "record_key = ?", key).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return model.toDomain(), nil
+}
+
+func (i *IdempotencyRecordRepository) Reserve(record *domains.Record, now time.Time) (bool, error) {
+	model := newIdempotencyRecordModel(record)
+	// overwrite only expired or stale in-progress record, so that concurrent requests can not reserve same key
+	createdAt := clause.Column{Table: "idempotency_record_models", Name: "created_at"}
+	result := i.Db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "record_key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"request_hash", "completed", "status_code", "content_type", "response_body", "response_hash", "created_at"}),
+		Where: clause.Where{Exprs: []clause.Expression{
+			clause.Or(
+				clause.Lte{Column: createdAt, Value: domains.GetExpiredBefore(now)},
+				clause.And(
+					clause.Eq{Column: clause.Column{Table: "idempotency_record_models", Name: "completed"}, Value: false},
+					clause.Lte{Column: createdAt, Value: domains.GetLeaseExpiredBefore(now)},
+				),
+			),
+		}},
+	}).Create(model)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (i *IdempotencyRecordRepository) Update(record *domains.Record) error {
+	model := newIdempotencyRecordModel(record)
+	result := i.Db.Model(&IdempotencyRecordModel{}).Where("record_key = ?", record.GetKey()).
+		Updates(map[string]interface{}{
+			"completed":     model.Completed,
+			"status_code":   model.StatusCode,
+			"content_type":  model.ContentType,
+			"response_body": model.ResponseBody,
+			"response_hash": model.ResponseHash,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("update target not exists")
+	}
+	return nil
+}
+
+func (i *IdempotencyRecordRepository) Delete(key string) error {
+	return i.Db.Where("record_key = ?", key).Delete(&IdempotencyRecordModel{}).Error
+}
+
+func (i *IdempotencyRecordRepository) DeleteExpired(now time.Time) error {
+	return i.Db.Where("created_at <= ?", domains.GetExpiredBefore(now)).Delete(&IdempotencyRecordModel{}).Error
+}
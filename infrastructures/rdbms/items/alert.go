@@ -0,0 +1,97 @@
+package items
+
+import (
+	"time"
+
+	"chico/takeout/common"
+	domains "chico/takeout/domains/item"
+	"chico/takeout/infrastructures/rdbms"
+
+	"gorm.io/gorm"
+)
+
+type ItemAlertRepository struct {
+	db *gorm.DB
+}
+
+func NewItemAlertRepository(db *gorm.DB) *ItemAlertRepository {
+	return &ItemAlertRepository{
+		db: db,
+	}
+}
+
+// same alert of item is raised once per target date
+type ItemAlertModel struct {
+	rdbms.BaseModel
+	ItemId     string     `gorm:"uniqueIndex:idx_item_alert"`
+	AlertType  string     `gorm:"uniqueIndex:idx_item_alert"`
+	TargetDate *time.Time `gorm:"uniqueIndex:idx_item_alert"`
+	ItemType   string
+	ItemName   string
+	Remain     int
+	Threshold  int
+}
+
+func newItemAlertModel(a *domains.ItemAlert) (*ItemAlertModel, error) {
+	targetDate, err := common.ConvertStrToDate(a.GetTargetDate())
+	if err != nil {
+		return nil, err
+	}
+	model := ItemAlertModel{}
+	model.ID = a.GetId()
+	model.CreatedAt = a.GetCreatedAt()
+	model.ItemId = a.GetItemId()
+	model.AlertType = string(a.GetAlertType())
+	model.TargetDate = targetDate
+	model.ItemType = a.GetItemType()
+	model.ItemName = a.GetItemName()
+	model.Remain = a.GetRemain()
+	model.Threshold = a.GetThreshold()
+	return &model, nil
+}
+
+func (a *ItemAlertModel) toDomain() (*domains.ItemAlert, error) {
+	return domains.NewItemAlertForOrm(a.ID, a.ItemId, a.ItemType, a.ItemName, a.AlertType, common.ConvertTimeToDateStr(*a.TargetDate), a.Remain, a.Threshold, a.CreatedAt)
+}
+
+func (a *ItemAlertRepository) Create(alert *domains.ItemAlert) error {
+	model, err := newItemAlertModel(alert)
+	if err != nil {
+		return err
+	}
+	return a.db.Create(&model).Error
+}
+
+func (a *ItemAlertRepository) Exists(itemId string, alertType domains.ItemAlertType, targetDate string) (bool, error) {
+	date, err := common.ConvertStrToDate(targetDate)
+	if err != nil {
+		return false, err
+	}
+	var count int64
+	err = a.db.Model(&ItemAlertModel{}).Where("item_id = ? and alert_type = ? and target_date = ?", itemId, string(alertType), date).Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (a *ItemAlertRepository) FindByTargetDate(targetDate string) ([]domains.ItemAlert, error) {
+	date, err := common.ConvertStrToDate(targetDate)
+	if err != nil {
+		return nil, err
+	}
+	models := []ItemAlertModel{}
+	err = a.db.Where("target_date = ?", date).Order("created_at").Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+	alerts := []domains.ItemAlert{}
+	for _, model := range models {
+		alert, err := model.toDomain()
+		if err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, *alert)
+	}
+	return alerts, nil
+}
@@ -42,6 +42,7 @@ type FoodItemModel struct {
 	BusinessHours   []store.BusinessHourModel `gorm:"many2many:foodItem_businessHours;"`
 	ImageUrl        string
 	AllowDates      ItemSchedule `gorm:"serializer:json"`
+	LeadTimeHours   uint         `gorm:"not null;default:0"`
 }
 
 type ItemSchedule struct {
@@ -88,6 +89,7 @@ func newFoodItemModel(s *domains.FoodItem) *FoodItemModel {
 	model.BusinessHours = hours
 
 	model.AllowDates = ItemSchedule{Dates: s.GetAllowDatesAsTime()}
+	model.LeadTimeHours = s.GetLeadTimeHours()
 
 	return &model
 }
@@ -101,7 +103,7 @@ func (s *FoodItemModel) toDomain() (*domains.FoodItem, error) {
 	for _, date := range s.AllowDates.Dates {
 		dates = append(dates, common.ConvertTimeToDateStr(date))
 	}
-	model, err := domains.NewFoodItemForOrm(s.ID, s.Name, s.Description, s.Priority, s.MaxOrder, s.MaxOrderPerDay, s.Price, s.ItemKindModelID, ids, s.Enabled, s.ImageUrl, dates)
+	model, err := domains.NewFoodItemForOrm(s.ID, s.Name, s.Description, s.Priority, s.MaxOrder, s.MaxOrderPerDay, s.Price, s.ItemKindModelID, ids, s.Enabled, s.ImageUrl, dates, s.LeadTimeHours)
 	if err != nil {
 		return nil, err
 	}
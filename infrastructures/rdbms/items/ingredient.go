@@ -0,0 +1,253 @@
+package items
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"chico/takeout/common"
+	domains "chico/takeout/domains/item"
+	"chico/takeout/infrastructures/rdbms"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type IngredientRepository struct {
+	db *gorm.DB
+}
+
+func NewIngredientRepository(db *gorm.DB) *IngredientRepository {
+	return &IngredientRepository{
+		db: db,
+	}
+}
+
+type IngredientModel struct {
+	rdbms.BaseModel
+	Name          string
+	Unit          string
+	DailyQuantity int
+}
+
+func newIngredientModel(i *domains.Ingredient) *IngredientModel {
+	model := IngredientModel{}
+	model.ID = i.GetId()
+	model.Name = i.GetName()
+	model.Unit = i.GetUnit()
+	model.DailyQuantity = i.GetDailyQuantity()
+	return &model
+}
+
+func (i *IngredientModel) toDomain() (*domains.Ingredient, error) {
+	return domains.NewIngredientForOrm(i.ID, i.Name, i.Unit, i.DailyQuantity)
+}
+
+func (i *IngredientRepository) Find(id string) (*domains.Ingredient, error) {
+	model := IngredientModel{}
+	err := i.db.First(&model, "ID=?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return model.toDomain()
+}
+
+func (i *IngredientRepository) FindAll() ([]domains.Ingredient, error) {
+	models := []IngredientModel{}
+	err := i.db.Order("name").Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+	ingredients := []domains.Ingredient{}
+	for _, model := range models {
+		ingredient, err := model.toDomain()
+		if err != nil {
+			return nil, err
+		}
+		ingredients = append(ingredients, *ingredient)
+	}
+	return ingredients, nil
+}
+
+func (i *IngredientRepository) Create(item *domains.Ingredient) (string, error) {
+	model := newIngredientModel(item)
+	err := i.db.Create(&model).Error
+	if err != nil {
+		return "", err
+	}
+	return item.GetId(), nil
+}
+
+func (i *IngredientRepository) Update(item *domains.Ingredient) error {
+	model := newIngredientModel(item)
+	return i.db.Save(&model).Error
+}
+
+func (i *IngredientRepository) Delete(id string) error {
+	model := IngredientModel{
+		BaseModel: rdbms.BaseModel{ID: id},
+	}
+	return i.db.Delete(&model).Error
+}
+
+type RecipeRepository struct {
+	db *gorm.DB
+}
+
+func NewRecipeRepository(db *gorm.DB) *RecipeRepository {
+	return &RecipeRepository{
+		db: db,
+	}
+}
+
+// one record per ingredient of item
+type RecipeIngredientModel struct {
+	rdbms.BaseModel
+	ItemId            string `gorm:"index"`
+	ItemType          string
+	IngredientModelID string `gorm:"index"`
+	Quantity          int
+}
+
+func toRecipes(models []RecipeIngredientModel) ([]domains.Recipe, error) {
+	itemIds := []string{}
+	itemTypes := map[string]string{}
+	lines := map[string][]domains.RecipeIngredient{}
+	for _, model := range models {
+		if _, ok := lines[model.ItemId]; !ok {
+			itemIds = append(itemIds, model.ItemId)
+			itemTypes[model.ItemId] = model.ItemType
+		}
+		line, err := domains.NewRecipeIngredient(model.IngredientModelID, model.Quantity)
+		if err != nil {
+			return nil, err
+		}
+		lines[model.ItemId] = append(lines[model.ItemId], *line)
+	}
+	sort.Strings(itemIds)
+	recipes := []domains.Recipe{}
+	for _, itemId := range itemIds {
+		recipe, err := domains.NewRecipe(itemId, itemTypes[itemId], lines[itemId])
+		if err != nil {
+			return nil, err
+		}
+		recipes = append(recipes, *recipe)
+	}
+	return recipes, nil
+}
+
+func (r *RecipeRepository) FindByItemId(itemId string) (*domains.Recipe, error) {
+	models := []RecipeIngredientModel{}
+	err := r.db.Where("item_id = ?", itemId).Order("created_at").Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+	recipes, err := toRecipes(models)
+	if err != nil {
+		return nil, err
+	}
+	if len(recipes) == 0 {
+		return nil, nil
+	}
+	return &recipes[0], nil
+}
+
+func (r *RecipeRepository) FindAll() ([]domains.Recipe, error) {
+	models := []RecipeIngredientModel{}
+	err := r.db.Order("created_at").Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+	return toRecipes(models)
+}
+
+func (r *RecipeRepository) Save(recipe *domains.Recipe) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Unscoped().Where("item_id = ?", recipe.GetItemId()).Delete(&RecipeIngredientModel{}).Error
+		if err != nil {
+			return err
+		}
+		for _, ingredient := range recipe.GetIngredients() {
+			model := RecipeIngredientModel{}
+			model.ID = uuid.NewString()
+			model.ItemId = recipe.GetItemId()
+			model.ItemType = recipe.GetItemType()
+			model.IngredientModelID = ingredient.GetIngredientId()
+			model.Quantity = ingredient.GetQuantity()
+			err = tx.Create(&model).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *RecipeRepository) Delete(itemId string) error {
+	return r.db.Unscoped().Where("item_id = ?", itemId).Delete(&RecipeIngredientModel{}).Error
+}
+
+type IngredientUsageRepository struct {
+	db *gorm.DB
+}
+
+func NewIngredientUsageRepository(db *gorm.DB) *IngredientUsageRepository {
+	return &IngredientUsageRepository{
+		db: db,
+	}
+}
+
+type IngredientUsageModel struct {
+	rdbms.BaseModel
+	IngredientModelID string     `gorm:"uniqueIndex:idx_ingredient_usage"`
+	Date              *time.Time `gorm:"uniqueIndex:idx_ingredient_usage"`
+	Used              int
+}
+
+func (u *IngredientUsageModel) toDomain() (*domains.IngredientUsage, error) {
+	return domains.NewIngredientUsageForOrm(u.IngredientModelID, common.ConvertTimeToDateStr(*u.Date), u.Used)
+}
+
+func (u *IngredientUsageRepository) FindByDate(date string) ([]domains.IngredientUsage, error) {
+	target, err := common.ConvertStrToDate(date)
+	if err != nil {
+		return nil, err
+	}
+	models := []IngredientUsageModel{}
+	err = u.db.Where("date = ?", target).Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+	usages := []domains.IngredientUsage{}
+	for _, model := range models {
+		usage, err := model.toDomain()
+		if err != nil {
+			return nil, err
+		}
+		usages = append(usages, *usage)
+	}
+	return usages, nil
+}
+
+func (u *IngredientUsageRepository) Save(usage *domains.IngredientUsage) error {
+	target, err := common.ConvertStrToDate(usage.GetDate())
+	if err != nil {
+		return err
+	}
+	model := IngredientUsageModel{}
+	err = u.db.Where("ingredient_model_id = ? and date = ?", usage.GetIngredientId(), target).First(&model).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		model.ID = uuid.NewString()
+		model.IngredientModelID = usage.GetIngredientId()
+		model.Date = target
+		model.Used = usage.GetUsed()
+		return u.db.Create(&model).Error
+	}
+	if err != nil {
+		return err
+	}
+	return u.db.Model(&IngredientUsageModel{}).Where("id = ?", model.ID).Update("used", usage.GetUsed()).Error
+}
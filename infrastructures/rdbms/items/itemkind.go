@@ -23,7 +23,18 @@ type ItemKindModel struct {
 	rdbms.BaseModel
 	Name             string
 	Priority         int
-	OptionItemModels []OptionItemModel `gorm:"many2many:itemKind_optionItems;"`
+	OptionItemModels []OptionItemModel  `gorm:"many2many:itemKind_optionItems;"`
+	OptionGroups     []OptionGroupModel `gorm:"serializer:json"`
+}
+
+type OptionGroupModel struct {
+	Name             string
+	OptionItemIds    []string
+	Required         bool
+	MinSelect        uint
+	MaxSelect        uint
+	MaxQuantity      uint
+	DefaultOptionIds []string
 }
 
 func (i *ItemKindModel) toDomain() (*domains.ItemKind, error) {
@@ -35,6 +46,18 @@ func (i *ItemKindModel) toDomain() (*domains.ItemKind, error) {
 	if err != nil {
 		return nil, err
 	}
+	groups := []domains.OptionGroup{}
+	for _, g := range i.OptionGroups {
+		group, err := domains.NewOptionGroup(g.Name, g.OptionItemIds, g.Required, g.MinSelect, g.MaxSelect, g.MaxQuantity, g.DefaultOptionIds)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, *group)
+	}
+	err = model.SetOptionGroups(groups)
+	if err != nil {
+		return nil, err
+	}
 	return model, nil
 }
 
@@ -53,6 +76,20 @@ func newItemModel(i *domains.ItemKind) *ItemKindModel {
 
 	model.OptionItemModels = options
 
+	groups := []OptionGroupModel{}
+	for _, group := range i.GetOptionGroups() {
+		groups = append(groups, OptionGroupModel{
+			Name:             group.GetName(),
+			OptionItemIds:    group.GetOptionItemIds(),
+			Required:         group.IsRequired(),
+			MinSelect:        group.GetMinSelect(),
+			MaxSelect:        group.GetMaxSelect(),
+			MaxQuantity:      group.GetMaxQuantity(),
+			DefaultOptionIds: group.GetDefaultOptionIds(),
+		})
+	}
+	model.OptionGroups = groups
+
 	return &model
 }
 
@@ -0,0 +1,130 @@
+package items
+
+import (
+	"errors"
+	"time"
+
+	"chico/takeout/common"
+	domains "chico/takeout/domains/item"
+	"chico/takeout/infrastructures/rdbms"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type FoodQuotaRepository struct {
+	db *gorm.DB
+}
+
+func NewFoodQuotaRepository(db *gorm.DB) *FoodQuotaRepository {
+	return &FoodQuotaRepository{
+		db: db,
+	}
+}
+
+type FoodQuotaModel struct {
+	rdbms.BaseModel
+	FoodItemModelID string     `gorm:"uniqueIndex:idx_food_quota"`
+	Date            *time.Time `gorm:"uniqueIndex:idx_food_quota"`
+	// empty means whole day
+	HourTypeId string `gorm:"uniqueIndex:idx_food_quota"`
+	Quantity   int
+	// manual or forecast
+	Source string
+}
+
+func (q *FoodQuotaModel) toDomain() (*domains.FoodQuota, error) {
+	return domains.NewFoodQuotaForOrm(q.FoodItemModelID, common.ConvertTimeToDateStr(*q.Date), q.HourTypeId, q.Quantity, q.Source)
+}
+
+func (q *FoodQuotaRepository) FindByDate(date string) ([]domains.FoodQuota, error) {
+	return q.FindByRange(date, date)
+}
+
+func (q *FoodQuotaRepository) FindByRange(startDate, endDate string) ([]domains.FoodQuota, error) {
+	start, err := common.ConvertStrToDate(startDate)
+	if err != nil {
+		return nil, err
+	}
+	end, err := common.ConvertStrToDate(endDate)
+	if err != nil {
+		return nil, err
+	}
+	models := []FoodQuotaModel{}
+	err = q.db.Where("date >= ? and date <= ?", start, end).Order("date, hour_type_id").Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+	quotas := []domains.FoodQuota{}
+	for _, model := range models {
+		quota, err := model.toDomain()
+		if err != nil {
+			return nil, err
+		}
+		quotas = append(quotas, *quota)
+	}
+	return quotas, nil
+}
+
+func (q *FoodQuotaRepository) Save(quota *domains.FoodQuota) error {
+	return saveFoodQuota(q.db, quota)
+}
+
+func (q *FoodQuotaRepository) SaveAll(quotas []domains.FoodQuota) error {
+	return q.db.Transaction(func(tx *gorm.DB) error {
+		for i := range quotas {
+			err := saveFoodQuota(tx, &quotas[i])
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func saveFoodQuota(tx *gorm.DB, quota *domains.FoodQuota) error {
+	target, err := common.ConvertStrToDate(quota.GetDate())
+	if err != nil {
+		return err
+	}
+	model := FoodQuotaModel{}
+	err = tx.Where("food_item_model_id = ? and date = ? and hour_type_id = ?", quota.GetItemId(), target, quota.GetHourTypeId()).First(&model).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		model.ID = uuid.NewString()
+		model.FoodItemModelID = quota.GetItemId()
+		model.Date = target
+		model.HourTypeId = quota.GetHourTypeId()
+		model.Quantity = quota.GetQuantity()
+		model.Source = string(quota.GetSource())
+		return tx.Create(&model).Error
+	}
+	if err != nil {
+		return err
+	}
+	return tx.Model(&FoodQuotaModel{}).Where("id = ?", model.ID).Updates(map[string]interface{}{"quantity": quota.GetQuantity(), "source": string(quota.GetSource())}).Error
+}
+
+// deleted permanently to keep unique index of item, date and hour type
+func (q *FoodQuotaRepository) Delete(itemId, date, hourTypeId string) error {
+	target, err := common.ConvertStrToDate(date)
+	if err != nil {
+		return err
+	}
+	return q.db.Unscoped().Where("food_item_model_id = ? and date = ? and hour_type_id = ?", itemId, target, hourTypeId).Delete(&FoodQuotaModel{}).Error
+}
+
+// deleted by one statement
+func (q *FoodQuotaRepository) DeleteAll(itemId, hourTypeId string, dates []string) error {
+	if len(dates) == 0 {
+		return nil
+	}
+	targets := []time.Time{}
+	for _, date := range dates {
+		target, err := common.ConvertStrToDate(date)
+		if err != nil {
+			return err
+		}
+		targets = append(targets, *target)
+	}
+	return q.db.Unscoped().Where("food_item_model_id = ? and hour_type_id = ? and date in ?", itemId, hourTypeId, targets).Delete(&FoodQuotaModel{}).Error
+}
@@ -0,0 +1,110 @@
+package items
+
+import (
+	"errors"
+	"time"
+
+	"chico/takeout/common"
+	domains "chico/takeout/domains/item"
+	"chico/takeout/infrastructures/rdbms"
+
+	"gorm.io/gorm"
+)
+
+type StockBatchRepository struct {
+	db *gorm.DB
+}
+
+func NewStockBatchRepository(db *gorm.DB) *StockBatchRepository {
+	return &StockBatchRepository{
+		db: db,
+	}
+}
+
+type StockBatchModel struct {
+	rdbms.BaseModel
+	StockItemModelID string `gorm:"index"`
+	AvailableFrom    *time.Time
+	ExpiresAt        *time.Time
+	Received         int
+	Remain           int
+	Wasted           int
+}
+
+func newStockBatchModel(s *domains.StockBatch) (*StockBatchModel, error) {
+	model := StockBatchModel{}
+	model.ID = s.GetId()
+	model.StockItemModelID = s.GetStockItemId()
+	availableFrom, err := common.ConvertStrToDate(s.GetAvailableFrom())
+	if err != nil {
+		return nil, err
+	}
+	model.AvailableFrom = availableFrom
+	expiresAt, err := common.ConvertStrToDate(s.GetExpiresAt())
+	if err != nil {
+		return nil, err
+	}
+	model.ExpiresAt = expiresAt
+	model.Received = s.GetReceived()
+	model.Remain = s.GetRemain()
+	model.Wasted = s.GetWasted()
+	return &model, nil
+}
+
+func (s *StockBatchModel) toDomain() (*domains.StockBatch, error) {
+	availableFrom := common.ConvertTimeToDateStr(*s.AvailableFrom)
+	expiresAt := common.ConvertTimeToDateStr(*s.ExpiresAt)
+	return domains.NewStockBatchForOrm(s.ID, s.StockItemModelID, availableFrom, expiresAt, s.Received, s.Remain, s.Wasted)
+}
+
+func (s *StockBatchRepository) Find(id string) (*domains.StockBatch, error) {
+	model := StockBatchModel{}
+	err := s.db.First(&model, "ID=?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return model.toDomain()
+}
+
+func (s *StockBatchRepository) FindByStockItemId(stockItemId string) ([]domains.StockBatch, error) {
+	models := []StockBatchModel{}
+	err := s.db.Where("stock_item_model_id = ?", stockItemId).Order("expires_at").Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+	batches := []domains.StockBatch{}
+	for _, model := range models {
+		batch, err := model.toDomain()
+		if err != nil {
+			return nil, err
+		}
+		batches = append(batches, *batch)
+	}
+	return batches, nil
+}
+
+func (s *StockBatchRepository) Create(batch *domains.StockBatch) (string, error) {
+	model, err := newStockBatchModel(batch)
+	if err != nil {
+		return "", err
+	}
+	err = s.db.Create(&model).Error
+	if err != nil {
+		return "", err
+	}
+	return batch.GetId(), nil
+}
+
+func (s *StockBatchRepository) Update(batch *domains.StockBatch) error {
+	model, err := newStockBatchModel(batch)
+	if err != nil {
+		return err
+	}
+	return s.db.Model(&StockBatchModel{}).Where("id = ?", model.ID).Updates(map[string]interface{}{
+		"remain": model.Remain,
+		"wasted": model.Wasted,
+	}).Error
+}
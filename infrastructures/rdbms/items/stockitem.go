@@ -32,6 +32,9 @@ type StockItemModel struct {
 	ItemKindModelID string
 	ItemKindModel   ItemKindModel
 	ImageUrl        string
+	TrackByBatch    bool
+	// 0 means only sold out is alerted
+	LowStockThreshold int
 }
 
 func newStockItemModel(s *domains.StockItem) *StockItemModel {
@@ -46,6 +49,8 @@ func newStockItemModel(s *domains.StockItem) *StockItemModel {
 	model.Remain = s.GetRemain()
 	model.ItemKindModelID = s.GetKindId()
 	model.ImageUrl = s.GetImageUrl()
+	model.TrackByBatch = s.IsTrackedByBatch()
+	model.LowStockThreshold = s.GetLowStockThreshold()
 
 	return &model
 }
@@ -55,6 +60,11 @@ func (s *StockItemModel) toDomain() (*domains.StockItem, error) {
 	if err != nil {
 		return nil, err
 	}
+	model.SetTrackByBatch(s.TrackByBatch)
+	err = model.SetLowStockThreshold(s.LowStockThreshold)
+	if err != nil {
+		return nil, err
+	}
 	return model, nil
 }
 
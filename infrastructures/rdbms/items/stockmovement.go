@@ -0,0 +1,90 @@
+package items
+
+import (
+	"time"
+
+	domains "chico/takeout/domains/item"
+	"chico/takeout/infrastructures/rdbms"
+
+	"gorm.io/gorm"
+)
+
+type StockMovementRepository struct {
+	db *gorm.DB
+}
+
+func NewStockMovementRepository(db *gorm.DB) *StockMovementRepository {
+	return &StockMovementRepository{
+		db: db,
+	}
+}
+
+// append only. created at is time of movement
+type StockMovementModel struct {
+	rdbms.BaseModel
+	StockItemModelID  string `gorm:"index"`
+	StockBatchModelID string
+	MovementType      string
+	Quantity          int
+	OrderInfoModelID  string
+	UserId            string
+	Memo              string
+}
+
+func newStockMovementModel(s *domains.StockMovement) *StockMovementModel {
+	model := StockMovementModel{}
+	model.ID = s.GetId()
+	model.CreatedAt = s.GetCreatedAt()
+	model.StockItemModelID = s.GetStockItemId()
+	model.StockBatchModelID = s.GetBatchId()
+	model.MovementType = string(s.GetMovementType())
+	model.Quantity = s.GetQuantity()
+	model.OrderInfoModelID = s.GetOrderId()
+	model.UserId = s.GetUserId()
+	model.Memo = s.GetMemo()
+	return &model
+}
+
+func (s *StockMovementModel) toDomain() (*domains.StockMovement, error) {
+	return domains.NewStockMovementForOrm(s.ID, s.StockItemModelID, s.StockBatchModelID, s.MovementType, s.Quantity, s.OrderInfoModelID, s.UserId, s.Memo, s.CreatedAt)
+}
+
+func (s *StockMovementRepository) Create(movement *domains.StockMovement) error {
+	model := newStockMovementModel(movement)
+	return s.db.Create(&model).Error
+}
+
+func (s *StockMovementRepository) FindByStockItemId(stockItemId string, from, to time.Time) ([]domains.StockMovement, error) {
+	models := []StockMovementModel{}
+	err := s.db.Where("stock_item_model_id = ? and created_at >= ? and created_at <= ?", stockItemId, from, to).Order("created_at").Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+	movements := []domains.StockMovement{}
+	for _, model := range models {
+		movement, err := model.toDomain()
+		if err != nil {
+			return nil, err
+		}
+		movements = append(movements, *movement)
+	}
+	return movements, nil
+}
+
+type stockMovementSum struct {
+	StockItemModelID string
+	Quantity         int
+}
+
+func (s *StockMovementRepository) SumQuantities() (map[string]int, error) {
+	rows := []stockMovementSum{}
+	err := s.db.Model(&StockMovementModel{}).Select("stock_item_model_id, SUM(quantity) as quantity").Group("stock_item_model_id").Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	sums := map[string]int{}
+	for _, row := range rows {
+		sums[row.StockItemModelID] = row.Quantity
+	}
+	return sums, nil
+}
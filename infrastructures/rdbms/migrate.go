@@ -0,0 +1,65 @@
+package rdbms
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type timestampColumn struct {
+	TableName  string
+	ColumnName string
+}
+
+// convert columns of the models' tables created as "timestamp without time zone" to timestamptz.
+// existing values are regarded as local time of the store.
+func MigrateTimestampToTimestamptz(db *gorm.DB, timeZone string, models ...interface{}) error {
+	zone, err := quoteTimeZone(timeZone)
+	if err != nil {
+		return err
+	}
+	tables := []string{}
+	for _, model := range models {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			return err
+		}
+		tables = append(tables, stmt.Schema.Table)
+	}
+	if len(tables) == 0 {
+		return nil
+	}
+	columns := []timestampColumn{}
+	err = db.Raw(`select table_name, column_name from information_schema.columns
+	 where table_schema = current_schema() and data_type = 'timestamp without time zone' and table_name in ?`, tables).Scan(&columns).Error
+	if err != nil {
+		return err
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, column := range columns {
+			fmt.Printf("migrate %s.%s to timestamptz\n", column.TableName, column.ColumnName)
+			table := tx.Statement.Quote(column.TableName)
+			name := tx.Statement.Quote(column.ColumnName)
+			// DDL does not accept bind parameters, so the validated zone is embedded as literal
+			sql := fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s TYPE timestamptz USING %s AT TIME ZONE %s", table, name, name, zone)
+			if err := tx.Exec(sql).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// quoted literal of IANA time zone name
+func quoteTimeZone(timeZone string) (string, error) {
+	// empty and Local are accepted by LoadLocation, but not known by database
+	if timeZone == "" || timeZone == "Local" {
+		return "", fmt.Errorf("time zone should be IANA name. value:%s", timeZone)
+	}
+	if _, err := time.LoadLocation(timeZone); err != nil {
+		return "", fmt.Errorf("time zone is not valid. value:%s %v", timeZone, err)
+	}
+	return "'" + strings.ReplaceAll(timeZone, "'", "''") + "'", nil
+}
@@ -0,0 +1,18 @@
+package rdbms
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuoteTimeZone(t *testing.T) {
+	zone, err := quoteTimeZone("Asia/Tokyo")
+	assert.NoError(t, err)
+	assert.Equal(t, "'Asia/Tokyo'", zone)
+
+	for _, invalid := range []string{"", "Local", "Asia/Unknown", "UTC'; DROP TABLE order_info_models; --"} {
+		_, err = quoteTimeZone(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
@@ -33,9 +33,12 @@ type OrderInfoModel struct {
 	UserEmail              string
 	UserTelNo              string
 	Memo                   string
-	OrderDateTime          time.Time
-	PickupDateTime         time.Time
+	OrderDateTime          time.Time `gorm:"type:timestamptz"`
+	PickupDateTime         time.Time `gorm:"type:timestamptz"`
 	Canceled               bool
+	CancelReason           string
+	Source                 string `gorm:"default:web"`
+	ConsumedIngredients    map[string]int         `gorm:"serializer:json"`
 	StockItemModels        []items.StockItemModel `gorm:"many2many:orderInfo_stockItems;"`
 	FoodItemModels         []items.FoodItemModel  `gorm:"many2many:orderInfo_foodItems;"`
 	OrderedStockItemModels []OrderedStockItemModel
@@ -111,16 +114,6 @@ func (r OrderedFoodOptionItemModel) Value() (driver.Value, error) {
 }
 
 func newOrderInfoModel(order *domains.OrderInfo) (*OrderInfoModel, error) {
-
-	orderDateTime, err := common.ConvertStrToDateTime(order.GetOrderDateTime())
-	if err != nil {
-		return nil, err
-	}
-	pickupDateTime, err := common.ConvertStrToDateTime(order.GetPickupDateTime())
-	if err != nil {
-		return nil, err
-	}
-
 	model := &OrderInfoModel{}
 	model.ID = order.GetId()
 	model.UserID = order.GetUserId()
@@ -128,8 +121,10 @@ func newOrderInfoModel(order *domains.OrderInfo) (*OrderInfoModel, error) {
 	model.UserEmail = order.GetUserEmail()
 	model.UserTelNo = order.GetUserTelNo()
 	model.Memo = order.GetMemo()
-	model.OrderDateTime = *orderDateTime
-	model.PickupDateTime = *pickupDateTime
+	model.OrderDateTime = order.GetOrderedAt()
+	model.PickupDateTime = order.GetPickupAt()
+	model.Source = order.GetSource()
+	model.ConsumedIngredients = order.GetConsumedIngredients()
 
 	// below data is not needed to insert
 
@@ -152,6 +147,54 @@ func newOrderInfoModel(order *domains.OrderInfo) (*OrderInfoModel, error) {
 	return model, nil
 }
 
+func newOrderedStockItemModels(order *domains.OrderInfo) []OrderedStockItemModel {
+	stocks := []OrderedStockItemModel{}
+	for _, stock := range order.GetStockItems() {
+		stockModel := OrderedStockItemModel{}
+		stockModel.OrderInfoModelID = order.GetId()
+		stockModel.StockItemModelID = stock.GetItemId()
+		stockModel.Name = stock.GetName()
+		stockModel.Price = stock.GetPrice()
+		stockModel.Quantity = stock.GetQuantity()
+		options := []OrderedStockOptionItemModel{}
+		for _, opt := range stock.GetOptionItems() {
+			option := OrderedStockOptionItemModel{}
+			option.OptionItemModelID = opt.GetId()
+			option.Name = opt.GetName()
+			option.Price = opt.GetPrice()
+			option.Quantity = opt.GetQuantity()
+			options = append(options, option)
+		}
+		stockModel.Options = options
+		stocks = append(stocks, stockModel)
+	}
+	return stocks
+}
+
+func newOrderedFoodItemModels(order *domains.OrderInfo) []OrderedFoodItemModel {
+	foods := []OrderedFoodItemModel{}
+	for _, food := range order.GetFoodItems() {
+		foodModel := OrderedFoodItemModel{}
+		foodModel.OrderInfoModelID = order.GetId()
+		foodModel.FoodItemModelID = food.GetItemId()
+		foodModel.Name = food.GetName()
+		foodModel.Price = food.GetPrice()
+		foodModel.Quantity = food.GetQuantity()
+		options := []OrderedFoodOptionItemModel{}
+		for _, opt := range food.GetOptionItems() {
+			option := OrderedFoodOptionItemModel{}
+			option.OptionItemModelID = opt.GetId()
+			option.Name = opt.GetName()
+			option.Price = opt.GetPrice()
+			option.Quantity = opt.GetQuantity()
+			options = append(options, option)
+		}
+		foodModel.Options = options
+		foods = append(foods, foodModel)
+	}
+	return foods
+}
+
 func (s *OrderInfoModel) toDomain(stocks []OrderedStockItemModel, foods []OrderedFoodItemModel) (*domains.OrderInfo, error) {
 	stockDoms := []domains.OrderStockItem{}
 	for _, stock := range stocks {
@@ -178,19 +221,19 @@ func (s *OrderInfoModel) toDomain(stocks []OrderedStockItemModel, foods []Ordere
 		foodDoms = append(foodDoms, *foodDom)
 	}
 
-	pickUp := common.ConvertTimeToDateTimeStr(s.PickupDateTime)
-	ordered := common.ConvertTimeToDateTimeStr(s.OrderDateTime)
-	dom, err := domains.NewOrderInfoForOrm(s.ID, s.UserID, s.UserName, s.UserEmail, s.UserTelNo, s.Memo, pickUp, ordered, stockDoms, foodDoms, s.Canceled)
+	dom, err := domains.NewOrderInfoForOrm(s.ID, s.UserID, s.UserName, s.UserEmail, s.UserTelNo, s.Memo, s.PickupDateTime, s.OrderDateTime, stockDoms, foodDoms, s.Canceled, s.CancelReason, s.Source)
 	if err != nil {
 		return nil, err
 	}
+	// null for orders before ingredients are recorded
+	dom.SetConsumedIngredients(s.ConsumedIngredients)
 	return dom, nil
 }
 
 func (o *OrderInfoModel) toFoodOptDomain(opts []OrderedFoodOptionItemModel) ([]domains.OptionItemInfo, error) {
 	options := []domains.OptionItemInfo{}
 	for _, opt := range opts {
-		op, err := domains.NewOptionItemInfo(opt.OptionItemModelID, opt.Name, opt.Price)
+		op, err := domains.NewOptionItemInfo(opt.OptionItemModelID, opt.Name, opt.Price, OptionQuantity(opt.Quantity))
 		if err != nil {
 			return nil, err
 		}
@@ -201,7 +244,7 @@ func (o *OrderInfoModel) toFoodOptDomain(opts []OrderedFoodOptionItemModel) ([]d
 func (o *OrderInfoModel) toStockOptDomain(opts []OrderedStockOptionItemModel) ([]domains.OptionItemInfo, error) {
 	options := []domains.OptionItemInfo{}
 	for _, opt := range opts {
-		op, err := domains.NewOptionItemInfo(opt.OptionItemModelID, opt.Name, opt.Price)
+		op, err := domains.NewOptionItemInfo(opt.OptionItemModelID, opt.Name, opt.Price, OptionQuantity(opt.Quantity))
 		if err != nil {
 			return nil, err
 		}
@@ -284,6 +327,31 @@ func (o *OrderInfoRepository) FindByPickupDate(date string) ([]domains.OrderInfo
 	return orders, nil
 }
 
+func (o *OrderInfoRepository) FindByOrderDate(date string) ([]domains.OrderInfo, error) {
+	orderDateStart, err := common.ConvertStrToDate(date)
+	if err != nil {
+		return nil, err
+	}
+	orderDateEnd := orderDateStart.AddDate(0, 0, 1)
+
+	models := []OrderInfoModel{}
+	err = o.Db.Preload("OrderedStockItemModels").
+		Preload("OrderedFoodItemModels").Where("order_date_time >= ? and order_date_time < ?", orderDateStart, orderDateEnd).Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	orders := []domains.OrderInfo{}
+	for _, model := range models {
+		order, err := model.toDomain(model.OrderedStockItemModels, model.OrderedFoodItemModels)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, *order)
+	}
+	return orders, nil
+}
+
 func (o *OrderInfoRepository) FindByUserId(userId string) ([]domains.OrderInfo, error) {
 	models := []OrderInfoModel{}
 	err := o.Db.Preload("OrderedStockItemModels").Preload("OrderedFoodItemModels").Where("user_id = ?", userId).Order("pickup_date_time desc").Find(&models).Error
@@ -302,10 +370,10 @@ func (o *OrderInfoRepository) FindByUserId(userId string) ([]domains.OrderInfo,
 	return orders, nil
 }
 
-func (o *OrderInfoRepository) FindActiveByUserId(userId string) ([]domains.OrderInfo, error) {
+func (o *OrderInfoRepository) FindActiveByUserId(userId string, now time.Time) ([]domains.OrderInfo, error) {
 	models := []OrderInfoModel{}
 	// until 30 minutes passed, treats as active
-	targetTime := common.GetNowDate().Add(time.Minute * -30)
+	targetTime := common.GetDateUntilMinutes(now).Add(time.Minute * -30)
 	err := o.Db.Preload("OrderedStockItemModels").Preload("OrderedFoodItemModels").Where("user_id = ? and canceled = false and pickup_date_time > ?", userId, targetTime).Order("pickup_date_time desc").Find(&models).Error
 	if err != nil {
 		return nil, err
@@ -329,47 +397,8 @@ func (o *OrderInfoRepository) Create(order *domains.OrderInfo) (string, error) {
 	}
 	var gError error = nil
 	o.Db.Transaction(func(tx *gorm.DB) error {
-		stocks := []OrderedStockItemModel{}
-		for _, stock := range order.GetStockItems() {
-			stockModel := OrderedStockItemModel{}
-			stockModel.OrderInfoModelID = order.GetId()
-			stockModel.StockItemModelID = stock.GetItemId()
-			stockModel.Name = stock.GetName()
-			stockModel.Price = stock.GetPrice()
-			stockModel.Quantity = stock.GetQuantity()
-			options := []OrderedStockOptionItemModel{}
-			for _, opt := range stock.GetOptionItems() {
-				option := OrderedStockOptionItemModel{}
-				option.OptionItemModelID = opt.GetId()
-				option.Name = opt.GetName()
-				option.Price = opt.GetPrice()
-				options = append(options, option)
-			}
-			stockModel.Options = options
-			stocks = append(stocks, stockModel)
-		}
-		model.OrderedStockItemModels = stocks
-
-		foods := []OrderedFoodItemModel{}
-		for _, food := range order.GetFoodItems() {
-			foodModel := OrderedFoodItemModel{}
-			foodModel.OrderInfoModelID = order.GetId()
-			foodModel.FoodItemModelID = food.GetItemId()
-			foodModel.Name = food.GetName()
-			foodModel.Price = food.GetPrice()
-			foodModel.Quantity = food.GetQuantity()
-			options := []OrderedFoodOptionItemModel{}
-			for _, opt := range food.GetOptionItems() {
-				option := OrderedFoodOptionItemModel{}
-				option.OptionItemModelID = opt.GetId()
-				option.Name = opt.GetName()
-				option.Price = opt.GetPrice()
-				options = append(options, option)
-			}
-			foodModel.Options = options
-			foods = append(foods, foodModel)
-		}
-		model.OrderedFoodItemModels = foods
+		model.OrderedStockItemModels = newOrderedStockItemModels(order)
+		model.OrderedFoodItemModels = newOrderedFoodItemModels(order)
 
 		err = o.Db.Create(&model).Error
 		if err != nil {
@@ -388,12 +417,59 @@ func (o *OrderInfoRepository) Create(order *domains.OrderInfo) (string, error) {
 
 func (o *OrderInfoRepository) UpdateOrderStatus(order *domains.OrderInfo) error {
 	model := OrderInfoModel{}
-	err := o.Db.Model(&model).Where("ID = ?", order.GetId()).Update("Canceled", order.GetCanceled()).Error
+	err := o.Db.Model(&model).Where("ID = ?", order.GetId()).Updates(map[string]interface{}{"canceled": order.GetCanceled(), "cancel_reason": order.GetCancelReason()}).Error
 	return err
 }
 
+func (o *OrderInfoRepository) UpdateOwner(order *domains.OrderInfo) error {
+	model := OrderInfoModel{}
+	err := o.Db.Model(&model).Where("ID = ?", order.GetId()).Update("user_id", order.GetUserId()).Error
+	return err
+}
+
+func (o *OrderInfoRepository) UpdateItems(order *domains.OrderInfo) error {
+	return o.Db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&OrderInfoModel{}).Where("ID = ?", order.GetId()).Updates(&OrderInfoModel{PickupDateTime: order.GetPickupAt(), ConsumedIngredients: order.GetConsumedIngredients()}).Error
+		if err != nil {
+			return err
+		}
+		// ordered items are replaced
+		err = tx.Where("order_info_model_id = ?", order.GetId()).Delete(&OrderedStockItemModel{}).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Where("order_info_model_id = ?", order.GetId()).Delete(&OrderedFoodItemModel{}).Error
+		if err != nil {
+			return err
+		}
+		stocks := newOrderedStockItemModels(order)
+		if len(stocks) > 0 {
+			err = tx.Create(&stocks).Error
+			if err != nil {
+				return err
+			}
+		}
+		foods := newOrderedFoodItemModels(order)
+		if len(foods) > 0 {
+			err = tx.Create(&foods).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 func (o *OrderInfoRepository) UpdateUserInfo(order *domains.OrderInfo) error {
 	model := OrderInfoModel{}
 	err := o.Db.Model(&model).Where("ID = ?", order.GetId()).Updates(OrderInfoModel{UserName: order.GetUserName(), UserEmail: order.GetUserEmail(), UserTelNo: order.GetUserTelNo(), Memo: order.GetMemo()}).Error
 	return err
 }
+
+// quantity of option was not stored before option group
+func OptionQuantity(quantity int) int {
+	if quantity == 0 {
+		return 1
+	}
+	return quantity
+}
@@ -0,0 +1,72 @@
+package order
+
+import (
+	"errors"
+
+	domains "chico/takeout/domains/order"
+	"chico/takeout/infrastructures/rdbms"
+
+	"gorm.io/gorm"
+)
+
+// order limit is single record
+const orderLimitId = "1"
+
+type OrderLimitRepository struct {
+	db *gorm.DB
+}
+
+func NewOrderLimitRepository(db *gorm.DB) *OrderLimitRepository {
+	return &OrderLimitRepository{
+		db: db,
+	}
+}
+
+type OrderLimitModel struct {
+	rdbms.BaseModel
+	MaxActivePerUser uint
+	MaxPerPickupDate uint
+	MaxPerHourSlot   uint
+}
+
+func (o *OrderLimitModel) toDomain() (*domains.OrderLimit, error) {
+	return domains.NewOrderLimit(o.MaxActivePerUser, o.MaxPerPickupDate, o.MaxPerHourSlot)
+}
+
+func newOrderLimitModel(limit *domains.OrderLimit) *OrderLimitModel {
+	model := OrderLimitModel{}
+	model.ID = orderLimitId
+	model.MaxActivePerUser = limit.GetMaxActivePerUser()
+	model.MaxPerPickupDate = limit.GetMaxPerPickupDate()
+	model.MaxPerHourSlot = limit.GetMaxPerHourSlot()
+	return &model
+}
+
+func (o *OrderLimitRepository) Fetch() (*domains.OrderLimit, error) {
+	model := OrderLimitModel{}
+
+	err := o.db.First(&model, "ID=?", orderLimitId).Error
+	// if no record return nil (default will be used)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return model.toDomain()
+}
+
+func (o *OrderLimitRepository) Update(target *domains.OrderLimit) error {
+	model := newOrderLimitModel(target)
+	return o.db.Model(&OrderLimitModel{}).Where("ID=?", orderLimitId).
+		Updates(map[string]interface{}{
+			"max_active_per_user": model.MaxActivePerUser,
+			"max_per_pickup_date": model.MaxPerPickupDate,
+			"max_per_hour_slot":   model.MaxPerHourSlot,
+		}).Error
+}
+
+func (o *OrderLimitRepository) Create(target *domains.OrderLimit) error {
+	model := newOrderLimitModel(target)
+	return o.db.Create(model).Error
+}
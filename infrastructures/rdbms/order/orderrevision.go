@@ -0,0 +1,137 @@
+package order
+
+import (
+	"time"
+
+	domains "chico/takeout/domains/order"
+	"chico/takeout/infrastructures/rdbms"
+
+	"gorm.io/gorm"
+)
+
+type OrderRevisionRepository struct {
+	db *gorm.DB
+}
+
+func NewOrderRevisionRepository(db *gorm.DB) *OrderRevisionRepository {
+	return &OrderRevisionRepository{
+		db: db,
+	}
+}
+
+// snapshot of ordered items before amended
+type OrderRevisionModel struct {
+	rdbms.BaseModel
+	OrderInfoModelID string `gorm:"index"`
+	Revision         int
+	PickupDateTime   time.Time                `gorm:"type:timestamptz"`
+	AmendedAt        time.Time                `gorm:"type:timestamptz"`
+	StockItems       []OrderRevisionItemModel `gorm:"serializer:json"`
+	FoodItems        []OrderRevisionItemModel `gorm:"serializer:json"`
+}
+
+type OrderRevisionItemModel struct {
+	ItemID   string
+	Name     string
+	Price    int
+	Quantity int
+	Options  []OrderRevisionOptionItemModel
+}
+
+type OrderRevisionOptionItemModel struct {
+	OptionItemID string
+	Name         string
+	Price        int
+	Quantity     int
+}
+
+func newOrderRevisionItemModel(itemId, name string, price, quantity int, options []domains.OptionItemInfo) OrderRevisionItemModel {
+	opts := []OrderRevisionOptionItemModel{}
+	for _, opt := range options {
+		opts = append(opts, OrderRevisionOptionItemModel{OptionItemID: opt.GetId(), Name: opt.GetName(), Price: opt.GetPrice(), Quantity: opt.GetQuantity()})
+	}
+	return OrderRevisionItemModel{ItemID: itemId, Name: name, Price: price, Quantity: quantity, Options: opts}
+}
+
+func newOrderRevisionModel(revision *domains.OrderRevision) (*OrderRevisionModel, error) {
+	model := &OrderRevisionModel{}
+	model.ID = revision.GetId()
+	model.OrderInfoModelID = revision.GetOrderId()
+	model.Revision = revision.GetRevision()
+	model.PickupDateTime = revision.GetPickupAt()
+	model.AmendedAt = revision.GetAmendedAtTime()
+	model.StockItems = []OrderRevisionItemModel{}
+	for _, stock := range revision.GetStockItems() {
+		model.StockItems = append(model.StockItems, newOrderRevisionItemModel(stock.GetItemId(), stock.GetName(), stock.GetPrice(), stock.GetQuantity(), stock.GetOptionItems()))
+	}
+	model.FoodItems = []OrderRevisionItemModel{}
+	for _, food := range revision.GetFoodItems() {
+		model.FoodItems = append(model.FoodItems, newOrderRevisionItemModel(food.GetItemId(), food.GetName(), food.GetPrice(), food.GetQuantity(), food.GetOptionItems()))
+	}
+	return model, nil
+}
+
+func (o *OrderRevisionItemModel) toOptionDomains() ([]domains.OptionItemInfo, error) {
+	options := []domains.OptionItemInfo{}
+	for _, opt := range o.Options {
+		op, err := domains.NewOptionItemInfo(opt.OptionItemID, opt.Name, opt.Price, OptionQuantity(opt.Quantity))
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, *op)
+	}
+	return options, nil
+}
+
+func (o *OrderRevisionModel) toDomain() (*domains.OrderRevision, error) {
+	stocks := []domains.OrderStockItem{}
+	for _, stock := range o.StockItems {
+		opts, err := stock.toOptionDomains()
+		if err != nil {
+			return nil, err
+		}
+		item, err := domains.NewOrderStockItem(stock.ItemID, stock.Name, stock.Price, stock.Quantity, opts)
+		if err != nil {
+			return nil, err
+		}
+		stocks = append(stocks, *item)
+	}
+	foods := []domains.OrderFoodItem{}
+	for _, food := range o.FoodItems {
+		opts, err := food.toOptionDomains()
+		if err != nil {
+			return nil, err
+		}
+		item, err := domains.NewOrderFoodItem(food.ItemID, food.Name, food.Price, food.Quantity, opts)
+		if err != nil {
+			return nil, err
+		}
+		foods = append(foods, *item)
+	}
+	return domains.NewOrderRevisionForOrm(o.ID, o.OrderInfoModelID, o.Revision, o.PickupDateTime, o.AmendedAt, stocks, foods)
+}
+
+func (o *OrderRevisionRepository) FindByOrderId(orderId string) ([]domains.OrderRevision, error) {
+	models := []OrderRevisionModel{}
+	err := o.db.Where("order_info_model_id = ?", orderId).Order("revision").Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+	revisions := []domains.OrderRevision{}
+	for _, model := range models {
+		revision, err := model.toDomain()
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, *revision)
+	}
+	return revisions, nil
+}
+
+func (o *OrderRevisionRepository) Create(item *domains.OrderRevision) error {
+	model, err := newOrderRevisionModel(item)
+	if err != nil {
+		return err
+	}
+	return o.db.Create(model).Error
+}
@@ -0,0 +1,110 @@
+package query
+
+import (
+	"time"
+
+	"chico/takeout/common"
+	orderRdbms "chico/takeout/infrastructures/rdbms/order"
+	order "chico/takeout/usecase/order/query"
+
+	"gorm.io/gorm"
+)
+
+// number of orders loaded at once
+const exportBatchSize = 200
+
+type OrderExportRdbmsQueryService struct {
+	db *gorm.DB
+}
+
+func NewOrderExportRdbmsQueryService(db *gorm.DB) *OrderExportRdbmsQueryService {
+	return &OrderExportRdbmsQueryService{
+		db: db,
+	}
+}
+
+func (o *OrderExportRdbmsQueryService) FetchOrders(basis order.DateBasis, startDate, endDate time.Time, fn func(data order.ExportOrderData) error) error {
+	start := *common.GetDateUntilDay(startDate)
+	end := common.GetDateUntilDay(endDate).AddDate(0, 0, 1)
+	column := "order_date_time"
+	if basis == order.DateBasisPickup {
+		column = "pickup_date_time"
+	}
+
+	// paging by (date time, id) keeps the order of rows between batches
+	var lastDateTime *time.Time
+	lastId := ""
+	for {
+		models := []orderRdbms.OrderInfoModel{}
+		tx := o.db.Preload("OrderedStockItemModels").Preload("OrderedFoodItemModels").
+			Where(column+" >= ? and "+column+" < ?", start, end)
+		if lastDateTime != nil {
+			tx = tx.Where("("+column+", id) > (?, ?)", *lastDateTime, lastId)
+		}
+		err := tx.Order(column + ", id").Limit(exportBatchSize).Find(&models).Error
+		if err != nil {
+			return err
+		}
+		for i := range models {
+			err = fn(newExportOrderData(&models[i]))
+			if err != nil {
+				return err
+			}
+		}
+		if len(models) < exportBatchSize {
+			return nil
+		}
+		last := models[len(models)-1]
+		lastDateTime = &last.OrderDateTime
+		if basis == order.DateBasisPickup {
+			lastDateTime = &last.PickupDateTime
+		}
+		lastId = last.ID
+	}
+}
+
+func newExportOrderData(model *orderRdbms.OrderInfoModel) order.ExportOrderData {
+	items := []order.ExportOrderItemData{}
+	for _, food := range model.OrderedFoodItemModels {
+		options := []order.ExportOptionData{}
+		for _, opt := range food.Options {
+			options = append(options, order.ExportOptionData{Id: opt.OptionItemModelID, Name: opt.Name, Price: opt.Price, Quantity: orderRdbms.OptionQuantity(opt.Quantity)})
+		}
+		items = append(items, order.ExportOrderItemData{
+			ItemType: "food",
+			ItemId:   food.FoodItemModelID,
+			Name:     food.Name,
+			Price:    food.Price,
+			Quantity: food.Quantity,
+			Options:  options,
+		})
+	}
+	for _, stock := range model.OrderedStockItemModels {
+		options := []order.ExportOptionData{}
+		for _, opt := range stock.Options {
+			options = append(options, order.ExportOptionData{Id: opt.OptionItemModelID, Name: opt.Name, Price: opt.Price, Quantity: orderRdbms.OptionQuantity(opt.Quantity)})
+		}
+		items = append(items, order.ExportOrderItemData{
+			ItemType: "stock",
+			ItemId:   stock.StockItemModelID,
+			Name:     stock.Name,
+			Price:    stock.Price,
+			Quantity: stock.Quantity,
+			Options:  options,
+		})
+	}
+	return order.ExportOrderData{
+		Id:             model.ID,
+		OrderDateTime:  model.OrderDateTime.In(common.GetStoreLocation()),
+		PickupDateTime: model.PickupDateTime.In(common.GetStoreLocation()),
+		Source:         model.Source,
+		UserId:         model.UserID,
+		UserName:       model.UserName,
+		UserEmail:      model.UserEmail,
+		UserTelNo:      model.UserTelNo,
+		Memo:           model.Memo,
+		Canceled:       model.Canceled,
+		CancelReason:   model.CancelReason,
+		Items:          items,
+	}
+}
@@ -4,6 +4,9 @@ import (
 	"time"
 
 	"chico/takeout/common"
+	itemDomains "chico/takeout/domains/item"
+	orderDomains "chico/takeout/domains/order"
+	storeDomains "chico/takeout/domains/store"
 	"chico/takeout/infrastructures/rdbms/items"
 	"chico/takeout/infrastructures/rdbms/store"
 
@@ -13,24 +16,36 @@ import (
 )
 
 type OrderableInfoRdbmsQueryService struct {
-	db *gorm.DB
+	db     *gorm.DB
+	policy orderDomains.OrderTimePolicy
+	clock  common.Clock
 }
 
-func NewOrderableInfoRdbmsQueryService(db *gorm.DB) *OrderableInfoRdbmsQueryService {
+func NewOrderableInfoRdbmsQueryService(db *gorm.DB, policy orderDomains.OrderTimePolicy, clock common.Clock) *OrderableInfoRdbmsQueryService {
 	return &OrderableInfoRdbmsQueryService{
-		db: db,
+		db:     db,
+		policy: policy,
+		clock:  clock,
 	}
 }
 
 func (o *OrderableInfoRdbmsQueryService) FetchByDate(startDate, endDate time.Time) (*order.OrderableInfo, error) {
 	// get holidays
-	holidays := []store.SpecialHolidayModel{}
+	holidayModels := []store.SpecialHolidayModel{}
 	// end is need escape
-	// get period in range record
-	err := o.db.Where("start <= ? and \"end\" >= ?", endDate, startDate).Find(&holidays).Error
+	// get period in range record (yearly holiday is repeated after end)
+	err := o.db.Where("start <= ? and (\"end\" >= ? or recurrence_type = ?)", endDate, startDate, string(storeDomains.RecurrenceYearly)).Find(&holidayModels).Error
 	if err != nil {
 		return nil, err
 	}
+	holidays := []storeDomains.SpecialHoliday{}
+	for _, model := range holidayModels {
+		holiday, err := model.ToDomain()
+		if err != nil {
+			return nil, err
+		}
+		holidays = append(holidays, *holiday)
+	}
 	// get special business hour
 	specialHours := []store.SpecialBusinessHourModel{}
 	err = o.db.Preload("BusinessHourModel").Where("date <= ? and date >= ?", endDate, startDate).Find(&specialHours).Error
@@ -53,7 +68,7 @@ func (o *OrderableInfoRdbmsQueryService) FetchByDate(startDate, endDate time.Tim
 	for _, date := range dates {
 		isHoliday := false
 		for _, holiday := range holidays {
-			if common.IsInRange(*holiday.Start, *holiday.End, date) {
+			if holiday.IsHolidayAt(date) {
 				isHoliday = true
 				break
 			}
@@ -63,7 +78,13 @@ func (o *OrderableInfoRdbmsQueryService) FetchByDate(startDate, endDate time.Tim
 		}
 	}
 
-	stocks, err := o.getStockItems()
+	stocks := []items.StockItemModel{}
+	err = o.db.Find(&stocks).Error
+	if err != nil {
+		return nil, err
+	}
+	batches := []items.StockBatchModel{}
+	err = o.db.Where("available_from <= ? and expires_at >= ? and remain > 0", endDate, startDate).Find(&batches).Error
 	if err != nil {
 		return nil, err
 	}
@@ -79,6 +100,36 @@ func (o *OrderableInfoRdbmsQueryService) FetchByDate(startDate, endDate time.Tim
 		return nil, err
 	}
 
+	recipes, err := items.NewRecipeRepository(o.db).FindAll()
+	if err != nil {
+		return nil, err
+	}
+	ingredients, err := items.NewIngredientRepository(o.db).FindAll()
+	if err != nil {
+		return nil, err
+	}
+	quotas, err := items.NewFoodQuotaRepository(o.db).FindByRange(common.ConvertTimeToDateStr(startDate), common.ConvertTimeToDateStr(endDate))
+	if err != nil {
+		return nil, err
+	}
+	// loaded only when quota of business hour is set
+	hourOrders := []foodOrderPickupTimeData{}
+	for _, quota := range quotas {
+		if !quota.IsWholeDay() {
+			// hour of end date can cross midnight
+			hourOrders, err = o.getPickupTimeFoodOrder(startDate, endDate.AddDate(0, 0, 1))
+			if err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+	usages := []items.IngredientUsageModel{}
+	err = o.db.Where("date >= ? and date <= ?", startDate, endDate).Find(&usages).Error
+	if err != nil {
+		return nil, err
+	}
+
 	// then check each hour
 	infoLists := []order.PerDayOrderableInfo{}
 	for _, date := range availableDates {
@@ -87,14 +138,17 @@ func (o *OrderableInfoRdbmsQueryService) FetchByDate(startDate, endDate time.Tim
 		for _, specialHour := range specialHours {
 			// special hour
 			if common.DateEqual(date, *specialHour.Date) {
-				foodItems := o.getFoodItems(specialHour.BusinessHourModelID, date, foods)
+				start, end := common.ConvertTimeToTimeStr(*specialHour.Start), common.ConvertTimeToTimeStr(*specialHour.End)
+				foodItems := o.getFoodItems(specialHour.BusinessHourModelID, date, foods, quotas)
 				foodItems = o.reduceFoodRemain(foodItems, foodConsumption, date)
-				allItems := append(foodItems, stocks...)
+				foodItems = o.reduceHourQuotaRemain(foodItems, quotas, hourOrders, date, specialHour.BusinessHourModelID, start, end)
+				allItems := append(foodItems, o.getStockItems(date, stocks, batches)...)
+				allItems = o.reduceIngredientRemain(allItems, recipes, ingredients, usages, date)
 				info := order.PerDayOrderableInfo{
 					Date:       common.ConvertTimeToDateStr(date),
 					HourTypeId: specialHour.BusinessHourModelID,
-					StartTime:  common.ConvertTimeToTimeStr(*specialHour.Start),
-					EndTime:    common.ConvertTimeToTimeStr(*specialHour.End),
+					StartTime:  start,
+					EndTime:    end,
 					Items:      allItems,
 				}
 				infoLists = append(infoLists, info)
@@ -106,14 +160,19 @@ func (o *OrderableInfoRdbmsQueryService) FetchByDate(startDate, endDate time.Tim
 			weekday := date.Weekday()
 			for _, hour := range hours {
 				if hour.HasWeekDay(int(weekday)) {
-					foodItems := o.getFoodItems(hour.ID, date, foods)
+					// weekday specific time has priority
+					shiftStart, shiftEnd := hour.GetShift(int(weekday))
+					start, end := common.ConvertTimeToTimeStr(*shiftStart), common.ConvertTimeToTimeStr(*shiftEnd)
+					foodItems := o.getFoodItems(hour.ID, date, foods, quotas)
 					foodItems = o.reduceFoodRemain(foodItems, foodConsumption, date)
-					allItems := append(foodItems, stocks...)
+					foodItems = o.reduceHourQuotaRemain(foodItems, quotas, hourOrders, date, hour.ID, start, end)
+					allItems := append(foodItems, o.getStockItems(date, stocks, batches)...)
+					allItems = o.reduceIngredientRemain(allItems, recipes, ingredients, usages, date)
 					info := order.PerDayOrderableInfo{
 						Date:       common.ConvertTimeToDateStr(date),
 						HourTypeId: hour.ID,
-						StartTime:  common.ConvertTimeToTimeStr(*hour.Start),
-						EndTime:    common.ConvertTimeToTimeStr(*hour.End),
+						StartTime:  start,
+						EndTime:    end,
 						Items:      allItems,
 					}
 					infoLists = append(infoLists, info)
@@ -132,43 +191,22 @@ func (o *OrderableInfoRdbmsQueryService) FetchByDate(startDate, endDate time.Tim
 	return &data, nil
 }
 
+// remove hours and food items which are not orderable now by lead time
 func (o *OrderableInfoRdbmsQueryService) modifyTodayInfo(info *order.OrderableInfo, hours []store.BusinessHourModel, specialHours []store.SpecialBusinessHourModel) error {
-	// add offset minutes (120min) and round as 30 minutes (ex:10:10 => 12:30)
-	// now := common.GetRound(*common.GetNowDateWithOffset(common.OffsetMinutesOrderableBefore), 30)
-	// today := common.ConvertTimeToDateStr(now)
-	// currentTargetTime := common.ConvertTimeToTimeStr(now)
-
-	// after add offset
-	baseNow := *common.GetNowDateWithOffset(0)
+	now := o.clock.Now()
 
 	modifiedOrder := []order.PerDayOrderableInfo{}
 	for _, perDay := range info.PerDayInfo {
-		// find offset hour
-		offsetHour := o.findOffsetHour(perDay, hours, specialHours)
-		limitNow := common.GetRound(*common.GetDateWithOffset(baseNow, int(offsetHour*60)), 30) // round as 30min (ex:12:10 => 12:30)
-		limitDate := common.ConvertTimeToDateStr(limitNow)
-		limitTime := common.ConvertTimeToTimeStr(limitNow)
-
-		// if date is before from current, skip
-		isBefore, err := common.StartIsBeforeEndDateStr(perDay.Date, limitDate)
+		start, err := common.ConvertStrToDateTime(perDay.Date + " " + perDay.StartTime)
 		if err != nil {
 			return err
 		}
-		if isBefore {
+		// find offset hour
+		offsetHour := o.findOffsetHour(perDay, hours, specialHours)
+		if !o.policy.IsOrderable(now, *start, o.policy.LeadTime(offsetHour)) {
 			continue
 		}
-		// if date is today. check start and end
-		if perDay.Date == limitDate {
-			// only startTime is before current (actual is + offset) is allowed
-			isOver, err := common.StartTimeIsBeforeEndTimeStr(perDay.StartTime, limitTime, 0)
-			if err != nil {
-				return err
-			}
-			// if start is over, not target
-			if isOver {
-				continue
-			}
-		}
+		perDay.Items = o.filterLeadTimeItems(perDay.Items, now, *start, offsetHour)
 		modifiedOrder = append(modifiedOrder, perDay)
 	}
 	// update
@@ -176,6 +214,20 @@ func (o *OrderableInfoRdbmsQueryService) modifyTodayInfo(info *order.OrderableIn
 	return nil
 }
 
+// items which need longer lead time than business hour are removed
+func (o *OrderableInfoRdbmsQueryService) filterLeadTimeItems(items []order.OrderableItemInfo, now, start time.Time, offsetHour uint) []order.OrderableItemInfo {
+	if len(items) == 0 {
+		return items
+	}
+	filtered := []order.OrderableItemInfo{}
+	for _, item := range items {
+		if o.policy.IsOrderable(now, start, o.policy.LeadTime(offsetHour, item.LeadTimeHours)) {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered
+}
+
 func (o *OrderableInfoRdbmsQueryService) findOffsetHour(info order.PerDayOrderableInfo, hours []store.BusinessHourModel, specialHours []store.SpecialBusinessHourModel) uint {
 	hourId := info.HourTypeId
 	// at first checking  special
@@ -193,16 +245,12 @@ func (o *OrderableInfoRdbmsQueryService) findOffsetHour(info order.PerDayOrderab
 	return 3
 }
 
-func (o *OrderableInfoRdbmsQueryService) getStockItems() ([]order.OrderableItemInfo, error) {
-	models := []items.StockItemModel{}
-
-	err := o.db.Find(&models).Error
-	if err != nil {
-		return nil, err
-	}
+// remain of stock item tracked by batch is sum of batches usable at target date
+func (o *OrderableInfoRdbmsQueryService) getStockItems(targetDate time.Time, stocks []items.StockItemModel, batches []items.StockBatchModel) []order.OrderableItemInfo {
 	infoList := []order.OrderableItemInfo{}
+	date := common.ConvertTimeToDateStr(targetDate)
 
-	for _, item := range models {
+	for _, item := range stocks {
 		if !item.Enabled {
 			continue
 		}
@@ -210,14 +258,26 @@ func (o *OrderableInfoRdbmsQueryService) getStockItems() ([]order.OrderableItemI
 		info.Id = item.ID
 		info.ItemType = "stock"
 		info.Remain = item.Remain
+		if item.TrackByBatch {
+			info.Remain = 0
+			for _, batch := range batches {
+				if batch.StockItemModelID != item.ID {
+					continue
+				}
+				if common.ConvertTimeToDateStr(*batch.AvailableFrom) <= date && date <= common.ConvertTimeToDateStr(*batch.ExpiresAt) {
+					info.Remain += batch.Remain
+				}
+			}
+		}
 
 		infoList = append(infoList, info)
 	}
 
-	return infoList, nil
+	return infoList
 }
 
-func (o *OrderableInfoRdbmsQueryService) getFoodItems(hourTypeId string, targetDate time.Time, foods []items.FoodItemModel) []order.OrderableItemInfo {
+// quota of the whole date has priority over max order per day
+func (o *OrderableInfoRdbmsQueryService) getFoodItems(hourTypeId string, targetDate time.Time, foods []items.FoodItemModel, quotas []itemDomains.FoodQuota) []order.OrderableItemInfo {
 	infoList := []order.OrderableItemInfo{}
 
 	for _, item := range foods {
@@ -252,6 +312,13 @@ func (o *OrderableInfoRdbmsQueryService) getFoodItems(hourTypeId string, targetD
 		info.Id = item.ID
 		info.ItemType = "food"
 		info.Remain = item.MaxOrderPerDay
+		for _, quota := range quotas {
+			if quota.IsWholeDay() && quota.GetItemId() == item.ID && quota.GetDate() == common.ConvertTimeToDateStr(targetDate) {
+				info.Remain = quota.GetQuantity()
+				break
+			}
+		}
+		info.LeadTimeHours = item.LeadTimeHours
 
 		infoList = append(infoList, info)
 	}
@@ -261,8 +328,7 @@ func (o *OrderableInfoRdbmsQueryService) getFoodItems(hourTypeId string, targetD
 
 func (o *OrderableInfoRdbmsQueryService) reduceFoodRemain(items []order.OrderableItemInfo, perDayOrder []foodOrderPerDayOrderedData, date time.Time) []order.OrderableItemInfo {
 	for _, order := range perDayOrder {
-		//fmt.Println("perDayOrder:", order, date, common.DateEqual(order.PickUpDate, date));
-		if common.DateEqual(order.PickUpDate, date) {
+		if order.PickUpDate == common.ConvertTimeToDateStr(date) {
 			// fmt.Println("same date:", date);
 			for i, item := range items {
 				// reduce the remain
@@ -275,10 +341,75 @@ func (o *OrderableInfoRdbmsQueryService) reduceFoodRemain(items []order.Orderabl
 	return items
 }
 
+// remain of item with quota of the business hour is limited by the quota minus ordered in the hour.
+// quota is set on the start date of the hour the same as FoodItemRemainChecker
+func (o *OrderableInfoRdbmsQueryService) reduceHourQuotaRemain(infoList []order.OrderableItemInfo, quotas []itemDomains.FoodQuota, hourOrders []foodOrderPickupTimeData, targetDate time.Time, hourTypeId, start, end string) []order.OrderableItemInfo {
+	date := common.ConvertTimeToDateStr(targetDate)
+	nextDate := common.ConvertTimeToDateStr(targetDate.AddDate(0, 0, 1))
+	overnight := end < start
+	for i, info := range infoList {
+		for _, quota := range quotas {
+			if quota.GetHourTypeId() != hourTypeId || quota.GetItemId() != info.Id || quota.GetDate() != date {
+				continue
+			}
+			ordered := 0
+			for _, hourOrder := range hourOrders {
+				if hourOrder.Id != info.Id {
+					continue
+				}
+				pickupDate := common.ConvertTimeToDateStr(hourOrder.PickupDateTime)
+				pickup := common.ConvertTimeToTimeStr(hourOrder.PickupDateTime)
+				// hour crossing midnight continues until the end time of next date
+				inHour := pickupDate == date && start <= pickup && (overnight || pickup <= end)
+				if overnight && pickupDate == nextDate && pickup <= end {
+					inHour = true
+				}
+				if inHour {
+					ordered += hourOrder.Quantity
+				}
+			}
+			if remain := quota.GetQuantity() - ordered; remain < info.Remain {
+				infoList[i].Remain = remain
+			}
+			break
+		}
+	}
+	return infoList
+}
+
+// remain of item with recipe is limited by min over ingredients remain at target date
+func (o *OrderableInfoRdbmsQueryService) reduceIngredientRemain(infoList []order.OrderableItemInfo, recipes []itemDomains.Recipe, ingredients []itemDomains.Ingredient, usages []items.IngredientUsageModel, targetDate time.Time) []order.OrderableItemInfo {
+	if len(recipes) == 0 {
+		return infoList
+	}
+	remains := map[string]int{}
+	for _, ingredient := range ingredients {
+		remains[ingredient.GetId()] = ingredient.GetDailyQuantity()
+	}
+	for _, usage := range usages {
+		if common.DateEqual(targetDate, *usage.Date) {
+			remains[usage.IngredientModelID] -= usage.Used
+		}
+	}
+	for i, info := range infoList {
+		for _, recipe := range recipes {
+			if recipe.GetItemId() != info.Id {
+				continue
+			}
+			available := recipe.AvailableQuantity(remains)
+			if available < info.Remain {
+				infoList[i].Remain = available
+			}
+			break
+		}
+	}
+	return infoList
+}
+
 func (o *OrderableInfoRdbmsQueryService) getPerDateFoodOrder(startDate, endDate time.Time) ([]foodOrderPerDayOrderedData, error) {
 	models := []foodOrderPerDayOrderedData{}
 	o.db.Raw(`select pick_up_date, food_item_model_id as id, food_order_quantity.quantity as quantity 
-	from (select order_info.pick_up_date, food_order.food_item_model_id , SUM(food_order.quantity) as quantity from (select *, CAST(pickup_date_time AS DATE) as pick_up_date  from order_info_models where pickup_date_time >= ? and pickup_date_time  <= ? and canceled = FALSE) as order_info
+	from (select order_info.pick_up_date, food_order.food_item_model_id , SUM(food_order.quantity) as quantity from (select *, to_char(pickup_date_time, 'YYYY/MM/DD') as pick_up_date  from order_info_models where pickup_date_time >= ? and pickup_date_time  <= ? and canceled = FALSE) as order_info
 	 inner join ordered_food_item_models as food_order on order_info.id = food_order.order_info_model_id
 	 group by food_order.food_item_model_id, order_info.pick_up_date) as food_order_quantity
 	 left join food_item_models as food_models on food_order_quantity.food_item_model_id = food_models.id
@@ -287,8 +418,28 @@ func (o *OrderableInfoRdbmsQueryService) getPerDateFoodOrder(startDate, endDate
 	return models, nil
 }
 
+// quantities of active orders per pick up date time. end date is included
+func (o *OrderableInfoRdbmsQueryService) getPickupTimeFoodOrder(startDate, endDate time.Time) ([]foodOrderPickupTimeData, error) {
+	models := []foodOrderPickupTimeData{}
+	err := o.db.Raw(`select order_info_models.pickup_date_time, food_order.food_item_model_id as id, SUM(food_order.quantity) as quantity
+	from order_info_models inner join ordered_food_item_models as food_order on order_info_models.id = food_order.order_info_model_id
+	where order_info_models.pickup_date_time >= ? and order_info_models.pickup_date_time < ? and order_info_models.canceled = FALSE and order_info_models.deleted_at is null
+	group by order_info_models.pickup_date_time, food_order.food_item_model_id`, *common.GetDateUntilDay(startDate), common.GetDateUntilDay(endDate).AddDate(0, 0, 1)).Scan(&models).Error
+	if err != nil {
+		return nil, err
+	}
+	return models, nil
+}
+
+type foodOrderPickupTimeData struct {
+	PickupDateTime time.Time
+	Id             string
+	Quantity       int
+}
+
 type foodOrderPerDayOrderedData struct {
-	PickUpDate time.Time
+	// yyyy/MM/dd on store time zone (session time zone)
+	PickUpDate string
 	Id         string
 	Quantity   int
 }
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"chico/takeout/common"
+	itemDomains "chico/takeout/domains/item"
 
 	"chico/takeout/infrastructures/rdbms"
 	"chico/takeout/infrastructures/rdbms/store"
@@ -14,15 +15,9 @@ import (
 )
 
 func TestModifyTodayInfo_HasPastDate_Filtered(t *testing.T) {
-	o := OrderableInfoRdbmsQueryService{}
-
 	// mock now (2022/10/5 10:20.30)
-	common.MockNow(func() time.Time {
-		return time.Date(2022, 10, 5, 10, 20, 30, 0, time.Local)
-	})
-	t.Cleanup(func() {
-		common.ResetNow()
-	})
+	clock := common.NewFakeClock(time.Date(2022, 10, 5, 10, 20, 30, 0, common.GetStoreLocation()))
+	o := OrderableInfoRdbmsQueryService{clock: clock}
 
 	// not have today (10/5) info
 	// has past (10/3, 4) info
@@ -90,15 +85,9 @@ func TestModifyTodayInfo_HasPastDate_Filtered(t *testing.T) {
 }
 
 func TestModifyTodayInfo_OnlyFutureDates_NotFiltered(t *testing.T) {
-	o := OrderableInfoRdbmsQueryService{}
-
 	// mock now (2022/10/5 10:20.30)
-	common.MockNow(func() time.Time {
-		return time.Date(2022, 10, 5, 10, 20, 30, 0, time.Local)
-	})
-	t.Cleanup(func() {
-		common.ResetNow()
-	})
+	clock := common.NewFakeClock(time.Date(2022, 10, 5, 10, 20, 30, 0, common.GetStoreLocation()))
+	o := OrderableInfoRdbmsQueryService{clock: clock}
 
 	// not have today (10/5) info
 	// only has 10/6 ~ info
@@ -187,15 +176,9 @@ func TestModifyTodayInfo_OnlyFutureDates_NotFiltered(t *testing.T) {
 }
 
 func TestModifyTodayInfo_HasToday_AlreadyPassed(t *testing.T) {
-	o := OrderableInfoRdbmsQueryService{}
-
 	// mock now (2022/10/5 10:20.30)
-	common.MockNow(func() time.Time {
-		return time.Date(2022, 10, 5, 10, 20, 30, 0, time.Local)
-	})
-	t.Cleanup(func() {
-		common.ResetNow()
-	})
+	clock := common.NewFakeClock(time.Date(2022, 10, 5, 10, 20, 30, 0, common.GetStoreLocation()))
+	o := OrderableInfoRdbmsQueryService{clock: clock}
 
 	// have today (10/5) info
 	// but today info is already passed (now * 2 hours is over the end time)
@@ -249,15 +232,9 @@ func TestModifyTodayInfo_HasToday_AlreadyPassed(t *testing.T) {
 }
 
 func TestModifyTodayInfo_HasToday_NotPassed(t *testing.T) {
-	o := OrderableInfoRdbmsQueryService{}
-
 	// mock now (2022/10/5 10:20.30)
-	common.MockNow(func() time.Time {
-		return time.Date(2022, 10, 5, 10, 20, 30, 0, time.Local)
-	})
-	t.Cleanup(func() {
-		common.ResetNow()
-	})
+	clock := common.NewFakeClock(time.Date(2022, 10, 5, 10, 20, 30, 0, common.GetStoreLocation()))
+	o := OrderableInfoRdbmsQueryService{clock: clock}
 
 	// have today (10/5) info
 	// but today info is not started yet (now * 2 hours is before start time)
@@ -318,15 +295,9 @@ func TestModifyTodayInfo_HasToday_NotPassed(t *testing.T) {
 }
 
 func TestModifyTodayInfo_HasToday_PartiallyPassed(t *testing.T) {
-	o := OrderableInfoRdbmsQueryService{}
-
 	// mock now (2022/10/5 10:20.30)
-	common.MockNow(func() time.Time {
-		return time.Date(2022, 10, 5, 10, 20, 30, 0, time.Local)
-	})
-	t.Cleanup(func() {
-		common.ResetNow()
-	})
+	clock := common.NewFakeClock(time.Date(2022, 10, 5, 10, 20, 30, 0, common.GetStoreLocation()))
+	o := OrderableInfoRdbmsQueryService{clock: clock}
 
 	// have today (10/5) info
 	// 1 item is passed, but another is still not passed yet
@@ -394,15 +365,9 @@ func TestModifyTodayInfo_HasToday_PartiallyPassed(t *testing.T) {
 }
 
 func TestModifyTodayInfo_HasToday_AllItemsPassed(t *testing.T) {
-	o := OrderableInfoRdbmsQueryService{}
-
 	// mock now (2022/10/5 17:20.30)
-	common.MockNow(func() time.Time {
-		return time.Date(2022, 10, 5, 17, 20, 30, 0, time.Local)
-	})
-	t.Cleanup(func() {
-		common.ResetNow()
-	})
+	clock := common.NewFakeClock(time.Date(2022, 10, 5, 17, 20, 30, 0, common.GetStoreLocation()))
+	o := OrderableInfoRdbmsQueryService{clock: clock}
 
 	// have today (10/5) info
 	// both items are passed
@@ -463,18 +428,12 @@ func TestModifyTodayInfo_HasToday_AllItemsPassed(t *testing.T) {
 }
 
 func TestModifyTodayInfo_HasToday_NowDateIsChangedToNextDate(t *testing.T) {
-	o := OrderableInfoRdbmsQueryService{}
-
 	// mock now (2022/10/5 23:20.30)
 	// morning +7 hours is tomorrow 6:20 => 6:30
 	// lunch  +3 hours is tomorrow 2:20 => 2:30
 	// dinner +4 hours is tomorrow 3:20 => 3:30
-	common.MockNow(func() time.Time {
-		return time.Date(2022, 10, 5, 23, 20, 30, 0, time.Local)
-	})
-	t.Cleanup(func() {
-		common.ResetNow()
-	})
+	clock := common.NewFakeClock(time.Date(2022, 10, 5, 23, 20, 30, 0, common.GetStoreLocation()))
+	o := OrderableInfoRdbmsQueryService{clock: clock}
 
 	// have today (10/5) info
 	info := order.OrderableInfo{
@@ -597,19 +556,13 @@ func TestModifyTodayInfo_HasToday_NowDateIsChangedToNextDate(t *testing.T) {
 }
 
 func TestModifyTodayInfo_HasToday_NowDateIsChangedToNextDate_BySpecialBusinessDay(t *testing.T) {
-	o := OrderableInfoRdbmsQueryService{}
-
 	// mock now (2022/10/5 23:20.30)
 	// morning +7 hours is tomorrow 6:20 => 6:30
 	// lunch  +3 hours is tomorrow 2:20 => 2:30
 	// sp_lunch +11 hours is tomorrow 10:20 => 10:30
 	// dinner +4 hours is tomorrow 3:20 => 3:30
-	common.MockNow(func() time.Time {
-		return time.Date(2022, 10, 5, 23, 20, 30, 0, time.Local)
-	})
-	t.Cleanup(func() {
-		common.ResetNow()
-	})
+	clock := common.NewFakeClock(time.Date(2022, 10, 5, 23, 20, 30, 0, common.GetStoreLocation()))
+	o := OrderableInfoRdbmsQueryService{clock: clock}
 
 	// have today (10/5) info
 	info := order.OrderableInfo{
@@ -680,7 +633,7 @@ func TestModifyTodayInfo_HasToday_NowDateIsChangedToNextDate_BySpecialBusinessDa
 		{BaseModel: rdbms.BaseModel{ID: "2"}, Name: "lunch", OffsetHour: 3, Enabled: true},
 		{BaseModel: rdbms.BaseModel{ID: "3"}, Name: "dinner", OffsetHour: 4, Enabled: true},
 	}
-	dt := time.Date(2022, 10, 6, 0, 0, 0, 0, time.Local)
+	dt := time.Date(2022, 10, 6, 0, 0, 0, 0, common.GetStoreLocation())
 	spHours := []store.SpecialBusinessHourModel{
 		{BaseModel: rdbms.BaseModel{ID: "1234"}, Name: "sp_launch", OffsetHour: 11, Date: &dt, BusinessHourModelID: "2"},
 		{BaseModel: rdbms.BaseModel{ID: "1235"}, Name: "sp_dinner", OffsetHour: 3, Date: &dt, BusinessHourModelID: "3"},
@@ -729,15 +682,9 @@ func TestModifyTodayInfo_HasToday_NowDateIsChangedToNextDate_BySpecialBusinessDa
 }
 
 func TestModifyTodayInfo_HasToday_ModifiedStartTime(t *testing.T) {
-	o := OrderableInfoRdbmsQueryService{}
-
 	// mock now (2022/10/5 11:10.30) => +2hours is 13:10 (rounded:13:30)
-	common.MockNow(func() time.Time {
-		return time.Date(2022, 10, 5, 11, 10, 30, 0, time.Local)
-	})
-	t.Cleanup(func() {
-		common.ResetNow()
-	})
+	clock := common.NewFakeClock(time.Date(2022, 10, 5, 11, 10, 30, 0, common.GetStoreLocation()))
+	o := OrderableInfoRdbmsQueryService{clock: clock}
 
 	// have today (10/5) info
 	info := order.OrderableInfo{
@@ -833,15 +780,9 @@ func TestModifyTodayInfo_HasToday_ModifiedStartTime(t *testing.T) {
 }
 
 func TestModifyTodayInfo_HasToday_ModifiedStartTime_SameEndTime(t *testing.T) {
-	o := OrderableInfoRdbmsQueryService{}
-
 	// mock now (2022/10/5 11:10.30) => +2hours is 13:30
-	common.MockNow(func() time.Time {
-		return time.Date(2022, 10, 5, 11, 10, 30, 0, time.Local)
-	})
-	t.Cleanup(func() {
-		common.ResetNow()
-	})
+	clock := common.NewFakeClock(time.Date(2022, 10, 5, 11, 10, 30, 0, common.GetStoreLocation()))
+	o := OrderableInfoRdbmsQueryService{clock: clock}
 
 	// have today (10/5) info
 	info := order.OrderableInfo{
@@ -918,3 +859,160 @@ func AssertPerDayOrderableInfo(t *testing.T, expected, actual order.PerDayOrdera
 	assert.Nil(t, expected.Items, "This text context Items should not set without nil.")
 	assert.Nil(t, actual.Items, "This text context Items should not set without nil.")
 }
+
+// values from db are returned as process local time zone
+func TestModifyTodayInfo_OtherProcessTimeZone(t *testing.T) {
+
+	orgLocal := time.Local
+	time.Local = time.FixedZone("UTC-8", -8*60*60)
+	t.Cleanup(func() {
+		time.Local = orgLocal
+	})
+
+	// mock now (2022/10/5 23:20.30 on store time zone. 2022/10/5 06:20.30 on process time zone)
+	clock := common.NewFakeClock(time.Date(2022, 10, 5, 23, 20, 30, 0, common.GetStoreLocation()).In(time.Local))
+	o := OrderableInfoRdbmsQueryService{clock: clock}
+
+	info := order.OrderableInfo{
+		StartDate: "2022/10/03",
+		EndDate:   "2022/10/20",
+		PerDayInfo: []order.PerDayOrderableInfo{
+			{
+				Date:       "2022/10/05",
+				HourTypeId: "2",
+				StartTime:  "11:00",
+				EndTime:    "14:00",
+				Items:      nil,
+			},
+			{
+				Date:       "2022/10/06",
+				HourTypeId: "2",
+				StartTime:  "11:00",
+				EndTime:    "14:00",
+				Items:      nil,
+			},
+			{
+				Date:       "2022/10/07",
+				HourTypeId: "2",
+				StartTime:  "11:00",
+				EndTime:    "14:00",
+				Items:      nil,
+			},
+		},
+	}
+
+	hours := []store.BusinessHourModel{
+		{BaseModel: rdbms.BaseModel{ID: "2"}, Name: "lunch", OffsetHour: 3, Enabled: true},
+	}
+	// 2022/10/06 on store time zone (2022/10/05 on process time zone)
+	dt := time.Date(2022, 10, 6, 0, 0, 0, 0, common.GetStoreLocation()).In(time.Local)
+	spHours := []store.SpecialBusinessHourModel{
+		{BaseModel: rdbms.BaseModel{ID: "1234"}, Name: "sp_launch", OffsetHour: 12, Date: &dt, BusinessHourModelID: "2"},
+	}
+
+	// act
+	err := o.modifyTodayInfo(&info, hours, spHours)
+	assert.NoError(t, err, "no error should be")
+
+	// today is passed and 10/06 is over the offset of special hour
+	expected := order.OrderableInfo{
+		StartDate: "2022/10/03",
+		EndDate:   "2022/10/20",
+		PerDayInfo: []order.PerDayOrderableInfo{
+			{
+				Date:       "2022/10/07",
+				HourTypeId: "2",
+				StartTime:  "11:00",
+				EndTime:    "14:00",
+				Items:      nil,
+			},
+		},
+	}
+	AssertOrderableInfo(t, expected, info)
+}
+
+func TestModifyTodayInfo_FoodLeadTime_Filtered(t *testing.T) {
+	// mock now (2022/10/5 10:20.30)
+	clock := common.NewFakeClock(time.Date(2022, 10, 5, 10, 20, 30, 0, common.GetStoreLocation()))
+	o := OrderableInfoRdbmsQueryService{clock: clock}
+
+	info := order.OrderableInfo{
+		StartDate: "2022/10/03",
+		EndDate:   "2022/10/20",
+		PerDayInfo: []order.PerDayOrderableInfo{
+			{
+				Date:       "2022/10/06",
+				HourTypeId: "1",
+				StartTime:  "10:00",
+				EndTime:    "14:00",
+				Items: []order.OrderableItemInfo{
+					{Id: "f1", ItemType: "food", Remain: 10, LeadTimeHours: 0},
+					{Id: "f2", ItemType: "food", Remain: 10, LeadTimeHours: 24},
+					{Id: "s1", ItemType: "stock", Remain: 10},
+				},
+			},
+			{
+				Date:       "2022/10/07",
+				HourTypeId: "1",
+				StartTime:  "10:00",
+				EndTime:    "14:00",
+				Items: []order.OrderableItemInfo{
+					{Id: "f1", ItemType: "food", Remain: 10, LeadTimeHours: 0},
+					{Id: "f2", ItemType: "food", Remain: 10, LeadTimeHours: 24},
+				},
+			},
+		},
+	}
+
+	hours := []store.BusinessHourModel{
+		{BaseModel: rdbms.BaseModel{ID: "1"}, Name: "morning", OffsetHour: 3, Enabled: true},
+	}
+	spHours := []store.SpecialBusinessHourModel{}
+
+	// act
+	err := o.modifyTodayInfo(&info, hours, spHours)
+	assert.NoError(t, err, "no error should be")
+
+	// 10/06 10:00 is before now + 24h. only item which needs 24h lead time is removed
+	assert.Equal(t, 2, len(info.PerDayInfo))
+	assert.Equal(t, "2022/10/06", info.PerDayInfo[0].Date)
+	assert.Equal(t, []string{"f1", "s1"}, itemIds(info.PerDayInfo[0].Items))
+	assert.Equal(t, "2022/10/07", info.PerDayInfo[1].Date)
+	assert.Equal(t, []string{"f1", "f2"}, itemIds(info.PerDayInfo[1].Items))
+}
+
+func itemIds(items []order.OrderableItemInfo) []string {
+	ids := []string{}
+	for _, item := range items {
+		ids = append(ids, item.Id)
+	}
+	return ids
+}
+
+func TestReduceHourQuotaRemain_Overnight(t *testing.T) {
+	o := OrderableInfoRdbmsQueryService{}
+	quota, _ := itemDomains.NewFoodQuota("food1", "2051/01/11", "late", 5)
+	quotas := []itemDomains.FoodQuota{*quota}
+	pickup := func(dateTime string) time.Time {
+		target, _ := common.ConvertStrToDateTime(dateTime)
+		return *target
+	}
+	hourOrders := []foodOrderPickupTimeData{
+		{PickupDateTime: pickup("2051/01/11 21:59"), Id: "food1", Quantity: 10},
+		{PickupDateTime: pickup("2051/01/11 22:30"), Id: "food1", Quantity: 1},
+		// after midnight is in the hour started at previous date
+		{PickupDateTime: pickup("2051/01/12 00:30"), Id: "food1", Quantity: 2},
+		{PickupDateTime: pickup("2051/01/12 02:01"), Id: "food1", Quantity: 10},
+		{PickupDateTime: pickup("2051/01/11 23:00"), Id: "food2", Quantity: 10},
+	}
+	date, _ := common.ConvertStrToDate("2051/01/11")
+
+	items := o.reduceHourQuotaRemain([]order.OrderableItemInfo{{Id: "food1", Remain: 10}, {Id: "food2", Remain: 10}}, quotas, hourOrders, *date, "late", "22:00", "02:00")
+	assert.Equal(t, 2, items[0].Remain)
+	assert.Equal(t, 10, items[1].Remain)
+
+	// quota is not used for the hour started at next date
+	next, _ := common.ConvertStrToDate("2051/01/12")
+	items = o.reduceHourQuotaRemain([]order.OrderableItemInfo{{Id: "food1", Remain: 10}}, quotas, hourOrders, *next, "late", "22:00", "02:00")
+	assert.Equal(t, 10, items[0].Remain)
+}
@@ -0,0 +1,66 @@
+package ratelimit
+
+import (
+	"time"
+
+	domains "chico/takeout/domains/ratelimit"
+	"chico/takeout/infrastructures/rdbms"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TokenBucketRepository struct {
+	rdbms.BaseRepository
+}
+
+func NewTokenBucketRepository(db *gorm.DB) *TokenBucketRepository {
+	return &TokenBucketRepository{
+		BaseRepository: rdbms.BaseRepository{Db: db},
+	}
+}
+
+// shared by all instances, so the row is locked while taking a token
+type TokenBucketModel struct {
+	BucketKey  string `gorm:"primaryKey"`
+	Tokens     float64
+	RefilledAt time.Time `gorm:"index"`
+}
+
+func newTokenBucketModel(bucket *domains.TokenBucket) *TokenBucketModel {
+	return &TokenBucketModel{
+		BucketKey:  bucket.GetKey(),
+		Tokens:     bucket.GetTokens(),
+		RefilledAt: bucket.GetRefilledAt(),
+	}
+}
+
+func (t *TokenBucketModel) toDomain() *domains.TokenBucket {
+	return domains.NewTokenBucketForOrm(t.BucketKey, t.Tokens, t.RefilledAt)
+}
+
+func (t *TokenBucketRepository) Take(key string, limit domains.Limit, now time.Time) (bool, error) {
+	allowed := false
+	err := t.Db.Transaction(func(tx *gorm.DB) error {
+		// create full bucket if not exists
+		initial := newTokenBucketModel(domains.NewTokenBucket(key, limit, now))
+		err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(initial).Error
+		if err != nil {
+			return err
+		}
+
+		model := TokenBucketModel{}
+		err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&model, "bucket_key = ?", key).Error
+		if err != nil {
+			return err
+		}
+		bucket := model.toDomain()
+		allowed = bucket.Take(limit, now)
+
+		return tx.Save(newTokenBucketModel(bucket)).Error
+	})
+	if err != nil {
+		return false, err
+	}
+	return allowed, nil
+}
@@ -1,6 +1,7 @@
 package store
 
 import (
+	"fmt"
 	"time"
 
 	"chico/takeout/common"
@@ -40,6 +41,16 @@ func (b *BusinessHourModel) HasWeekDay(weekday int) bool {
 	return false
 }
 
+// return weekday specific time if exists
+func (b *BusinessHourModel) GetShift(weekday int) (*time.Time, *time.Time) {
+	for _, week := range b.Weekdays {
+		if week.Value == weekday && week.HasShift() {
+			return week.Start, week.End
+		}
+	}
+	return b.Start, b.End
+}
+
 func newBusinessHourModel(b *domains.BusinessHour) (*BusinessHourModel, error) {
 	model := BusinessHourModel{}
 	model.ID = b.GetId()
@@ -59,6 +70,21 @@ func newBusinessHourModel(b *domains.BusinessHour) (*BusinessHourModel, error) {
 	weekdays := []WeekDaysModel{}
 	for _, weekday := range b.GetWeekdays() {
 		week := WeekDaysModel{BusinessHourModelID: b.GetId(), Value: int(weekday)}
+		for _, ws := range b.GetWeekdayShifts() {
+			if ws.GetWeekday() != weekday {
+				continue
+			}
+			wStart, err := common.ConvertStrToTime(ws.GetStart())
+			if err != nil {
+				return nil, err
+			}
+			wEnd, err := common.ConvertStrToTime(ws.GetEnd())
+			if err != nil {
+				return nil, err
+			}
+			week.Start = wStart
+			week.End = wEnd
+		}
 		weekdays = append(weekdays, week)
 	}
 	model.Weekdays = weekdays
@@ -73,21 +99,40 @@ func (b *BusinessHourModel) toDomain() (*domains.BusinessHour, error) {
 	startStr := common.ConvertTimeToTimeStr(*b.Start)
 	endStr := common.ConvertTimeToTimeStr(*b.End)
 	weekdays := []domains.Weekday{}
+	shifts := []domains.WeekdayShift{}
 	for _, weekday := range b.Weekdays {
 		val := domains.Weekday(weekday.Value)
 		weekdays = append(weekdays, val)
+		if weekday.HasShift() {
+			shift, err := domains.NewWeekdayShift(val, common.ConvertTimeToTimeStr(*weekday.Start), common.ConvertTimeToTimeStr(*weekday.End))
+			if err != nil {
+				return nil, err
+			}
+			shifts = append(shifts, *shift)
+		}
 	}
 	model, err := domains.NewBusinessHourForOrm(b.ID, b.Name, startStr, endStr, weekdays, b.Enabled, b.OffsetHour)
 
+	if err != nil {
+		return nil, err
+	}
+	err = model.SetWeekdayShifts(shifts)
 	if err != nil {
 		return nil, err
 	}
 	return model, nil
 }
 
+// Start and End are set only if the weekday has specific time
 type WeekDaysModel struct {
 	BusinessHourModelID string `gorm:"primaryKey"`
 	Value               int    `gorm:"primaryKey"`
+	Start               *time.Time
+	End                 *time.Time
+}
+
+func (w *WeekDaysModel) HasShift() bool {
+	return w.Start != nil && w.End != nil
 }
 
 func (b *BusinessHoursRepository) Fetch() (*domains.BusinessHours, error) {
@@ -121,60 +166,98 @@ func (b *BusinessHoursRepository) Fetch() (*domains.BusinessHours, error) {
 }
 
 func (b *BusinessHoursRepository) Update(target *domains.BusinessHours) error {
+	models, err := newBusinessHourModels(target)
+	if err != nil {
+		return err
+	}
+	return b.db.Transaction(func(tx *gorm.DB) error {
+		return updateBusinessHourModels(tx, models)
+	})
+}
 
-	models := []BusinessHourModel{}
-	for _, schedule := range target.GetSchedules() {
-		model, err := newBusinessHourModel(&schedule)
+// food items and special business hours referring the removed schedule are updated in the same transaction
+func (b *BusinessHoursRepository) RemoveSchedule(target *domains.BusinessHours, removedId string) error {
+	models, err := newBusinessHourModels(target)
+	if err != nil {
+		return err
+	}
+	return b.db.Transaction(func(tx *gorm.DB) error {
+		// join table of FoodItemModel.BusinessHours
+		joinTable := tx.Statement.Quote(tx.NamingStrategy.JoinTableName("foodItem_businessHours"))
+		err := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE business_hour_model_id = ?", joinTable), removedId).Error
 		if err != nil {
 			return err
 		}
-		models = append(models, *model)
-	}
-
-	var gError error = nil
-	b.db.Transaction(func(tx *gorm.DB) error {
-		// at first delete all week days
-		err := b.db.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&WeekDaysModel{}).Error
+		err = tx.Where("business_hour_model_id = ?", removedId).Delete(&SpecialBusinessHourModel{}).Error
 		if err != nil {
-			gError = err
 			return err
 		}
-		for _, model := range models {
-			//err := b.db.Session(&gorm.Session{FullSaveAssociations: true}).Updates(&model).Error
-			err = b.db.Save(&model).Error
-			if err != nil {
-				gError = err
-				return err
-			}
-		}
-		return nil
+		return updateBusinessHourModels(tx, models)
 	})
-
-	return gError
 }
 
-func (b *BusinessHoursRepository) Create(target *domains.BusinessHours) error {
-
+func newBusinessHourModels(target *domains.BusinessHours) ([]BusinessHourModel, error) {
 	models := []BusinessHourModel{}
 	for _, schedule := range target.GetSchedules() {
 		model, err := newBusinessHourModel(&schedule)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		models = append(models, *model)
 	}
+	return models, nil
+}
+
+func updateBusinessHourModels(tx *gorm.DB, models []BusinessHourModel) error {
+	ids := []string{}
+	for _, model := range models {
+		ids = append(ids, model.ID)
+	}
+	// at first delete all week days
+	err := tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&WeekDaysModel{}).Error
+	if err != nil {
+		return err
+	}
+	// removed schedules are soft deleted
+	err = tx.Where("id NOT IN ?", ids).Delete(&BusinessHourModel{}).Error
+	if err != nil {
+		return err
+	}
+	for _, model := range models {
+		err = tx.Save(&model).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	var gError error = nil
-	b.db.Transaction(func(tx *gorm.DB) error {
+func (b *BusinessHoursRepository) Create(target *domains.BusinessHours) error {
+	models, err := newBusinessHourModels(target)
+	if err != nil {
+		return err
+	}
+	return b.db.Transaction(func(tx *gorm.DB) error {
 		for _, model := range models {
-			err := b.db.Create(&model).Error
+			err := tx.Create(&model).Error
 			if err != nil {
-				gError = err
 				return err
 			}
 		}
 		return nil
 	})
+}
 
-	return gError
+// remove references to deleted business hours which were left by old versions
+func CleanUpBusinessHourReferences(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		active := tx.Model(&BusinessHourModel{}).Select("id")
+		// join table of FoodItemModel.BusinessHours
+		joinTable := tx.Statement.Quote(tx.NamingStrategy.JoinTableName("foodItem_businessHours"))
+		err := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE business_hour_model_id NOT IN (?)", joinTable), active).Error
+		if err != nil {
+			return err
+		}
+		return tx.Where("business_hour_model_id NOT IN (?)", active).Delete(&SpecialBusinessHourModel{}).Error
+	})
 }
@@ -1,6 +1,10 @@
 package store
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"time"
 
 	"chico/takeout/common"
@@ -22,15 +26,53 @@ func NewSpecialHolidayRepository(db *gorm.DB) *SpecialHolidayRepository {
 
 type SpecialHolidayModel struct {
 	rdbms.BaseModel
-	Name  string
-	Start *time.Time
-	End   *time.Time
+	Name           string
+	Start          *time.Time
+	End            *time.Time
+	RecurrenceType string                `gorm:"not null;default:''"`
+	Recurrence     HolidayRecurrenceRule `gorm:"serializer:json"`
 }
 
-func (s *SpecialHolidayModel) toDomain() (*domains.SpecialHoliday, error) {
+type HolidayRecurrenceRule struct {
+	Weekdays []int
+	Nth      int
+	Month    int
+}
+
+func (h *HolidayRecurrenceRule) Scan(value interface{}) error {
+	// records before recurrence is introduced
+	if value == nil {
+		return nil
+	}
+	val, ok := value.([]byte)
+	if !ok {
+		return errors.New(fmt.Sprint("Failed to unmarshal string value:", value))
+	}
+
+	return json.Unmarshal([]byte(val), h)
+}
+
+func (h HolidayRecurrenceRule) Value() (driver.Value, error) {
+	val, err := json.Marshal(&h)
+	if err != nil {
+		return nil, err
+	}
+
+	return val, nil
+}
+
+func (s *SpecialHolidayModel) ToDomain() (*domains.SpecialHoliday, error) {
 	startStr := common.ConvertTimeToDateStr(*s.Start)
 	endStr := common.ConvertTimeToDateStr(*s.End)
-	model, err := domains.NewSpecialHolidayForOrm(s.ID, s.Name, startStr, endStr)
+	weekdays := []domains.Weekday{}
+	for _, weekday := range s.Recurrence.Weekdays {
+		weekdays = append(weekdays, domains.Weekday(weekday))
+	}
+	recurrence, err := domains.NewHolidayRecurrence(s.RecurrenceType, weekdays, s.Recurrence.Nth, s.Recurrence.Month)
+	if err != nil {
+		return nil, err
+	}
+	model, err := domains.NewSpecialHolidayForOrm(s.ID, s.Name, startStr, endStr, *recurrence)
 	if err != nil {
 		return nil, err
 	}
@@ -54,6 +96,14 @@ func newSpecialHolidayModel(s *domains.SpecialHoliday) (*SpecialHolidayModel, er
 	}
 	model.End = end
 
+	recurrence := s.GetRecurrence()
+	weekdays := []int{}
+	for _, weekday := range recurrence.GetWeekdays() {
+		weekdays = append(weekdays, int(weekday))
+	}
+	model.RecurrenceType = recurrence.GetType()
+	model.Recurrence = HolidayRecurrenceRule{Weekdays: weekdays, Nth: recurrence.GetNth(), Month: recurrence.GetMonth()}
+
 	return &model, nil
 }
 
@@ -65,7 +115,7 @@ func (s *SpecialHolidayRepository) Find(id string) (*domains.SpecialHoliday, err
 		return nil, err
 	}
 
-	dom, err := model.toDomain()
+	dom, err := model.ToDomain()
 	if err != nil {
 		return nil, err
 	}
@@ -82,7 +132,7 @@ func (s *SpecialHolidayRepository) FindAll() ([]domains.SpecialHoliday, error) {
 
 	items := []domains.SpecialHoliday{}
 	for _, model := range models {
-		item, err := model.toDomain()
+		item, err := model.ToDomain()
 		if err != nil {
 			return nil, err
 		}
@@ -20,6 +20,14 @@ func (s *SmtpSendOrderMail) SendCancel(data order.OrderCancelMailData) error {
 	return s.mailer.sendMail(data.Title, data.Message, data.SendFrom, data.Cc, data.SendTo)
 }
 
+func (s *SmtpSendOrderMail) SendChange(data order.OrderChangeMailData) error {
+	return s.mailer.sendMail(data.Title, data.Message, data.SendFrom, data.Cc, data.SendTo)
+}
+
 func (s *SmtpSendOrderMail) SendDailySummary(data order.ReservationSummaryMailData) error {
 	return s.mailer.sendMail(data.Title, data.Message, data.SendFrom, data.Cc, data.SendTo)
+}
+
+func (s *SmtpSendOrderMail) SendItemAlert(data order.ItemAlertMailData) error {
+	return s.mailer.sendMail(data.Title, data.Message, data.SendFrom, data.Cc, data.SendTo)
 }
\ No newline at end of file
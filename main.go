@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"net/http"
 	"time"
+	// embed time zone database for STORE_TIMEZONE
+	_ "time/tzdata"
 
 	"chico/takeout/common"
 	itemHandler "chico/takeout/handlers/item"
@@ -11,11 +13,18 @@ import (
 	orderHandler "chico/takeout/handlers/order"
 	storeHandler "chico/takeout/handlers/store"
 
+	idempotencyDomain "chico/takeout/domains/idempotency"
+	orderDomain "chico/takeout/domains/order"
+	rateLimitDomain "chico/takeout/domains/ratelimit"
 	"chico/takeout/infrastructures/mail"
+	"chico/takeout/infrastructures/memory"
+	"chico/takeout/infrastructures/rdbms"
 	itemRDBMS "chico/takeout/infrastructures/rdbms/items"
 	messageRDBMS "chico/takeout/infrastructures/rdbms/message"
 	orderRDBMS "chico/takeout/infrastructures/rdbms/order"
+	idempotencyRDBMS "chico/takeout/infrastructures/rdbms/idempotency"
 	orderQueryRDBMS "chico/takeout/infrastructures/rdbms/order/query"
+	rateLimitRDBMS "chico/takeout/infrastructures/rdbms/ratelimit"
 	storeRDBMS "chico/takeout/infrastructures/rdbms/store"
 
 	"chico/takeout/middleware"
@@ -45,9 +54,12 @@ func main() {
 	defer sqlDb.Close()
 
 	auth := initAuthService()
-	r := setupRouter(db, auth, cfg)
+	clock := common.NewSystemClock()
+	// shared by middleware and cleanup task, memory store has records only in the instance
+	idempotencyService := setUpIdempotencyService(db, cfg.Idempotency.Store, clock)
+	r := setupRouter(db, auth, cfg, idempotencyService, clock)
 
-	go scheduleTask(db, cfg)
+	go scheduleTask(db, cfg, idempotencyService, clock)
 
 	r.Run(":" + cfg.AppPort)
 }
@@ -69,7 +81,7 @@ func initAuthService() middleware.AuthService {
 	return service
 }
 
-func setupRouter(db *gorm.DB, auth middleware.AuthService, cfg *common.Config) *gin.Engine {
+func setupRouter(db *gorm.DB, auth middleware.AuthService, cfg *common.Config, idempotencyService *idempotencyDomain.IdempotencyService, clock common.Clock) *gin.Engine {
 	// Disable Console Color
 	// gin.DisableConsoleColor()
 	r := gin.Default()
@@ -98,6 +110,7 @@ func setupRouter(db *gorm.DB, auth middleware.AuthService, cfg *common.Config) *
 			"Accept-Encoding",
 			"Authorization",
 			"X-Requested-With",
+			middleware.IdempotencyKeyHeader,
 		},
 		// cookieなどの情報を必要とするかどうか
 		AllowCredentials: true,
@@ -145,21 +158,48 @@ func setupRouter(db *gorm.DB, auth middleware.AuthService, cfg *common.Config) *
 	}
 
 	stockRepo := itemRDBMS.NewStockItemRepository(db)
+	stockBatchRepo := itemRDBMS.NewStockBatchRepository(db)
+	stockMovementRepo := itemRDBMS.NewStockMovementRepository(db)
+	alertRepo := itemRDBMS.NewItemAlertRepository(db)
 	stock := r.Group("/item/stock")
 	{
 		stock.Use(middleware.CheckAuthInfo(auth))
-		useCase := itemUseCase.NewStockItemUseCase(stockRepo, kindRepo)
+		useCase := itemUseCase.NewStockItemUseCase(stockRepo, kindRepo, stockBatchRepo, stockMovementRepo, alertRepo, clock)
 		handler := itemHandler.NewStockItemHandler(useCase)
+		stock.Use(middleware.SetContext(handler.InitContext))
 		stock.GET("/:id", handler.Get)
 		stock.GET("/", handler.GetAll)
 		stock.POST("/", middleware.CheckAdmin(), handler.Post)
 		stock.PUT("/:id", middleware.CheckAdmin(), handler.Put)
 		stock.PUT("/:id/remain", middleware.CheckAdmin(), handler.PutRemain)
 		stock.DELETE("/:id", middleware.CheckAdmin(), handler.Delete)
+
+		batchUseCase := itemUseCase.NewStockBatchUseCase(stockRepo, stockBatchRepo, stockMovementRepo, alertRepo, clock)
+		batchHandler := itemHandler.NewStockBatchHandler(batchUseCase)
+		stock.Use(middleware.SetContext(batchHandler.InitContext))
+		stock.GET("/:id/batch", middleware.CheckAdmin(), batchHandler.GetAll)
+		stock.POST("/:id/batch", middleware.CheckAdmin(), batchHandler.Post)
+		stock.PUT("/:id/batch/:batchId/waste", middleware.CheckAdmin(), batchHandler.PutWaste)
+
+		movementUseCase := itemUseCase.NewStockMovementUseCase(stockRepo, stockBatchRepo, stockMovementRepo, clock)
+		movementHandler := itemHandler.NewStockMovementHandler(movementUseCase)
+		stock.Use(middleware.SetContext(movementHandler.InitContext))
+		stock.GET("/movement/drift", middleware.CheckAdmin(), movementHandler.GetDrifts)
+		stock.GET("/:id/movement", middleware.CheckAdmin(), movementHandler.GetByStockItem)
+		stock.PUT("/:id/movement/reconcile", middleware.CheckAdmin(), movementHandler.PutReconcile)
+	}
+
+	alert := r.Group("/item/alert")
+	{
+		alert.Use(middleware.CheckAuthInfo(auth))
+		useCase := itemUseCase.NewItemAlertUseCase(alertRepo)
+		handler := itemHandler.NewItemAlertHandler(useCase)
+		alert.GET("/", middleware.CheckAdmin(), handler.GetByDate)
 	}
 
 	businessHoursRepo := storeRDBMS.NewBusinessHoursRepository(db)
 	foodRepo := itemRDBMS.NewFoodItemRepository(db)
+	foodQuotaRepo := itemRDBMS.NewFoodQuotaRepository(db)
 	// todo idのGET紐付け
 	food := r.Group("/item/food")
 	{
@@ -171,13 +211,44 @@ func setupRouter(db *gorm.DB, auth middleware.AuthService, cfg *common.Config) *
 		food.POST("/", middleware.CheckAdmin(), handler.Post)
 		food.PUT("/:id", middleware.CheckAdmin(), handler.Put)
 		food.DELETE("/:id", middleware.CheckAdmin(), handler.Delete)
+
+		quotaHandler := itemHandler.NewFoodQuotaHandler(itemUseCase.NewFoodQuotaUseCase(foodRepo, foodQuotaRepo))
+		food.GET("/:id/quota", middleware.CheckAdmin(), quotaHandler.GetAll)
+		food.PUT("/:id/quota", middleware.CheckAdmin(), quotaHandler.Put)
+		food.DELETE("/:id/quota", middleware.CheckAdmin(), quotaHandler.Delete)
+		food.PUT("/:id/quota/range", middleware.CheckAdmin(), quotaHandler.PutRange)
+		food.DELETE("/:id/quota/range", middleware.CheckAdmin(), quotaHandler.DeleteRange)
+	}
+
+	ingredientRepo := itemRDBMS.NewIngredientRepository(db)
+	recipeRepo := itemRDBMS.NewRecipeRepository(db)
+	ingredientUsageRepo := itemRDBMS.NewIngredientUsageRepository(db)
+	ingredientUseCase := itemUseCase.NewIngredientUseCase(ingredientRepo, recipeRepo, ingredientUsageRepo, stockRepo, foodRepo)
+	ingredient := r.Group("/item/ingredient")
+	{
+		ingredient.Use(middleware.CheckAuthInfo(auth))
+		handler := itemHandler.NewIngredientHandler(ingredientUseCase)
+		ingredient.GET("/remain", middleware.CheckAdmin(), handler.GetRemains)
+		ingredient.GET("/:id", middleware.CheckAdmin(), handler.Get)
+		ingredient.GET("/", middleware.CheckAdmin(), handler.GetAll)
+		ingredient.POST("/", middleware.CheckAdmin(), handler.Post)
+		ingredient.PUT("/:id", middleware.CheckAdmin(), handler.Put)
+		ingredient.DELETE("/:id", middleware.CheckAdmin(), handler.Delete)
+	}
+	recipe := r.Group("/item/recipe")
+	{
+		recipe.Use(middleware.CheckAuthInfo(auth))
+		handler := itemHandler.NewIngredientHandler(ingredientUseCase)
+		recipe.GET("/:itemId", middleware.CheckAdmin(), handler.GetRecipe)
+		recipe.PUT("/:itemId", middleware.CheckAdmin(), handler.PutRecipe)
+		recipe.DELETE("/:itemId", middleware.CheckAdmin(), handler.DeleteRecipe)
 	}
 
 	spBusinessHourRepo := storeRDBMS.NewSpecialBusinessHoursRepository(db)
 	hour := r.Group("/store/hour")
 	{
 		hour.Use(middleware.CheckAuthInfo(auth))
-		useCase := storeUseCase.NewBusinessHoursUseCase(businessHoursRepo, spBusinessHourRepo)
+		useCase := storeUseCase.NewBusinessHoursUseCase(businessHoursRepo, spBusinessHourRepo, foodRepo, clock)
 		// init
 		err := useCase.InitIfNotExists()
 		if err != nil {
@@ -185,8 +256,10 @@ func setupRouter(db *gorm.DB, auth middleware.AuthService, cfg *common.Config) *
 		}
 		handler := storeHandler.BusinessHoursHandler(useCase)
 		hour.GET("/", handler.Get)
+		hour.POST("/", middleware.CheckAdmin(), handler.Post)
 		hour.PUT("/:id", middleware.CheckAdmin(), handler.Put)
 		hour.PUT("/:id/enabled", middleware.CheckAdmin(), handler.PutEnabled)
+		hour.DELETE("/:id", middleware.CheckAdmin(), handler.Delete)
 	}
 
 	specialHour := r.Group("/store/special_hour")
@@ -202,6 +275,15 @@ func setupRouter(db *gorm.DB, auth middleware.AuthService, cfg *common.Config) *
 	}
 
 	holidayRepo := storeRDBMS.NewSpecialHolidayRepository(db)
+	calendar := r.Group("/store/calendar")
+	{
+		useCase := storeUseCase.NewStoreCalendarUseCase(businessHoursRepo, holidayRepo, spBusinessHourRepo, clock)
+		handler := storeHandler.NewStoreCalendarHandler(useCase, clock)
+		calendar.GET("/", middleware.CheckAuthInfo(auth), handler.Get)
+		// public for subscribing from calendar apps
+		r.GET("/store/calendar.ics", handler.GetIcs)
+	}
+
 	holiday := r.Group("/store/holiday")
 	{
 		holiday.Use(middleware.CheckAuthInfo(auth))
@@ -212,43 +294,108 @@ func setupRouter(db *gorm.DB, auth middleware.AuthService, cfg *common.Config) *
 		holiday.POST("/", middleware.CheckAdmin(), handler.Post)
 		holiday.PUT("/:id", middleware.CheckAdmin(), handler.Put)
 		holiday.DELETE("/:id", middleware.CheckAdmin(), handler.Delete)
+		importUseCase := storeUseCase.NewHolidayImportUseCase(holidayRepo, spBusinessHourRepo, businessHoursRepo, clock)
+		importHandler := storeHandler.NewHolidayImportHandler(importUseCase)
+		holiday.POST("/import", middleware.CheckAdmin(), importHandler.Post)
 	}
 
 	orderRepo, err := orderRDBMS.NewOrderInfoRepository(db)
 	if err != nil {
 		panic(err)
 	}
+	orderPolicy, err := orderDomain.NewOrderTimePolicy(cfg.Order.CancelCutoffMinutes)
+	if err != nil {
+		panic(err)
+	}
+	// guest checkout is disabled without secret
+	var guestSigner *orderDomain.GuestAccessTokenSigner
+	if cfg.Order.GuestTokenSecret != "" {
+		guestSigner, err = orderDomain.NewGuestAccessTokenSigner(cfg.Order.GuestTokenSecret, cfg.Order.GuestTokenValidHours)
+		if err != nil {
+			panic(err)
+		}
+	}
+	ipLimiter, userLimiter := setUpOrderRateLimiter(db, cfg.RateLimit, clock)
+	idempotency := middleware.CheckIdempotency(idempotencyService)
+	guestIdempotency := middleware.CheckGuestIdempotency(idempotencyService)
+	revisionRepo := orderRDBMS.NewOrderRevisionRepository(db)
+	limitRepo := orderRDBMS.NewOrderLimitRepository(db)
+	orderInfoUseCase := orderUseCase.NewOrderInfoUseCase(orderRepo, revisionRepo, limitRepo, stockRepo, stockBatchRepo, stockMovementRepo, alertRepo, ingredientRepo, recipeRepo, ingredientUsageRepo, foodRepo, foodQuotaRepo, kindRepo, optionItemRepos, businessHoursRepo, spBusinessHourRepo, holidayRepo, mailer, *orderPolicy, guestSigner, clock)
+	guest := r.Group("/order/guest")
+	{
+		handler := orderHandler.NewOrderInfoHandler(orderInfoUseCase)
+		guest.Use(middleware.SetContext(handler.InitContext))
+		guest.POST("/", middleware.LimitRateByIp(ipLimiter), guestIdempotency, handler.PostGuestCreate)
+		guest.GET("/:token", handler.GetByGuestToken)
+		guest.PUT("/:token", guestIdempotency, handler.PutGuestCancel)
+	}
 	order := r.Group("/order")
 	{
-		useCase := orderUseCase.NewOrderInfoUseCase(orderRepo, stockRepo, foodRepo, kindRepo, optionItemRepos, businessHoursRepo, spBusinessHourRepo, holidayRepo, mailer)
-		handler := orderHandler.NewOrderInfoHandler(useCase)
+		handler := orderHandler.NewOrderInfoHandler(orderInfoUseCase)
 
 		order.Use(middleware.CheckAuthInfo(auth))
 		order.Use(middleware.SetContext(handler.InitContext))
 		order.GET("/:id", handler.Get)
 		order.GET("/user/:userId", handler.GetByUser)
 		order.GET("/user/active/:userId", handler.GetActiveByUser)
-		order.POST("/", handler.PostCreate)
-		order.PUT("/:id", handler.PutCancel)
-		order.PUT("user/:userId/:orderId", handler.PutUpdateUserInfo)
+		order.POST("/", middleware.LimitRateByIp(ipLimiter), middleware.LimitRateByUser(userLimiter), idempotency, handler.PostCreate)
+		order.POST("/proxy", middleware.CheckAdmin(), idempotency, handler.PostProxyCreate)
+		order.PUT("/guest/link", handler.PutLinkGuest)
+		order.PUT("/:id", idempotency, handler.PutCancel)
+		order.PUT("/:id/amend", idempotency, handler.PutAmend)
+		order.GET("/:id/revisions", handler.GetRevisions)
+		order.PUT("user/:userId/:orderId", idempotency, handler.PutUpdateUserInfo)
 		order.GET("/admin_all/", middleware.CheckAdmin(), handler.GetAll)
 		order.GET("/active/:date", middleware.CheckAdmin(), handler.GetActiveByDate)
 		statistic := order.Group("/statistic")
 		{
-			qService := orderQueryRDBMS.NewOrderStatisticQueryService(db)
+			qService := orderQueryRDBMS.NewOrderExportRdbmsQueryService(db)
 			sUseCase := orderQueryUseCase.NewOrderStatisticUseCase(qService)
 			sHandler := orderHandler.NewStatisticInfoHandler(sUseCase)
 			statistic.Use(middleware.CheckAuthInfo(auth))
 			statistic.Use(middleware.CheckAdmin())
 			statistic.GET("/month", sHandler.GetMonthly)
+			aUseCase := orderQueryUseCase.NewOrderAnalyticsUseCase(qService, stockRepo, foodRepo, kindRepo, businessHoursRepo, holidayRepo, spBusinessHourRepo)
+			aHandler := orderHandler.NewAnalyticsHandler(aUseCase)
+			statistic.GET("/summary", aHandler.GetSummary)
+			statistic.GET("/item", aHandler.GetItems)
+			statistic.GET("/kind", aHandler.GetKinds)
+			statistic.GET("/option", aHandler.GetOptions)
+			statistic.GET("/daily", aHandler.GetDaily)
+			statistic.GET("/heatmap", aHandler.GetHeatmap)
+		}
+		limit := order.Group("/limit")
+		{
+			lHandler := orderHandler.NewOrderLimitHandler(orderUseCase.NewOrderLimitUseCase(limitRepo))
+			limit.GET("/", lHandler.Get)
+			limit.PUT("/", middleware.CheckAdmin(), lHandler.Put)
 		}
 	}
 
+	report := r.Group("/report")
+	{
+		report.Use(middleware.CheckAuthInfo(auth))
+		report.Use(middleware.CheckAdmin())
+		useCase := orderUseCase.NewPrepReportUseCase(orderRepo, mailer, businessHoursRepo, holidayRepo, spBusinessHourRepo)
+		handler := orderHandler.NewPrepReportHandler(useCase)
+		report.GET("/prep", handler.Get)
+		report.POST("/prep/mail", handler.PostMail)
+		exportUseCase := orderQueryUseCase.NewOrderExportUseCase(orderQueryRDBMS.NewOrderExportRdbmsQueryService(db))
+		exportHandler := orderHandler.NewExportHandler(exportUseCase)
+		report.GET("/export/orders", exportHandler.GetOrders)
+		report.GET("/export/sales", exportHandler.GetDailySales)
+		report.GET("/export/monthly", exportHandler.GetMonthly)
+		forecastUseCase := orderQueryUseCase.NewDemandForecastUseCase(orderQueryRDBMS.NewOrderExportRdbmsQueryService(db), stockRepo, stockBatchRepo, stockMovementRepo, foodRepo, foodQuotaRepo, businessHoursRepo, holidayRepo, spBusinessHourRepo, clock)
+		forecastHandler := orderHandler.NewForecastHandler(forecastUseCase)
+		report.GET("/forecast", forecastHandler.Get)
+		report.POST("/forecast/apply", forecastHandler.PostApply)
+	}
+
 	orderable := r.Group("/orderable")
 	{
 		orderable.Use(middleware.CheckAuthInfo(auth))
-		qService := orderQueryRDBMS.NewOrderableInfoRdbmsQueryService(db)
-		useCase := orderQueryUseCase.NewOrderQueryUseCase(qService)
+		qService := orderQueryRDBMS.NewOrderableInfoRdbmsQueryService(db, *orderPolicy, clock)
+		useCase := orderQueryUseCase.NewOrderQueryUseCase(qService, clock)
 		handler := orderHandler.NewOrderableInfoHandler(useCase)
 		orderable.GET("/", handler.Get)
 	}
@@ -256,7 +403,7 @@ func setupRouter(db *gorm.DB, auth middleware.AuthService, cfg *common.Config) *
 	message := r.Group("/message/store")
 	{
 		messageRepo := messageRDBMS.NewStoreMessageRepository(db)
-		useCase := messageUseCase.NewStoreMessageUseCase(messageRepo)
+		useCase := messageUseCase.NewStoreMessageUseCase(messageRepo, clock)
 		err := useCase.CreateInitialMessage()
 		if err != nil {
 			panic("failed init error")
@@ -275,8 +422,49 @@ func setupRouter(db *gorm.DB, auth middleware.AuthService, cfg *common.Config) *
 	return r
 }
 
+const (
+	StoreRdbms = "Rdbms"
+)
+
+func setUpOrderRateLimiter(db *gorm.DB, cfg common.RateLimitConfig, clock common.Clock) (*rateLimitDomain.RateLimiter, *rateLimitDomain.RateLimiter) {
+	// memory store sweeps buckets by its limit, so each limiter has own repository
+	newRepo := func() rateLimitDomain.TokenBucketRepository {
+		if cfg.Store == StoreRdbms {
+			return rateLimitRDBMS.NewTokenBucketRepository(db)
+		}
+		return memory.NewTokenBucketMemoryRepository()
+	}
+	if cfg.Store == StoreRdbms {
+		fmt.Println("use rdbms rate limit store.")
+	} else {
+		fmt.Println("use memory rate limit store.")
+	}
+	ipLimit, err := rateLimitDomain.NewLimit(cfg.IpRatePerMinute, cfg.IpBurst)
+	if err != nil {
+		panic(err)
+	}
+	userLimit, err := rateLimitDomain.NewLimit(cfg.UserRatePerMinute, cfg.UserBurst)
+	if err != nil {
+		panic(err)
+	}
+	ipLimiter := rateLimitDomain.NewRateLimiter(newRepo(), "order_ip", *ipLimit, clock)
+	userLimiter := rateLimitDomain.NewRateLimiter(newRepo(), "order_user", *userLimit, clock)
+	return ipLimiter, userLimiter
+}
+
+func setUpIdempotencyService(db *gorm.DB, store string, clock common.Clock) *idempotencyDomain.IdempotencyService {
+	if store == StoreRdbms {
+		fmt.Println("use rdbms idempotency store.")
+		return idempotencyDomain.NewIdempotencyService(idempotencyRDBMS.NewIdempotencyRecordRepository(db), clock)
+	}
+	fmt.Println("use memory idempotency store.")
+	return idempotencyDomain.NewIdempotencyService(memory.NewIdempotencyRecordMemoryRepository(), clock)
+}
+
 func setUpDb(cfg common.DbConfig) *gorm.DB {
 	dsn := "host=" + cfg.Server + " user=" + cfg.User + " password=" + cfg.Pass + " dbname=" + cfg.DbName + " port=" + cfg.Port + " sslmode=disable"
+	// date functions in sql (ex:DATE_TRUNC) work on store time zone
+	dsn += " TimeZone=" + common.GetStoreLocation().String()
 	// dsn := "host=localhost user=gorm password=gorm dbname=gorm port=9920 sslmode=disable TimeZone=Asia/Shanghai"
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
 	if err != nil {
@@ -296,7 +484,18 @@ func setUpDb(cfg common.DbConfig) *gorm.DB {
 }
 
 func migrateDb(db *gorm.DB) {
-	err := db.AutoMigrate(&itemRDBMS.ItemKindModel{})
+	// only tables of the app are converted
+	err := rdbms.MigrateTimestampToTimestamptz(db, common.GetStoreLocation().String(),
+		&itemRDBMS.ItemKindModel{}, &itemRDBMS.OptionItemModel{}, &itemRDBMS.StockItemModel{}, &itemRDBMS.StockBatchModel{},
+		&itemRDBMS.StockMovementModel{}, &itemRDBMS.ItemAlertModel{}, &itemRDBMS.IngredientModel{}, &itemRDBMS.RecipeIngredientModel{},
+		&itemRDBMS.IngredientUsageModel{}, &itemRDBMS.FoodQuotaModel{}, &itemRDBMS.FoodItemModel{},
+		&storeRDBMS.BusinessHourModel{}, &storeRDBMS.SpecialBusinessHourModel{}, &storeRDBMS.SpecialHolidayModel{}, &storeRDBMS.WeekDaysModel{},
+		&orderRDBMS.OrderedStockItemModel{}, &orderRDBMS.OrderedFoodItemModel{}, &orderRDBMS.OrderInfoModel{}, &orderRDBMS.OrderRevisionModel{},
+		&orderRDBMS.OrderLimitModel{}, &messageRDBMS.StoreMessageModel{}, &rateLimitRDBMS.TokenBucketModel{}, &idempotencyRDBMS.IdempotencyRecordModel{})
+	if err != nil {
+		panic(err.Error())
+	}
+	err = db.AutoMigrate(&itemRDBMS.ItemKindModel{})
 	if err != nil {
 		panic(err.Error())
 	}
@@ -308,6 +507,34 @@ func migrateDb(db *gorm.DB) {
 	if err != nil {
 		panic(err.Error())
 	}
+	err = db.AutoMigrate(&itemRDBMS.StockBatchModel{})
+	if err != nil {
+		panic(err.Error())
+	}
+	err = db.AutoMigrate(&itemRDBMS.StockMovementModel{})
+	if err != nil {
+		panic(err.Error())
+	}
+	err = db.AutoMigrate(&itemRDBMS.ItemAlertModel{})
+	if err != nil {
+		panic(err.Error())
+	}
+	err = db.AutoMigrate(&itemRDBMS.IngredientModel{})
+	if err != nil {
+		panic(err.Error())
+	}
+	err = db.AutoMigrate(&itemRDBMS.RecipeIngredientModel{})
+	if err != nil {
+		panic(err.Error())
+	}
+	err = db.AutoMigrate(&itemRDBMS.IngredientUsageModel{})
+	if err != nil {
+		panic(err.Error())
+	}
+	err = db.AutoMigrate(&itemRDBMS.FoodQuotaModel{})
+	if err != nil {
+		panic(err.Error())
+	}
 	err = db.AutoMigrate(&storeRDBMS.BusinessHourModel{})
 	if err != nil {
 		panic(err.Error())
@@ -328,6 +555,10 @@ func migrateDb(db *gorm.DB) {
 	if err != nil {
 		panic(err.Error())
 	}
+	err = storeRDBMS.CleanUpBusinessHourReferences(db)
+	if err != nil {
+		panic(err.Error())
+	}
 	err = db.AutoMigrate(&orderRDBMS.OrderedStockItemModel{})
 	if err != nil {
 		panic(err.Error())
@@ -349,13 +580,29 @@ func migrateDb(db *gorm.DB) {
 	if err != nil {
 		panic(err.Error())
 	}
+	err = db.AutoMigrate(&orderRDBMS.OrderRevisionModel{})
+	if err != nil {
+		panic(err.Error())
+	}
+	err = db.AutoMigrate(&orderRDBMS.OrderLimitModel{})
+	if err != nil {
+		panic(err.Error())
+	}
 	err = db.AutoMigrate(&messageRDBMS.StoreMessageModel{})
 	if err != nil {
 		panic(err.Error())
 	}
+	err = db.AutoMigrate(&rateLimitRDBMS.TokenBucketModel{})
+	if err != nil {
+		panic(err.Error())
+	}
+	err = db.AutoMigrate(&idempotencyRDBMS.IdempotencyRecordModel{})
+	if err != nil {
+		panic(err.Error())
+	}
 }
 
-func scheduleTask(db *gorm.DB, cfg *common.Config) {
+func scheduleTask(db *gorm.DB, cfg *common.Config, idempotencyService *idempotencyDomain.IdempotencyService, clock common.Clock) {
 	mailer := mail.NewSendOrderMailService(cfg.Mail)
 	orderRepo, err := orderRDBMS.NewOrderInfoRepository(db)
 	if err != nil {
@@ -373,11 +620,60 @@ func scheduleTask(db *gorm.DB, cfg *common.Config) {
 	if err != nil {
 		panic(err)
 	}
-	useCase := orderUseCase.NewOrderTaskUseCase(orderRepo, mailer, businessHoursRepo, holidayRepo, spBusinessHourRepo)
+	alertRepo := itemRDBMS.NewItemAlertRepository(db)
+	useCase := orderUseCase.NewOrderTaskUseCase(orderRepo, mailer, businessHoursRepo, holidayRepo, spBusinessHourRepo, alertRepo)
+	// alerts of previous day
+	alertDigest, err := common.NewDailySchedularTask(cfg.Order.AlertDigestTime, func() {
+		err := useCase.NotifyDailyItemAlert(clock.Now().AddDate(0, 0, -1))
+		if err != nil {
+			fmt.Printf("failed to send item alert digest.%s\n", err)
+		}
+	}, clock)
+	if err != nil {
+		panic("failed to init schedular")
+	}
+	forecastApply, err := newForecastApplyTask(db, cfg, clock)
+	if err != nil {
+		panic("failed to init schedular")
+	}
 	// 30 minutes interval
 	timer, err := common.NewTimerScheduleTask(30, func(now time.Time){
 		useCase.NotifyOrderByHour(now)
-	})
+		alertDigest.CheckAndExecTask()
+		if forecastApply != nil {
+			forecastApply.CheckAndExecTask()
+		}
+	}, clock)
+	if err != nil {
+		panic("failed to init schedular")
+	}
+	go scheduleIdempotencyCleanup(idempotencyService, clock)
+	timer.Start()
+}
+
+// nil if auto apply is disabled
+func newForecastApplyTask(db *gorm.DB, cfg *common.Config, clock common.Clock) (*common.DailySchedularTask, error) {
+	if cfg.Order.ForecastApplyTime == "" {
+		return nil, nil
+	}
+	useCase := orderQueryUseCase.NewDemandForecastUseCase(orderQueryRDBMS.NewOrderExportRdbmsQueryService(db),
+		itemRDBMS.NewStockItemRepository(db), itemRDBMS.NewStockBatchRepository(db), itemRDBMS.NewStockMovementRepository(db),
+		itemRDBMS.NewFoodItemRepository(db), itemRDBMS.NewFoodQuotaRepository(db),
+		storeRDBMS.NewBusinessHoursRepository(db), storeRDBMS.NewSpecialHolidayRepository(db), storeRDBMS.NewSpecialBusinessHoursRepository(db), clock)
+	return common.NewDailySchedularTask(cfg.Order.ForecastApplyTime, func() {
+		if _, err := useCase.ApplyForecast(); err != nil {
+			fmt.Printf("failed to apply demand forecast.%s\n", err)
+		}
+	}, clock)
+}
+
+func scheduleIdempotencyCleanup(service *idempotencyDomain.IdempotencyService, clock common.Clock) {
+	// 60 minutes interval
+	timer, err := common.NewTimerScheduleTask(60, func(now time.Time) {
+		if err := service.DeleteExpired(); err != nil {
+			fmt.Printf("failed to delete expired idempotency keys.%s\n", err)
+		}
+	}, clock)
 	if err != nil {
 		panic("failed to init schedular")
 	}
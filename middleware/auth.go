@@ -33,6 +33,7 @@ func CheckAuthInfo(auth AuthService) gin.HandlerFunc {
 		// set auth role and userId
 		setIsAdmin(c, result.IsAdmin)
 		setUserId(c, result.UserId)
+		setUserEmail(c, result.Email)
 		c.Next()
 	}
 }
@@ -71,6 +72,11 @@ func setUserId(c *gin.Context, userId string) {
 	c.Request = c.Request.WithContext(ctx)
 }
 
+func setUserEmail(c *gin.Context, email string) {
+	ctx := common.SetUserEmail(email, c.Request.Context())
+	c.Request = c.Request.WithContext(ctx)
+}
+
 func handleUnAuth(c *gin.Context) {
 	c.JSON(401, gin.H{"message": "invalid auth"})
 	c.Abort()
@@ -20,6 +20,8 @@ type AuthData struct {
 	UserId       string
 	IsAuthorized bool
 	IsAdmin      bool
+	// empty if not verified
+	Email string
 }
 
 func NewFirebaseApp() (*firebaseApp, error) {
@@ -41,6 +43,11 @@ func (app *firebaseApp) VerifyIDToken(ctx context.Context, idToken string) (*Aut
 		return &AuthData{UserId: "", IsAdmin: false, IsAuthorized: false}, err
 	}
 	result := AuthData{UserId: token.UID, IsAdmin: false, IsAuthorized: true}
+	if verified, ok := token.Claims["email_verified"].(bool); ok && verified {
+		if email, ok := token.Claims["email"].(string); ok {
+			result.Email = email
+		}
+	}
 	if role, ok := token.Claims["role"]; ok {
 		if role.(string) == "Admin" {
 			result.IsAdmin = true
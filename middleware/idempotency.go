@@ -0,0 +1,125 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"chico/takeout/common"
+	"chico/takeout/domains/idempotency"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+type responseRecorder struct {
+	gin.ResponseWriter
+	body *bytes.Buffer
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
+
+func (r *responseRecorder) WriteString(s string) (int, error) {
+	r.body.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
+
+// replay stored response if same key and same request is sent.
+// need to be used after CheckAuthInfo since key is separated per user.
+func CheckIdempotency(service *idempotency.IdempotencyService) gin.HandlerFunc {
+	return checkIdempotency(service, func(c *gin.Context) string {
+		return common.GetUserId(c.Request.Context())
+	})
+}
+
+// guest has no user id, so key is separated per guest token or per client ip
+func CheckGuestIdempotency(service *idempotency.IdempotencyService) gin.HandlerFunc {
+	return checkIdempotency(service, func(c *gin.Context) string {
+		if token := c.Param("token"); token != "" {
+			return "guest_token:" + token
+		}
+		return "guest_ip:" + c.ClientIP()
+	})
+}
+
+func checkIdempotency(service *idempotency.IdempotencyService, getScope func(c *gin.Context) string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "failed to read body"})
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewBuffer(body))
+
+		userKey := getScope(c) + ":" + key
+		requestHash := idempotency.HashBytes([]byte(c.Request.Method), []byte(c.Request.URL.Path), body)
+		record, err := service.Start(userKey, requestHash)
+		if err != nil {
+			handleIdempotencyError(c, err)
+			return
+		}
+		if record != nil {
+			c.Header(IdempotentReplayedHeader, "true")
+			c.Data(record.GetStatusCode(), record.GetContentType(), record.GetResponseBody())
+			c.Abort()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer, body: &bytes.Buffer{}}
+		c.Writer = recorder
+		// panic is recovered outside, so key is released here to allow retry
+		defer func() {
+			if r := recover(); r != nil {
+				if err := service.Release(userKey); err != nil {
+					fmt.Printf("failed to release idempotency key.%s\n", err)
+				}
+				panic(r)
+			}
+		}()
+		c.Next()
+
+		// server error is not stored to allow retry
+		status := recorder.Status()
+		if status >= http.StatusInternalServerError {
+			err = service.Release(userKey)
+		} else {
+			err = service.Finish(userKey, status, recorder.Header().Get("Content-Type"), recorder.body.Bytes())
+		}
+		if err != nil {
+			fmt.Printf("failed to store idempotency record.%s\n", err)
+		}
+	}
+}
+
+func handleIdempotencyError(c *gin.Context, err error) {
+	var vErr *common.ValidationError
+	if errors.As(err, &vErr) {
+		c.JSON(http.StatusBadRequest, gin.H{"message": vErr.Error()})
+		c.Abort()
+		return
+	}
+	var cErr *common.ConflictError
+	if errors.As(err, &cErr) {
+		c.JSON(http.StatusConflict, gin.H{"message": cErr.Error()})
+		c.Abort()
+		return
+	}
+	fmt.Printf("failed to check idempotency.%s\n", err)
+	c.JSON(http.StatusInternalServerError, gin.H{"message": "failed to check idempotency"})
+	c.Abort()
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"chico/takeout/common"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RateLimiter interface {
+	Allow(key string) (bool, error)
+}
+
+func LimitRateByIp(limiter RateLimiter) gin.HandlerFunc {
+	return limitRate(limiter, func(c *gin.Context) string {
+		return c.ClientIP()
+	})
+}
+
+// need to be used after CheckAuthInfo
+func LimitRateByUser(limiter RateLimiter) gin.HandlerFunc {
+	return limitRate(limiter, func(c *gin.Context) string {
+		return common.GetUserId(c.Request.Context())
+	})
+}
+
+// admin is not limited
+func limitRate(limiter RateLimiter, getKey func(c *gin.Context) string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if getIsAdmin(c) {
+			c.Next()
+			return
+		}
+		allowed, err := limiter.Allow(getKey(c))
+		if err != nil {
+			fmt.Printf("failed to check rate limit.%s\n", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "failed to check rate limit"})
+			c.Abort()
+			return
+		}
+		if !allowed {
+			handleTooManyRequests(c)
+			return
+		}
+		c.Next()
+	}
+}
+
+func handleTooManyRequests(c *gin.Context) {
+	c.JSON(http.StatusTooManyRequests, gin.H{"message": "too many requests"})
+	c.Abort()
+}
@@ -8,6 +8,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"chico/takeout/common"
 	domains "chico/takeout/domains/store"
 	storeHandler "chico/takeout/handlers/store"
 	"chico/takeout/infrastructures/memory"
@@ -26,15 +27,18 @@ func SetupHourRouter() *gin.Engine {
 	// hour
 	businessHourRepo := memory.NewBusinessHoursMemoryRepository()
 	spBusinessHourRepo := memory.NewSpecialBusinessHourMemoryRepository()
+	foodRepo := memory.NewFoodItemMemoryRepository()
 	hour := r.Group(hoursUrl)
 	{
 		businessHourRepo.Reset()
 		businessHoursMemory = businessHourRepo.GetMemory()
-		useCase := storeUseCase.NewBusinessHoursUseCase(businessHourRepo, spBusinessHourRepo)
+		useCase := storeUseCase.NewBusinessHoursUseCase(businessHourRepo, spBusinessHourRepo, foodRepo, common.NewSystemClock())
 		handler := storeHandler.BusinessHoursHandler(useCase)
 		hour.GET("/", handler.Get)
+		hour.POST("/", handler.Post)
 		hour.PUT("/:id", handler.Put)
 		hour.PUT("/:id/enabled", handler.PutEnabled)
+		hour.DELETE("/:id", handler.Delete)
 	}
 	return r
 }
@@ -359,3 +363,82 @@ func TestBusinessHoursHandler_PUT_ENABLED(t *testing.T) {
 		}
 	}
 }
+
+func TestBusinessHoursHandler_POST_DELETE(t *testing.T) {
+	r := SetupHourRouter()
+	spBusinessHourRepo := memory.NewSpecialBusinessHourMemoryRepository()
+
+	// add
+	jBytes, _ := json.Marshal(map[string]interface{}{"name": "night", "start": "21:30", "end": "23:00", "weekdays": []int{6}, "offsetHour": 2})
+	req, _ := http.NewRequest("POST", "/store/hour/", bytes.NewBuffer(jBytes))
+	req.Header.Add("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &response)
+	id := response["id"].(string)
+	results := getAllBusinessHour(t, r)
+	assert.Equal(t, 4, len(results))
+	AssertMaps(t, results[3], map[string]interface{}{"id": id, "name": "night", "start": "21:30", "end": "23:00", "enabled": true, "weekdays": []int{6}, "offsetHour": 2})
+
+	// overlap is not allowed
+	jBytes, _ = json.Marshal(map[string]interface{}{"name": "late", "start": "22:00", "end": "23:30", "weekdays": []int{6}, "offsetHour": 2})
+	req, _ = http.NewRequest("POST", "/store/hour/", bytes.NewBuffer(jBytes))
+	req.Header.Add("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// upcoming special business hour blocks delete
+	spHour, _ := domains.NewSpecialBusinessHour("特別夜", "2050/01/01", "21:00", "23:00", id, 2)
+	spBusinessHourRepo.Create(spHour)
+	req, _ = http.NewRequest("DELETE", "/store/hour/"+id, nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	spBusinessHourRepo.Delete(spHour.GetId())
+
+	req, _ = http.NewRequest("DELETE", "/store/hour/"+id, nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 3, len(getAllBusinessHour(t, r)))
+
+	req, _ = http.NewRequest("DELETE", "/store/hour/"+id, nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestBusinessHoursHandler_PUT_WeekdayShifts(t *testing.T) {
+	r := SetupHourRouter()
+	id := businessHoursMemory.GetSchedules()[2].GetId()
+
+	inputs := []struct {
+		name     string
+		shifts   []map[string]interface{}
+		wantCode int
+	}{
+		{name: "saturday only late night", shifts: []map[string]interface{}{{"weekday": 6, "start": "18:00", "end": "01:00"}}, wantCode: http.StatusOK},
+		{name: "overlap with sunday morning", shifts: []map[string]interface{}{{"weekday": 6, "start": "19:00", "end": "07:01"}}, wantCode: http.StatusBadRequest},
+		{name: "weekday is not in weekdays", shifts: []map[string]interface{}{{"weekday": 1, "start": "18:00", "end": "22:00"}}, wantCode: http.StatusBadRequest},
+	}
+	for _, tt := range inputs {
+		args := map[string]interface{}{"name": "dinner", "start": "18:00", "end": "21:00", "weekdays": []int{3, 6}, "offsetHour": 3, "weekdayShifts": tt.shifts}
+		jBytes, _ := json.Marshal(args)
+		req, _ := http.NewRequest("PUT", "/store/hour/"+id, bytes.NewBuffer(jBytes))
+		req.Header.Add("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, tt.wantCode, w.Code, tt.name)
+	}
+
+	// GET to confirm result
+	results := getAllBusinessHour(t, r)
+	AssertMaps(t, results[2], map[string]interface{}{"name": "dinner", "start": "18:00", "end": "21:00", "weekdays": []int{3, 6}})
+	shifts := results[2]["weekdayShifts"].([]interface{})
+	assert.Equal(t, 1, len(shifts))
+	assert.Equal(t, map[string]interface{}{"weekday": float64(6), "start": "18:00", "end": "01:00"}, shifts[0])
+}
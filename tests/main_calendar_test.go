@@ -0,0 +1,102 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"chico/takeout/common"
+	storeHandler "chico/takeout/handlers/store"
+	"chico/takeout/infrastructures/memory"
+	storeUseCase "chico/takeout/usecase/store"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+const calendarUrl = "/store/calendar"
+
+func SetupStoreCalendarRouter() *gin.Engine {
+	r := gin.Default()
+	businessHourRepo := memory.NewBusinessHoursMemoryRepository()
+	holidayRepo := memory.NewSpecialHolidayMemoryRepository()
+	holidayRepo.Reset()
+	spBusinessHourRepo := memory.NewSpecialBusinessHourMemoryRepository()
+	spBusinessHourRepo.Reset()
+	calendar := r.Group(calendarUrl)
+	{
+		clock := common.NewFakeClock(time.Date(2022, 5, 1, 10, 0, 0, 0, common.GetStoreLocation()))
+		useCase := storeUseCase.NewStoreCalendarUseCase(businessHourRepo, holidayRepo, spBusinessHourRepo, clock)
+		handler := storeHandler.NewStoreCalendarHandler(useCase, clock)
+		calendar.GET("/", handler.Get)
+		r.GET(calendarUrl+".ics", handler.GetIcs)
+	}
+	return r
+}
+
+func TestStoreCalendarHandler_GET(t *testing.T) {
+	r := SetupStoreCalendarRouter()
+
+	req, _ := http.NewRequest("GET", calendarUrl+"/?from=2022-05-04&to=2022-05-07", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, "2022/05/04", response["from"])
+	assert.Equal(t, "2022/05/07", response["to"])
+
+	days := response["days"].([]interface{})
+	assert.Equal(t, 4, len(days))
+	wants := []map[string]interface{}{
+		{"date": "2022/05/04", "isHoliday": false, "holidayName": ""},
+		{"date": "2022/05/05", "isHoliday": false, "holidayName": ""},
+		{"date": "2022/05/06", "isHoliday": true, "holidayName": "おやすみ１", "isSpecial": false, "hours": []map[string]interface{}{}},
+		{"date": "2022/05/07", "isHoliday": true, "holidayName": "おやすみ１", "isSpecial": false, "hours": []map[string]interface{}{}},
+	}
+	for i, want := range wants {
+		AssertMaps(t, days[i].(map[string]interface{}), want)
+	}
+}
+
+func TestStoreCalendarHandler_GET_BadRequest(t *testing.T) {
+	r := SetupStoreCalendarRouter()
+
+	urls := []string{
+		calendarUrl + "/?from=2022/05/04",
+		calendarUrl + "/?from=2022-05-04&to=2022-05-03",
+		calendarUrl + "/?from=2022-05-04&to=2023-05-05",
+	}
+	for _, url := range urls {
+		req, _ := http.NewRequest("GET", url, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, url)
+	}
+}
+
+func TestStoreCalendarHandler_GETIcs(t *testing.T) {
+	r := SetupStoreCalendarRouter()
+
+	req, _ := http.NewRequest("GET", calendarUrl+".ics?from=2022-05-05&to=2022-05-06", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/calendar; charset=utf-8", w.Header().Get("Content-Type"))
+
+	body := w.Body.String()
+	assert.True(t, strings.HasPrefix(body, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	assert.True(t, strings.HasSuffix(body, "END:VCALENDAR\r\n"))
+	assert.Contains(t, body, "UID:holiday-20220506@chico-takeout\r\n")
+	assert.Contains(t, body, "DTSTART;VALUE=DATE:20220506\r\nDTEND;VALUE=DATE:20220507\r\n")
+	assert.Contains(t, body, "SUMMARY:休業日(おやすみ１)\r\n")
+	// stamp is current time in UTC
+	assert.Contains(t, body, "DTSTAMP:20220501T010000Z\r\n")
+	for _, line := range strings.Split(body, "\r\n") {
+		assert.LessOrEqual(t, len(line), 75)
+	}
+}
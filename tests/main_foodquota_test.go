@@ -0,0 +1,156 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	itemHandler "chico/takeout/handlers/item"
+	"chico/takeout/infrastructures/memory"
+	itemUseCase "chico/takeout/usecase/item"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func SetupFoodQuotaRouter() *gin.Engine {
+	r := gin.Default()
+	food := r.Group("/item/food")
+	{
+		foodRepo := memory.NewFoodItemMemoryRepository()
+		foodMemoryMaps = foodRepo.GetMemory()
+		handler := itemHandler.NewFoodQuotaHandler(itemUseCase.NewFoodQuotaUseCase(foodRepo, memory.NewFoodQuotaMemoryRepository()))
+		food.GET("/:id/quota", handler.GetAll)
+		food.PUT("/:id/quota", handler.Put)
+		food.DELETE("/:id/quota", handler.Delete)
+		food.PUT("/:id/quota/range", handler.PutRange)
+		food.DELETE("/:id/quota/range", handler.DeleteRange)
+	}
+	return r
+}
+
+func TestFoodQuotaHandler(t *testing.T) {
+	r := SetupFoodQuotaRouter()
+	request := func(method, url string, body map[string]interface{}) *httptest.ResponseRecorder {
+		jBytes, _ := json.Marshal(body)
+		req, _ := http.NewRequest(method, url, bytes.NewBuffer(jBytes))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	fetch := func(url string) []map[string]interface{} {
+		w := request("GET", url+"?start=2052/01/01&end=2052/01/31", nil)
+		assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var quotas []map[string]interface{}
+		_ = json.Unmarshal(w.Body.Bytes(), &quotas)
+		return quotas
+	}
+	foodId, hourTypeId, otherHourTypeId := "", "", ""
+	for id, food := range foodMemoryMaps {
+		if food.GetName() == "food1" {
+			foodId = id
+			hourTypeId = food.GetScheduleIds()[1]
+		}
+	}
+	for _, schedule := range memory.NewBusinessHoursMemoryRepository().GetMemory().GetSchedules() {
+		if !foodMemoryMaps[foodId].HasScheduleId(schedule.GetId()) {
+			otherHourTypeId = schedule.GetId()
+		}
+	}
+	url := "/item/food/" + foodId + "/quota"
+
+	// whole day and business hour (0 means sold out)
+	w := request("PUT", url, map[string]interface{}{"date": "2052/01/10", "quantity": 30})
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = request("PUT", url, map[string]interface{}{"date": "2052/01/10", "hourTypeId": hourTypeId, "quantity": 0})
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	// replaced
+	w = request("PUT", url, map[string]interface{}{"date": "2052/01/10", "quantity": 20})
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	quotas := fetch(url)
+	assert.Equal(t, []map[string]interface{}{
+		{"date": "2052/01/10", "hourTypeId": "", "quantity": float64(20), "source": "manual"},
+		{"date": "2052/01/10", "hourTypeId": hourTypeId, "quantity": float64(0), "source": "manual"},
+	}, quotas)
+
+	// bad requests
+	w = request("PUT", url, map[string]interface{}{"date": "2052/01/10"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = request("PUT", url, map[string]interface{}{"date": "2052/01/10", "quantity": 1001})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = request("PUT", url, map[string]interface{}{"date": "2052/01/10", "hourTypeId": otherHourTypeId, "quantity": 1})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = request("PUT", "/item/food/unknown/quota", map[string]interface{}{"date": "2052/01/10", "quantity": 1})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// tuesdays of the range
+	w = request("PUT", url+"/range", map[string]interface{}{"start": "2052/01/01", "end": "2052/01/31", "weekdays": []int{2}, "quantity": 5})
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var count map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &count)
+	assert.EqualValues(t, 5, count["count"])
+	w = request("PUT", url+"/range", map[string]interface{}{"start": "2052/01/31", "end": "2052/01/01", "quantity": 5})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = request("PUT", url+"/range", map[string]interface{}{"start": "2052/01/01", "end": "2053/01/01", "quantity": 5})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = request("PUT", url+"/range", map[string]interface{}{"start": "2052/01/01", "end": "2052/01/31", "weekdays": []int{7}, "quantity": 5})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	quotas = fetch(url)
+	assert.Equal(t, 7, len(quotas))
+	for _, quota := range quotas {
+		if quota["date"] == "2052/01/16" {
+			assert.Equal(t, float64(5), quota["quantity"])
+		}
+	}
+
+	// delete
+	w = request("DELETE", url+"?date=2052/01/10&hourTypeId="+hourTypeId, nil)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = request("DELETE", url+"/range?start=2052/01/01&end=2052/01/15&weekdays=2", nil)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = request("DELETE", url+"/range?end=2052/01/15", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	quotas = fetch(url)
+	assert.Equal(t, []map[string]interface{}{
+		{"date": "2052/01/10", "hourTypeId": "", "quantity": float64(20), "source": "manual"},
+		{"date": "2052/01/16", "hourTypeId": "", "quantity": float64(5), "source": "manual"},
+		{"date": "2052/01/23", "hourTypeId": "", "quantity": float64(5), "source": "manual"},
+		{"date": "2052/01/30", "hourTypeId": "", "quantity": float64(5), "source": "manual"},
+	}, quotas)
+}
+
+func TestFoodQuotaHandler_BadDate(t *testing.T) {
+	r := SetupFoodQuotaRouter()
+	request := func(method, url string, body map[string]interface{}) *httptest.ResponseRecorder {
+		jBytes, _ := json.Marshal(body)
+		req, _ := http.NewRequest(method, url, bytes.NewBuffer(jBytes))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	foodId := ""
+	for id, food := range foodMemoryMaps {
+		if food.GetName() == "food1" {
+			foodId = id
+		}
+	}
+	url := "/item/food/" + foodId + "/quota"
+
+	// malformed or missing dates are client errors
+	w := request("GET", url, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	w = request("GET", url+"?start=2052-01-01&end=2052/01/31", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	w = request("GET", url+"?start=2052/01/01&end=bad", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	w = request("DELETE", url+"?date=bad", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	w = request("PUT", url+"/range", map[string]interface{}{"start": "2052/13/01", "end": "2052/01/31", "quantity": 5})
+	assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	w = request("DELETE", url+"/range?start=2052/01/01&end=01/15", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+}
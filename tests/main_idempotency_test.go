@@ -0,0 +1,140 @@
+package tests
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"chico/takeout/common"
+	"chico/takeout/domains/idempotency"
+	"chico/takeout/infrastructures/memory"
+	"chico/takeout/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func SetupIdempotencyRouter(count *int) *gin.Engine {
+	r := gin.Default()
+	service := idempotency.NewIdempotencyService(memory.NewIdempotencyRecordMemoryRepository(), common.NewSystemClock())
+	r.POST("/order", middleware.CheckIdempotency(service), func(c *gin.Context) {
+		*count++
+		c.JSON(http.StatusOK, gin.H{"count": *count})
+	})
+	r.POST("/error", middleware.CheckIdempotency(service), func(c *gin.Context) {
+		*count++
+		c.String(http.StatusInternalServerError, "Server Error")
+	})
+	r.POST("/panic", middleware.CheckIdempotency(service), func(c *gin.Context) {
+		*count++
+		panic("unexpected")
+	})
+	r.POST("/guest", middleware.CheckGuestIdempotency(service), func(c *gin.Context) {
+		*count++
+		c.JSON(http.StatusOK, gin.H{"count": *count})
+	})
+	r.PUT("/guest/:token", middleware.CheckGuestIdempotency(service), func(c *gin.Context) {
+		*count++
+		c.JSON(http.StatusOK, gin.H{"count": *count})
+	})
+	return r
+}
+
+func postWithKey(r *gin.Engine, url, key, body string) *httptest.ResponseRecorder {
+	return requestWithKey(r, "POST", url, key, "", body)
+}
+
+func requestWithKey(r *gin.Engine, method, url, key, remoteAddr, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, url, bytes.NewBufferString(body))
+	if remoteAddr != "" {
+		req.RemoteAddr = remoteAddr
+	}
+	if key != "" {
+		req.Header.Set(middleware.IdempotencyKeyHeader, key)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotency_Replay(t *testing.T) {
+	count := 0
+	r := SetupIdempotencyRouter(&count)
+
+	w := postWithKey(r, "/order", "key1", `{"a":1}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `{"count":1}`, w.Body.String())
+
+	// retried request returns same response without processing
+	w = postWithKey(r, "/order", "key1", `{"a":1}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `{"count":1}`, w.Body.String())
+	assert.Equal(t, "true", w.Header().Get(middleware.IdempotentReplayedHeader))
+	assert.Equal(t, 1, count)
+
+	// different payload with same key
+	w = postWithKey(r, "/order", "key1", `{"a":2}`)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, 1, count)
+
+	// no key is always processed
+	w = postWithKey(r, "/order", "", `{"a":1}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 2, count)
+}
+
+func TestIdempotency_ServerErrorIsNotStored(t *testing.T) {
+	count := 0
+	r := SetupIdempotencyRouter(&count)
+
+	w := postWithKey(r, "/error", "key1", `{"a":1}`)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	w = postWithKey(r, "/error", "key1", `{"a":1}`)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, 2, count)
+}
+
+func TestIdempotency_PanicReleasesKey(t *testing.T) {
+	count := 0
+	r := SetupIdempotencyRouter(&count)
+
+	w := postWithKey(r, "/panic", "key1", `{"a":1}`)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	// retry is processed again instead of in progress conflict
+	w = postWithKey(r, "/panic", "key1", `{"a":1}`)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, 2, count)
+}
+
+func TestIdempotency_GuestKeyIsSeparated(t *testing.T) {
+	count := 0
+	r := SetupIdempotencyRouter(&count)
+
+	w := requestWithKey(r, "POST", "/guest", "key1", "192.0.2.1:1234", `{"a":1}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `{"count":1}`, w.Body.String())
+	w = requestWithKey(r, "POST", "/guest", "key1", "192.0.2.1:1234", `{"a":1}`)
+	assert.Equal(t, `{"count":1}`, w.Body.String())
+	assert.Equal(t, "true", w.Header().Get(middleware.IdempotentReplayedHeader))
+
+	// other guest with same key and same payload is processed
+	w = requestWithKey(r, "POST", "/guest", "key1", "192.0.2.2:1234", `{"a":1}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `{"count":2}`, w.Body.String())
+	assert.Empty(t, w.Header().Get(middleware.IdempotentReplayedHeader))
+	// other guest with same key and different payload is not conflicted
+	w = requestWithKey(r, "POST", "/guest", "key1", "192.0.2.3:1234", `{"a":2}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `{"count":3}`, w.Body.String())
+
+	// separated per token
+	w = requestWithKey(r, "PUT", "/guest/token1", "key2", "192.0.2.1:1234", `{}`)
+	assert.Equal(t, `{"count":4}`, w.Body.String())
+	w = requestWithKey(r, "PUT", "/guest/token2", "key2", "192.0.2.1:1234", `{}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `{"count":5}`, w.Body.String())
+	w = requestWithKey(r, "PUT", "/guest/token1", "key2", "192.0.2.2:1234", `{}`)
+	assert.Equal(t, `{"count":4}`, w.Body.String())
+	assert.Equal(t, 5, count)
+}
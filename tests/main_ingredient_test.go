@@ -0,0 +1,110 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	idomains "chico/takeout/domains/item"
+	itemHandler "chico/takeout/handlers/item"
+	"chico/takeout/infrastructures/memory"
+	itemUseCase "chico/takeout/usecase/item"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+var ingredientUsageRepo *memory.IngredientUsageMemoryRepository
+
+func SetupIngredientRouter() *gin.Engine {
+	r := gin.Default()
+	ingredientRepo := memory.NewIngredientMemoryRepository()
+	recipeRepo := memory.NewRecipeMemoryRepository()
+	ingredientUsageRepo = memory.NewIngredientUsageMemoryRepository()
+	useCase := itemUseCase.NewIngredientUseCase(ingredientRepo, recipeRepo, ingredientUsageRepo, memory.NewStockItemMemoryRepository(), memory.NewFoodItemMemoryRepository())
+	handler := itemHandler.NewIngredientHandler(useCase)
+	ingredient := r.Group("/item/ingredient")
+	{
+		ingredient.GET("/remain", handler.GetRemains)
+		ingredient.GET("/:id", handler.Get)
+		ingredient.GET("/", handler.GetAll)
+		ingredient.POST("/", handler.Post)
+		ingredient.PUT("/:id", handler.Put)
+		ingredient.DELETE("/:id", handler.Delete)
+	}
+	recipe := r.Group("/item/recipe")
+	{
+		recipe.GET("/:itemId", handler.GetRecipe)
+		recipe.PUT("/:itemId", handler.PutRecipe)
+		recipe.DELETE("/:itemId", handler.DeleteRecipe)
+	}
+	return r
+}
+
+func TestIngredientHandler(t *testing.T) {
+	r := SetupIngredientRouter()
+	request := func(method, url string, body map[string]interface{}) *httptest.ResponseRecorder {
+		jBytes, _ := json.Marshal(body)
+		req, _ := http.NewRequest(method, url, bytes.NewBuffer(jBytes))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	w := request("POST", "/item/ingredient/", map[string]interface{}{"name": "basmati rice", "unit": "g", "dailyQuantity": -1})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = request("POST", "/item/ingredient/", map[string]interface{}{"name": "basmati rice", "unit": "g", "dailyQuantity": 1000})
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var created map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &created)
+	ingredientId := created["id"].(string)
+
+	w = request("PUT", "/item/ingredient/"+ingredientId, map[string]interface{}{"name": "basmati rice", "unit": "g", "dailyQuantity": 2000})
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = request("GET", "/item/ingredient/"+ingredientId, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var ingredient map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &ingredient)
+	assert.EqualValues(t, 2000, ingredient["dailyQuantity"])
+
+	// recipe of food item
+	foodId := ""
+	for id := range memory.NewFoodItemMemoryRepository().GetMemory() {
+		foodId = id
+		break
+	}
+	w = request("PUT", "/item/recipe/not-exists", map[string]interface{}{"ingredients": []map[string]interface{}{{"ingredientId": ingredientId, "quantity": 150}}})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = request("PUT", "/item/recipe/"+foodId, map[string]interface{}{"ingredients": []map[string]interface{}{{"ingredientId": "not-exists", "quantity": 150}}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = request("PUT", "/item/recipe/"+foodId, map[string]interface{}{"ingredients": []map[string]interface{}{{"ingredientId": ingredientId, "quantity": 150}}})
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = request("GET", "/item/recipe/"+foodId, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var recipe map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &recipe)
+	assert.Equal(t, "food", recipe["itemType"])
+	assert.Equal(t, 1, len(recipe["ingredients"].([]interface{})))
+
+	// remain of date
+	usage, _ := idomains.NewIngredientUsageForOrm(ingredientId, "2052/12/10", 300)
+	ingredientUsageRepo.Save(usage)
+	w = request("GET", "/item/ingredient/remain?date=2052/12/10", nil)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var remains []map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &remains)
+	assert.Equal(t, 1, len(remains))
+	assert.EqualValues(t, 1700, remains[0]["remain"])
+
+	// ingredient used by recipe can not be deleted
+	w = request("DELETE", "/item/ingredient/"+ingredientId, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = request("DELETE", "/item/recipe/"+foodId, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = request("DELETE", "/item/ingredient/"+ingredientId, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = request("GET", "/item/ingredient/", nil)
+	assert.Equal(t, "[]", w.Body.String())
+}
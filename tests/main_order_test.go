@@ -29,6 +29,27 @@ const (
 )
 
 var orderMemoryMaps map[string]*domains.OrderInfo
+var orderRevisionMemoryMaps map[string]*domains.OrderRevision
+var orderClock *common.FakeClock
+
+// current time of order tests unless a test sets it. memory orders are ordered on 2050/12/08 and picked up on 2050/12/10 and 2050/12/14
+func orderTestNow() time.Time {
+	return time.Date(2050, 12, 8, 12, 0, 0, 0, common.GetStoreLocation())
+}
+
+// stock item of kind with option groups (stock memory is not reset in each setup)
+var optionGroupStockId string
+var batchStockId string
+var stockBatchMemoryMaps map[string]*idomains.StockBatch
+
+// items with alerts (low stock threshold and daily quota)
+var alertStockId string
+var alertFoodId string
+var itemAlertMemoryRepo *memory.ItemAlertMemoryRepository
+
+// food items sharing one ingredient
+var ingredientFoodIds []string
+var ingredientUsageMemoryRepo *memory.IngredientUsageMemoryRepository
 
 func SetupOrderInfoRouter() *gin.Engine {
 	r := gin.Default()
@@ -65,34 +86,104 @@ func SetupOrderInfoRouter() *gin.Engine {
 	newStock2.SetRemain(3)
 	stockRepo.Create(newStock2)
 
+	// kind with option groups (option ids are initial data of memory)
+	groupKind, _ := idomains.NewItemKind("kind3", 3, []string{"1", "2", "3"})
+	spiceGroup, _ := idomains.NewOptionGroup("辛さ", []string{"1", "2"}, true, 1, 1, 1, []string{"1"})
+	toppingGroup, _ := idomains.NewOptionGroup("トッピング", []string{"3"}, false, 0, 3, 3, []string{})
+	groupKind.SetOptionGroups([]idomains.OptionGroup{*spiceGroup, *toppingGroup})
+	kindRepo.Create(groupKind)
+	newStock3, _ := idomains.NewStockItem("stock5", "item5", 6, 4, 500, groupKind.GetId(), true, "https://stock5.png")
+	newStock3.SetRemain(99)
+	stockRepo.Create(newStock3)
+	optionGroupStockId = newStock3.GetId()
+	// stock item tracked by batch, only available from 2052/12/10 to 2052/12/11
+	batchRepo := memory.NewStockBatchMemoryRepository()
+	stockBatchMemoryMaps = batchRepo.GetMemory()
+	newStock4, _ := idomains.NewStockItem("stock6", "item6", 8, 4, 600, kindIds[0], true, "")
+	newStock4.SetTrackByBatch(true)
+	stockRepo.Create(newStock4)
+	batch, _ := idomains.NewStockBatch(newStock4.GetId(), "2052/12/10", "2052/12/11", 3)
+	batchRepo.Create(batch)
+	batchStockId = newStock4.GetId()
+	newStock5, _ := idomains.NewStockItem("stock7", "item7", 6, 5, 700, kindIds[0], true, "")
+	newStock5.SetRemain(5)
+	newStock5.SetLowStockThreshold(2)
+	stockRepo.Create(newStock5)
+	alertStockId = newStock5.GetId()
+
 	foodRepo := memory.NewFoodItemMemoryRepository()
 	// foodRepo.Reset()
 	foodMemoryMaps = foodRepo.GetMemory()
 	// add new food item
 	scheduleIds1 := []string{schedule.GetSchedules()[0].GetId(), schedule.GetSchedules()[1].GetId()}
-	food1, _ := idomains.NewFoodItem("food4", "item4", 4, 10, 11, 222, kindIds[0], scheduleIds1, true, "https://food1.jpg", []string{})
+	food1, _ := idomains.NewFoodItem("food4", "item4", 4, 10, 11, 222, kindIds[0], scheduleIds1, true, "https://food1.jpg", []string{}, 0)
 	foodRepo.Create(food1)
+	food2, _ := idomains.NewFoodItem("food7", "item7", 4, 5, 5, 333, kindIds[0], scheduleIds1, true, "", []string{}, 0)
+	foodRepo.Create(food2)
+	alertFoodId = food2.GetId()
+	// rice of 500 is shared by 2 foods and 150 is used for each
+	ingredientRepo := memory.NewIngredientMemoryRepository()
+	recipeRepo := memory.NewRecipeMemoryRepository()
+	ingredientUsageMemoryRepo = memory.NewIngredientUsageMemoryRepository()
+	rice, _ := idomains.NewIngredient("rice", "g", 500)
+	ingredientRepo.Create(rice)
+	riceLine, _ := idomains.NewRecipeIngredient(rice.GetId(), 150)
+	ingredientFoodIds = []string{}
+	for _, name := range []string{"food8", "food9"} {
+		food, _ := idomains.NewFoodItem(name, "item", 4, 5, 5, 444, kindIds[0], scheduleIds1, true, "", []string{}, 0)
+		foodRepo.Create(food)
+		recipe, _ := idomains.NewRecipe(food.GetId(), "food", []idomains.RecipeIngredient{*riceLine})
+		recipeRepo.Save(recipe)
+		ingredientFoodIds = append(ingredientFoodIds, food.GetId())
+	}
 
+	memory.NewOptionItemMemoryRepository().Reset()
 	optRepos := memory.NewOptionItemMemoryRepository()
-	optRepos.Reset()
 
 	orderRepos := memory.NewOrderInfoMemoryRepository()
 	orderRepos.Reset()
 	orderMemoryMaps = orderRepos.GetMemory()
+	revisionRepo := memory.NewOrderRevisionMemoryRepository()
+	orderRevisionMemoryMaps = revisionRepo.GetMemory()
+	limitRepo := memory.NewOrderLimitMemoryRepository()
+	limitRepo.Reset()
+	// current time can be changed in each test
+	orderClock = common.NewFakeClock(orderTestNow())
+	orderPolicy, _ := domains.NewOrderTimePolicy(60)
 	order := r.Group(orderUrl)
 	{
 		mailer := memory.NewMemorySendOrderMail()
-		useCase := orderUseCase.NewOrderInfoUseCase(orderRepos, stockRepo, foodRepo, kindRepo, optRepos, businessHoursRepo, spBusinessHourRepo, holidayRepo, mailer)
+		itemAlertMemoryRepo = memory.NewItemAlertMemoryRepository()
+		guestSigner, _ := domains.NewGuestAccessTokenSigner("test-secret", 24)
+		useCase := orderUseCase.NewOrderInfoUseCase(orderRepos, revisionRepo, limitRepo, stockRepo, batchRepo, memory.NewStockMovementMemoryRepository(), itemAlertMemoryRepo, ingredientRepo, recipeRepo, ingredientUsageMemoryRepo, foodRepo, memory.NewFoodQuotaMemoryRepository(), kindRepo, optRepos, businessHoursRepo, spBusinessHourRepo, holidayRepo, mailer, *orderPolicy, guestSigner, orderClock)
 		handler := orderHandler.NewOrderInfoHandler(useCase)
+		// auth info is given by header in tests
+		order.Use(func(c *gin.Context) {
+			ctx := common.SetIsAdmin(c.GetHeader("X-Admin") == "true", c.Request.Context())
+			ctx = common.SetUserId(c.GetHeader("X-User"), ctx)
+			ctx = common.SetUserEmail(c.GetHeader("X-Email"), ctx)
+			c.Request = c.Request.WithContext(ctx)
+			c.Next()
+		})
 		order.Use(middleware.SetContext(handler.InitContext))
 		order.GET("/:id", handler.Get)
 		order.POST("/", handler.PostCreate)
+		order.POST("/proxy", handler.PostProxyCreate)
+		order.POST("/guest/", handler.PostGuestCreate)
+		order.GET("/guest/:token", handler.GetByGuestToken)
+		order.PUT("/guest/link", handler.PutLinkGuest)
+		order.PUT("/guest/:token", handler.PutGuestCancel)
 		order.PUT("/:id", handler.PutCancel)
+		order.PUT("/:id/amend", handler.PutAmend)
+		order.GET("/:id/revisions", handler.GetRevisions)
 		order.GET("/user/:userId", handler.GetByUser)
 		order.GET("/user/active/:userId", handler.GetActiveByUser)
 		order.PUT("user/:userId/:orderId", handler.PutUpdateUserInfo)
 		order.GET("/admin_all/", handler.GetAll)
 		order.GET("/active/*date", handler.GetActiveByDate)
+		limitHandler := orderHandler.NewOrderLimitHandler(orderUseCase.NewOrderLimitUseCase(limitRepo))
+		order.GET("/limit/", limitHandler.Get)
+		order.PUT("/limit/", limitHandler.Put)
 	}
 	return r
 }
@@ -222,10 +313,8 @@ func TestOrderInfoHandler_GET_ALL(t *testing.T) {
 func TestOrderInfoHandler_GetByDateNow(t *testing.T) {
 	r := SetupOrderInfoRouter()
 
-	// mock now
-	common.MockNow(func() time.Time {
-		return time.Date(2050, 12, 10, 0, 0, 0, 0, time.Local)
-	})
+	// pick up date of order1
+	orderClock.Set(orderTestNow().AddDate(0, 0, 2))
 
 	stockIds := map[string]string{}
 	for id, value := range stockMemoryMaps {
@@ -261,8 +350,6 @@ func TestOrderInfoHandler_GetByDateNow(t *testing.T) {
 		want := wants[index]
 		assertResponse(t, want, got)
 	}
-
-	common.ResetNow()
 }
 
 func TestOrderInfoHandler_GetByDate(t *testing.T) {
@@ -354,6 +441,8 @@ func TestOrderInfoHandler_GETByUser(t *testing.T) {
 
 func TestOrderInfoHandler_POST_CREATE(t *testing.T) {
 	r := SetupOrderInfoRouter()
+	// same user orders several times
+	putOrderLimit(t, r, 0, 0, 0)
 
 	stockIds := map[string]string{}
 	for id, value := range stockMemoryMaps {
@@ -415,7 +504,7 @@ func TestOrderInfoHandler_POST_CREATE(t *testing.T) {
 				{"itemId": foodIds["food1"], "quantity": 1},
 			}, // both stock and food
 		},
-		{"userId": "123", "Memo": "めも", "pickupDateTime": "2052/12/10 09:00",
+		{"userId": "123", "Memo": "めも", "pickupDateTime": "2052/12/10 11:30", // food2 is served from lunch
 			"userName":  "ユーザー123",
 			"userEmail": "userx@hoge.com", "userTelNo": "123456789",
 			"stockItems": []map[string]interface{}{
@@ -479,7 +568,7 @@ func TestOrderInfoHandler_POST_CREATE(t *testing.T) {
 				{"itemId": foodIds["food1"], "name": "food1", "price": 100, "quantity": 1, "options": []string{}},
 			},
 		},
-		{"userId": "123", "memo": "めも", "pickupDateTime": "2052/12/10 09:00",
+		{"userId": "123", "memo": "めも", "pickupDateTime": "2052/12/10 11:30",
 			"userName": "ユーザー123", "canceled": false,
 			"userEmail": "userx@hoge.com", "userTelNo": "123456789",
 			"stockItems": []map[string]interface{}{
@@ -781,9 +870,7 @@ func TestOrderInfoHandler_POST_BadRequest_PickupDateIsPast(t *testing.T) {
 	}
 
 	// mock now
-	domains.MockNow(func() time.Time {
-		return time.Date(2022, 03, 04, 12, 10, 0, 0, time.Local)
-	})
+	orderClock.Set(time.Date(2022, 03, 04, 12, 10, 0, 0, common.GetStoreLocation()))
 
 	var items = []orderInfoErrorData{
 		{name: "pickupDate is over from now(year)", args: map[string]interface{}{
@@ -838,9 +925,6 @@ func TestOrderInfoHandler_POST_BadRequest_PickupDateIsPast(t *testing.T) {
 		// check validation message
 		assert.Equal(t, true, strings.Contains(w.Body.String(), "Name:PickupDateTime"))
 	}
-
-	// back mock
-	domains.ResetNow()
 }
 
 func TestOrderInfoHandler_POST_BadRequest_FoodOrderLimits(t *testing.T) {
@@ -912,3 +996,592 @@ func TestOrderInfoHandler_POST_BadRequest_FoodOrderLimits(t *testing.T) {
 	fmt.Println("body", w.Body)
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
+
+func TestOrderInfoHandler_PUT_Cancel(t *testing.T) {
+	inputs := []struct {
+		name       string
+		user       string
+		isAdmin    bool
+		now        time.Time
+		body       string
+		wantCode   int
+		wantReason string
+	}{
+		{name: "owner before cutoff", user: "user3", now: time.Date(2050, 12, 10, 10, 59, 0, 0, common.GetStoreLocation()), body: "", wantCode: http.StatusOK, wantReason: ""},
+		{name: "owner with reason", user: "user3", now: time.Date(2050, 12, 10, 10, 0, 0, 0, common.GetStoreLocation()), body: `{"reason":"予定が変わったため"}`, wantCode: http.StatusOK, wantReason: "予定が変わったため"},
+		{name: "owner after cutoff", user: "user3", now: time.Date(2050, 12, 10, 11, 1, 0, 0, common.GetStoreLocation()), body: "", wantCode: http.StatusBadRequest},
+		{name: "not owner", user: "user4", now: time.Date(2050, 12, 10, 10, 0, 0, 0, common.GetStoreLocation()), body: "", wantCode: http.StatusBadRequest},
+		{name: "admin without reason", user: "admin", isAdmin: true, now: time.Date(2050, 12, 10, 11, 30, 0, 0, common.GetStoreLocation()), body: "", wantCode: http.StatusBadRequest},
+		{name: "admin with reason after cutoff", user: "admin", isAdmin: true, now: time.Date(2050, 12, 10, 11, 30, 0, 0, common.GetStoreLocation()), body: `{"reason":"材料が入荷できなかったため"}`, wantCode: http.StatusOK, wantReason: "材料が入荷できなかったため"},
+	}
+	for _, tt := range inputs {
+		fmt.Println("name:", tt.name)
+		r := SetupOrderInfoRouter()
+		orderClock.Set(tt.now)
+		// pick up time is 2050/12/10 12:00. cutoff is 60 minutes
+		order, _ := domains.NewOrderInfoForOrm("c1", "user3", "ユーザー3", "user3@hoge.com", "123456789", "", parseDateTime("2050/12/10 12:00"), parseDateTime("2050/12/08 12:00"), []domains.OrderStockItem{}, []domains.OrderFoodItem{}, false, "", "")
+		orderMemoryMaps[order.GetId()] = order
+
+		req, _ := http.NewRequest("PUT", orderUrl+"/"+order.GetId(), strings.NewReader(tt.body))
+		if tt.body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		req.Header.Set("X-User", tt.user)
+		if tt.isAdmin {
+			req.Header.Set("X-Admin", "true")
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, tt.wantCode, w.Code, w.Body.String())
+
+		got := orderMemoryMaps[order.GetId()]
+		assert.Equal(t, tt.wantCode == http.StatusOK, got.GetCanceled())
+		assert.Equal(t, tt.wantReason, got.GetCancelReason())
+	}
+}
+
+func TestOrderInfoHandler_PUT_Amend(t *testing.T) {
+	r := SetupOrderInfoRouter()
+
+	stockIds := map[string]string{}
+	for id, value := range stockMemoryMaps {
+		stockIds[value.GetName()] = id
+	}
+	stock1Id := stockIds["stock1"]
+	remain := stockMemoryMaps[stock1Id].GetRemain()
+
+	stockItem, _ := domains.NewOrderStockItem(stock1Id, "stock1", 100, 1, []domains.OptionItemInfo{})
+	order, _ := domains.NewOrderInfoForOrm("a1", "user3", "ユーザー3", "user3@hoge.com", "123456789", "", parseDateTime("2052/12/10 09:00"), parseDateTime("2052/12/01 12:00"), []domains.OrderStockItem{*stockItem}, []domains.OrderFoodItem{}, false, "", "")
+	orderMemoryMaps[order.GetId()] = order
+
+	body := map[string]interface{}{
+		"pickupDateTime": "2052/12/10 11:30",
+		"stockItems": []map[string]interface{}{
+			{"itemId": stock1Id, "quantity": 3, "options": []map[string]interface{}{}},
+		},
+		"foodItems": []map[string]interface{}{},
+	}
+	jsonStr, _ := json.Marshal(body)
+
+	// other user can not amend
+	req, _ := http.NewRequest("PUT", orderUrl+"/"+order.GetId()+"/amend", bytes.NewBuffer(jsonStr))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-User", "user4")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	req, _ = http.NewRequest("PUT", orderUrl+"/"+order.GetId()+"/amend", bytes.NewBuffer(jsonStr))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-User", "user3")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	// only difference is consumed
+	assert.Equal(t, remain-2, stockMemoryMaps[stock1Id].GetRemain())
+	got := orderMemoryMaps[order.GetId()]
+	assert.Equal(t, "2052/12/10 11:30", got.GetPickupDateTime())
+	assert.Equal(t, 3, got.GetStockItems()[0].GetQuantity())
+	assert.Equal(t, "2052/12/01 12:00", got.GetOrderDateTime())
+
+	// same contents is not allowed
+	req, _ = http.NewRequest("PUT", orderUrl+"/"+order.GetId()+"/amend", bytes.NewBuffer(jsonStr))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-User", "user3")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// revision keeps contents before amended
+	req, _ = http.NewRequest("GET", orderUrl+"/"+order.GetId()+"/revisions", nil)
+	req.Header.Set("X-User", "user3")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var revisions []map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &revisions)
+	assert.Equal(t, 1, len(revisions))
+	assert.Equal(t, float64(1), revisions[0]["revision"])
+	assert.Equal(t, "2052/12/10 09:00", revisions[0]["pickupDateTime"])
+	stocks := revisions[0]["stockItems"].([]interface{})
+	assert.Equal(t, float64(1), stocks[0].(map[string]interface{})["quantity"])
+}
+
+func putOrderLimit(t *testing.T, r http.Handler, maxActivePerUser, maxPerPickupDate, maxPerHourSlot int) {
+	body := fmt.Sprintf(`{"maxActivePerUser":%d,"maxPerPickupDate":%d,"maxPerHourSlot":%d}`, maxActivePerUser, maxPerPickupDate, maxPerHourSlot)
+	req, _ := http.NewRequest("PUT", orderUrl+"/limit/", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Admin", "true")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+}
+
+func TestOrderInfoHandler_POST_OrderLimit(t *testing.T) {
+	r := SetupOrderInfoRouter()
+
+	stockIds := map[string]string{}
+	for id, value := range stockMemoryMaps {
+		stockIds[value.GetName()] = id
+	}
+	post := func(pickupDateTime string) *httptest.ResponseRecorder {
+		body := map[string]interface{}{"userId": "limit1", "memo": "", "pickupDateTime": pickupDateTime,
+			"userName":  "ユーザーlimit1",
+			"userEmail": "limit1@hoge.com", "userTelNo": "123456789",
+			"stockItems": []map[string]interface{}{
+				{"itemId": stockIds["stock3"], "quantity": 1},
+			},
+			"foodItems": []map[string]interface{}{},
+		}
+		jBytes, _ := json.Marshal(body)
+		req, _ := http.NewRequest("POST", orderUrl+"/", bytes.NewBuffer(jBytes))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-User", "limit1")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// default allows only one active order
+	req, _ := http.NewRequest("GET", orderUrl+"/limit/", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var limit map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &limit)
+	assert.Equal(t, map[string]interface{}{"maxActivePerUser": float64(1), "maxPerPickupDate": float64(0), "maxPerHourSlot": float64(0)}, limit)
+
+	// tuesday morning
+	assert.Equal(t, http.StatusOK, post("2052/12/10 09:00").Code)
+	assert.Equal(t, http.StatusBadRequest, post("2052/12/11 12:00").Code)
+
+	// one order per business hour of same date
+	putOrderLimit(t, r, 3, 2, 1)
+	assert.Equal(t, http.StatusBadRequest, post("2052/12/10 08:00").Code)
+	assert.Equal(t, http.StatusOK, post("2052/12/10 12:00").Code)
+	// per pickup date is over
+	assert.Equal(t, http.StatusBadRequest, post("2052/12/10 13:00").Code)
+	assert.Equal(t, http.StatusOK, post("2052/12/11 12:00").Code)
+	// per user is over
+	w = post("2052/12/13 12:00")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "active orders per user is over limit(3).")
+
+	// out of range
+	req, _ = http.NewRequest("PUT", orderUrl+"/limit/", strings.NewReader(`{"maxActivePerUser":100,"maxPerPickupDate":0,"maxPerHourSlot":0}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Admin", "true")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestOrderInfoHandler_POST_Proxy(t *testing.T) {
+	r := SetupOrderInfoRouter()
+
+	stockIds := map[string]string{}
+	for id, value := range stockMemoryMaps {
+		stockIds[value.GetName()] = id
+	}
+	// tuesday morning. lead time of business hour is not satisfied
+	orderClock.Set(time.Date(2052, 12, 10, 8, 30, 0, 0, common.GetStoreLocation()))
+	post := func(isAdmin, ignoreLeadTime bool, source string) *httptest.ResponseRecorder {
+		body := map[string]interface{}{"source": source, "memo": "", "pickupDateTime": "2052/12/10 09:00",
+			"userName": "来店客", "userEmail": "", "userTelNo": "123456789",
+			"ignoreLeadTime": ignoreLeadTime,
+			"stockItems": []map[string]interface{}{
+				{"itemId": stockIds["stock3"], "quantity": 1},
+			},
+			"foodItems": []map[string]interface{}{},
+		}
+		jBytes, _ := json.Marshal(body)
+		req, _ := http.NewRequest("POST", orderUrl+"/proxy", bytes.NewBuffer(jBytes))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-User", "admin")
+		if isAdmin {
+			req.Header.Set("X-Admin", "true")
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusBadRequest, post(false, true, "walk_in").Code)
+	assert.Equal(t, http.StatusBadRequest, post(true, false, "walk_in").Code)
+	assert.Equal(t, http.StatusBadRequest, post(true, true, "web").Code)
+
+	w := post(true, true, "walk_in")
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var idResponse map[string]string
+	json.Unmarshal(w.Body.Bytes(), &idResponse)
+
+	req, _ := http.NewRequest("GET", orderUrl+"/"+idResponse["id"], nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, "guest", response["userId"])
+	assert.Equal(t, "", response["userEmail"])
+	assert.Equal(t, "walk_in", response["source"])
+}
+
+func TestOrderInfoHandler_Guest(t *testing.T) {
+	r := SetupOrderInfoRouter()
+	putOrderLimit(t, r, 1, 0, 0)
+
+	stockIds := map[string]string{}
+	for id, value := range stockMemoryMaps {
+		stockIds[value.GetName()] = id
+	}
+	orderClock.Set(time.Date(2052, 12, 9, 8, 0, 0, 0, common.GetStoreLocation()))
+	post := func(email, telNo string) *httptest.ResponseRecorder {
+		body := map[string]interface{}{"memo": "", "pickupDateTime": "2052/12/10 12:00",
+			"userName": "ゲスト", "userEmail": email, "userTelNo": telNo,
+			"stockItems": []map[string]interface{}{
+				{"itemId": stockIds["stock3"], "quantity": 1},
+			},
+			"foodItems": []map[string]interface{}{},
+		}
+		jBytes, _ := json.Marshal(body)
+		req, _ := http.NewRequest("POST", orderUrl+"/guest/", bytes.NewBuffer(jBytes))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	get := func(token string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", orderUrl+"/guest/"+token, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// email is required for guest
+	assert.Equal(t, http.StatusBadRequest, post("", "123456789").Code)
+	w := post("guest@example.com", "123456789")
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var created map[string]string
+	json.Unmarshal(w.Body.Bytes(), &created)
+	assert.NotEmpty(t, created["token"])
+	// same contact is limited as same user
+	assert.Equal(t, http.StatusBadRequest, post("guest@example.com", "123456789").Code)
+
+	w = get(created["token"])
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, created["id"], response["id"])
+	assert.Equal(t, "guest", response["userId"])
+
+	// tampered token
+	assert.Equal(t, http.StatusBadRequest, get(created["token"]+"x").Code)
+
+	// other guest order is canceled by token
+	w = post("other@example.com", "987654321")
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var other map[string]string
+	json.Unmarshal(w.Body.Bytes(), &other)
+	req, _ := http.NewRequest("PUT", orderUrl+"/guest/"+other["token"], strings.NewReader(`{"reason":"予定変更"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	json.Unmarshal(get(other["token"]).Body.Bytes(), &response)
+	assert.Equal(t, true, response["canceled"])
+
+	// guest order is linked to account of same verified email
+	req, _ = http.NewRequest("PUT", orderUrl+"/guest/link", nil)
+	req.Header.Set("X-User", "guestLinkUser")
+	req.Header.Set("X-Email", "Guest@example.com")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.JSONEq(t, `{"linked":1}`, w.Body.String())
+	// token access is not allowed after linked
+	assert.Equal(t, http.StatusNotFound, get(created["token"]).Code)
+	req, _ = http.NewRequest("GET", orderUrl+"/"+created["id"], nil)
+	req.Header.Set("X-User", "guestLinkUser")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, "guestLinkUser", response["userId"])
+}
+
+func TestOrderInfoHandler_POST_OptionGroup(t *testing.T) {
+	r := SetupOrderInfoRouter()
+	putOrderLimit(t, r, 0, 0, 0)
+
+	orderClock.Set(time.Date(2052, 12, 9, 8, 0, 0, 0, common.GetStoreLocation()))
+	post := func(options []map[string]interface{}) *httptest.ResponseRecorder {
+		body := map[string]interface{}{"memo": "", "pickupDateTime": "2052/12/10 12:00",
+			"userId": "optionUser", "userName": "オプション", "userEmail": "option@example.com", "userTelNo": "123456789",
+			"stockItems": []map[string]interface{}{
+				{"itemId": optionGroupStockId, "quantity": 2, "options": options},
+			},
+			"foodItems": []map[string]interface{}{},
+		}
+		jBytes, _ := json.Marshal(body)
+		req, _ := http.NewRequest("POST", orderUrl+"/", bytes.NewBuffer(jBytes))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-User", "optionUser")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	errInputs := []struct {
+		name    string
+		options []map[string]interface{}
+	}{
+		{name: "over max select", options: []map[string]interface{}{{"itemId": "1"}, {"itemId": "2"}}},
+		{name: "over quantity of option", options: []map[string]interface{}{{"itemId": "2", "quantity": 2}}},
+		{name: "over max select by quantity", options: []map[string]interface{}{{"itemId": "3", "quantity": 4}}},
+		{name: "not option of kind", options: []map[string]interface{}{{"itemId": "99"}}},
+	}
+	for _, tt := range errInputs {
+		w := post(tt.options)
+		assert.Equal(t, http.StatusBadRequest, w.Code, tt.name)
+		assert.NotContains(t, w.Body.String(), "bad parameters", tt.name)
+	}
+
+	getOptions := func(w *httptest.ResponseRecorder) []interface{} {
+		assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var idResponse map[string]string
+		json.Unmarshal(w.Body.Bytes(), &idResponse)
+		req, _ := http.NewRequest("GET", orderUrl+"/"+idResponse["id"], nil)
+		req.Header.Set("X-User", "optionUser")
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return response["stockItems"].([]interface{})[0].(map[string]interface{})["options"].([]interface{})
+	}
+	// default of required group is applied
+	options := getOptions(post([]map[string]interface{}{}))
+	assert.Len(t, options, 1)
+	assert.Equal(t, "1", options[0].(map[string]interface{})["itemId"])
+
+	options = getOptions(post([]map[string]interface{}{{"itemId": "2"}, {"itemId": "3", "quantity": 3}}))
+	assert.Len(t, options, 2)
+	assert.Equal(t, "2", options[0].(map[string]interface{})["itemId"])
+	assert.Equal(t, float64(3), options[1].(map[string]interface{})["quantity"])
+}
+
+func TestOrderInfoHandler_POST_FoodSchedule(t *testing.T) {
+	r := SetupOrderInfoRouter()
+	putOrderLimit(t, r, 0, 0, 0)
+
+	foodIds := map[string]string{}
+	for id, value := range foodMemoryMaps {
+		foodIds[value.GetName()] = id
+	}
+	orderClock.Set(time.Date(2052, 12, 9, 8, 0, 0, 0, common.GetStoreLocation()))
+	post := func(pickupDateTime string, foodNames ...string) *httptest.ResponseRecorder {
+		foods := []map[string]interface{}{}
+		for _, name := range foodNames {
+			foods = append(foods, map[string]interface{}{"itemId": foodIds[name], "quantity": 1})
+		}
+		body := map[string]interface{}{"userId": "scheduleUser", "memo": "", "pickupDateTime": pickupDateTime,
+			"userName": "スケジュール", "userEmail": "schedule@example.com", "userTelNo": "123456789",
+			"stockItems": []map[string]interface{}{},
+			"foodItems":  foods,
+		}
+		jBytes, _ := json.Marshal(body)
+		req, _ := http.NewRequest("POST", orderUrl+"/", bytes.NewBuffer(jBytes))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-User", "scheduleUser")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	// each item error is returned
+	w := post("2052/12/10 09:00", "food1", "food2", "food3")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.NotContains(t, w.Body.String(), "food1 is")
+	assert.Contains(t, w.Body.String(), "food2 is not served in the business hour of pickup time")
+	assert.Contains(t, w.Body.String(), "food3 is not served in the business hour of pickup time")
+	// food3 is served only on allow dates
+	w = post("2052/12/10 11:30", "food2", "food3")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "food3 is not served on the pickup date")
+	w = post("2052/12/10 11:30", "food1", "food2")
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+}
+
+func TestOrderInfoHandler_POST_StockBatch(t *testing.T) {
+	r := SetupOrderInfoRouter()
+	putOrderLimit(t, r, 0, 0, 0)
+
+	orderClock.Set(time.Date(2052, 12, 9, 8, 0, 0, 0, common.GetStoreLocation()))
+	post := func(pickupDateTime string, quantity int) *httptest.ResponseRecorder {
+		body := map[string]interface{}{"userId": "batchUser", "memo": "", "pickupDateTime": pickupDateTime,
+			"userName": "バッチ", "userEmail": "batch@example.com", "userTelNo": "123456789",
+			"stockItems": []map[string]interface{}{{"itemId": batchStockId, "quantity": quantity}},
+			"foodItems":  []map[string]interface{}{},
+		}
+		jBytes, _ := json.Marshal(body)
+		req, _ := http.NewRequest("POST", orderUrl+"/", bytes.NewBuffer(jBytes))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-User", "batchUser")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	batchRemain := func() int {
+		remain := 0
+		for _, batch := range stockBatchMemoryMaps {
+			if batch.GetStockItemId() == batchStockId {
+				remain += batch.GetRemain()
+			}
+		}
+		return remain
+	}
+	// batch is not usable at pickup date
+	w := post("2052/12/13 11:30", 1)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "remain count is insufficient at 2052/12/13")
+
+	w = post("2052/12/10 11:30", 2)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, 1, batchRemain())
+	var created map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &created)
+
+	// out of stock
+	w = post("2052/12/11 11:30", 2)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, 1, batchRemain())
+
+	// canceled quantity is restored to batch
+	req, _ := http.NewRequest("PUT", orderUrl+"/"+created["id"].(string), nil)
+	req.Header.Set("X-User", "batchUser")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, 3, batchRemain())
+}
+
+func TestOrderInfoHandler_POST_ItemAlert(t *testing.T) {
+	r := SetupOrderInfoRouter()
+	putOrderLimit(t, r, 0, 0, 0)
+
+	orderClock.Set(time.Date(2052, 12, 9, 8, 0, 0, 0, common.GetStoreLocation()))
+	post := func(email string, stockQuantity, foodQuantity int) *httptest.ResponseRecorder {
+		stockItems := []map[string]interface{}{}
+		if stockQuantity > 0 {
+			stockItems = append(stockItems, map[string]interface{}{"itemId": alertStockId, "quantity": stockQuantity})
+		}
+		foodItems := []map[string]interface{}{}
+		if foodQuantity > 0 {
+			foodItems = append(foodItems, map[string]interface{}{"itemId": alertFoodId, "quantity": foodQuantity})
+		}
+		body := map[string]interface{}{"userId": "alertUser", "memo": "", "pickupDateTime": "2052/12/10 09:00",
+			"userName": "アラート", "userEmail": email, "userTelNo": "123456789",
+			"stockItems": stockItems,
+			"foodItems":  foodItems,
+		}
+		jBytes, _ := json.Marshal(body)
+		req, _ := http.NewRequest("POST", orderUrl+"/", bytes.NewBuffer(jBytes))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-User", "alertUser")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	// mail config is empty in tests, alerts are checked by repository
+	alerts := func() []idomains.ItemAlert {
+		return itemAlertMemoryRepo.GetMemory()
+	}
+
+	// 5 -> 3 is over threshold
+	w := post("alert1@example.com", 2, 0)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Len(t, alerts(), 0)
+
+	// 3 -> 2 crosses threshold
+	w = post("alert2@example.com", 1, 0)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Len(t, alerts(), 1)
+	assert.Equal(t, idomains.ItemAlertLowStock, alerts()[0].GetAlertType())
+	assert.Equal(t, 2, alerts()[0].GetRemain())
+	assert.Equal(t, "2052/12/09", alerts()[0].GetTargetDate())
+
+	// same alert is not raised twice, sold out is raised
+	w = post("alert3@example.com", 2, 0)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Len(t, alerts(), 2)
+	assert.Equal(t, idomains.ItemAlertSoldOut, alerts()[1].GetAlertType())
+	assert.Equal(t, alertStockId, alerts()[1].GetItemId())
+
+	// food quota of pick up date is sold out
+	w = post("alert4@example.com", 0, 5)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Len(t, alerts(), 3)
+	assert.Equal(t, alertFoodId, alerts()[2].GetItemId())
+	assert.Equal(t, "2052/12/10", alerts()[2].GetTargetDate())
+}
+
+func TestOrderInfoHandler_POST_Ingredient(t *testing.T) {
+	r := SetupOrderInfoRouter()
+	putOrderLimit(t, r, 0, 0, 0)
+
+	orderClock.Set(time.Date(2052, 12, 9, 8, 0, 0, 0, common.GetStoreLocation()))
+	post := func(pickupDateTime, foodId string, quantity int) *httptest.ResponseRecorder {
+		body := map[string]interface{}{"userId": "riceUser", "memo": "", "pickupDateTime": pickupDateTime,
+			"userName": "ライス", "userEmail": "rice@example.com", "userTelNo": "123456789",
+			"stockItems": []map[string]interface{}{},
+			"foodItems":  []map[string]interface{}{{"itemId": foodId, "quantity": quantity}},
+		}
+		jBytes, _ := json.Marshal(body)
+		req, _ := http.NewRequest("POST", orderUrl+"/", bytes.NewBuffer(jBytes))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-User", "riceUser")
+		req.Header.Set("X-Admin", "true")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	used := func(date string) int {
+		usages, _ := ingredientUsageMemoryRepo.FindByDate(date)
+		total := 0
+		for _, usage := range usages {
+			total += usage.GetUsed()
+		}
+		return total
+	}
+
+	w := post("2052/12/10 09:00", ingredientFoodIds[0], 2)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, 300, used("2052/12/10"))
+	var created map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &created)
+
+	// other food shares the rice, quota of each food is not over
+	w = post("2052/12/10 09:00", ingredientFoodIds[1], 2)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "ingredient remain is insufficient at 2052/12/10")
+	assert.Equal(t, 300, used("2052/12/10"))
+	w = post("2052/12/10 09:00", ingredientFoodIds[1], 1)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, 450, used("2052/12/10"))
+
+	// other date has own daily quantity
+	w = post("2052/12/11 09:00", ingredientFoodIds[1], 3)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, 450, used("2052/12/11"))
+
+	// canceled ingredients are restored
+	req, _ := http.NewRequest("PUT", orderUrl+"/"+created["id"].(string), nil)
+	req.Header.Set("X-User", "riceUser")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, 150, used("2052/12/10"))
+}
+
+// fixture date time in store location
+func parseDateTime(value string) time.Time {
+	t, _ := common.ConvertStrToDateTime(value)
+	return *t
+}
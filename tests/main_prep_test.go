@@ -0,0 +1,55 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"chico/takeout/common"
+	domains "chico/takeout/domains/order"
+	orderHandler "chico/takeout/handlers/order"
+	"chico/takeout/infrastructures/memory"
+	orderUseCase "chico/takeout/usecase/order"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+const prepUrl = "/report/prep"
+
+var prepHourId string
+
+func SetupPrepRouter() *gin.Engine {
+	r := gin.Default()
+	orderRepo := memory.NewOrderInfoMemoryRepository()
+	businessHoursRepo := memory.NewBusinessHoursMemoryRepository()
+	prepHourId = businessHoursRepo.GetMemory().GetSchedules()[0].GetId()
+
+	// morning order whose memo looks like a spreadsheet formula
+	orderedAt := time.Date(2050, 12, 1, 10, 0, 0, 0, common.GetStoreLocation())
+	curry, _ := domains.NewOrderFoodItem("prep_food1", "カレー", 800, 2, []domains.OptionItemInfo{})
+	order, _ := domains.NewOrderInfo("user2", "=ユーザー2", "user2@hoge.com", "123456789", `=HYPERLINK("https://example.com")`, "2051/01/14 07:30", []domains.OrderStockItem{}, []domains.OrderFoodItem{*curry}, orderedAt)
+	orderRepo.GetMemory()[order.GetId()] = order
+
+	useCase := orderUseCase.NewPrepReportUseCase(orderRepo, memory.NewMemorySendOrderMail(), businessHoursRepo, memory.NewSpecialHolidayMemoryRepository(), memory.NewSpecialBusinessHourMemoryRepository())
+	handler := orderHandler.NewPrepReportHandler(useCase)
+	r.GET(prepUrl, handler.Get)
+	return r
+}
+
+func TestPrepReportHandler_GetCsv(t *testing.T) {
+	r := SetupPrepRouter()
+
+	req, _ := http.NewRequest("GET", prepUrl+"?date=2051/01/14&hourId="+prepHourId+"&format=csv", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "attachment; filename=prep_20510114.csv", w.Header().Get("Content-Disposition"))
+	// utf-8 bom for excel and customer text is not evaluated as formula
+	assert.True(t, strings.HasPrefix(w.Body.String(), "\xEF\xBB\xBFtype,name,option,quantity,pickupDateTime,userName,memo\n"))
+	assert.True(t, strings.Contains(w.Body.String(), "food,カレー,,2,,,\n"))
+	assert.True(t, strings.Contains(w.Body.String(), `memo,,,,2051/01/14 07:30,'=ユーザー2,"'=HYPERLINK(""https://example.com"")"`+"\n"))
+}
@@ -0,0 +1,89 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"chico/takeout/common"
+	domains "chico/takeout/domains/ratelimit"
+	"chico/takeout/infrastructures/memory"
+	"chico/takeout/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func SetupRateLimitRouter(isAdmin bool) *gin.Engine {
+	r := gin.Default()
+	limit, _ := domains.NewLimit(1, 2)
+	// tokens are not refilled while testing
+	clock := common.NewFakeClock(time.Date(2050, 12, 10, 10, 0, 0, 0, common.GetStoreLocation()))
+	ipLimiter := domains.NewRateLimiter(memory.NewTokenBucketMemoryRepository(), "ip", *limit, clock)
+	userLimiter := domains.NewRateLimiter(memory.NewTokenBucketMemoryRepository(), "user", *limit, clock)
+	r.Use(func(c *gin.Context) {
+		ctx := common.SetIsAdmin(isAdmin, c.Request.Context())
+		ctx = common.SetUserId(c.GetHeader("X-User"), ctx)
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	})
+	r.POST("/ip", middleware.LimitRateByIp(ipLimiter), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	r.POST("/user", middleware.LimitRateByUser(userLimiter), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return r
+}
+
+func TestRateLimit_ByIp(t *testing.T) {
+	r := SetupRateLimitRouter(false)
+
+	wants := []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests}
+	for _, want := range wants {
+		req, _ := http.NewRequest("POST", "/ip", nil)
+		req.RemoteAddr = "192.0.2.1:1234"
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, want, w.Code)
+	}
+
+	// other ip has own bucket
+	req, _ := http.NewRequest("POST", "/ip", nil)
+	req.RemoteAddr = "192.0.2.2:1234"
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestRateLimit_ByUser(t *testing.T) {
+	r := SetupRateLimitRouter(false)
+
+	wants := []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests}
+	for _, want := range wants {
+		req, _ := http.NewRequest("POST", "/user", nil)
+		req.Header.Set("X-User", "user1")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, want, w.Code)
+	}
+
+	req, _ := http.NewRequest("POST", "/user", nil)
+	req.Header.Set("X-User", "user2")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestRateLimit_AdminIsNotLimited(t *testing.T) {
+	r := SetupRateLimitRouter(true)
+
+	for i := 0; i < 5; i++ {
+		req, _ := http.NewRequest("POST", "/user", nil)
+		req.Header.Set("X-User", "admin")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+}
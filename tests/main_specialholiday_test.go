@@ -4,10 +4,12 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"chico/takeout/common"
 	domains "chico/takeout/domains/store"
 	storeHandler "chico/takeout/handlers/store"
 	"chico/takeout/infrastructures/memory"
@@ -38,6 +40,11 @@ func SetupSpecialHolidayRouter() *gin.Engine {
 		holiday.POST("/", handler.Post)
 		holiday.PUT("/:id", handler.Put)
 		holiday.DELETE("/:id", handler.Delete)
+		spBusinessHourRepo := memory.NewSpecialBusinessHourMemoryRepository()
+		spBusinessHourRepo.Reset()
+		importUseCase := storeUseCase.NewHolidayImportUseCase(holidayRepo, spBusinessHourRepo, businessHourRepo, common.NewSystemClock())
+		importHandler := storeHandler.NewHolidayImportHandler(importUseCase)
+		holiday.POST("/import", importHandler.Post)
 	}
 	return r
 }
@@ -378,3 +385,186 @@ func TestSpecialHolidayHandler_DELETE_NotFound(t *testing.T) {
 
 	assert.Equal(t, http.StatusNotFound, w.Code)
 }
+
+func TestSpecialHolidayHandler_POST_Recurring(t *testing.T) {
+	r := SetupSpecialHolidayRouter()
+
+	bodies := []map[string]interface{}{
+		{"name": "年末年始", "start": "2050/12/30", "end": "2051/01/03", "recurrence": map[string]interface{}{"type": "yearly"}},
+		{"name": "定休日", "start": "2050/06/01", "end": "2050/06/30", "recurrence": map[string]interface{}{"type": "weekly", "weekdays": []int{2, 3}}},
+		{"name": "第3月曜", "start": "2050/01/01", "end": "2050/12/31", "recurrence": map[string]interface{}{"type": "nthWeekday", "weekdays": []int{1}, "nth": 3}},
+	}
+	for _, body := range bodies {
+		jBytes, err := json.Marshal(body)
+		assert.NoError(t, err, "init json is failed")
+
+		req, _ := http.NewRequest("POST", holidayUrl+"/", bytes.NewBuffer(jBytes))
+		req.Header.Add("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code, body["name"])
+
+		var idResponse map[string]string
+		_ = json.Unmarshal([]byte(w.Body.Bytes()), &idResponse)
+
+		getReq, _ := http.NewRequest("GET", holidayUrl+"/"+idResponse["id"], nil)
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, getReq)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response struct {
+			Recurrence struct {
+				Type     string `json:"type"`
+				Weekdays []int  `json:"weekdays"`
+				Nth      int    `json:"nth"`
+			} `json:"recurrence"`
+		}
+		_ = json.Unmarshal([]byte(w.Body.Bytes()), &response)
+		want := body["recurrence"].(map[string]interface{})
+		assert.Equal(t, want["type"], response.Recurrence.Type)
+		if weekdays, ok := want["weekdays"]; ok {
+			assert.Equal(t, weekdays, response.Recurrence.Weekdays)
+		}
+	}
+
+	// 2050/06/21 is tuesday (closed by weekly rule) and 2050/06/20 is 3rd monday
+	errBodies := []map[string]interface{}{
+		{"name": "overlap1", "start": "2050/06/21", "end": "2050/06/21"},
+		{"name": "overlap2", "start": "2050/06/20", "end": "2050/06/20"},
+		{"name": "overlap3", "start": "2060/01/01", "end": "2060/01/01"},
+		{"name": "invalid", "start": "2050/07/01", "end": "2050/07/01", "recurrence": map[string]interface{}{"type": "weekly"}},
+	}
+	for _, body := range errBodies {
+		jBytes, err := json.Marshal(body)
+		assert.NoError(t, err, "init json is failed")
+
+		req, _ := http.NewRequest("POST", holidayUrl+"/", bytes.NewBuffer(jBytes))
+		req.Header.Add("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, body["name"])
+	}
+}
+
+func newHolidayImportRequest(t *testing.T, fileName string, content []byte, fields map[string]string) *http.Request {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("file", fileName)
+	assert.NoError(t, err)
+	_, err = part.Write(content)
+	assert.NoError(t, err)
+	for key, value := range fields {
+		assert.NoError(t, writer.WriteField(key, value))
+	}
+	assert.NoError(t, writer.Close())
+
+	req, _ := http.NewRequest("POST", holidayUrl+"/import", body)
+	req.Header.Add("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+type holidayImportResponse struct {
+	Imported []map[string]string `json:"imported"`
+	Skipped  []map[string]string `json:"skipped"`
+}
+
+func TestSpecialHolidayHandler_POST_Import_Ics(t *testing.T) {
+	r := SetupSpecialHolidayRouter()
+
+	ics := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n" +
+		"BEGIN:VEVENT\r\nDTSTART;VALUE=DATE:20500101\r\nDTEND;VALUE=DATE:20500102\r\nSUMMARY:元日\r\nEND:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\nDTSTART;VALUE=DATE:20500503\r\nDTEND;VALUE=DATE:20500506\r\nSUMMARY:連\r\n 休\r\nEND:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\nDTSTART;VALUE=DATE:20200101\r\nSUMMARY:過去\r\nEND:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+	req := newHolidayImportRequest(t, "holidays.ics", []byte(ics), map[string]string{"mode": "closed"})
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response holidayImportResponse
+	_ = json.Unmarshal([]byte(w.Body.Bytes()), &response)
+	assert.Equal(t, []map[string]string{
+		{"date": "2050/01/01", "name": "元日"},
+		{"date": "2050/05/03", "name": "連休"},
+		{"date": "2050/05/04", "name": "連休"},
+		{"date": "2050/05/05", "name": "連休"},
+	}, response.Imported)
+	assert.Equal(t, 0, len(response.Skipped))
+	assert.Equal(t, 6, len(spHolidayMemory))
+
+	// same file again is skipped as overlapped
+	req = newHolidayImportRequest(t, "holidays.ics", []byte(ics), map[string]string{"mode": "closed"})
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	_ = json.Unmarshal([]byte(w.Body.Bytes()), &response)
+	assert.Equal(t, 0, len(response.Imported))
+	assert.Equal(t, 4, len(response.Skipped))
+	assert.Equal(t, 6, len(spHolidayMemory))
+}
+
+func TestSpecialHolidayHandler_POST_Import_Csv(t *testing.T) {
+	r := SetupSpecialHolidayRouter()
+
+	// Shift_JIS encoded "国民の祝日・休日月日,国民の祝日・休日名称\n2050/1/1,元日\n2050/1/10,成人の日\n"
+	csvData := []byte("\x8d\x91\x96\xaf\x82\xcc\x8f\x6a\x93\xfa\x81\x45\x8b\x78\x93\xfa\x8c\x8e\x93\xfa,\x8d\x91\x96\xaf\x82\xcc\x8f\x6a\x93\xfa\x81\x45\x8b\x78\x93\xfa\x96\xbc\x8f\xcc\r\n" +
+		"2050/1/1,\x8c\xb3\x93\xfa\r\n" +
+		"2050/1/10,\x90\xac\x90\x6c\x82\xcc\x93\xfa\r\n")
+	req := newHolidayImportRequest(t, "syukujitsu.csv", csvData, map[string]string{"mode": "closed"})
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response holidayImportResponse
+	_ = json.Unmarshal([]byte(w.Body.Bytes()), &response)
+	assert.Equal(t, []map[string]string{
+		{"date": "2050/01/01", "name": "元日"},
+		{"date": "2050/01/10", "name": "成人の日"},
+	}, response.Imported)
+}
+
+func TestSpecialHolidayHandler_POST_Import_Special(t *testing.T) {
+	r := SetupSpecialHolidayRouter()
+
+	var scheduleId string
+	for _, schedule := range businessHoursMemory.GetSchedules() {
+		scheduleId = schedule.GetId()
+		break
+	}
+	csvData := []byte("2050-01-01,元日\n2050-01-10,成人の日\n")
+	fields := map[string]string{"mode": "special", "businessHourId": scheduleId, "start": "10:00", "end": "13:00", "offsetHour": "1"}
+	req := newHolidayImportRequest(t, "holidays.csv", csvData, fields)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response holidayImportResponse
+	_ = json.Unmarshal([]byte(w.Body.Bytes()), &response)
+	assert.Equal(t, 2, len(response.Imported))
+	// not added as holiday
+	assert.Equal(t, 2, len(spHolidayMemory))
+}
+
+func TestSpecialHolidayHandler_POST_Import_BadRequest(t *testing.T) {
+	r := SetupSpecialHolidayRouter()
+
+	tests := []struct {
+		name     string
+		fileName string
+		content  string
+		fields   map[string]string
+	}{
+		{name: "no mode", fileName: "a.csv", content: "2050/01/01,a\n", fields: map[string]string{}},
+		{name: "unknown mode", fileName: "a.csv", content: "2050/01/01,a\n", fields: map[string]string{"mode": "open"}},
+		{name: "no entry", fileName: "a.csv", content: "date,name\n", fields: map[string]string{"mode": "closed"}},
+		{name: "invalid date", fileName: "a.csv", content: "2050/01/01,a\n2050/13/01,b\n", fields: map[string]string{"mode": "closed"}},
+		{name: "not exists business hour", fileName: "a.csv", content: "2050/01/01,a\n", fields: map[string]string{"mode": "special", "businessHourId": "1234", "start": "10:00", "end": "13:00"}},
+	}
+	for _, tt := range tests {
+		req := newHolidayImportRequest(t, tt.fileName, []byte(tt.content), tt.fields)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, tt.name)
+	}
+	assert.Equal(t, 2, len(spHolidayMemory))
+}
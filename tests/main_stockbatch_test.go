@@ -0,0 +1,89 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	idomains "chico/takeout/domains/item"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStockBatchHandler(t *testing.T) {
+	r := SetupStockItemRouter()
+	kindId := ""
+	for id := range kindMemoryMaps {
+		kindId = id
+		break
+	}
+	request := func(method, url string, body map[string]interface{}) *httptest.ResponseRecorder {
+		jBytes, _ := json.Marshal(body)
+		req, _ := http.NewRequest(method, url, bytes.NewBuffer(jBytes))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	w := request("POST", "/item/stock/", map[string]interface{}{"name": "batch", "description": "batch item", "priority": 3, "maxOrder": 5, "price": 100,
+		"kindId": kindId, "enabled": true, "imageUrl": "", "trackByBatch": true, "lowStockThreshold": 6})
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var created map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &created)
+	url := "/item/stock/" + created["id"].(string)
+
+	// remain of batch tracked item can not be updated directly
+	w = request("PUT", url+"/remain", map[string]interface{}{"remain": 10})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// receive
+	w = request("POST", url+"/batch", map[string]interface{}{"availableFrom": "2052/12/10", "expiresAt": "2052/12/09", "quantity": 10})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = request("POST", url+"/batch", map[string]interface{}{"availableFrom": "2052/12/10", "expiresAt": "2052/12/12", "quantity": 10})
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var batch map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &batch)
+
+	// waste
+	w = request("PUT", url+"/batch/"+batch["id"].(string)+"/waste", map[string]interface{}{"quantity": 11})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = request("PUT", url+"/batch/"+batch["id"].(string)+"/waste", map[string]interface{}{"quantity": 4})
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	w = request("GET", url+"/batch", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var batches []map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &batches)
+	assert.Equal(t, 1, len(batches))
+	assert.Equal(t, "2052/12/10", batches[0]["availableFrom"])
+	assert.Equal(t, "2052/12/12", batches[0]["expiresAt"])
+	assert.EqualValues(t, 10, batches[0]["received"])
+	assert.EqualValues(t, 6, batches[0]["remain"])
+	assert.EqualValues(t, 4, batches[0]["wasted"])
+	// receipt and waste are recorded
+	balances, _ := stockMovementRepo.SumQuantities()
+	assert.Equal(t, 6, balances[created["id"].(string)])
+	// waste raises alerts as same as order
+	alerts := stockAlertRepo.GetMemory()
+	assert.Equal(t, 1, len(alerts))
+	assert.Equal(t, idomains.ItemAlertLowStock, alerts[0].GetAlertType())
+	assert.Equal(t, 6, alerts[0].GetRemain())
+	w = request("PUT", url+"/batch/"+batch["id"].(string)+"/waste", map[string]interface{}{"quantity": 6})
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	alerts = stockAlertRepo.GetMemory()
+	assert.Equal(t, 2, len(alerts))
+	assert.Equal(t, idomains.ItemAlertSoldOut, alerts[1].GetAlertType())
+	assert.Equal(t, created["id"], alerts[1].GetItemId())
+	assert.Equal(t, "2052/12/10", alerts[1].GetTargetDate())
+
+	// not tracked item has no batch
+	for id, stock := range stockMemoryMaps {
+		if !stock.IsTrackedByBatch() {
+			w = request("GET", "/item/stock/"+id+"/batch", nil)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			break
+		}
+	}
+}
@@ -8,10 +8,13 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"chico/takeout/common"
 	domains "chico/takeout/domains/item"
 	itemHandler "chico/takeout/handlers/item"
 	"chico/takeout/infrastructures/memory"
+	"chico/takeout/middleware"
 	itemUseCase "chico/takeout/usecase/item"
 
 	"github.com/gin-gonic/gin"
@@ -19,6 +22,9 @@ import (
 )
 
 var stockMemoryMaps map[string]*domains.StockItem
+var stockMovementRepo *memory.StockMovementMemoryRepository
+var stockClock *common.FakeClock
+var stockAlertRepo *memory.ItemAlertMemoryRepository
 
 func SetupStockItemRouter() *gin.Engine {
 	r := gin.Default()
@@ -30,14 +36,39 @@ func SetupStockItemRouter() *gin.Engine {
 		stockRepo := memory.NewStockItemMemoryRepository()
 		stockRepo.Reset()
 		stockMemoryMaps = stockRepo.GetMemory()
-		useCase := itemUseCase.NewStockItemUseCase(stockRepo, kindRepo)
+		stockMovementRepo = memory.NewStockMovementMemoryRepository()
+		stockClock = common.NewFakeClock(time.Date(2052, 12, 10, 10, 0, 0, 0, common.GetStoreLocation()))
+		batchRepo := memory.NewStockBatchMemoryRepository()
+		stockAlertRepo = memory.NewItemAlertMemoryRepository()
+		// auth info is given by header in tests
+		stock.Use(func(c *gin.Context) {
+			ctx := common.SetUserId(c.GetHeader("X-User"), c.Request.Context())
+			c.Request = c.Request.WithContext(ctx)
+			c.Next()
+		})
+		useCase := itemUseCase.NewStockItemUseCase(stockRepo, kindRepo, batchRepo, stockMovementRepo, stockAlertRepo, stockClock)
 		handler := itemHandler.NewStockItemHandler(useCase)
+		stock.Use(middleware.SetContext(handler.InitContext))
 		stock.GET("/:id", handler.Get)
 		stock.GET("/", handler.GetAll)
 		stock.POST("/", handler.Post)
 		stock.PUT("/:id", handler.Put)
 		stock.PUT("/:id/remain", handler.PutRemain)
 		stock.DELETE("/:id", handler.Delete)
+
+		batchUseCase := itemUseCase.NewStockBatchUseCase(stockRepo, batchRepo, stockMovementRepo, stockAlertRepo, stockClock)
+		batchHandler := itemHandler.NewStockBatchHandler(batchUseCase)
+		stock.Use(middleware.SetContext(batchHandler.InitContext))
+		stock.GET("/:id/batch", batchHandler.GetAll)
+		stock.POST("/:id/batch", batchHandler.Post)
+		stock.PUT("/:id/batch/:batchId/waste", batchHandler.PutWaste)
+
+		movementUseCase := itemUseCase.NewStockMovementUseCase(stockRepo, batchRepo, stockMovementRepo, stockClock)
+		movementHandler := itemHandler.NewStockMovementHandler(movementUseCase)
+		stock.Use(middleware.SetContext(movementHandler.InitContext))
+		stock.GET("/movement/drift", movementHandler.GetDrifts)
+		stock.GET("/:id/movement", movementHandler.GetByStockItem)
+		stock.PUT("/:id/movement/reconcile", movementHandler.PutReconcile)
 	}
 	return r
 }
@@ -527,6 +558,39 @@ func TestStockItemHandler_PUTRemain(t *testing.T) {
 	}
 }
 
+func TestStockItemHandler_PUTRemain_ItemAlert(t *testing.T) {
+	r := SetupStockItemRouter()
+	var stock *domains.StockItem
+	for _, item := range stockMemoryMaps {
+		if !item.IsTrackedByBatch() {
+			stock = item
+			break
+		}
+	}
+	_ = stock.SetLowStockThreshold(3)
+	put := func(remain int) {
+		jBytes, _ := json.Marshal(map[string]interface{}{"remain": remain})
+		req, _ := http.NewRequest("PUT", "/item/stock/"+stock.GetId()+"/remain", bytes.NewBuffer(jBytes))
+		req.Header.Add("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	}
+
+	put(10)
+	assert.Equal(t, 0, len(stockAlertRepo.GetMemory()))
+	put(2)
+	alerts := stockAlertRepo.GetMemory()
+	assert.Equal(t, 1, len(alerts))
+	assert.Equal(t, domains.ItemAlertLowStock, alerts[0].GetAlertType())
+	assert.Equal(t, stock.GetId(), alerts[0].GetItemId())
+	assert.Equal(t, 2, alerts[0].GetRemain())
+	// same alert is not raised twice on the date
+	put(10)
+	put(1)
+	assert.Equal(t, 1, len(stockAlertRepo.GetMemory()))
+}
+
 func TestStockItemHandler_PUTRemain_BadRequest(t *testing.T) {
 	r := SetupStockItemRouter()
 
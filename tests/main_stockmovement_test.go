@@ -0,0 +1,70 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStockMovementHandler(t *testing.T) {
+	r := SetupStockItemRouter()
+	request := func(method, url string, body map[string]interface{}) *httptest.ResponseRecorder {
+		jBytes, _ := json.Marshal(body)
+		req, _ := http.NewRequest(method, url, bytes.NewBuffer(jBytes))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-User", "admin1")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	stockIds := map[string]string{}
+	for id, stock := range stockMemoryMaps {
+		stockIds[stock.GetName()] = id
+	}
+
+	// initial remain of stock1 is not recorded
+	w := request("GET", "/item/stock/movement/drift", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var drifts []map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &drifts)
+	assert.Equal(t, 1, len(drifts))
+	assert.Equal(t, stockIds["stock1"], drifts[0]["stockItemId"])
+	assert.EqualValues(t, 10, drifts[0]["remain"])
+	assert.EqualValues(t, 0, drifts[0]["balance"])
+	assert.EqualValues(t, 10, drifts[0]["drift"])
+
+	w = request("PUT", "/item/stock/"+stockIds["stock1"]+"/movement/reconcile", nil)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = request("GET", "/item/stock/movement/drift", nil)
+	_ = json.Unmarshal(w.Body.Bytes(), &drifts)
+	assert.Equal(t, 0, len(drifts))
+
+	// manual update is recorded as difference
+	w = request("PUT", "/item/stock/"+stockIds["stock1"]+"/remain", map[string]interface{}{"remain": 4})
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	w = request("GET", "/item/stock/"+stockIds["stock1"]+"/movement?from=2052/12/10&to=2052/12/10", nil)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var report map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &report)
+	movements := report["movements"].([]interface{})
+	assert.Equal(t, 2, len(movements))
+	last := movements[1].(map[string]interface{})
+	assert.Equal(t, "adjustment", last["movementType"])
+	assert.EqualValues(t, -6, last["quantity"])
+	assert.Equal(t, "admin1", last["userId"])
+	assert.Equal(t, "2052/12/10 10:00", last["createdAt"])
+	assert.EqualValues(t, 4, report["adjustment"])
+	assert.EqualValues(t, 4, report["net"])
+
+	// out of range
+	w = request("GET", "/item/stock/"+stockIds["stock1"]+"/movement?from=2052/12/11&to=2052/12/31", nil)
+	_ = json.Unmarshal(w.Body.Bytes(), &report)
+	assert.Equal(t, 0, len(report["movements"].([]interface{})))
+	w = request("GET", "/item/stock/"+stockIds["stock1"]+"/movement?from=2052/12/11&to=2052/12/10", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
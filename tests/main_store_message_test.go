@@ -8,6 +8,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"chico/takeout/common"
 	handler "chico/takeout/handlers/message"
 	"chico/takeout/infrastructures/memory"
 	useCase "chico/takeout/usecase/message"
@@ -22,7 +23,7 @@ func SetupStoreMessageRouter() *gin.Engine {
 	{
 		messageRepo := memory.NewStoreMessageRepository()
 		messageRepo.Reset()
-		useCase := useCase.NewStoreMessageUseCase(messageRepo)
+		useCase := useCase.NewStoreMessageUseCase(messageRepo, common.NewSystemClock())
 		err := useCase.CreateInitialMessage()
 		if err != nil {
 			panic("unexpected error")
@@ -25,3 +25,7 @@ func (b *BaseUseCase) IsAdmin() bool {
 func (b *BaseUseCase) GetUserId() string {
 	return common.GetUserId(b.ctx)
 }
+
+func (b *BaseUseCase) GetUserEmail() string {
+	return common.GetUserEmail(b.ctx)
+}
@@ -0,0 +1,82 @@
+package item
+
+import (
+	"fmt"
+
+	"chico/takeout/common"
+	domains "chico/takeout/domains/item"
+	"chico/takeout/domains/shared"
+)
+
+type ItemAlertModel struct {
+	Id         string
+	ItemId     string
+	ItemType   string
+	ItemName   string
+	AlertType  string
+	TargetDate string
+	Remain     int
+	Threshold  int
+	CreatedAt  string
+}
+
+type ItemAlertUseCase interface {
+	// date is yyyy/MM/dd
+	FindByDate(date string) ([]ItemAlertModel, error)
+}
+
+type itemAlertUseCase struct {
+	alertRepository domains.ItemAlertRepository
+}
+
+func NewItemAlertUseCase(alertRepository domains.ItemAlertRepository) ItemAlertUseCase {
+	return &itemAlertUseCase{
+		alertRepository: alertRepository,
+	}
+}
+
+func (i *itemAlertUseCase) FindByDate(date string) ([]ItemAlertModel, error) {
+	targetDate, err := shared.NewDate(date)
+	if err != nil {
+		return nil, err
+	}
+	alerts, err := i.alertRepository.FindByTargetDate(targetDate.GetValue())
+	if err != nil {
+		return nil, err
+	}
+	models := []ItemAlertModel{}
+	for _, alert := range alerts {
+		models = append(models, ItemAlertModel{
+			Id:         alert.GetId(),
+			ItemId:     alert.GetItemId(),
+			ItemType:   alert.GetItemType(),
+			ItemName:   alert.GetItemName(),
+			AlertType:  string(alert.GetAlertType()),
+			TargetDate: alert.GetTargetDate(),
+			Remain:     alert.GetRemain(),
+			Threshold:  alert.GetThreshold(),
+			CreatedAt:  common.ConvertTimeToDateTimeStr(alert.GetCreatedAt()),
+		})
+	}
+	return models, nil
+}
+
+// alert error not treats as error of stock update only displaying as info.
+// raised alerts are notified to admin by daily digest
+func snapshotStockRemains(checker *domains.StockAlertChecker) map[string]int {
+	remains, err := checker.SnapshotRemains()
+	if err != nil {
+		fmt.Printf("item alert error.%s", err)
+		return nil
+	}
+	return remains
+}
+
+func raiseStockAlerts(checker *domains.StockAlertChecker, remains map[string]int) {
+	if remains == nil {
+		return
+	}
+	if _, err := checker.Check(remains); err != nil {
+		fmt.Printf("item alert error.%s", err)
+	}
+}
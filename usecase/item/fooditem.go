@@ -13,6 +13,7 @@ type FoodItemModel struct {
 	ScheduleIds    []string
 	MaxOrderPerDay int
 	AllowDates     []string
+	LeadTimeHours  uint
 }
 
 func newFoodItemModel(item *domains.FoodItem, kind *domains.ItemKind) *FoodItemModel {
@@ -33,6 +34,7 @@ func newFoodItemModel(item *domains.FoodItem, kind *domains.ItemKind) *FoodItemM
 		ScheduleIds:    item.GetScheduleIds(),
 		MaxOrderPerDay: item.GetMaxOrderPerDay(),
 		AllowDates:     item.GetAllowDates(),
+		LeadTimeHours:  item.GetLeadTimeHours(),
 	}
 }
 
@@ -41,6 +43,7 @@ type FoodItemCreateModel struct {
 	ScheduleIds    []string
 	MaxOrderPerDay int
 	AllowDates     []string
+	LeadTimeHours  uint
 }
 
 type FoodItemUpdateModel struct {
@@ -48,6 +51,7 @@ type FoodItemUpdateModel struct {
 	ScheduleIds    []string
 	MaxOrderPerDay int
 	AllowDates     []string
+	LeadTimeHours  uint
 }
 
 type FoodItemUseCase interface {
@@ -117,7 +121,7 @@ func (f *foodItemUseCase) FindAll() ([]FoodItemModel, error) {
 }
 
 func (f *foodItemUseCase) Create(model *FoodItemCreateModel) (string, error) {
-	item, err := domains.NewFoodItem(model.Name, model.Description, model.Priority, model.MaxOrder, model.MaxOrderPerDay, model.Price, model.KindId, model.ScheduleIds, model.Enabled, model.ImageUrl, model.AllowDates)
+	item, err := domains.NewFoodItem(model.Name, model.Description, model.Priority, model.MaxOrder, model.MaxOrderPerDay, model.Price, model.KindId, model.ScheduleIds, model.Enabled, model.ImageUrl, model.AllowDates, model.LeadTimeHours)
 	if err != nil {
 		return "", err
 	}
@@ -149,7 +153,7 @@ func (i *foodItemUseCase) Update(model *FoodItemUpdateModel) error {
 		return common.NewUpdateTargetNotFoundError(model.Id)
 	}
 
-	err = item.Set(model.Name, model.Description, model.Priority, model.MaxOrder, model.MaxOrderPerDay, model.Price, model.KindId, model.ScheduleIds, model.Enabled, model.ImageUrl, model.AllowDates)
+	err = item.Set(model.Name, model.Description, model.Priority, model.MaxOrder, model.MaxOrderPerDay, model.Price, model.KindId, model.ScheduleIds, model.Enabled, model.ImageUrl, model.AllowDates, model.LeadTimeHours)
 	if err != nil {
 		return err
 	}
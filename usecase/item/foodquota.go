@@ -0,0 +1,210 @@
+package item
+
+import (
+	"fmt"
+	"time"
+
+	"chico/takeout/common"
+	domains "chico/takeout/domains/item"
+)
+
+// dates edited at once
+const foodQuotaMaxRangeDays = 366
+
+type FoodQuotaModel struct {
+	ItemId string
+	Date   string
+	// empty means whole day
+	HourTypeId string
+	Quantity   int
+	// manual or forecast. saved quota is always manual
+	Source string
+}
+
+type FoodQuotaRangeModel struct {
+	ItemId string
+	// yyyy/MM/dd. start and end are included
+	Start      string
+	End        string
+	HourTypeId string
+	// 0:Sunday ~ 6:Saturday. empty means every day
+	Weekdays []int
+	// not used for delete
+	Quantity int
+}
+
+func newFoodQuotaModel(quota *domains.FoodQuota) *FoodQuotaModel {
+	return &FoodQuotaModel{
+		ItemId:     quota.GetItemId(),
+		Date:       quota.GetDate(),
+		HourTypeId: quota.GetHourTypeId(),
+		Quantity:   quota.GetQuantity(),
+		Source:     string(quota.GetSource()),
+	}
+}
+
+type FoodQuotaUseCase interface {
+	// start and end (yyyy/MM/dd) are included
+	FindByItem(itemId, start, end string) ([]FoodQuotaModel, error)
+	// replaced if quota of the date and hour type exists
+	Save(model *FoodQuotaModel) error
+	Delete(itemId, date, hourTypeId string) error
+	// returns number of saved dates
+	SaveRange(model *FoodQuotaRangeModel) (int, error)
+	// returns number of deleted dates
+	DeleteRange(model *FoodQuotaRangeModel) (int, error)
+}
+
+type foodQuotaUseCase struct {
+	foodItemRepository  domains.FoodItemRepository
+	foodQuotaRepository domains.FoodQuotaRepository
+}
+
+func NewFoodQuotaUseCase(foodItemRepository domains.FoodItemRepository, foodQuotaRepository domains.FoodQuotaRepository) FoodQuotaUseCase {
+	return &foodQuotaUseCase{
+		foodItemRepository:  foodItemRepository,
+		foodQuotaRepository: foodQuotaRepository,
+	}
+}
+
+func (f *foodQuotaUseCase) FindByItem(itemId, start, end string) ([]FoodQuotaModel, error) {
+	_, err := f.findFoodItem(itemId, "")
+	if err != nil {
+		return nil, err
+	}
+	_, err = listUpQuotaDates(start, end, nil)
+	if err != nil {
+		return nil, err
+	}
+	quotas, err := f.foodQuotaRepository.FindByRange(start, end)
+	if err != nil {
+		return nil, err
+	}
+	models := []FoodQuotaModel{}
+	for i := range quotas {
+		if quotas[i].GetItemId() == itemId {
+			models = append(models, *newFoodQuotaModel(&quotas[i]))
+		}
+	}
+	return models, nil
+}
+
+func (f *foodQuotaUseCase) Save(model *FoodQuotaModel) error {
+	_, err := f.findFoodItem(model.ItemId, model.HourTypeId)
+	if err != nil {
+		return err
+	}
+	quota, err := domains.NewFoodQuota(model.ItemId, model.Date, model.HourTypeId, model.Quantity)
+	if err != nil {
+		return err
+	}
+	return f.foodQuotaRepository.Save(quota)
+}
+
+func (f *foodQuotaUseCase) Delete(itemId, date, hourTypeId string) error {
+	_, err := f.findFoodItem(itemId, "")
+	if err != nil {
+		return err
+	}
+	_, err = common.ConvertStrToDate(date)
+	if err != nil {
+		return common.NewValidationError("date", fmt.Sprintf("not allowed date format:%s", date))
+	}
+	return f.foodQuotaRepository.Delete(itemId, date, hourTypeId)
+}
+
+// all quotas are validated before saved, and saved in one transaction
+func (f *foodQuotaUseCase) SaveRange(model *FoodQuotaRangeModel) (int, error) {
+	_, err := f.findFoodItem(model.ItemId, model.HourTypeId)
+	if err != nil {
+		return 0, err
+	}
+	dates, err := listUpQuotaDates(model.Start, model.End, model.Weekdays)
+	if err != nil {
+		return 0, err
+	}
+	quotas := []domains.FoodQuota{}
+	for _, date := range dates {
+		quota, err := domains.NewFoodQuota(model.ItemId, common.ConvertTimeToDateStr(date), model.HourTypeId, model.Quantity)
+		if err != nil {
+			return 0, err
+		}
+		quotas = append(quotas, *quota)
+	}
+	err = f.foodQuotaRepository.SaveAll(quotas)
+	if err != nil {
+		return 0, err
+	}
+	return len(quotas), nil
+}
+
+func (f *foodQuotaUseCase) DeleteRange(model *FoodQuotaRangeModel) (int, error) {
+	_, err := f.findFoodItem(model.ItemId, "")
+	if err != nil {
+		return 0, err
+	}
+	dates, err := listUpQuotaDates(model.Start, model.End, model.Weekdays)
+	if err != nil {
+		return 0, err
+	}
+	targets := []string{}
+	for _, date := range dates {
+		targets = append(targets, common.ConvertTimeToDateStr(date))
+	}
+	err = f.foodQuotaRepository.DeleteAll(model.ItemId, model.HourTypeId, targets)
+	if err != nil {
+		return 0, err
+	}
+	return len(dates), nil
+}
+
+// hour type should be one of business hours of the item
+func (f *foodQuotaUseCase) findFoodItem(itemId, hourTypeId string) (*domains.FoodItem, error) {
+	item, err := f.foodItemRepository.Find(itemId)
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, common.NewNotFoundError(fmt.Sprintf("item not found:%s", itemId))
+	}
+	if hourTypeId != "" && !item.HasScheduleId(hourTypeId) {
+		return nil, common.NewValidationError("hourTypeId", fmt.Sprintf("not business hour of the item:%s", hourTypeId))
+	}
+	return item, nil
+}
+
+// dates of the weekdays in the range. empty weekdays means every day
+func listUpQuotaDates(startStr, endStr string, weekdays []int) ([]time.Time, error) {
+	start, err := common.ConvertStrToDate(startStr)
+	if err != nil {
+		return nil, common.NewValidationError("Start", fmt.Sprintf("not allowed date format:%s", startStr))
+	}
+	end, err := common.ConvertStrToDate(endStr)
+	if err != nil {
+		return nil, common.NewValidationError("End", fmt.Sprintf("not allowed date format:%s", endStr))
+	}
+	if end.Before(*start) {
+		return nil, common.NewValidationError("Start, End", fmt.Sprintf("start should be before end. start:%s, end:%s", startStr, endStr))
+	}
+	if !end.Before(start.AddDate(0, 0, foodQuotaMaxRangeDays)) {
+		return nil, common.NewValidationError("Start, End", fmt.Sprintf("range should be within %d days. start:%s, end:%s", foodQuotaMaxRangeDays, startStr, endStr))
+	}
+	targets := map[time.Weekday]bool{}
+	for _, weekday := range weekdays {
+		if weekday < 0 || weekday > 6 {
+			return nil, common.NewValidationError("Weekdays", fmt.Sprintf("should be 0 ~ 6. value:%d", weekday))
+		}
+		targets[time.Weekday(weekday)] = true
+	}
+	dates, err := common.ListUpDates(*start, *end, common.GetStoreLocation())
+	if err != nil {
+		return nil, err
+	}
+	result := []time.Time{}
+	for _, date := range dates {
+		if len(targets) == 0 || targets[date.Weekday()] {
+			result = append(result, date)
+		}
+	}
+	return result, nil
+}
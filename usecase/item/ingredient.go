@@ -0,0 +1,272 @@
+package item
+
+import (
+	"chico/takeout/common"
+	domains "chico/takeout/domains/item"
+	"chico/takeout/domains/shared"
+	"fmt"
+)
+
+type IngredientModel struct {
+	Id            string
+	Name          string
+	Unit          string
+	DailyQuantity int
+}
+
+type IngredientCreateModel struct {
+	Name          string
+	Unit          string
+	DailyQuantity int
+}
+
+type IngredientUpdateModel struct {
+	Id            string
+	Name          string
+	Unit          string
+	DailyQuantity int
+}
+
+type IngredientRemainModel struct {
+	Id            string
+	Name          string
+	Unit          string
+	DailyQuantity int
+	Remain        int
+}
+
+type RecipeIngredientModel struct {
+	IngredientId string
+	Quantity     int
+}
+
+type RecipeModel struct {
+	ItemId      string
+	ItemType    string
+	Ingredients []RecipeIngredientModel
+}
+
+func newIngredientModel(item *domains.Ingredient) *IngredientModel {
+	return &IngredientModel{
+		Id:            item.GetId(),
+		Name:          item.GetName(),
+		Unit:          item.GetUnit(),
+		DailyQuantity: item.GetDailyQuantity(),
+	}
+}
+
+func newRecipeModel(recipe *domains.Recipe) *RecipeModel {
+	ingredients := []RecipeIngredientModel{}
+	for _, ingredient := range recipe.GetIngredients() {
+		ingredients = append(ingredients, RecipeIngredientModel{
+			IngredientId: ingredient.GetIngredientId(),
+			Quantity:     ingredient.GetQuantity(),
+		})
+	}
+	return &RecipeModel{
+		ItemId:      recipe.GetItemId(),
+		ItemType:    recipe.GetItemType(),
+		Ingredients: ingredients,
+	}
+}
+
+type IngredientUseCase interface {
+	Find(id string) (*IngredientModel, error)
+	FindAll() ([]IngredientModel, error)
+	Create(model *IngredientCreateModel) (string, error)
+	Update(model *IngredientUpdateModel) error
+	// ingredient used by recipe can not be deleted
+	Delete(id string) error
+	// date is yyyy/MM/dd
+	FindRemains(date string) ([]IngredientRemainModel, error)
+	FindRecipe(itemId string) (*RecipeModel, error)
+	// item type is found from item id
+	SaveRecipe(model *RecipeModel) error
+	DeleteRecipe(itemId string) error
+}
+
+type ingredientUseCase struct {
+	ingredientRepository domains.IngredientRepository
+	recipeRepository     domains.RecipeRepository
+	stockRepository      domains.StockItemRepository
+	foodRepository       domains.FoodItemRepository
+	service              domains.IngredientService
+}
+
+func NewIngredientUseCase(ingredientRepository domains.IngredientRepository, recipeRepository domains.RecipeRepository, usageRepository domains.IngredientUsageRepository,
+	stockRepository domains.StockItemRepository, foodRepository domains.FoodItemRepository) IngredientUseCase {
+	return &ingredientUseCase{
+		ingredientRepository: ingredientRepository,
+		recipeRepository:     recipeRepository,
+		stockRepository:      stockRepository,
+		foodRepository:       foodRepository,
+		service:              *domains.NewIngredientService(ingredientRepository, recipeRepository, usageRepository),
+	}
+}
+
+func (i *ingredientUseCase) Find(id string) (*IngredientModel, error) {
+	item, err := i.ingredientRepository.Find(id)
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, common.NewNotFoundError(id)
+	}
+	return newIngredientModel(item), nil
+}
+
+func (i *ingredientUseCase) FindAll() ([]IngredientModel, error) {
+	items, err := i.ingredientRepository.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	models := []IngredientModel{}
+	for _, item := range items {
+		models = append(models, *newIngredientModel(&item))
+	}
+	return models, nil
+}
+
+func (i *ingredientUseCase) Create(model *IngredientCreateModel) (string, error) {
+	item, err := domains.NewIngredient(model.Name, model.Unit, model.DailyQuantity)
+	if err != nil {
+		return "", err
+	}
+	return i.ingredientRepository.Create(item)
+}
+
+func (i *ingredientUseCase) Update(model *IngredientUpdateModel) error {
+	item, err := i.ingredientRepository.Find(model.Id)
+	if err != nil {
+		return err
+	}
+	if item == nil {
+		return common.NewUpdateTargetNotFoundError(model.Id)
+	}
+	err = item.Set(model.Name, model.Unit, model.DailyQuantity)
+	if err != nil {
+		return err
+	}
+	return i.ingredientRepository.Update(item)
+}
+
+func (i *ingredientUseCase) Delete(id string) error {
+	item, err := i.ingredientRepository.Find(id)
+	if err != nil {
+		return err
+	}
+	if item == nil {
+		return common.NewUpdateTargetNotFoundError(id)
+	}
+	recipes, err := i.recipeRepository.FindAll()
+	if err != nil {
+		return err
+	}
+	for _, recipe := range recipes {
+		if recipe.Uses(id) {
+			return common.NewValidationError("id", fmt.Sprintf("ingredient is used by item:%s", recipe.GetItemId()))
+		}
+	}
+	return i.ingredientRepository.Delete(id)
+}
+
+func (i *ingredientUseCase) FindRemains(date string) ([]IngredientRemainModel, error) {
+	targetDate, err := shared.NewDate(date)
+	if err != nil {
+		return nil, err
+	}
+	items, err := i.ingredientRepository.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	remains, err := i.service.RemainsAt(targetDate.GetValue())
+	if err != nil {
+		return nil, err
+	}
+	models := []IngredientRemainModel{}
+	for _, item := range items {
+		models = append(models, IngredientRemainModel{
+			Id:            item.GetId(),
+			Name:          item.GetName(),
+			Unit:          item.GetUnit(),
+			DailyQuantity: item.GetDailyQuantity(),
+			Remain:        remains[item.GetId()],
+		})
+	}
+	return models, nil
+}
+
+func (i *ingredientUseCase) FindRecipe(itemId string) (*RecipeModel, error) {
+	recipe, err := i.recipeRepository.FindByItemId(itemId)
+	if err != nil {
+		return nil, err
+	}
+	if recipe == nil {
+		return nil, common.NewNotFoundError(itemId)
+	}
+	return newRecipeModel(recipe), nil
+}
+
+func (i *ingredientUseCase) SaveRecipe(model *RecipeModel) error {
+	itemType, err := i.findItemType(model.ItemId)
+	if err != nil {
+		return err
+	}
+	if itemType == "" {
+		return common.NewUpdateTargetNotFoundError(model.ItemId)
+	}
+	ingredients := []domains.RecipeIngredient{}
+	for _, line := range model.Ingredients {
+		ingredient, err := i.ingredientRepository.Find(line.IngredientId)
+		if err != nil {
+			return err
+		}
+		if ingredient == nil {
+			return common.NewValidationError("ingredientId", fmt.Sprintf("not found:%s", line.IngredientId))
+		}
+		recipeIngredient, err := domains.NewRecipeIngredient(line.IngredientId, line.Quantity)
+		if err != nil {
+			return err
+		}
+		ingredients = append(ingredients, *recipeIngredient)
+	}
+	recipe, err := domains.NewRecipe(model.ItemId, itemType, ingredients)
+	if err != nil {
+		return err
+	}
+	return i.recipeRepository.Save(recipe)
+}
+
+func (i *ingredientUseCase) DeleteRecipe(itemId string) error {
+	recipe, err := i.recipeRepository.FindByItemId(itemId)
+	if err != nil {
+		return err
+	}
+	if recipe == nil {
+		return common.NewUpdateTargetNotFoundError(itemId)
+	}
+	return i.recipeRepository.Delete(itemId)
+}
+
+// empty if item is not found
+func (i *ingredientUseCase) findItemType(itemId string) (string, error) {
+	stocks, err := i.stockRepository.FindAll()
+	if err != nil {
+		return "", err
+	}
+	for _, stock := range stocks {
+		if stock.HasSameId(itemId) {
+			return "stock", nil
+		}
+	}
+	foods, err := i.foodRepository.FindAll()
+	if err != nil {
+		return "", err
+	}
+	for _, food := range foods {
+		if food.HasSameId(itemId) {
+			return "food", nil
+		}
+	}
+	return "", nil
+}
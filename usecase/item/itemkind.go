@@ -10,6 +10,46 @@ type ItemKindModel struct {
 	Name     string
 	Priority int
 	OptionItemIds []string
+	OptionGroups  []OptionGroupModel
+}
+
+// 0 MaxSelect means no limit
+type OptionGroupModel struct {
+	Name             string
+	OptionItemIds    []string
+	Required         bool
+	MinSelect        uint
+	MaxSelect        uint
+	MaxQuantity      uint
+	DefaultOptionIds []string
+}
+
+func newOptionGroupModels(groups []domains.OptionGroup) []OptionGroupModel {
+	models := []OptionGroupModel{}
+	for _, group := range groups {
+		models = append(models, OptionGroupModel{
+			Name:             group.GetName(),
+			OptionItemIds:    group.GetOptionItemIds(),
+			Required:         group.IsRequired(),
+			MinSelect:        group.GetMinSelect(),
+			MaxSelect:        group.GetMaxSelect(),
+			MaxQuantity:      group.GetMaxQuantity(),
+			DefaultOptionIds: group.GetDefaultOptionIds(),
+		})
+	}
+	return models
+}
+
+func toOptionGroups(models []OptionGroupModel) ([]domains.OptionGroup, error) {
+	groups := []domains.OptionGroup{}
+	for _, model := range models {
+		group, err := domains.NewOptionGroup(model.Name, model.OptionItemIds, model.Required, model.MinSelect, model.MaxSelect, model.MaxQuantity, model.DefaultOptionIds)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, *group)
+	}
+	return groups, nil
 }
 
 func newItemKindModel(item *domains.ItemKind) *ItemKindModel {
@@ -18,6 +58,7 @@ func newItemKindModel(item *domains.ItemKind) *ItemKindModel {
 		Name:     item.GetName(),
 		Priority: item.GetPriority(),
 		OptionItemIds: item.GetOptionItemIds(),
+		OptionGroups:  newOptionGroupModels(item.GetOptionGroups()),
 	}
 }
 
@@ -25,6 +66,7 @@ type ItemKindCreateModel struct {
 	Name          string
 	Priority      int
 	OptionItemIds []string
+	OptionGroups  []OptionGroupModel
 }
 
 type ItemKindUpdateModel struct {
@@ -32,6 +74,7 @@ type ItemKindUpdateModel struct {
 	Name          string
 	Priority      int
 	OptionItemIds []string
+	OptionGroups  []OptionGroupModel
 }
 
 type ItemKindUseCase interface {
@@ -91,6 +134,14 @@ func (i *itemKindUseCase) Create(model *ItemKindCreateModel) (string, error) {
 	if err != nil {
 		return "", err
 	}
+	groups, err := toOptionGroups(model.OptionGroups)
+	if err != nil {
+		return "", err
+	}
+	err = item.SetOptionGroups(groups)
+	if err != nil {
+		return "", err
+	}
 	return i.repository.Create(item)
 }
 
@@ -113,6 +164,14 @@ func (i *itemKindUseCase) Update(model *ItemKindUpdateModel) error {
 	if err != nil {
 		return err
 	}
+	groups, err := toOptionGroups(model.OptionGroups)
+	if err != nil {
+		return err
+	}
+	err = item.SetOptionGroups(groups)
+	if err != nil {
+		return err
+	}
 	return i.repository.Update(item)
 }
 
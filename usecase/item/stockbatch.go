@@ -0,0 +1,163 @@
+package item
+
+import (
+	"context"
+	"fmt"
+
+	"chico/takeout/common"
+	domains "chico/takeout/domains/item"
+	"chico/takeout/usecase"
+)
+
+type StockBatchModel struct {
+	Id            string
+	StockItemId   string
+	AvailableFrom string
+	ExpiresAt     string
+	Received      int
+	Remain        int
+	Wasted        int
+	Consumed      int
+}
+
+type StockBatchReceiveModel struct {
+	StockItemId   string
+	AvailableFrom string
+	ExpiresAt     string
+	Quantity      int
+}
+
+type StockBatchWasteModel struct {
+	StockItemId string
+	BatchId     string
+	Quantity    int
+}
+
+func newStockBatchModel(batch *domains.StockBatch) *StockBatchModel {
+	return &StockBatchModel{
+		Id:            batch.GetId(),
+		StockItemId:   batch.GetStockItemId(),
+		AvailableFrom: batch.GetAvailableFrom(),
+		ExpiresAt:     batch.GetExpiresAt(),
+		Received:      batch.GetReceived(),
+		Remain:        batch.GetRemain(),
+		Wasted:        batch.GetWasted(),
+		Consumed:      batch.GetConsumed(),
+	}
+}
+
+type StockBatchUseCase interface {
+	InitContext(ctx context.Context)
+	FindByStockItem(stockItemId string) ([]StockBatchModel, error)
+	Receive(model *StockBatchReceiveModel) (string, error)
+	Waste(model *StockBatchWasteModel) error
+}
+
+// receipt and waste are recorded as stock movement
+type stockBatchUseCase struct {
+	*usecase.BaseUseCase
+	stockItemRepository     domains.StockItemRepository
+	stockBatchRepository    domains.StockBatchRepository
+	stockMovementRepository domains.StockMovementRepository
+	alertChecker            domains.StockAlertChecker
+	clock                   common.Clock
+}
+
+func NewStockBatchUseCase(stockItemRepository domains.StockItemRepository, stockBatchRepository domains.StockBatchRepository, stockMovementRepository domains.StockMovementRepository, alertRepository domains.ItemAlertRepository, clock common.Clock) StockBatchUseCase {
+	return &stockBatchUseCase{
+		BaseUseCase:             usecase.NewBaseUseCase(),
+		stockItemRepository:     stockItemRepository,
+		stockBatchRepository:    stockBatchRepository,
+		stockMovementRepository: stockMovementRepository,
+		alertChecker:            *domains.NewStockAlertChecker(stockItemRepository, stockBatchRepository, stockMovementRepository, alertRepository, clock),
+		clock:                   clock,
+	}
+}
+
+func (s *stockBatchUseCase) FindByStockItem(stockItemId string) ([]StockBatchModel, error) {
+	_, err := s.findTrackedStockItem(stockItemId)
+	if err != nil {
+		return nil, err
+	}
+	batches, err := s.stockBatchRepository.FindByStockItemId(stockItemId)
+	if err != nil {
+		return nil, err
+	}
+	models := []StockBatchModel{}
+	for i := range batches {
+		models = append(models, *newStockBatchModel(&batches[i]))
+	}
+	return models, nil
+}
+
+// delivery or production of stock item
+func (s *stockBatchUseCase) Receive(model *StockBatchReceiveModel) (string, error) {
+	_, err := s.findTrackedStockItem(model.StockItemId)
+	if err != nil {
+		return "", err
+	}
+	batch, err := domains.NewStockBatch(model.StockItemId, model.AvailableFrom, model.ExpiresAt, model.Quantity)
+	if err != nil {
+		return "", err
+	}
+	id, err := s.stockBatchRepository.Create(batch)
+	if err != nil {
+		return "", err
+	}
+	err = s.record(batch, domains.StockMovementReceipt, batch.GetReceived())
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (s *stockBatchUseCase) Waste(model *StockBatchWasteModel) error {
+	_, err := s.findTrackedStockItem(model.StockItemId)
+	if err != nil {
+		return err
+	}
+	batch, err := s.stockBatchRepository.Find(model.BatchId)
+	if err != nil {
+		return err
+	}
+	if batch == nil || batch.GetStockItemId() != model.StockItemId {
+		return common.NewUpdateTargetNotFoundError(model.BatchId)
+	}
+	remains := snapshotStockRemains(&s.alertChecker)
+	err = batch.Waste(model.Quantity)
+	if err != nil {
+		return err
+	}
+	err = s.stockBatchRepository.Update(batch)
+	if err != nil {
+		return err
+	}
+	err = s.record(batch, domains.StockMovementWaste, -model.Quantity)
+	if err != nil {
+		return err
+	}
+	raiseStockAlerts(&s.alertChecker, remains)
+	return nil
+}
+
+func (s *stockBatchUseCase) record(batch *domains.StockBatch, movementType domains.StockMovementType, quantity int) error {
+	movement, err := domains.NewStockMovement(batch.GetStockItemId(), batch.GetId(), movementType, quantity, "", s.GetUserId(), "", s.clock.Now())
+	if err != nil {
+		return err
+	}
+	return s.stockMovementRepository.Create(movement)
+}
+
+func (s *stockBatchUseCase) findTrackedStockItem(stockItemId string) (*domains.StockItem, error) {
+	item, err := s.stockItemRepository.Find(stockItemId)
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, common.NewNotFoundError(fmt.Sprintf("item not found:%s", stockItemId))
+	}
+	if !item.IsTrackedByBatch() {
+		return nil, common.NewValidationError("trackByBatch", fmt.Sprintf("stock item is not tracked by batch:%s", stockItemId))
+	}
+	return item, nil
+}
@@ -1,23 +1,31 @@
 package item
 
 import (
+	"context"
 	"fmt"
 
 	"chico/takeout/common"
 	domains "chico/takeout/domains/item"
+	"chico/takeout/usecase"
 )
 
 type StockItemModel struct {
 	CommonItemModel
-	Remain int
+	Remain            int
+	TrackByBatch      bool
+	LowStockThreshold int
 }
 
 type StockItemCreateModel struct {
 	CommonItemCreateModel
+	TrackByBatch      bool
+	LowStockThreshold int
 }
 
 type StockItemUpdateModel struct {
 	CommonItemUpdateModel
+	TrackByBatch      bool
+	LowStockThreshold int
 }
 
 type StockItemRemainUpdateModel struct {
@@ -40,11 +48,14 @@ func newStockItemModel(item *domains.StockItem, kind *domains.ItemKind) *StockIt
 				ImageUrl:    item.GetImageUrl(),
 			},
 		},
-		Remain: item.GetRemain(),
+		Remain:            item.GetRemain(),
+		TrackByBatch:      item.IsTrackedByBatch(),
+		LowStockThreshold: item.GetLowStockThreshold(),
 	}
 }
 
 type StockItemUseCase interface {
+	InitContext(ctx context.Context)
 	Find(id string) (*StockItemModel, error)
 	FindAll() ([]StockItemModel, error)
 	Create(model *StockItemCreateModel) (string, error)
@@ -54,16 +65,24 @@ type StockItemUseCase interface {
 }
 
 type stockItemUseCase struct {
-	stockItemRepository domains.StockItemRepository
-	itemKindRepository  domains.ItemKindRepository
+	*usecase.BaseUseCase
+	stockItemRepository     domains.StockItemRepository
+	itemKindRepository      domains.ItemKindRepository
+	stockMovementRepository domains.StockMovementRepository
+	alertChecker            domains.StockAlertChecker
+	clock                   common.Clock
 	commonItemUseCase
 }
 
-func NewStockItemUseCase(stockItemRepository domains.StockItemRepository, itemKindRepository domains.ItemKindRepository) StockItemUseCase {
+func NewStockItemUseCase(stockItemRepository domains.StockItemRepository, itemKindRepository domains.ItemKindRepository, stockBatchRepository domains.StockBatchRepository, stockMovementRepository domains.StockMovementRepository, alertRepository domains.ItemAlertRepository, clock common.Clock) StockItemUseCase {
 	return &stockItemUseCase{
-		stockItemRepository: stockItemRepository,
-		itemKindRepository:  itemKindRepository,
-		commonItemUseCase:   *newCommonItemUseCase(itemKindRepository),
+		BaseUseCase:             usecase.NewBaseUseCase(),
+		stockItemRepository:     stockItemRepository,
+		itemKindRepository:      itemKindRepository,
+		stockMovementRepository: stockMovementRepository,
+		alertChecker:            *domains.NewStockAlertChecker(stockItemRepository, stockBatchRepository, stockMovementRepository, alertRepository, clock),
+		clock:                   clock,
+		commonItemUseCase:       *newCommonItemUseCase(itemKindRepository),
 	}
 }
 
@@ -112,6 +131,11 @@ func (i *stockItemUseCase) Create(model *StockItemCreateModel) (string, error) {
 	if err != nil {
 		return "", err
 	}
+	item.SetTrackByBatch(model.TrackByBatch)
+	err = item.SetLowStockThreshold(model.LowStockThreshold)
+	if err != nil {
+		return "", err
+	}
 
 	err = i.ExistsKind(item)
 	if err != nil {
@@ -135,6 +159,11 @@ func (i *stockItemUseCase) Update(model *StockItemUpdateModel) error {
 	if err != nil {
 		return err
 	}
+	item.SetTrackByBatch(model.TrackByBatch)
+	err = item.SetLowStockThreshold(model.LowStockThreshold)
+	if err != nil {
+		return err
+	}
 
 	err = i.ExistsKind(item)
 	if err != nil {
@@ -164,10 +193,33 @@ func (i *stockItemUseCase) UpdateRemain(model *StockItemRemainUpdateModel) error
 	if item == nil {
 		return common.NewUpdateTargetNotFoundError(model.Id)
 	}
+	// remain of batch tracked item is sum of batches
+	if item.IsTrackedByBatch() {
+		return common.NewValidationError("remain", "remain of batch tracked item is updated by receiving or wasting batch")
+	}
 
+	// difference is recorded as adjustment
+	delta := model.Remain - item.GetRemain()
+	remains := snapshotStockRemains(&i.alertChecker)
 	err = item.SetRemain(model.Remain)
 	if err != nil {
 		return err
 	}
-	return i.stockItemRepository.Update(item)
+	err = i.stockItemRepository.Update(item)
+	if err != nil {
+		return err
+	}
+	if delta == 0 {
+		return nil
+	}
+	movement, err := domains.NewStockMovement(item.GetId(), "", domains.StockMovementAdjustment, delta, "", i.GetUserId(), "", i.clock.Now())
+	if err != nil {
+		return err
+	}
+	err = i.stockMovementRepository.Create(movement)
+	if err != nil {
+		return err
+	}
+	raiseStockAlerts(&i.alertChecker, remains)
+	return nil
 }
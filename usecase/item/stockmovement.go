@@ -0,0 +1,192 @@
+package item
+
+import (
+	"context"
+	"fmt"
+
+	"chico/takeout/common"
+	domains "chico/takeout/domains/item"
+	"chico/takeout/usecase"
+)
+
+type StockMovementModel struct {
+	Id           string
+	BatchId      string
+	MovementType string
+	Quantity     int
+	OrderId      string
+	UserId       string
+	Memo         string
+	CreatedAt    string
+}
+
+// totals are sum of signed quantity per movement type
+type StockMovementReportModel struct {
+	StockItemId string
+	From        string
+	To          string
+	Movements   []StockMovementModel
+	Receipt     int
+	Order       int
+	Cancel      int
+	Adjustment  int
+	Waste       int
+	Net         int
+}
+
+type StockDriftModel struct {
+	StockItemId string
+	Name        string
+	Remain      int
+	Balance     int
+	Drift       int
+}
+
+func newStockMovementModel(movement *domains.StockMovement) *StockMovementModel {
+	return &StockMovementModel{
+		Id:           movement.GetId(),
+		BatchId:      movement.GetBatchId(),
+		MovementType: string(movement.GetMovementType()),
+		Quantity:     movement.GetQuantity(),
+		OrderId:      movement.GetOrderId(),
+		UserId:       movement.GetUserId(),
+		Memo:         movement.GetMemo(),
+		CreatedAt:    common.ConvertTimeToDateTimeStr(movement.GetCreatedAt()),
+	}
+}
+
+func newStockDriftModel(drift *domains.StockDrift, name string) *StockDriftModel {
+	return &StockDriftModel{
+		StockItemId: drift.GetStockItemId(),
+		Name:        name,
+		Remain:      drift.GetRemain(),
+		Balance:     drift.GetBalance(),
+		Drift:       drift.GetDrift(),
+	}
+}
+
+type StockMovementUseCase interface {
+	InitContext(ctx context.Context)
+	// from and to are date (yyyy/MM/dd) and included
+	FindByStockItem(stockItemId, from, to string) (*StockMovementReportModel, error)
+	// only stock items which remain is not same as balance of movements
+	FindDrifts() ([]StockDriftModel, error)
+	// drift is recorded as adjustment so that balance is same as remain
+	Reconcile(stockItemId string) error
+}
+
+type stockMovementUseCase struct {
+	*usecase.BaseUseCase
+	stockItemRepository     domains.StockItemRepository
+	stockMovementRepository domains.StockMovementRepository
+	ledgerService           domains.StockLedgerService
+	clock                   common.Clock
+}
+
+func NewStockMovementUseCase(stockItemRepository domains.StockItemRepository, stockBatchRepository domains.StockBatchRepository, stockMovementRepository domains.StockMovementRepository, clock common.Clock) StockMovementUseCase {
+	return &stockMovementUseCase{
+		BaseUseCase:             usecase.NewBaseUseCase(),
+		stockItemRepository:     stockItemRepository,
+		stockMovementRepository: stockMovementRepository,
+		ledgerService:           *domains.NewStockLedgerService(stockItemRepository, stockBatchRepository, stockMovementRepository),
+		clock:                   clock,
+	}
+}
+
+func (s *stockMovementUseCase) FindByStockItem(stockItemId, from, to string) (*StockMovementReportModel, error) {
+	item, err := s.stockItemRepository.Find(stockItemId)
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, common.NewNotFoundError(fmt.Sprintf("item not found:%s", stockItemId))
+	}
+	fromDate, err := common.ConvertStrToDate(from)
+	if err != nil {
+		return nil, common.NewValidationError("from", fmt.Sprintf("failed to convert date:%s", from))
+	}
+	toDate, err := common.ConvertStrToDate(to)
+	if err != nil {
+		return nil, common.NewValidationError("to", fmt.Sprintf("failed to convert date:%s", to))
+	}
+	if toDate.Before(*fromDate) {
+		return nil, common.NewValidationError("to", fmt.Sprintf("Need to be after from:%s", from))
+	}
+	// until end of to date
+	movements, err := s.stockMovementRepository.FindByStockItemId(stockItemId, *fromDate, toDate.AddDate(0, 0, 1).Add(-1))
+	if err != nil {
+		return nil, err
+	}
+	report := StockMovementReportModel{
+		StockItemId: stockItemId,
+		From:        common.ConvertTimeToDateStr(*fromDate),
+		To:          common.ConvertTimeToDateStr(*toDate),
+		Movements:   []StockMovementModel{},
+	}
+	for i := range movements {
+		movement := &movements[i]
+		report.Movements = append(report.Movements, *newStockMovementModel(movement))
+		switch movement.GetMovementType() {
+		case domains.StockMovementReceipt:
+			report.Receipt += movement.GetQuantity()
+		case domains.StockMovementOrder:
+			report.Order += movement.GetQuantity()
+		case domains.StockMovementCancel:
+			report.Cancel += movement.GetQuantity()
+		case domains.StockMovementAdjustment:
+			report.Adjustment += movement.GetQuantity()
+		case domains.StockMovementWaste:
+			report.Waste += movement.GetQuantity()
+		}
+		report.Net += movement.GetQuantity()
+	}
+	return &report, nil
+}
+
+func (s *stockMovementUseCase) FindDrifts() ([]StockDriftModel, error) {
+	items, err := s.stockItemRepository.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	drifts, err := s.ledgerService.CheckDrifts()
+	if err != nil {
+		return nil, err
+	}
+	models := []StockDriftModel{}
+	for i := range drifts {
+		if drifts[i].GetDrift() == 0 {
+			continue
+		}
+		name := ""
+		for _, item := range items {
+			if item.HasSameId(drifts[i].GetStockItemId()) {
+				name = item.GetName()
+				break
+			}
+		}
+		models = append(models, *newStockDriftModel(&drifts[i], name))
+	}
+	return models, nil
+}
+
+func (s *stockMovementUseCase) Reconcile(stockItemId string) error {
+	item, err := s.stockItemRepository.Find(stockItemId)
+	if err != nil {
+		return err
+	}
+	if item == nil {
+		return common.NewUpdateTargetNotFoundError(stockItemId)
+	}
+	drift, err := s.ledgerService.CheckDrift(item)
+	if err != nil {
+		return err
+	}
+	if drift.GetDrift() == 0 {
+		return nil
+	}
+	movement, err := domains.NewStockMovement(stockItemId, "", domains.StockMovementAdjustment, drift.GetDrift(), "", s.GetUserId(), "reconciliation", s.clock.Now())
+	if err != nil {
+		return err
+	}
+	return s.stockMovementRepository.Create(movement)
+}
@@ -41,11 +41,13 @@ type StoreMessageUseCase interface {
 
 type storeMessageUseCase struct {
 	repository domains.MessageRepository
+	clock      common.Clock
 }
 
-func NewStoreMessageUseCase(repository domains.MessageRepository) StoreMessageUseCase {
+func NewStoreMessageUseCase(repository domains.MessageRepository, clock common.Clock) StoreMessageUseCase {
 	return &storeMessageUseCase{
 		repository: repository,
+		clock:      clock,
 	}
 }
 
@@ -56,7 +58,7 @@ func (m *storeMessageUseCase) CreateInitialMessage() error {
 		return nil
 	}
 	if topMessage == nil {
-		item1, err := domains.NewStoreTopMessage("トップメッセージです。")
+		item1, err := domains.NewStoreTopMessage("トップメッセージです。", m.clock.Now())
 		if err != nil {
 			return err
 		}
@@ -71,7 +73,7 @@ func (m *storeMessageUseCase) CreateInitialMessage() error {
 		return nil
 	}
 	if myMessage == nil {
-		item1, err := domains.NewMyPageMessage("マイページメッセージです。")
+		item1, err := domains.NewMyPageMessage("マイページメッセージです。", m.clock.Now())
 		if err != nil {
 			return err
 		}
@@ -85,7 +87,7 @@ func (m *storeMessageUseCase) CreateInitialMessage() error {
 
 // Create implements MessageUseCase
 func (m *storeMessageUseCase) Create(model *StoreMessageCreateModel) (string, error) {
-	item, err := domains.NewMessage(model.Id, model.Content)
+	item, err := domains.NewMessage(model.Id, model.Content, m.clock.Now())
 	if err != nil {
 		return "", err
 	}
@@ -116,7 +118,7 @@ func (m *storeMessageUseCase) Update(model *StoreMessageUpdateModel) error {
 		return common.NewUpdateTargetNotFoundError(model.Id)
 	}
 
-	err = item.Set(model.Content)
+	err = item.Set(model.Content, m.clock.Now())
 	if err != nil {
 		return err
 	}
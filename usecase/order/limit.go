@@ -0,0 +1,65 @@
+package order
+
+import (
+	domains "chico/takeout/domains/order"
+)
+
+type OrderLimitModel struct {
+	MaxActivePerUser uint
+	MaxPerPickupDate uint
+	MaxPerHourSlot   uint
+}
+
+func newOrderLimitModel(item *domains.OrderLimit) *OrderLimitModel {
+	return &OrderLimitModel{
+		MaxActivePerUser: item.GetMaxActivePerUser(),
+		MaxPerPickupDate: item.GetMaxPerPickupDate(),
+		MaxPerHourSlot:   item.GetMaxPerHourSlot(),
+	}
+}
+
+type OrderLimitUseCase interface {
+	Fetch() (*OrderLimitModel, error)
+	Update(model *OrderLimitModel) error
+}
+
+type orderLimitUseCase struct {
+	limitRepository domains.OrderLimitRepository
+}
+
+func NewOrderLimitUseCase(limitRepository domains.OrderLimitRepository) OrderLimitUseCase {
+	return &orderLimitUseCase{
+		limitRepository: limitRepository,
+	}
+}
+
+// default limit is returned if not created yet
+func (o *orderLimitUseCase) Fetch() (*OrderLimitModel, error) {
+	item, err := o.limitRepository.Fetch()
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		item = domains.NewDefaultOrderLimit()
+	}
+	return newOrderLimitModel(item), nil
+}
+
+func (o *orderLimitUseCase) Update(model *OrderLimitModel) error {
+	item, err := o.limitRepository.Fetch()
+	if err != nil {
+		return err
+	}
+	if item == nil {
+		item, err = domains.NewOrderLimit(model.MaxActivePerUser, model.MaxPerPickupDate, model.MaxPerHourSlot)
+		if err != nil {
+			return err
+		}
+		return o.limitRepository.Create(item)
+	}
+	err = item.Set(model.MaxActivePerUser, model.MaxPerPickupDate, model.MaxPerHourSlot)
+	if err != nil {
+		return err
+	}
+	return o.limitRepository.Update(item)
+}
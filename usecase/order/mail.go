@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"chico/takeout/common"
+	idomains "chico/takeout/domains/item"
 	domains "chico/takeout/domains/order"
 	"chico/takeout/domains/shared/validator"
 )
@@ -13,20 +14,29 @@ import (
 type SendOrderMailService interface {
 	SendComplete(data OrderCompleteMailData) error
 	SendCancel(data OrderCancelMailData) error
+	SendChange(data OrderChangeMailData) error
 	SendDailySummary(data ReservationSummaryMailData) error
+	SendItemAlert(data ItemAlertMailData) error
 }
 
 type OrderCompleteMailData struct {
 	commonMailData
 }
 
-func NewOrderCompleteMailData(order *domains.OrderInfo, sendFrom, adminMail string) (*OrderCompleteMailData, error) {
+// guest order has no my page, so access url with token is written instead
+func NewOrderCompleteMailData(order *domains.OrderInfo, guestAccessUrl, sendFrom, adminMail string) (*OrderCompleteMailData, error) {
 	title := "予約完了のお知らせ.(CHICO SPICE)"
 
 	b := &strings.Builder{}
 	b.WriteString("予約が完了いたしました。")
 	b.WriteString("\n\n")
-	b.WriteString("※ご注文内容に関してはマイページからご確認下さい。")
+	if guestAccessUrl != "" {
+		b.WriteString("※ご注文内容の確認とキャンセルは下記のURLから可能です。(有効期限があります)")
+		b.WriteString("\n")
+		b.WriteString(guestAccessUrl)
+	} else {
+		b.WriteString("※ご注文内容に関してはマイページからご確認下さい。")
+	}
 	b.WriteString("\n")
 	b.WriteString("※決済は当日、店舗にて実施させていただきます。")
 	b.WriteString("\n\n")
@@ -65,15 +75,31 @@ type OrderCancelMailData struct {
 	commonMailData
 }
 
-func NewOrderCancelMailData(order *domains.OrderInfo, sendFrom, adminMail string) (*OrderCancelMailData, error) {
+// canceled by admin case, customer is notified with the reason
+func NewOrderCancelMailData(order *domains.OrderInfo, byAdmin bool, sendFrom, adminMail string) (*OrderCancelMailData, error) {
 	title := "キャンセル完了のお知らせ.(CHICO SPICE)"
 
 	b := &strings.Builder{}
-	b.WriteString("予約をキャンセルいたしました。")
+	if byAdmin {
+		b.WriteString("誠に申し訳ございませんが、店舗にてご予約をキャンセルさせていただきました。")
+	} else {
+		b.WriteString("予約をキャンセルいたしました。")
+	}
 	b.WriteString("\n")
 	b.WriteString("またのご利用をお待ちしております。")
 	b.WriteString("\n\n")
 
+	b.WriteString("--キャンセル情報--")
+	b.WriteString("\n")
+	b.WriteString(fmt.Sprintf("受取日時:%s", order.GetPickupDateTime()))
+	b.WriteString("\n")
+	b.WriteString(fmt.Sprintf("氏名:%s", order.GetUserName()))
+	b.WriteString("\n")
+	if order.GetCancelReason() != "" {
+		b.WriteString(fmt.Sprintf("キャンセル理由:%s", order.GetCancelReason()))
+		b.WriteString("\n")
+	}
+
 	b.WriteString("\n\n")
 	b.WriteString(fmt.Sprintf("本メールに心当たりが無い方は、お手数ですが(%s)宛にご連絡をお願いいたします。(本メールは送信専用アドレスから送信しているため、直接の返信は不可能です。)", adminMail))
 	b.WriteString("\n")
@@ -91,6 +117,68 @@ func NewOrderCancelMailData(order *domains.OrderInfo, sendFrom, adminMail string
 	}, nil
 }
 
+type OrderChangeMailData struct {
+	commonMailData
+}
+
+func NewOrderChangeMailData(order *domains.OrderInfo, before *domains.OrderRevision, sendFrom, adminMail string) (*OrderChangeMailData, error) {
+	title := "予約変更のお知らせ.(CHICO SPICE)"
+
+	b := &strings.Builder{}
+	b.WriteString("予約内容を変更いたしました。")
+	b.WriteString("\n\n")
+	b.WriteString("※ご注文内容に関してはマイページからご確認下さい。")
+	b.WriteString("\n\n")
+
+	b.WriteString("--変更後の予約情報--")
+	b.WriteString("\n")
+	b.WriteString(fmt.Sprintf("受取日時:%s", order.GetPickupDateTime()))
+	b.WriteString("\n")
+	b.WriteString(fmt.Sprintf("氏名:%s", order.GetUserName()))
+	b.WriteString("\n")
+	for _, food := range order.GetFoodItems() {
+		b.WriteString(fmt.Sprintf("%s, %d円, %d個", food.GetName(), food.GetPrice(), food.GetQuantity()))
+		b.WriteString("\n")
+	}
+	for _, stock := range order.GetStockItems() {
+		b.WriteString(fmt.Sprintf("%s, %d円, %d個", stock.GetName(), stock.GetPrice(), stock.GetQuantity()))
+		b.WriteString("\n")
+	}
+	b.WriteString(fmt.Sprintf("合計:: %d円", order.GetTotalCost()))
+	b.WriteString("\n\n")
+
+	b.WriteString("--変更前の予約情報--")
+	b.WriteString("\n")
+	b.WriteString(fmt.Sprintf("受取日時:%s", before.GetPickupDateTime()))
+	b.WriteString("\n")
+	for _, food := range before.GetFoodItems() {
+		b.WriteString(fmt.Sprintf("%s, %d円, %d個", food.GetName(), food.GetPrice(), food.GetQuantity()))
+		b.WriteString("\n")
+	}
+	for _, stock := range before.GetStockItems() {
+		b.WriteString(fmt.Sprintf("%s, %d円, %d個", stock.GetName(), stock.GetPrice(), stock.GetQuantity()))
+		b.WriteString("\n")
+	}
+	b.WriteString(fmt.Sprintf("合計:: %d円", before.GetTotalCost()))
+	b.WriteString("\n")
+
+	b.WriteString("\n\n")
+	b.WriteString(fmt.Sprintf("本メールに心当たりが無い方は、お手数ですが(%s)宛にご連絡をお願いいたします。(本メールは送信専用アドレスから送信しているため、直接の返信は不可能です。)", adminMail))
+	b.WriteString("\n")
+
+	message := b.String()
+	sendTo := []string{order.GetUserEmail()}
+	cc := adminMail
+
+	comm, err := newCommonMailData(title, message, sendFrom, cc, sendTo)
+	if err != nil {
+		return nil, err
+	}
+	return &OrderChangeMailData{
+		commonMailData: *comm,
+	}, nil
+}
+
 type ReservationSummaryMailData struct {
 	commonMailData
 }
@@ -283,6 +371,113 @@ func NewNoHourReservationSummaryMailData(sendFrom, sendTo, date, startTime, endT
 	}, nil
 }
 
+// totals of items and options for kitchen
+func NewHourPrepListMailData(prep *domains.PrepList, sendFrom, sendTo, date, startTime, endTime string) (*ReservationSummaryMailData, error) {
+	title := fmt.Sprintf("仕込みリスト(%s %s ~ %s)", date, startTime, endTime)
+
+	b := &strings.Builder{}
+	b.WriteString(fmt.Sprintf("仕込みリストは下記になります。(%s :: %s ~ %s)", date, startTime, endTime))
+	b.WriteString("\n\n")
+	b.WriteString(fmt.Sprintf("注文数:%d", prep.GetOrderCount()))
+	b.WriteString("\n")
+	b.WriteString("-------------")
+	b.WriteString("\n")
+	for _, item := range prep.GetItems() {
+		b.WriteString(fmt.Sprintf("%s, %d個", item.GetName(), item.GetQuantity()))
+		b.WriteString("\n")
+		for _, opt := range item.GetOptions() {
+			b.WriteString(fmt.Sprintf("  (%s, %d個)", opt.GetName(), opt.GetQuantity()))
+			b.WriteString("\n")
+		}
+	}
+	memos := prep.GetMemos()
+	if len(memos) > 0 {
+		b.WriteString("-------------")
+		b.WriteString("\n")
+		b.WriteString("**要望やメッセージ**")
+		b.WriteString("\n")
+		for _, memo := range memos {
+			b.WriteString(fmt.Sprintf("%s %s: %s", memo.GetPickupDateTime(), memo.GetUserName(), memo.GetMemo()))
+			b.WriteString("\n")
+		}
+	}
+
+	message := b.String()
+	cc := ""
+	sendToAr := []string{sendTo}
+
+	comm, err := newCommonMailData(title, message, sendFrom, cc, sendToAr)
+	if err != nil {
+		return nil, err
+	}
+	return &ReservationSummaryMailData{
+		commonMailData: *comm,
+	}, nil
+}
+
+type ItemAlertMailData struct {
+	commonMailData
+}
+
+func writeItemAlert(b *strings.Builder, alert *idomains.ItemAlert) {
+	switch {
+	case alert.GetAlertType() == idomains.ItemAlertLowStock:
+		b.WriteString(fmt.Sprintf("[残りわずか] %s 残り:%d個 (しきい値:%d個)", alert.GetItemName(), alert.GetRemain(), alert.GetThreshold()))
+	case alert.GetItemType() == "food":
+		b.WriteString(fmt.Sprintf("[完売] %s 受取日:%s の1日の販売数に達しました", alert.GetItemName(), alert.GetTargetDate()))
+	default:
+		b.WriteString(fmt.Sprintf("[在庫切れ] %s 残り:%d個", alert.GetItemName(), alert.GetRemain()))
+	}
+	b.WriteString("\n")
+}
+
+func NewItemAlertMailData(alert *idomains.ItemAlert, sendFrom, sendTo string) (*ItemAlertMailData, error) {
+	title := fmt.Sprintf("在庫アラート(%s)", alert.GetItemName())
+
+	b := &strings.Builder{}
+	b.WriteString(fmt.Sprintf("在庫アラートが発生しました。(%s)", common.ConvertTimeToDateTimeStr(alert.GetCreatedAt())))
+	b.WriteString("\n\n")
+	writeItemAlert(b, alert)
+
+	message := b.String()
+	cc := ""
+	sendToAr := []string{sendTo}
+
+	comm, err := newCommonMailData(title, message, sendFrom, cc, sendToAr)
+	if err != nil {
+		return nil, err
+	}
+	return &ItemAlertMailData{
+		commonMailData: *comm,
+	}, nil
+}
+
+// alerts of the date are listed. digest is not created without alerts
+func NewItemAlertDigestMailData(alerts []idomains.ItemAlert, sendFrom, sendTo, date string) (*ItemAlertMailData, error) {
+	title := fmt.Sprintf("在庫アラートのまとめ(%s)", date)
+
+	b := &strings.Builder{}
+	b.WriteString(fmt.Sprintf("在庫アラートは下記になります。(%s)", date))
+	b.WriteString("\n\n")
+	b.WriteString(fmt.Sprintf("アラート数:%d", len(alerts)))
+	b.WriteString("\n")
+	for i := range alerts {
+		writeItemAlert(b, &alerts[i])
+	}
+
+	message := b.String()
+	cc := ""
+	sendToAr := []string{sendTo}
+
+	comm, err := newCommonMailData(title, message, sendFrom, cc, sendToAr)
+	if err != nil {
+		return nil, err
+	}
+	return &ItemAlertMailData{
+		commonMailData: *comm,
+	}, nil
+}
+
 type commonMailData struct {
 	Title    string
 	SendTo   []string
@@ -3,6 +3,7 @@ package order
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"chico/takeout/common"
 	idomains "chico/takeout/domains/item"
@@ -23,6 +24,8 @@ type OrderInfoModel struct {
 	StockItems     []CommonItemOrderModel
 	FoodItems      []CommonItemOrderModel
 	Canceled       bool
+	CancelReason   string
+	Source         string
 }
 
 type CommonItemOrderModel struct {
@@ -34,9 +37,10 @@ type CommonItemOrderModel struct {
 }
 
 type OptionItemOrderModel struct {
-	ItemId string
-	Name   string
-	Price  int
+	ItemId   string
+	Name     string
+	Price    int
+	Quantity int
 }
 
 func newCommonItemOrderModel(itemId, name string, price, quantity int, options []OptionItemOrderModel) *CommonItemOrderModel {
@@ -49,45 +53,77 @@ func newCommonItemOrderModel(itemId, name string, price, quantity int, options [
 	}
 }
 
-func newOptionItemOrderModel(itemId, name string, price int) *OptionItemOrderModel {
+func newOptionItemOrderModel(itemId, name string, price, quantity int) *OptionItemOrderModel {
 	return &OptionItemOrderModel{
 		ItemId:   itemId,
 		Name:     name,
 		Price:    price,
+		Quantity: quantity,
 	}
 }
 
 func newOrderInfoModel(item *domains.OrderInfo) *OrderInfoModel {
+	stocks := newStockItemOrderModels(item.GetStockItems())
+	foods := newFoodItemOrderModels(item.GetFoodItems())
+	return &OrderInfoModel{
+		Id:             item.GetId(),
+		UserId:         item.GetUserId(),
+		UserName:       item.GetUserName(),
+		UserEmail:      item.GetUserEmail(),
+		UserTelNo:      item.GetUserTelNo(),
+		Memo:           item.GetMemo(),
+		OrderDateTime:  item.GetOrderDateTime(),
+		PickupDateTime: item.GetPickupDateTime(),
+		Canceled:       item.GetCanceled(),
+		CancelReason:   item.GetCancelReason(),
+		Source:         item.GetSource(),
+		StockItems:     stocks,
+		FoodItems:      foods,
+	}
+}
+
+func newStockItemOrderModels(items []domains.OrderStockItem) []CommonItemOrderModel {
 	stocks := []CommonItemOrderModel{}
-	for _, stock := range item.GetStockItems() {
+	for _, stock := range items {
 		options := []OptionItemOrderModel{}
 		for _, opt := range stock.GetOptionItems() {
-			op := newOptionItemOrderModel(opt.GetId(), opt.GetName(), opt.GetPrice())
+			op := newOptionItemOrderModel(opt.GetId(), opt.GetName(), opt.GetPrice(), opt.GetQuantity())
 			options = append(options, *op)
 		}
 		stocks = append(stocks, *newCommonItemOrderModel(stock.GetItemId(), stock.GetName(), stock.GetPrice(), stock.GetQuantity(), options))
 	}
+	return stocks
+}
+
+func newFoodItemOrderModels(items []domains.OrderFoodItem) []CommonItemOrderModel {
 	foods := []CommonItemOrderModel{}
-	for _, food := range item.GetFoodItems() {
+	for _, food := range items {
 		options := []OptionItemOrderModel{}
 		for _, opt := range food.GetOptionItems() {
-			op := newOptionItemOrderModel(opt.GetId(), opt.GetName(), opt.GetPrice())
+			op := newOptionItemOrderModel(opt.GetId(), opt.GetName(), opt.GetPrice(), opt.GetQuantity())
 			options = append(options, *op)
 		}
 		foods = append(foods, *newCommonItemOrderModel(food.GetItemId(), food.GetName(), food.GetPrice(), food.GetQuantity(), options))
 	}
-	return &OrderInfoModel{
-		Id:             item.GetId(),
-		UserId:         item.GetUserId(),
-		UserName:       item.GetUserName(),
-		UserEmail:      item.GetUserEmail(),
-		UserTelNo:      item.GetUserTelNo(),
-		Memo:           item.GetMemo(),
-		OrderDateTime:  item.GetOrderDateTime(),
+	return foods
+}
+
+// contents of order before amended
+type OrderRevisionModel struct {
+	Revision       int
+	PickupDateTime string
+	AmendedAt      string
+	StockItems     []CommonItemOrderModel
+	FoodItems      []CommonItemOrderModel
+}
+
+func newOrderRevisionModel(item *domains.OrderRevision) *OrderRevisionModel {
+	return &OrderRevisionModel{
+		Revision:       item.GetRevision(),
 		PickupDateTime: item.GetPickupDateTime(),
-		Canceled:       item.GetCanceled(),
-		StockItems:     stocks,
-		FoodItems:      foods,
+		AmendedAt:      item.GetAmendedAt(),
+		StockItems:     newStockItemOrderModels(item.GetStockItems()),
+		FoodItems:      newFoodItemOrderModels(item.GetFoodItems()),
 	}
 }
 
@@ -106,6 +142,43 @@ type OrderInfoCreateModel struct {
 	FoodItems      []CommonItemOrderCreateModel
 }
 
+// order entered by admin for customer without account
+type OrderProxyCreateModel struct {
+	// phone or walk_in
+	Source         string
+	UserName       string
+	UserEmail      string
+	UserTelNo      string
+	Memo           string
+	PickupDateTime string
+	// lead time of business hour and food items are not checked
+	IgnoreLeadTime bool
+	StockItems     []CommonItemOrderCreateModel
+	FoodItems      []CommonItemOrderCreateModel
+}
+
+// guest checkout without account
+type OrderGuestCreateModel struct {
+	UserName       string
+	UserEmail      string
+	UserTelNo      string
+	Memo           string
+	PickupDateTime string
+	StockItems     []CommonItemOrderCreateModel
+	FoodItems      []CommonItemOrderCreateModel
+}
+
+// token is used to access guest order instead of login
+type OrderGuestCreatedModel struct {
+	Id    string
+	Token string
+}
+
+type OrderGuestCancelModel struct {
+	Token  string
+	Reason string
+}
+
 type OrderUserInfoUpdateModel struct {
 	OrderId   string
 	UserId    string
@@ -126,22 +199,45 @@ func NewOrderUserInfoUpdateModel(orderId, userId, userName, userEmail, userTelNo
 	}
 }
 
+// items and pick up time are replaced with new one
+type OrderAmendModel struct {
+	OrderId        string
+	PickupDateTime string
+	StockItems     []CommonItemOrderCreateModel
+	FoodItems      []CommonItemOrderCreateModel
+}
+
+type OrderCancelModel struct {
+	OrderId string
+	// optional for user. required when admin cancels other user's order
+	Reason string
+}
+
+func NewOrderCancelModel(orderId, reason string) *OrderCancelModel {
+	return &OrderCancelModel{
+		OrderId: orderId,
+		Reason:  reason,
+	}
+}
+
 type CommonItemOrderCreateModel struct {
 	ItemId   string
 	Quantity int
 	Options  []OptionItemOrderCreateModel
 }
 
-func (c *CommonItemOrderCreateModel) toOptionIds() []string {
-	ids := []string{}
+func (c *CommonItemOrderCreateModel) toOptionOrders() []domains.OptionOrder {
+	options := []domains.OptionOrder{}
 	for _, opt := range c.Options {
-		ids = append(ids, opt.ItemId)
+		options = append(options, *domains.NewOptionOrder(opt.ItemId, opt.Quantity))
 	}
-	return ids
+	return options
 }
 
 type OptionItemOrderCreateModel struct {
 	ItemId string
+	// 0 means one
+	Quantity int
 }
 
 func newCommonItemOrderCreateModel(itemId string, quantity int, options []string) *CommonItemOrderCreateModel {
@@ -164,47 +260,82 @@ type OrderInfoUseCase interface {
 	FindActiveByUserId(userId string) ([]OrderInfoModel, error)
 	FindActiveByPickupDate(dateStr string) ([]OrderInfoModel, error)
 	Create(model *OrderInfoCreateModel) (string, error)
+	CreateProxy(model *OrderProxyCreateModel) (string, error)
+	CreateGuest(model *OrderGuestCreateModel) (*OrderGuestCreatedModel, error)
+	FindByGuestToken(token string) (*OrderInfoModel, error)
+	CancelByGuestToken(model *OrderGuestCancelModel) error
+	// guest orders of verified email of user are linked to the user
+	LinkGuestOrders() (int, error)
 	UpdateUserInfo(model *OrderUserInfoUpdateModel) error
-	Cancel(id string) error
+	Cancel(model *OrderCancelModel) error
+	Amend(model *OrderAmendModel) error
+	FindRevisions(orderId string) ([]OrderRevisionModel, error)
 }
 
 type orderInfoUseCase struct {
 	*usecase.BaseUseCase
 	orderInfoRepository   domains.OrderInfoRepository
+	revisionRepository    domains.OrderRevisionRepository
 	stockRepo             idomains.StockItemRepository
 	busRepo               sdomains.BusinessHoursRepository
 	spBusRepo             sdomains.SpecialBusinessHourRepository
 	spHolidayRepo         sdomains.SpecialHolidayRepository
 	factory               domains.OrderInfoFactory
 	stockConsumer         domains.StockItemRemainCheckAndConsumer
+	ingredientConsumer    domains.IngredientConsumer
 	foodRemainChecker     domains.FoodItemRemainChecker
-	orderDuplicateChecker domains.OrderDuplicateChecker
+	orderLimitChecker     domains.OrderLimitChecker
+	contactLimitChecker   domains.OrderContactLimitChecker
+	alertChecker          domains.ItemAlertChecker
 	mailerService         SendOrderMailService
+	policy                domains.OrderTimePolicy
+	guestSigner           *domains.GuestAccessTokenSigner
+	clock                 common.Clock
 }
 
 func NewOrderInfoUseCase(
 	orderInfoRepository domains.OrderInfoRepository,
+	revisionRepository domains.OrderRevisionRepository,
+	limitRepository domains.OrderLimitRepository,
 	stockRepo idomains.StockItemRepository,
+	batchRepo idomains.StockBatchRepository,
+	movementRepo idomains.StockMovementRepository,
+	alertRepo idomains.ItemAlertRepository,
+	ingredientRepo idomains.IngredientRepository,
+	recipeRepo idomains.RecipeRepository,
+	usageRepo idomains.IngredientUsageRepository,
 	foodRepo idomains.FoodItemRepository,
+	quotaRepo idomains.FoodQuotaRepository,
 	kindRepo idomains.ItemKindRepository,
 	optionRepo idomains.OptionItemRepository,
 	busRepo sdomains.BusinessHoursRepository,
 	spBusRepo sdomains.SpecialBusinessHourRepository,
 	spHolidayRepo sdomains.SpecialHolidayRepository,
 	mailerService SendOrderMailService,
+	policy domains.OrderTimePolicy,
+	// nil means guest order is disabled
+	guestSigner *domains.GuestAccessTokenSigner,
+	clock common.Clock,
 ) OrderInfoUseCase {
 	return &orderInfoUseCase{
 		BaseUseCase:           usecase.NewBaseUseCase(),
 		orderInfoRepository:   orderInfoRepository,
+		revisionRepository:    revisionRepository,
 		stockRepo:             stockRepo,
 		busRepo:               busRepo,
 		spBusRepo:             spBusRepo,
 		spHolidayRepo:         spHolidayRepo,
-		factory:               *domains.NewOrderInfoFactory(stockRepo, foodRepo, kindRepo, optionRepo),
-		stockConsumer:         *domains.NewStockItemRemainCheckAndConsumer(stockRepo),
-		foodRemainChecker:     *domains.NewFoodItemRemainChecker(orderInfoRepository, foodRepo),
-		orderDuplicateChecker: *domains.NewOrderDuplicateChecker(orderInfoRepository),
+		factory:               *domains.NewOrderInfoFactory(stockRepo, foodRepo, kindRepo, optionRepo, busRepo, spBusRepo, spHolidayRepo, policy, clock),
+		stockConsumer:         *domains.NewStockItemRemainCheckAndConsumer(stockRepo, batchRepo, movementRepo, clock),
+		ingredientConsumer:    *domains.NewIngredientConsumer(ingredientRepo, recipeRepo, usageRepo),
+		foodRemainChecker:     *domains.NewFoodItemRemainChecker(orderInfoRepository, foodRepo, quotaRepo, busRepo, spBusRepo, spHolidayRepo),
+		orderLimitChecker:     *domains.NewOrderLimitChecker(orderInfoRepository, limitRepository, busRepo, spBusRepo, spHolidayRepo, clock),
+		contactLimitChecker:   *domains.NewOrderContactLimitChecker(orderInfoRepository, common.GetConfig().Order.MaxDailyOrdersPerContact),
+		alertChecker:          *domains.NewItemAlertChecker(stockRepo, batchRepo, movementRepo, orderInfoRepository, foodRepo, quotaRepo, busRepo, spBusRepo, spHolidayRepo, alertRepo, clock),
 		mailerService:         mailerService,
+		policy:                policy,
+		guestSigner:           guestSigner,
+		clock:                 clock,
 	}
 }
 
@@ -252,7 +383,7 @@ func (o *orderInfoUseCase) FindByUserId(userId string) ([]OrderInfoModel, error)
 }
 
 func (o *orderInfoUseCase) FindActiveByUserId(userId string) ([]OrderInfoModel, error) {
-	userOrders, err := o.orderInfoRepository.FindActiveByUserId(userId)
+	userOrders, err := o.orderInfoRepository.FindActiveByUserId(userId, o.clock.Now())
 	if err != nil {
 		return nil, err
 	}
@@ -268,7 +399,7 @@ func (o *orderInfoUseCase) FindActiveByUserId(userId string) ([]OrderInfoModel,
 
 func (o *orderInfoUseCase) FindActiveByPickupDate(dateStr string) ([]OrderInfoModel, error) {
 	// empty treats now
-	targetDate := common.GetNowDate()
+	targetDate := common.GetDateUntilMinutes(o.clock.Now())
 	if dateStr != "" {
 		converted, err := common.ConvertHyphenStrToDate(dateStr)
 		if err != nil {
@@ -292,35 +423,135 @@ func (o *orderInfoUseCase) FindActiveByPickupDate(dateStr string) ([]OrderInfoMo
 }
 
 func (o *orderInfoUseCase) Create(model *OrderInfoCreateModel) (string, error) {
-	// todo: currently food item schedule id and pickup date time relation is not checking
+	stockOrders := []domains.ItemOrder{}
+	for _, item := range model.StockItems {
+		stockOrders = append(stockOrders, *domains.NewItemOrder(item.ItemId, item.Quantity, item.toOptionOrders()))
+	}
+	foodOrders := []domains.ItemOrder{}
+	for _, item := range model.FoodItems {
+		foodOrders = append(foodOrders, *domains.NewItemOrder(item.ItemId, item.Quantity, item.toOptionOrders()))
+	}
+	// factory check each item id existence also (will return error)
+	// factory check pickup date time is past or not, schedule and lead time of business hour and food items
+	order, err := o.factory.Create(model.UserId, model.UserName, model.UserEmail, model.UserTelNo, model.Memo, model.PickupDateTime, stockOrders, foodOrders)
+	if err != nil {
+		return "", err
+	}
+	return o.create(order)
+}
 
-	// if not admin, can not reserve 2 times.
+func (o *orderInfoUseCase) CreateProxy(model *OrderProxyCreateModel) (string, error) {
 	if !o.IsAdmin() {
-		fmt.Println("not admin. checking order duplicated...")
-		duplicated, err := o.orderDuplicateChecker.ActiveOrderExists(model.UserId)
-		if err != nil {
-			return "", err
-		}
-		if duplicated {
-			return "", common.NewValidationError("UserId", "active order is already exists")
-		}
+		return "", common.NewValidationError("UserID", "only admin can create proxy order")
 	}
-
 	stockOrders := []domains.ItemOrder{}
 	for _, item := range model.StockItems {
-		stockOrders = append(stockOrders, *domains.NewItemOrder(item.ItemId, item.Quantity, item.toOptionIds()))
+		stockOrders = append(stockOrders, *domains.NewItemOrder(item.ItemId, item.Quantity, item.toOptionOrders()))
 	}
 	foodOrders := []domains.ItemOrder{}
 	for _, item := range model.FoodItems {
-		foodOrders = append(foodOrders, *domains.NewItemOrder(item.ItemId, item.Quantity, item.toOptionIds()))
+		foodOrders = append(foodOrders, *domains.NewItemOrder(item.ItemId, item.Quantity, item.toOptionOrders()))
 	}
-	// factory check each item id existence also (will return error)
-	// factory check pickup date time is past or not
-	order, err := o.factory.Create(model.UserId, model.UserName, model.UserEmail, model.UserTelNo, model.Memo, model.PickupDateTime, stockOrders, foodOrders)
+	order, err := o.factory.CreateProxy(model.Source, model.UserName, model.UserEmail, model.UserTelNo, model.Memo, model.PickupDateTime, stockOrders, foodOrders, model.IgnoreLeadTime)
 	if err != nil {
 		return "", err
 	}
+	return o.create(order)
+}
+
+func (o *orderInfoUseCase) CreateGuest(model *OrderGuestCreateModel) (*OrderGuestCreatedModel, error) {
+	if o.guestSigner == nil {
+		return nil, common.NewValidationError("UserID", "guest order is not available")
+	}
+	stockOrders := []domains.ItemOrder{}
+	for _, item := range model.StockItems {
+		stockOrders = append(stockOrders, *domains.NewItemOrder(item.ItemId, item.Quantity, item.toOptionOrders()))
+	}
+	foodOrders := []domains.ItemOrder{}
+	for _, item := range model.FoodItems {
+		foodOrders = append(foodOrders, *domains.NewItemOrder(item.ItemId, item.Quantity, item.toOptionOrders()))
+	}
+	order, err := o.factory.CreateGuest(model.UserName, model.UserEmail, model.UserTelNo, model.Memo, model.PickupDateTime, stockOrders, foodOrders)
+	if err != nil {
+		return nil, err
+	}
+	id, err := o.create(order)
+	if err != nil {
+		return nil, err
+	}
+	return &OrderGuestCreatedModel{Id: id, Token: o.guestSigner.Sign(order)}, nil
+}
+
+func (o *orderInfoUseCase) FindByGuestToken(token string) (*OrderInfoModel, error) {
+	order, err := o.findByGuestToken(token)
+	if err != nil {
+		return nil, err
+	}
+	return newOrderInfoModel(order), nil
+}
+
+// guest can cancel until cutoff as same as user
+func (o *orderInfoUseCase) CancelByGuestToken(model *OrderGuestCancelModel) error {
+	order, err := o.findByGuestToken(model.Token)
+	if err != nil {
+		return err
+	}
+	err = o.policy.CheckCancelable(order, o.clock.Now())
+	if err != nil {
+		return err
+	}
+	return o.cancel(order, model.Reason, false)
+}
+
+func (o *orderInfoUseCase) findByGuestToken(token string) (*domains.OrderInfo, error) {
+	if o.guestSigner == nil {
+		return nil, common.NewValidationError("token", "guest order is not available")
+	}
+	orderId, err := o.guestSigner.Verify(token, o.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+	order, err := o.orderInfoRepository.Find(orderId)
+	if err != nil {
+		return nil, err
+	}
+	// linked order should be accessed by login
+	if order == nil || !order.IsGuest() {
+		return nil, common.NewNotFoundError(orderId)
+	}
+	return order, nil
+}
+
+func (o *orderInfoUseCase) LinkGuestOrders() (int, error) {
+	email := o.GetUserEmail()
+	if email == "" {
+		return 0, common.NewValidationError("UserEmail", "verified email is required to link guest orders")
+	}
+	guestOrders, err := o.orderInfoRepository.FindByUserId(domains.GuestUserId)
+	if err != nil {
+		return 0, err
+	}
+	linked := 0
+	for i := range guestOrders {
+		order := &guestOrders[i]
+		if !order.HasEmail() || !strings.EqualFold(order.GetUserEmail(), email) {
+			continue
+		}
+		err = order.LinkUser(o.GetUserId(), email)
+		if err != nil {
+			return linked, err
+		}
+		err = o.orderInfoRepository.UpdateOwner(order)
+		if err != nil {
+			return linked, err
+		}
+		linked++
+	}
+	return linked, nil
+}
 
+func (o *orderInfoUseCase) create(order *domains.OrderInfo) (string, error) {
+	remains := o.snapshotStockRemains()
 	var gError error = nil
 	var id = ""
 	o.orderInfoRepository.Transact(func() error {
@@ -341,7 +572,7 @@ func (o *orderInfoUseCase) Create(model *OrderInfoCreateModel) (string, error) {
 		}
 		// check pickup time is in store business time
 		holidaySpec := sdomains.NewHolidaySpecification(*schedules, spSchedules, spHoliday)
-		isInBusiness, err := holidaySpec.IsStoreInBusiness(model.PickupDateTime)
+		isInBusiness, err := holidaySpec.IsStoreInBusiness(order.GetPickupDateTime())
 		if err != nil {
 			gError = err
 			return err
@@ -352,14 +583,35 @@ func (o *orderInfoUseCase) Create(model *OrderInfoCreateModel) (string, error) {
 			return gError
 		}
 
+		// if not admin, active orders are limited by policy per user, pickup date and business hour
+		// and orders of same email or tel no are limited per day
+		if !o.IsAdmin() {
+			err = o.orderLimitChecker.CheckLimit(order)
+			if err != nil {
+				gError = err
+				return err
+			}
+			err = o.contactLimitChecker.CheckDailyLimit(order)
+			if err != nil {
+				gError = err
+				return err
+			}
+		}
+
 		// check and update stock remain
-		err = o.stockConsumer.ConsumeRemainStock(stockOrders)
+		err = o.stockConsumer.ConsumeRemainStock(order)
+		if err != nil {
+			gError = err
+			return err
+		}
+		// check and update ingredients shared by items
+		err = o.ingredientConsumer.ConsumeIngredients(order)
 		if err != nil {
 			gError = err
 			return err
 		}
 		// check food remain
-		err = o.foodRemainChecker.CheckRemain(order.GetPickupDate(), order.GetFoodItems())
+		err = o.foodRemainChecker.CheckRemain(order.GetPickupDateTime(), order.GetFoodItems())
 		if err != nil {
 			gError = err
 			return err
@@ -382,21 +634,48 @@ func (o *orderInfoUseCase) Create(model *OrderInfoCreateModel) (string, error) {
 	if mError != nil {
 		fmt.Printf("mail send error.%s", mError)
 	}
+	o.notifyItemAlerts(remains, order)
 
 	return id, nil
 }
 
-func (o *orderInfoUseCase) Cancel(id string) error {
-	order, err := o.orderInfoRepository.Find(id)
+func (o *orderInfoUseCase) Cancel(model *OrderCancelModel) error {
+	order, err := o.orderInfoRepository.Find(model.OrderId)
 	if err != nil {
 		return err
 	}
 	if order == nil {
-		return common.NewUpdateTargetNotFoundError(id)
+		return common.NewUpdateTargetNotFoundError(model.OrderId)
+	}
+	// user can cancel only own order until cutoff
+	// admin can cancel any order after cutoff, reason is needed to notify customer
+	byAdmin := o.IsAdmin()
+	if !byAdmin {
+		if !order.IsOwnedBy(o.GetUserId()) {
+			return common.NewValidationError("UserID", "UserId is invalid. not match authorized user.")
+		}
+		err = o.policy.CheckCancelable(order, o.clock.Now())
+		if err != nil {
+			return err
+		}
+	} else if !order.IsOwnedBy(o.GetUserId()) && strings.TrimSpace(model.Reason) == "" {
+		return common.NewValidationError("Reason", "required when admin cancels other user's order")
+	}
+	return o.cancel(order, model.Reason, byAdmin)
+}
+
+func (o *orderInfoUseCase) cancel(order *domains.OrderInfo, reason string, byAdmin bool) error {
+	err := order.SetCancel(reason)
+	if err != nil {
+		return err
 	}
-	order.SetCancel()
+	remains := o.snapshotStockRemains()
 	// increment stock
-	err = o.stockConsumer.IncrementCanceledRemain(order.GetStockItems())
+	err = o.stockConsumer.IncrementCanceledRemain(order)
+	if err != nil {
+		return err
+	}
+	err = o.ingredientConsumer.RestoreCanceledIngredients(order)
 	if err != nil {
 		return err
 	}
@@ -405,14 +684,123 @@ func (o *orderInfoUseCase) Cancel(id string) error {
 		return upErr
 	}
 
-	mError := o.sendCancelMail(order)
+	mError := o.sendCancelMail(order, byAdmin)
+	// mail error not treats as error only displaying as info
+	if mError != nil {
+		fmt.Printf("mail send error.%s", mError)
+	}
+	if remains != nil {
+		alerts, err := o.alertChecker.CheckStock(remains)
+		o.sendItemAlerts(alerts, err)
+	}
+	return nil
+}
+
+// amendment is allowed until cancel cutoff, and new contents are checked as same as new order
+func (o *orderInfoUseCase) Amend(model *OrderAmendModel) error {
+	order, err := o.orderInfoRepository.Find(model.OrderId)
+	if err != nil {
+		return err
+	}
+	if order == nil {
+		return common.NewUpdateTargetNotFoundError(model.OrderId)
+	}
+	if !o.IsAdmin() {
+		if !order.IsOwnedBy(o.GetUserId()) {
+			return common.NewValidationError("UserID", "UserId is invalid. not match authorized user.")
+		}
+		err = o.policy.CheckCancelable(order, o.clock.Now())
+		if err != nil {
+			return err
+		}
+	}
+	revisions, err := o.revisionRepository.FindByOrderId(order.GetId())
+	if err != nil {
+		return err
+	}
+	// keep contents before amended
+	revision, err := domains.NewOrderRevision(order, len(revisions)+1, o.clock.Now())
+	if err != nil {
+		return err
+	}
+
+	stockOrders := []domains.ItemOrder{}
+	for _, item := range model.StockItems {
+		stockOrders = append(stockOrders, *domains.NewItemOrder(item.ItemId, item.Quantity, item.toOptionOrders()))
+	}
+	foodOrders := []domains.ItemOrder{}
+	for _, item := range model.FoodItems {
+		foodOrders = append(foodOrders, *domains.NewItemOrder(item.ItemId, item.Quantity, item.toOptionOrders()))
+	}
+	// factory check each item existence, pickup date time is in business and lead time
+	err = o.factory.Amend(order, model.PickupDateTime, stockOrders, foodOrders)
+	if err != nil {
+		return err
+	}
+	// amended pickup date time can be over limit
+	if !o.IsAdmin() {
+		err = o.orderLimitChecker.CheckLimit(order)
+		if err != nil {
+			return err
+		}
+	}
+
+	remains := o.snapshotStockRemains()
+	err = o.orderInfoRepository.Transact(func() error {
+		// only difference of quantity is consumed or returned
+		err := o.stockConsumer.ApplyAmendedRemain(revision, order)
+		if err != nil {
+			return err
+		}
+		err = o.ingredientConsumer.ApplyAmendedIngredients(revision, order)
+		if err != nil {
+			return err
+		}
+		err = o.foodRemainChecker.CheckAmendedRemain(order)
+		if err != nil {
+			return err
+		}
+		err = o.orderInfoRepository.UpdateItems(order)
+		if err != nil {
+			return err
+		}
+		return o.revisionRepository.Create(revision)
+	})
+	if err != nil {
+		return err
+	}
+
+	mError := o.sendChangeMail(order, revision)
 	// mail error not treats as error only displaying as info
 	if mError != nil {
 		fmt.Printf("mail send error.%s", mError)
 	}
+	o.notifyItemAlerts(remains, order)
 	return nil
 }
 
+func (o *orderInfoUseCase) FindRevisions(orderId string) ([]OrderRevisionModel, error) {
+	order, err := o.orderInfoRepository.Find(orderId)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, common.NewNotFoundError(orderId)
+	}
+	if !o.IsAdmin() && !order.IsOwnedBy(o.GetUserId()) {
+		return nil, common.NewValidationError("UserID", "UserId is invalid. not match authorized user.")
+	}
+	revisions, err := o.revisionRepository.FindByOrderId(orderId)
+	if err != nil {
+		return nil, err
+	}
+	models := []OrderRevisionModel{}
+	for _, revision := range revisions {
+		models = append(models, *newOrderRevisionModel(&revision))
+	}
+	return models, nil
+}
+
 func (o *orderInfoUseCase) UpdateUserInfo(model *OrderUserInfoUpdateModel) error {
 	order, err := o.orderInfoRepository.Find(model.OrderId)
 	if err != nil {
@@ -440,20 +828,82 @@ func (o *orderInfoUseCase) UpdateUserInfo(model *OrderUserInfoUpdateModel) error
 	return nil
 }
 
+// mail is skipped for proxy order without email
 func (o *orderInfoUseCase) sendCompleteMail(order *domains.OrderInfo) error {
+	if !order.HasEmail() {
+		return nil
+	}
 	cfg := common.GetConfig().Mail
-	mailData, err := NewOrderCompleteMailData(order, cfg.From, cfg.Admin)
+	mailData, err := NewOrderCompleteMailData(order, o.guestAccessUrl(order), cfg.From, cfg.Admin)
 	if err != nil {
 		return err
 	}
 	return o.mailerService.SendComplete(*mailData)
 }
 
-func (o *orderInfoUseCase) sendCancelMail(order *domains.OrderInfo) error {
+func (o *orderInfoUseCase) sendChangeMail(order *domains.OrderInfo, before *domains.OrderRevision) error {
+	if !order.HasEmail() {
+		return nil
+	}
 	cfg := common.GetConfig().Mail
-	mailData, err := NewOrderCancelMailData(order, cfg.From, cfg.Admin)
+	mailData, err := NewOrderChangeMailData(order, before, cfg.From, cfg.Admin)
+	if err != nil {
+		return err
+	}
+	return o.mailerService.SendChange(*mailData)
+}
+
+func (o *orderInfoUseCase) sendCancelMail(order *domains.OrderInfo, byAdmin bool) error {
+	if !order.HasEmail() {
+		return nil
+	}
+	cfg := common.GetConfig().Mail
+	mailData, err := NewOrderCancelMailData(order, byAdmin, cfg.From, cfg.Admin)
 	if err != nil {
 		return err
 	}
 	return o.mailerService.SendCancel(*mailData)
 }
+
+// alert error not treats as error of order only displaying as info, and stock alerts are skipped
+func (o *orderInfoUseCase) snapshotStockRemains() map[string]int {
+	remains, err := o.alertChecker.SnapshotStockRemains()
+	if err != nil {
+		fmt.Printf("item alert error.%s", err)
+		return nil
+	}
+	return remains
+}
+
+// alert is notified to admin once per item and date.
+// alert error not treats as error of order only displaying as info
+func (o *orderInfoUseCase) notifyItemAlerts(remains map[string]int, order *domains.OrderInfo) {
+	o.sendItemAlerts(o.alertChecker.Check(remains, order))
+}
+
+func (o *orderInfoUseCase) sendItemAlerts(alerts []idomains.ItemAlert, err error) {
+	if err != nil {
+		fmt.Printf("item alert error.%s", err)
+		return
+	}
+	cfg := common.GetConfig().Mail
+	for i := range alerts {
+		mailData, err := NewItemAlertMailData(&alerts[i], cfg.From, cfg.Admin)
+		if err != nil {
+			fmt.Printf("mail send error.%s", err)
+			continue
+		}
+		err = o.mailerService.SendItemAlert(*mailData)
+		if err != nil {
+			fmt.Printf("mail send error.%s", err)
+		}
+	}
+}
+
+// empty for order of user account
+func (o *orderInfoUseCase) guestAccessUrl(order *domains.OrderInfo) string {
+	if !order.IsGuest() || o.guestSigner == nil {
+		return ""
+	}
+	return common.GetConfig().Order.GuestAccessUrl + o.guestSigner.Sign(order)
+}
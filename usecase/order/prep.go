@@ -0,0 +1,151 @@
+package order
+
+import (
+	"chico/takeout/common"
+	domains "chico/takeout/domains/order"
+	storeDomains "chico/takeout/domains/store"
+)
+
+type PrepOptionModel struct {
+	OptionId string
+	Name     string
+	Quantity int
+}
+
+type PrepItemModel struct {
+	ItemId   string
+	ItemType string
+	Name     string
+	Quantity int
+	Options  []PrepOptionModel
+}
+
+type PrepMemoModel struct {
+	PickupDateTime string
+	UserName       string
+	Memo           string
+}
+
+type PrepListModel struct {
+	Date       string
+	HourTypeId string
+	HourName   string
+	StartTime  string
+	EndTime    string
+	OrderCount int
+	Items      []PrepItemModel
+	Memos      []PrepMemoModel
+}
+
+func newPrepListModel(prep *domains.PrepList, date string, hour *storeDomains.HourInfo) *PrepListModel {
+	items := []PrepItemModel{}
+	for _, item := range prep.GetItems() {
+		options := []PrepOptionModel{}
+		for _, opt := range item.GetOptions() {
+			options = append(options, PrepOptionModel{OptionId: opt.GetOptionId(), Name: opt.GetName(), Quantity: opt.GetQuantity()})
+		}
+		items = append(items, PrepItemModel{
+			ItemId:   item.GetItemId(),
+			ItemType: item.GetItemType(),
+			Name:     item.GetName(),
+			Quantity: item.GetQuantity(),
+			Options:  options,
+		})
+	}
+	memos := []PrepMemoModel{}
+	for _, memo := range prep.GetMemos() {
+		memos = append(memos, PrepMemoModel{PickupDateTime: memo.GetPickupDateTime(), UserName: memo.GetUserName(), Memo: memo.GetMemo()})
+	}
+	return &PrepListModel{
+		Date:       date,
+		HourTypeId: hour.HourTypeId,
+		HourName:   hour.Name,
+		StartTime:  hour.StartTime,
+		EndTime:    hour.EndTime,
+		OrderCount: prep.GetOrderCount(),
+		Items:      items,
+		Memos:      memos,
+	}
+}
+
+type PrepReportUseCase interface {
+	// date is yyyy/MM/dd, hour is business hour (or special business hour) of the date
+	FindPrepList(date, hourId string) (*PrepListModel, error)
+	// prep list is sent to admin
+	SendPrepList(date, hourId string) error
+}
+
+type prepReportUseCase struct {
+	filter        domains.OrderFilter
+	mngService    storeDomains.BusinessHourManagementService
+	mailerService SendOrderMailService
+}
+
+func NewPrepReportUseCase(
+	orderRepos domains.OrderInfoRepository,
+	mailerService SendOrderMailService,
+	businessHoursRepository storeDomains.BusinessHoursRepository,
+	specialHolidayRepository storeDomains.SpecialHolidayRepository,
+	specialBusinessHourRepository storeDomains.SpecialBusinessHourRepository) PrepReportUseCase {
+	return &prepReportUseCase{
+		filter:        *domains.NewOrderFilter(orderRepos),
+		mngService:    *storeDomains.NewBusinessHourManagementService(businessHoursRepository, specialHolidayRepository, specialBusinessHourRepository),
+		mailerService: mailerService,
+	}
+}
+
+func (p *prepReportUseCase) FindPrepList(date, hourId string) (*PrepListModel, error) {
+	prep, hour, err := p.findPrepList(date, hourId)
+	if err != nil {
+		return nil, err
+	}
+	return newPrepListModel(prep, date, hour), nil
+}
+
+func (p *prepReportUseCase) SendPrepList(date, hourId string) error {
+	prep, hour, err := p.findPrepList(date, hourId)
+	if err != nil {
+		return err
+	}
+	cfg := common.GetConfig().Mail
+	mailData, err := NewHourPrepListMailData(prep, cfg.From, cfg.Admin, date, hour.StartTime, hour.EndTime)
+	if err != nil {
+		return err
+	}
+	return p.mailerService.SendDailySummary(*mailData)
+}
+
+// only active orders in the hour are aggregated
+func (p *prepReportUseCase) findPrepList(date, hourId string) (*domains.PrepList, *storeDomains.HourInfo, error) {
+	targetDate, err := common.ConvertStrToDate(date)
+	if err != nil {
+		return nil, nil, common.NewValidationError("date", "format should be yyyy/MM/dd")
+	}
+	data, err := p.mngService.GetSpecificDateHour(*targetDate)
+	if err != nil {
+		return nil, nil, err
+	}
+	var hour *storeDomains.HourInfo
+	for i := range data.Hours {
+		if data.Hours[i].HourTypeId == hourId {
+			hour = &data.Hours[i]
+			break
+		}
+	}
+	if hour == nil {
+		return nil, nil, common.NewNotFoundError(hourId)
+	}
+	startTime, err := common.ConvertStrToTime(hour.StartTime)
+	if err != nil {
+		return nil, nil, err
+	}
+	endTime, err := common.ConvertStrToTime(hour.EndTime)
+	if err != nil {
+		return nil, nil, err
+	}
+	orders, err := p.filter.GetActiveOrderOfSpecifiedDayAndTime(*targetDate, *startTime, *endTime)
+	if err != nil {
+		return nil, nil, err
+	}
+	return domains.NewPrepList(orders), hour, nil
+}
@@ -0,0 +1,129 @@
+package order_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"chico/takeout/common"
+	domains "chico/takeout/domains/order"
+	"chico/takeout/infrastructures/memory"
+	"chico/takeout/usecase/order"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindPrepList(t *testing.T) {
+	setUpEnv(t)
+	useCase, _, morningId := setUpPrepUseCase()
+
+	prep, err := useCase.FindPrepList("2051/01/14", morningId)
+	assert.Nil(t, err)
+	assert.Equal(t, "2051/01/14", prep.Date)
+	assert.Equal(t, "07:00", prep.StartTime)
+	assert.Equal(t, "09:30", prep.EndTime)
+	// canceled order and lunch order are excluded
+	assert.Equal(t, 2, prep.OrderCount)
+	assert.Equal(t, 2, len(prep.Items))
+	assert.Equal(t, "prep_curry", prep.Items[0].Name)
+	assert.Equal(t, 3, prep.Items[0].Quantity)
+	assert.Equal(t, []order.PrepOptionModel{{OptionId: "prep_opt1", Name: "large", Quantity: 2}}, prep.Items[0].Options)
+	assert.Equal(t, "stock", prep.Items[1].ItemType)
+	assert.Equal(t, 2, prep.Items[1].Quantity)
+	assert.Equal(t, []order.PrepMemoModel{{PickupDateTime: "2051/01/14 07:30", UserName: "ユーザー2", Memo: "no onion"}}, prep.Memos)
+}
+
+func TestFindPrepList_NotFound(t *testing.T) {
+	setUpEnv(t)
+	useCase, _, morningId := setUpPrepUseCase()
+
+	_, err := useCase.FindPrepList("2051/01/14", "unknown")
+	_, ok := err.(*common.NotFoundError)
+	assert.True(t, ok)
+
+	_, err = useCase.FindPrepList("2051-01-14", morningId)
+	_, ok = err.(*common.ValidationError)
+	assert.True(t, ok)
+}
+
+func TestSendPrepList(t *testing.T) {
+	setUpEnv(t)
+	useCase, mail, morningId := setUpPrepUseCase()
+
+	err := useCase.SendPrepList("2051/01/14", morningId)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(mail.Sent))
+	assert.Equal(t, []string{"admin@dummy.co.jp"}, mail.Sent[0].SendTo)
+	assert.Equal(t, "仕込みリスト(2051/01/14 07:00 ~ 09:30)", mail.Sent[0].Title)
+	assert.True(t, strings.Contains(mail.Sent[0].Message, "prep_curry, 3個"))
+	assert.True(t, strings.Contains(mail.Sent[0].Message, "no onion"))
+}
+
+func setUpPrepUseCase() (order.PrepReportUseCase, *memory.MemorySendOrderMail, string) {
+	repo := memory.NewOrderInfoMemoryRepository()
+	createPrepOrders(repo.GetMemory())
+
+	businessHourRepo := memory.NewBusinessHoursMemoryRepository()
+	schedules := businessHourRepo.GetMemory().GetSchedules()
+
+	mail := memory.NewMemorySendOrderMail()
+	useCase := order.NewPrepReportUseCase(repo, mail, businessHourRepo, memory.NewSpecialHolidayMemoryRepository(), memory.NewSpecialBusinessHourMemoryRepository())
+
+	return useCase, mail, schedules[0].GetId()
+}
+
+func createPrepOrders(orders map[string]*domains.OrderInfo) {
+
+	large, err := domains.NewOptionItemInfo("prep_opt1", "large", 100, 1)
+	if err != nil {
+		fmt.Println(err)
+		panic("failed to create option")
+	}
+	curry1, err := domains.NewOrderFoodItem("prep_food1", "prep_curry", 800, 1, []domains.OptionItemInfo{})
+	if err != nil {
+		fmt.Println(err)
+		panic("failed to create food order")
+	}
+	curry2, err := domains.NewOrderFoodItem("prep_food1", "prep_curry", 800, 2, []domains.OptionItemInfo{*large})
+	if err != nil {
+		fmt.Println(err)
+		panic("failed to create food order")
+	}
+	drink, err := domains.NewOrderStockItem("prep_stock1", "prep_drink", 300, 2, []domains.OptionItemInfo{})
+	if err != nil {
+		fmt.Println(err)
+		panic("failed to create stock order")
+	}
+
+	// morning
+	order1, err := domains.NewOrderInfo("user2", "ユーザー2", "user2@hoge.com", "123456789", "no onion", "2051/01/14 7:30", []domains.OrderStockItem{*drink}, []domains.OrderFoodItem{*curry1}, orderedAt)
+	if err != nil {
+		fmt.Println(err)
+		panic("failed to create order")
+	}
+	orders[order1.GetId()] = order1
+
+	order2, err := domains.NewOrderInfo("user3", "ユーザー3", "user3@hoge.com", "123456789", "", "2051/01/14 9:00", []domains.OrderStockItem{}, []domains.OrderFoodItem{*curry2}, orderedAt)
+	if err != nil {
+		fmt.Println(err)
+		panic("failed to create order")
+	}
+	orders[order2.GetId()] = order2
+
+	// morning and cancel
+	order3, err := domains.NewOrderInfo("user4", "ユーザー4", "user4@hoge.com", "123456789", "canceled", "2051/01/14 8:00", []domains.OrderStockItem{}, []domains.OrderFoodItem{*curry2}, orderedAt)
+	if err != nil {
+		fmt.Println(err)
+		panic("failed to create order")
+	}
+	order3.SetCancel("")
+	orders[order3.GetId()] = order3
+
+	// lunch
+	order4, err := domains.NewOrderInfo("user5", "ユーザー5", "user5@hoge.com", "123456789", "lunch", "2051/01/14 12:00", []domains.OrderStockItem{}, []domains.OrderFoodItem{*curry1}, orderedAt)
+	if err != nil {
+		fmt.Println(err)
+		panic("failed to create order")
+	}
+	orders[order4.GetId()] = order4
+}
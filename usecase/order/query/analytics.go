@@ -0,0 +1,468 @@
+package order
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"chico/takeout/common"
+	itemDomains "chico/takeout/domains/item"
+	orderDomains "chico/takeout/domains/order"
+	storeDomains "chico/takeout/domains/store"
+)
+
+type AnalyticsRequestModel struct {
+	// yyyy/MM/dd
+	Start string
+	End   string
+	// order (default) or pickup
+	Basis string
+}
+
+type AnalyticsData struct {
+	StartDate string
+	EndDate   string
+	Basis     string
+	Summary   AnalyticsSummary
+	Items     []ItemAnalytics
+	Kinds     []KindAnalytics
+	Options   []OptionAnalytics
+	Daily     []DailyAnalytics
+	Heatmap   []HeatmapAnalytics
+}
+
+// totals of active orders. option prices are included in money total
+type AnalyticsSummary struct {
+	OrderTotal    int
+	CanceledTotal int
+	QuantityTotal int
+	MoneyTotal    int
+	// money total / order total
+	AverageOrderValue float64
+	// canceled / (active + canceled)
+	CancellationRate float64
+	// customers are identified by user id, or email (tel) for guest
+	CustomerTotal int
+	// customers who ordered 2 times or more in the range
+	RepeatCustomerTotal int
+	RepeatCustomerRatio float64
+}
+
+type ItemAnalytics struct {
+	ItemId   string
+	ItemType string
+	Name     string
+	// empty if item is already deleted
+	KindId     string
+	Quantity   int
+	MoneyTotal int
+}
+
+type KindAnalytics struct {
+	KindId     string
+	Name       string
+	Quantity   int
+	MoneyTotal int
+}
+
+// quantity is option quantity x item quantity
+type OptionAnalytics struct {
+	OptionId   string
+	Name       string
+	Quantity   int
+	MoneyTotal int
+}
+
+type DailyAnalytics struct {
+	Date          string
+	OrderTotal    int
+	CanceledTotal int
+	QuantityTotal int
+	MoneyTotal    int
+}
+
+// grouped by weekday and business hour of pickup date time. empty hour means out of business hours
+type HeatmapAnalytics struct {
+	// 0:Sunday ~ 6:Saturday
+	Weekday       int
+	HourTypeId    string
+	HourName      string
+	StartTime     string
+	OrderTotal    int
+	QuantityTotal int
+	MoneyTotal    int
+}
+
+type OrderAnalyticsUseCase struct {
+	service         OrderExportQueryService
+	stockRepository itemDomains.StockItemRepository
+	foodRepository  itemDomains.FoodItemRepository
+	kindRepository  itemDomains.ItemKindRepository
+	hourManagement  storeDomains.BusinessHourManagementService
+}
+
+func NewOrderAnalyticsUseCase(queryService OrderExportQueryService,
+	stockRepository itemDomains.StockItemRepository,
+	foodRepository itemDomains.FoodItemRepository,
+	kindRepository itemDomains.ItemKindRepository,
+	businessHoursRepository storeDomains.BusinessHoursRepository,
+	specialHolidayRepository storeDomains.SpecialHolidayRepository,
+	specialBusinessHourRepository storeDomains.SpecialBusinessHourRepository) *OrderAnalyticsUseCase {
+	return &OrderAnalyticsUseCase{
+		service:         queryService,
+		stockRepository: stockRepository,
+		foodRepository:  foodRepository,
+		kindRepository:  kindRepository,
+		hourManagement:  *storeDomains.NewBusinessHourManagementService(businessHoursRepository, specialHolidayRepository, specialBusinessHourRepository),
+	}
+}
+
+func (o *OrderAnalyticsUseCase) FetchAnalytics(req AnalyticsRequestModel) (*AnalyticsData, error) {
+	start, end, err := convertExportDateRange(ExportRequestModel{Start: req.Start, End: req.End})
+	if err != nil {
+		return nil, err
+	}
+	// allow within 1 year
+	if end.After(start.AddDate(1, 0, 0)) {
+		return nil, common.NewValidationError("Start, End", fmt.Sprintf("start should within 1year from end. start:%s, end:%s", req.Start, req.End))
+	}
+	basis, err := NewDateBasis(req.Basis)
+	if err != nil {
+		return nil, err
+	}
+	kindIds, err := o.findItemKindIds()
+	if err != nil {
+		return nil, err
+	}
+	kinds, err := o.kindRepository.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	hours, err := o.newHourFinder(*start, *end)
+	if err != nil {
+		return nil, err
+	}
+
+	aggregator := newAnalyticsAggregator(basis, *start, *end, kindIds, kinds, hours)
+	err = o.service.FetchOrders(basis, *start, *end, aggregator.add)
+	if err != nil {
+		return nil, err
+	}
+	return aggregator.result()
+}
+
+// item id to kind id
+func (o *OrderAnalyticsUseCase) findItemKindIds() (map[string]string, error) {
+	kindIds := map[string]string{}
+	stocks, err := o.stockRepository.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	for _, stock := range stocks {
+		kindIds[stock.GetId()] = stock.GetKindId()
+	}
+	foods, err := o.foodRepository.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	for _, food := range foods {
+		kindIds[food.GetId()] = food.GetKindId()
+	}
+	return kindIds, nil
+}
+
+// hours of dates in the range are loaded at once. other dates (ex:pickup date of order basis) are loaded when needed
+func (o *OrderAnalyticsUseCase) newHourFinder(start, end time.Time) (*hourFinder, error) {
+	days, err := o.hourManagement.GetCalendar(start, end)
+	if err != nil {
+		return nil, err
+	}
+	finder := &hourFinder{
+		service: o.hourManagement,
+		hours:   map[string][]storeDomains.HourInfo{},
+	}
+	for _, day := range days {
+		finder.hours[day.Date] = day.Hours
+	}
+	return finder, nil
+}
+
+type hourFinder struct {
+	service storeDomains.BusinessHourManagementService
+	hours   map[string][]storeDomains.HourInfo
+}
+
+func (h *hourFinder) hoursOf(date time.Time) ([]storeDomains.HourInfo, error) {
+	dateStr := common.ConvertTimeToDateStr(date)
+	if hours, ok := h.hours[dateStr]; ok {
+		return hours, nil
+	}
+	info, err := h.service.GetSpecificDateHour(date)
+	if err != nil {
+		return nil, err
+	}
+	h.hours[dateStr] = info.Hours
+	return info.Hours, nil
+}
+
+// nil if the time is out of business hours.
+// weekday is the date of the hour (hour crossing midnight starts at previous date)
+func (h *hourFinder) find(dateTime time.Time) (*storeDomains.HourInfo, time.Weekday, error) {
+	target := common.ConvertTimeToTimeStr(dateTime)
+	hours, err := h.hoursOf(dateTime)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range hours {
+		hour := &hours[i]
+		if hour.EndTime < hour.StartTime {
+			if target >= hour.StartTime {
+				return hour, dateTime.Weekday(), nil
+			}
+			continue
+		}
+		if hour.StartTime <= target && target <= hour.EndTime {
+			return hour, dateTime.Weekday(), nil
+		}
+	}
+	previous := dateTime.AddDate(0, 0, -1)
+	hours, err = h.hoursOf(previous)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range hours {
+		hour := &hours[i]
+		if hour.EndTime < hour.StartTime && target <= hour.EndTime {
+			return hour, previous.Weekday(), nil
+		}
+	}
+	return nil, dateTime.Weekday(), nil
+}
+
+type analyticsAggregator struct {
+	basis     DateBasis
+	start     time.Time
+	end       time.Time
+	kindIds   map[string]string
+	kinds     []itemDomains.ItemKind
+	hours     *hourFinder
+	summary   AnalyticsSummary
+	items     map[string]*ItemAnalytics
+	options   map[string]*OptionAnalytics
+	daily     map[string]*DailyAnalytics
+	heatmap   map[string]*HeatmapAnalytics
+	customers map[string]int
+}
+
+func newAnalyticsAggregator(basis DateBasis, start, end time.Time, kindIds map[string]string, kinds []itemDomains.ItemKind, hours *hourFinder) *analyticsAggregator {
+	return &analyticsAggregator{
+		basis:     basis,
+		start:     start,
+		end:       end,
+		kindIds:   kindIds,
+		kinds:     kinds,
+		hours:     hours,
+		items:     map[string]*ItemAnalytics{},
+		options:   map[string]*OptionAnalytics{},
+		daily:     map[string]*DailyAnalytics{},
+		heatmap:   map[string]*HeatmapAnalytics{},
+		customers: map[string]int{},
+	}
+}
+
+func (a *analyticsAggregator) add(data ExportOrderData) error {
+	basisDateTime := data.OrderDateTime
+	if a.basis == DateBasisPickup {
+		basisDateTime = data.PickupDateTime
+	}
+	daily := a.dailyOf(common.ConvertTimeToDateStr(basisDateTime))
+	if data.Canceled {
+		a.summary.CanceledTotal++
+		daily.CanceledTotal++
+		return nil
+	}
+
+	quantity, money := 0, 0
+	for _, item := range data.Items {
+		optionPrice := 0
+		for _, opt := range item.Options {
+			optionPrice += opt.Price * opt.Quantity
+			option, ok := a.options[opt.Id]
+			if !ok {
+				option = &OptionAnalytics{OptionId: opt.Id, Name: opt.Name}
+				a.options[opt.Id] = option
+			}
+			option.Quantity += opt.Quantity * item.Quantity
+			option.MoneyTotal += opt.Price * opt.Quantity * item.Quantity
+		}
+		subtotal := (item.Price + optionPrice) * item.Quantity
+		analytics, ok := a.items[item.ItemId]
+		if !ok {
+			analytics = &ItemAnalytics{ItemId: item.ItemId, ItemType: item.ItemType, Name: item.Name, KindId: a.kindIds[item.ItemId]}
+			a.items[item.ItemId] = analytics
+		}
+		analytics.Quantity += item.Quantity
+		analytics.MoneyTotal += subtotal
+		quantity += item.Quantity
+		money += subtotal
+	}
+
+	a.summary.OrderTotal++
+	a.summary.QuantityTotal += quantity
+	a.summary.MoneyTotal += money
+	daily.OrderTotal++
+	daily.QuantityTotal += quantity
+	daily.MoneyTotal += money
+
+	hour, weekday, err := a.hours.find(data.PickupDateTime)
+	if err != nil {
+		return err
+	}
+	cell := &HeatmapAnalytics{Weekday: int(weekday)}
+	if hour != nil {
+		cell.HourTypeId = hour.HourTypeId
+		cell.HourName = hour.Name
+		cell.StartTime = hour.StartTime
+	}
+	key := fmt.Sprintf("%d_%s", cell.Weekday, cell.HourTypeId)
+	if found, ok := a.heatmap[key]; ok {
+		cell = found
+	} else {
+		a.heatmap[key] = cell
+	}
+	cell.OrderTotal++
+	cell.QuantityTotal += quantity
+	cell.MoneyTotal += money
+
+	if customer := customerKey(data); customer != "" {
+		a.customers[customer]++
+	}
+	return nil
+}
+
+func (a *analyticsAggregator) dailyOf(date string) *DailyAnalytics {
+	daily, ok := a.daily[date]
+	if !ok {
+		daily = &DailyAnalytics{Date: date}
+		a.daily[date] = daily
+	}
+	return daily
+}
+
+func (a *analyticsAggregator) result() (*AnalyticsData, error) {
+	summary := a.summary
+	if summary.OrderTotal > 0 {
+		summary.AverageOrderValue = roundRate(float64(summary.MoneyTotal) / float64(summary.OrderTotal))
+	}
+	if all := summary.OrderTotal + summary.CanceledTotal; all > 0 {
+		summary.CancellationRate = roundRate(float64(summary.CanceledTotal) / float64(all))
+	}
+	summary.CustomerTotal = len(a.customers)
+	for _, count := range a.customers {
+		if count > 1 {
+			summary.RepeatCustomerTotal++
+		}
+	}
+	if summary.CustomerTotal > 0 {
+		summary.RepeatCustomerRatio = roundRate(float64(summary.RepeatCustomerTotal) / float64(summary.CustomerTotal))
+	}
+
+	items := []ItemAnalytics{}
+	kinds := map[string]*KindAnalytics{}
+	for _, item := range a.items {
+		items = append(items, *item)
+		kind, ok := kinds[item.KindId]
+		if !ok {
+			kind = &KindAnalytics{KindId: item.KindId}
+			for _, k := range a.kinds {
+				if k.GetId() == item.KindId {
+					kind.Name = k.GetName()
+				}
+			}
+			kinds[item.KindId] = kind
+		}
+		kind.Quantity += item.Quantity
+		kind.MoneyTotal += item.MoneyTotal
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].MoneyTotal != items[j].MoneyTotal {
+			return items[i].MoneyTotal > items[j].MoneyTotal
+		}
+		return items[i].Name < items[j].Name
+	})
+	kindList := []KindAnalytics{}
+	for _, kind := range kinds {
+		kindList = append(kindList, *kind)
+	}
+	sort.Slice(kindList, func(i, j int) bool {
+		if kindList[i].MoneyTotal != kindList[j].MoneyTotal {
+			return kindList[i].MoneyTotal > kindList[j].MoneyTotal
+		}
+		return kindList[i].Name < kindList[j].Name
+	})
+	options := []OptionAnalytics{}
+	for _, option := range a.options {
+		options = append(options, *option)
+	}
+	sort.Slice(options, func(i, j int) bool {
+		if options[i].Quantity != options[j].Quantity {
+			return options[i].Quantity > options[j].Quantity
+		}
+		return options[i].Name < options[j].Name
+	})
+
+	// dates without order are included as 0
+	daily := []DailyAnalytics{}
+	dates, err := common.ListUpDates(a.start, a.end, common.GetStoreLocation())
+	if err != nil {
+		return nil, err
+	}
+	for _, date := range dates {
+		dateStr := common.ConvertTimeToDateStr(date)
+		if d, ok := a.daily[dateStr]; ok {
+			daily = append(daily, *d)
+		} else {
+			daily = append(daily, DailyAnalytics{Date: dateStr})
+		}
+	}
+
+	heatmap := []HeatmapAnalytics{}
+	for _, cell := range a.heatmap {
+		heatmap = append(heatmap, *cell)
+	}
+	sort.Slice(heatmap, func(i, j int) bool {
+		if heatmap[i].Weekday != heatmap[j].Weekday {
+			return heatmap[i].Weekday < heatmap[j].Weekday
+		}
+		return heatmap[i].StartTime < heatmap[j].StartTime
+	})
+
+	return &AnalyticsData{
+		StartDate: common.ConvertTimeToDateStr(a.start),
+		EndDate:   common.ConvertTimeToDateStr(a.end),
+		Basis:     string(a.basis),
+		Summary:   summary,
+		Items:     items,
+		Kinds:     kindList,
+		Options:   options,
+		Daily:     daily,
+		Heatmap:   heatmap,
+	}, nil
+}
+
+// empty if the customer can not be identified
+func customerKey(data ExportOrderData) string {
+	if data.UserId != "" && data.UserId != orderDomains.GuestUserId {
+		return data.UserId
+	}
+	if data.UserEmail != "" {
+		return strings.ToLower(data.UserEmail)
+	}
+	return data.UserTelNo
+}
+
+func roundRate(value float64) float64 {
+	return math.Round(value*10000) / 10000
+}
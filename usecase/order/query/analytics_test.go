@@ -0,0 +1,121 @@
+package order
+
+import (
+	"testing"
+
+	"chico/takeout/common"
+	"chico/takeout/infrastructures/memory"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFetchAnalytics(t *testing.T) {
+	stockRepo := memory.NewStockItemMemoryRepository()
+	stocks, _ := stockRepo.FindAll()
+	kinds, _ := memory.NewItemKindMemoryRepository().FindAll()
+	stock1, stock2 := stocks[0], stocks[1]
+
+	service := &fakeExportQueryService{orders: []ExportOrderData{
+		// saturday morning
+		{Id: "order1", OrderDateTime: parseDateTime("2050/12/09 10:00"), PickupDateTime: parseDateTime("2050/12/10 09:00"), UserId: "user1", Items: []ExportOrderItemData{
+			{ItemType: "stock", ItemId: stock1.GetId(), Name: "item1", Price: 100, Quantity: 2, Options: []ExportOptionData{{Id: "opt1", Name: "large", Price: 50, Quantity: 1}}},
+			{ItemType: "food", ItemId: "deleted", Name: "deleted", Price: 500, Quantity: 1},
+		}},
+		// canceled
+		{Id: "order2", OrderDateTime: parseDateTime("2050/12/09 11:00"), PickupDateTime: parseDateTime("2050/12/10 09:00"), UserId: "user2", Canceled: true, Items: []ExportOrderItemData{
+			{ItemType: "stock", ItemId: stock1.GetId(), Name: "item1", Price: 100, Quantity: 5},
+		}},
+		// saturday lunch
+		{Id: "order3", OrderDateTime: parseDateTime("2050/12/10 08:00"), PickupDateTime: parseDateTime("2050/12/10 12:00"), UserId: "user1", Items: []ExportOrderItemData{
+			{ItemType: "stock", ItemId: stock2.GetId(), Name: "item2", Price: 200, Quantity: 1},
+		}},
+		// out of business hours by guest
+		{Id: "order4", OrderDateTime: parseDateTime("2050/12/10 08:30"), PickupDateTime: parseDateTime("2050/12/10 22:00"), UserId: "guest", UserEmail: "Guest@hoge.com", Items: []ExportOrderItemData{
+			{ItemType: "stock", ItemId: stock1.GetId(), Name: "item1", Price: 100, Quantity: 1},
+		}},
+	}}
+	useCase := NewOrderAnalyticsUseCase(service, stockRepo, memory.NewFoodItemMemoryRepository(), memory.NewItemKindMemoryRepository(),
+		memory.NewBusinessHoursMemoryRepository(), memory.NewSpecialHolidayMemoryRepository(), memory.NewSpecialBusinessHourMemoryRepository())
+
+	data, err := useCase.FetchAnalytics(AnalyticsRequestModel{Start: "2050/12/09", End: "2050/12/10"})
+	assert.Nil(t, err)
+	assert.Equal(t, DateBasisOrder, service.basis)
+	assert.Equal(t, AnalyticsSummary{
+		OrderTotal:          3,
+		CanceledTotal:       1,
+		QuantityTotal:       5,
+		MoneyTotal:          1100,
+		AverageOrderValue:   366.6667,
+		CancellationRate:    0.25,
+		CustomerTotal:       2,
+		RepeatCustomerTotal: 1,
+		RepeatCustomerRatio: 0.5,
+	}, data.Summary)
+
+	// option price is included
+	assert.Equal(t, []ItemAnalytics{
+		{ItemId: "deleted", ItemType: "food", Name: "deleted", Quantity: 1, MoneyTotal: 500},
+		{ItemId: stock1.GetId(), ItemType: "stock", Name: "item1", KindId: stock1.GetKindId(), Quantity: 3, MoneyTotal: 400},
+		{ItemId: stock2.GetId(), ItemType: "stock", Name: "item2", KindId: stock2.GetKindId(), Quantity: 1, MoneyTotal: 200},
+	}, data.Items)
+	kindName := func(id string) string {
+		for _, k := range kinds {
+			if k.GetId() == id {
+				return k.GetName()
+			}
+		}
+		return ""
+	}
+	assert.Equal(t, []KindAnalytics{
+		{KindId: "", Name: "", Quantity: 1, MoneyTotal: 500},
+		{KindId: stock1.GetKindId(), Name: kindName(stock1.GetKindId()), Quantity: 3, MoneyTotal: 400},
+		{KindId: stock2.GetKindId(), Name: kindName(stock2.GetKindId()), Quantity: 1, MoneyTotal: 200},
+	}, data.Kinds)
+	assert.Equal(t, []OptionAnalytics{{OptionId: "opt1", Name: "large", Quantity: 2, MoneyTotal: 100}}, data.Options)
+
+	assert.Equal(t, []DailyAnalytics{
+		{Date: "2050/12/09", OrderTotal: 1, CanceledTotal: 1, QuantityTotal: 3, MoneyTotal: 800},
+		{Date: "2050/12/10", OrderTotal: 2, QuantityTotal: 2, MoneyTotal: 300},
+	}, data.Daily)
+
+	assert.Equal(t, 3, len(data.Heatmap))
+	// out of business hours
+	assert.Equal(t, 6, data.Heatmap[0].Weekday)
+	assert.Equal(t, "", data.Heatmap[0].HourTypeId)
+	assert.Equal(t, 100, data.Heatmap[0].MoneyTotal)
+	assert.Equal(t, "07:00", data.Heatmap[1].StartTime)
+	assert.Equal(t, 800, data.Heatmap[1].MoneyTotal)
+	assert.Equal(t, "11:30", data.Heatmap[2].StartTime)
+	assert.Equal(t, 200, data.Heatmap[2].MoneyTotal)
+}
+
+func TestFetchAnalytics_PickupBasis(t *testing.T) {
+	service := &fakeExportQueryService{orders: []ExportOrderData{
+		{Id: "order1", OrderDateTime: parseDateTime("2050/12/09 10:00"), PickupDateTime: parseDateTime("2050/12/10 09:00"), UserId: "user1", Items: []ExportOrderItemData{
+			{ItemType: "food", ItemId: "food1", Name: "food1", Price: 500, Quantity: 1},
+		}},
+	}}
+	useCase := NewOrderAnalyticsUseCase(service, memory.NewStockItemMemoryRepository(), memory.NewFoodItemMemoryRepository(), memory.NewItemKindMemoryRepository(),
+		memory.NewBusinessHoursMemoryRepository(), memory.NewSpecialHolidayMemoryRepository(), memory.NewSpecialBusinessHourMemoryRepository())
+
+	data, err := useCase.FetchAnalytics(AnalyticsRequestModel{Start: "2050/12/09", End: "2050/12/10", Basis: "pickup"})
+	assert.Nil(t, err)
+	assert.Equal(t, DateBasisPickup, service.basis)
+	assert.Equal(t, "pickup", data.Basis)
+	// grouped by pickup date
+	assert.Equal(t, 0, data.Daily[0].OrderTotal)
+	assert.Equal(t, 1, data.Daily[1].OrderTotal)
+}
+
+func TestFetchAnalytics_InvalidRange(t *testing.T) {
+	useCase := NewOrderAnalyticsUseCase(&fakeExportQueryService{}, memory.NewStockItemMemoryRepository(), memory.NewFoodItemMemoryRepository(), memory.NewItemKindMemoryRepository(),
+		memory.NewBusinessHoursMemoryRepository(), memory.NewSpecialHolidayMemoryRepository(), memory.NewSpecialBusinessHourMemoryRepository())
+
+	// over 1 year
+	_, err := useCase.FetchAnalytics(AnalyticsRequestModel{Start: "2050/01/01", End: "2051/01/02"})
+	_, ok := err.(*common.ValidationError)
+	assert.True(t, ok)
+	_, err = useCase.FetchAnalytics(AnalyticsRequestModel{Start: "2050/01/01", End: "2050/01/02", Basis: "cancel"})
+	_, ok = err.(*common.ValidationError)
+	assert.True(t, ok)
+}
@@ -0,0 +1,239 @@
+package order
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"chico/takeout/common"
+)
+
+// row writer of export file (csv, xlsx). value of row is string or int
+type TableWriter interface {
+	WriteRow(values []interface{}) error
+	// should be called after all rows are written
+	Flush() error
+}
+
+// date which orders are filtered and grouped by
+type DateBasis string
+
+const (
+	DateBasisOrder  DateBasis = "order"
+	DateBasisPickup DateBasis = "pickup"
+)
+
+// empty means order date
+func NewDateBasis(basis string) (DateBasis, error) {
+	switch basis {
+	case "", string(DateBasisOrder):
+		return DateBasisOrder, nil
+	case string(DateBasisPickup):
+		return DateBasisPickup, nil
+	}
+	return "", common.NewValidationError("Basis", fmt.Sprintf("should be order or pickup:%s", basis))
+}
+
+type OrderExportQueryService interface {
+	// fn is called per order in order of the basis date time, so that all orders are not loaded at once.
+	// end date is included
+	FetchOrders(basis DateBasis, startDate, endDate time.Time, fn func(data ExportOrderData) error) error
+}
+
+// date times are in store location
+type ExportOrderData struct {
+	Id             string
+	OrderDateTime  time.Time
+	PickupDateTime time.Time
+	Source         string
+	UserId         string
+	UserName       string
+	UserEmail      string
+	UserTelNo      string
+	Memo           string
+	Canceled       bool
+	CancelReason   string
+	Items          []ExportOrderItemData
+}
+
+type ExportOrderItemData struct {
+	// stock or food
+	ItemType string
+	ItemId   string
+	Name     string
+	Price    int
+	Quantity int
+	Options  []ExportOptionData
+}
+
+type ExportOptionData struct {
+	Id       string
+	Name     string
+	Price    int
+	Quantity int
+}
+
+type DailySalesData struct {
+	Date          string
+	OrderTotal    int
+	QuantityTotal int
+	MoneyTotal    int
+}
+
+type ExportRequestModel struct {
+	// yyyy/MM/dd
+	Start string
+	End   string
+	// order (default) or pickup. not used for daily sales
+	Basis string
+}
+
+type OrderExportUseCase struct {
+	service   OrderExportQueryService
+	statistic *OrderStatisticUseCase
+}
+
+func NewOrderExportUseCase(exportService OrderExportQueryService) *OrderExportUseCase {
+	return &OrderExportUseCase{
+		service:   exportService,
+		statistic: NewOrderStatisticUseCase(exportService),
+	}
+}
+
+// one row per ordered item. option price is included in subtotal
+func (o *OrderExportUseCase) ExportOrders(req ExportRequestModel, writer TableWriter) error {
+	start, end, err := convertExportDateRange(req)
+	if err != nil {
+		return err
+	}
+	basis, err := NewDateBasis(req.Basis)
+	if err != nil {
+		return err
+	}
+	err = writer.WriteRow([]interface{}{"orderId", "orderDateTime", "pickupDateTime", "source", "userId", "userName", "userEmail", "userTelNo", "memo", "canceled", "cancelReason",
+		"itemType", "itemId", "itemName", "price", "quantity", "options", "optionPrice", "subtotal"})
+	if err != nil {
+		return err
+	}
+	err = o.service.FetchOrders(basis, *start, *end, func(data ExportOrderData) error {
+		for _, item := range data.Items {
+			options := []string{}
+			for _, opt := range item.Options {
+				options = append(options, fmt.Sprintf("%s x%d", opt.Name, opt.Quantity))
+			}
+			err := writer.WriteRow([]interface{}{data.Id, common.ConvertTimeToDateTimeStr(data.OrderDateTime), common.ConvertTimeToDateTimeStr(data.PickupDateTime), data.Source, data.UserId, data.UserName, data.UserEmail, data.UserTelNo, data.Memo, data.Canceled, data.CancelReason,
+				item.ItemType, item.ItemId, item.Name, item.Price, item.Quantity, strings.Join(options, ", "), optionPriceOf(item), subtotalOf(item)})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return writer.Flush()
+}
+
+// grouped by order date. canceled orders are excluded and option prices are included.
+// dates without order are included as 0
+func (o *OrderExportUseCase) ExportDailySales(req ExportRequestModel, writer TableWriter) error {
+	start, end, err := convertExportDateRange(req)
+	if err != nil {
+		return err
+	}
+	sales := map[string]*DailySalesData{}
+	err = o.service.FetchOrders(DateBasisOrder, *start, *end, func(data ExportOrderData) error {
+		if data.Canceled {
+			return nil
+		}
+		date := common.ConvertTimeToDateStr(data.OrderDateTime)
+		s, ok := sales[date]
+		if !ok {
+			s = &DailySalesData{Date: date}
+			sales[date] = s
+		}
+		s.OrderTotal++
+		for _, item := range data.Items {
+			s.QuantityTotal += item.Quantity
+			s.MoneyTotal += subtotalOf(item)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	dates, err := common.ListUpDates(*start, *end, common.GetStoreLocation())
+	if err != nil {
+		return err
+	}
+	err = writer.WriteRow([]interface{}{"date", "orderTotal", "quantityTotal", "moneyTotal"})
+	if err != nil {
+		return err
+	}
+	for _, date := range dates {
+		row := DailySalesData{Date: common.ConvertTimeToDateStr(date)}
+		if s, ok := sales[row.Date]; ok {
+			row = *s
+		}
+		err = writer.WriteRow([]interface{}{row.Date, row.OrderTotal, row.QuantityTotal, row.MoneyTotal})
+		if err != nil {
+			return err
+		}
+	}
+	return writer.Flush()
+}
+
+// total row of month is followed by rows per order source
+func (o *OrderExportUseCase) ExportMonthlyStatistic(req MonthlyStatisticRequestModel, writer TableWriter) error {
+	data, err := o.statistic.FetchMonthlyData(req)
+	if err != nil {
+		return err
+	}
+	err = writer.WriteRow([]interface{}{"month", "source", "orderTotal", "quantityTotal", "moneyTotal"})
+	if err != nil {
+		return err
+	}
+	for _, d := range data.Data {
+		err = writer.WriteRow([]interface{}{d.Month, "all", d.OrderTotal, d.QuantityTotal, d.MoneyTotal})
+		if err != nil {
+			return err
+		}
+		for _, s := range d.Sources {
+			err = writer.WriteRow([]interface{}{d.Month, s.Source, s.OrderTotal, s.QuantityTotal, s.MoneyTotal})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return writer.Flush()
+}
+
+// price of options per one item
+func optionPriceOf(item ExportOrderItemData) int {
+	price := 0
+	for _, opt := range item.Options {
+		price += opt.Price * opt.Quantity
+	}
+	return price
+}
+
+// (price + option price) x quantity
+func subtotalOf(item ExportOrderItemData) int {
+	return (item.Price + optionPriceOf(item)) * item.Quantity
+}
+
+func convertExportDateRange(req ExportRequestModel) (*time.Time, *time.Time, error) {
+	start, err := common.ConvertStrToDate(req.Start)
+	if err != nil {
+		return nil, nil, common.NewValidationError("Start", fmt.Sprintf("failed to convert date data:%s", err))
+	}
+	end, err := common.ConvertStrToDate(req.End)
+	if err != nil {
+		return nil, nil, common.NewValidationError("End", fmt.Sprintf("failed to convert date data:%s", err))
+	}
+	if start.After(*end) {
+		return nil, nil, common.NewValidationError("Start, End", fmt.Sprintf("start should be before end. start:%s, end:%s", req.Start, req.End))
+	}
+	return start, end, nil
+}
@@ -0,0 +1,126 @@
+package order
+
+import (
+	"testing"
+	"time"
+
+	"chico/takeout/common"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeExportQueryService struct {
+	orders []ExportOrderData
+	basis  DateBasis
+	start  time.Time
+	end    time.Time
+}
+
+func (f *fakeExportQueryService) FetchOrders(basis DateBasis, startDate, endDate time.Time, fn func(data ExportOrderData) error) error {
+	f.basis, f.start, f.end = basis, startDate, endDate
+	for _, data := range f.orders {
+		if err := fn(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fixture date time in store location
+func parseDateTime(value string) time.Time {
+	t, _ := common.ConvertStrToDateTime(value)
+	return *t
+}
+
+type memoryTableWriter struct {
+	rows    [][]interface{}
+	flushed bool
+}
+
+func (m *memoryTableWriter) WriteRow(values []interface{}) error {
+	m.rows = append(m.rows, values)
+	return nil
+}
+
+func (m *memoryTableWriter) Flush() error {
+	m.flushed = true
+	return nil
+}
+
+func TestExportOrders(t *testing.T) {
+	service := &fakeExportQueryService{orders: []ExportOrderData{
+		{Id: "order1", OrderDateTime: parseDateTime("2050/12/01 10:00"), PickupDateTime: parseDateTime("2050/12/10 09:10"), Source: "web", UserName: "user1", Items: []ExportOrderItemData{
+			{ItemType: "food", ItemId: "food1", Name: "curry", Price: 800, Quantity: 2, Options: []ExportOptionData{
+				{Id: "opt1", Name: "large", Price: 100, Quantity: 1},
+				{Id: "opt2", Name: "cheese", Price: 50, Quantity: 2},
+			}},
+			{ItemType: "stock", ItemId: "stock1", Name: "lassi", Price: 300, Quantity: 1, Options: []ExportOptionData{}},
+		}},
+	}}
+	useCase := NewOrderExportUseCase(service)
+	writer := &memoryTableWriter{}
+
+	err := useCase.ExportOrders(ExportRequestModel{Start: "2050/12/01", End: "2050/12/31"}, writer)
+	assert.Nil(t, err)
+	assert.True(t, writer.flushed)
+	assert.Equal(t, 3, len(writer.rows))
+	assert.Equal(t, "orderId", writer.rows[0][0])
+	// one row per item
+	assert.Equal(t, []interface{}{"order1", "2050/12/01 10:00", "2050/12/10 09:10", "web", "", "user1", "", "", "", false, "",
+		"food", "food1", "curry", 800, 2, "large x1, cheese x2", 200, 2000}, writer.rows[1])
+	assert.Equal(t, []interface{}{"order1", "2050/12/01 10:00", "2050/12/10 09:10", "web", "", "user1", "", "", "", false, "",
+		"stock", "stock1", "lassi", 300, 1, "", 0, 300}, writer.rows[2])
+	assert.Equal(t, DateBasisOrder, service.basis)
+	assert.Equal(t, "2050/12/01", common.ConvertTimeToDateStr(service.start))
+	assert.Equal(t, "2050/12/31", common.ConvertTimeToDateStr(service.end))
+}
+
+func TestExportOrders_InvalidRange(t *testing.T) {
+	useCase := NewOrderExportUseCase(&fakeExportQueryService{})
+	writer := &memoryTableWriter{}
+
+	err := useCase.ExportOrders(ExportRequestModel{Start: "2050/12/31", End: "2050/12/01"}, writer)
+	_, ok := err.(*common.ValidationError)
+	assert.True(t, ok)
+	err = useCase.ExportOrders(ExportRequestModel{Start: "2050-12-01", End: "2050/12/31"}, writer)
+	_, ok = err.(*common.ValidationError)
+	assert.True(t, ok)
+	err = useCase.ExportOrders(ExportRequestModel{Start: "2050/12/01", End: "2050/12/31", Basis: "cancel"}, writer)
+	_, ok = err.(*common.ValidationError)
+	assert.True(t, ok)
+	// nothing is written before validation
+	assert.Equal(t, 0, len(writer.rows))
+}
+
+func TestExportDailySales(t *testing.T) {
+	service := &fakeExportQueryService{orders: []ExportOrderData{
+		// two identical lines in one order are both counted
+		{Id: "order1", OrderDateTime: parseDateTime("2050/12/02 10:00"), PickupDateTime: parseDateTime("2050/12/02 12:00"), Items: []ExportOrderItemData{
+			{ItemType: "food", ItemId: "food1", Name: "curry", Price: 800, Quantity: 1, Options: []ExportOptionData{}},
+			{ItemType: "food", ItemId: "food1", Name: "curry", Price: 800, Quantity: 1, Options: []ExportOptionData{}},
+		}},
+		// option price x option quantity is added per item
+		{Id: "order2", OrderDateTime: parseDateTime("2050/12/02 11:00"), PickupDateTime: parseDateTime("2050/12/02 12:00"), Items: []ExportOrderItemData{
+			{ItemType: "food", ItemId: "food1", Name: "curry", Price: 800, Quantity: 2, Options: []ExportOptionData{
+				{Id: "opt1", Name: "large", Price: 100, Quantity: 1},
+				{Id: "opt2", Name: "cheese", Price: 50, Quantity: 2},
+			}},
+			{ItemType: "stock", ItemId: "stock1", Name: "lassi", Price: 300, Quantity: 1, Options: []ExportOptionData{}},
+		}},
+		{Id: "order3", OrderDateTime: parseDateTime("2050/12/02 12:00"), PickupDateTime: parseDateTime("2050/12/02 13:00"), Canceled: true, Items: []ExportOrderItemData{
+			{ItemType: "stock", ItemId: "stock1", Name: "lassi", Price: 300, Quantity: 1, Options: []ExportOptionData{}},
+		}},
+	}}
+	useCase := NewOrderExportUseCase(service)
+	writer := &memoryTableWriter{}
+
+	err := useCase.ExportDailySales(ExportRequestModel{Start: "2050/12/01", End: "2050/12/03"}, writer)
+	assert.Nil(t, err)
+	assert.Equal(t, [][]interface{}{
+		{"date", "orderTotal", "quantityTotal", "moneyTotal"},
+		{"2050/12/01", 0, 0, 0},
+		{"2050/12/02", 2, 5, 3900},
+		{"2050/12/03", 0, 0, 0},
+	}, writer.rows)
+	assert.Equal(t, DateBasisOrder, service.basis)
+}
@@ -0,0 +1,347 @@
+package order
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"chico/takeout/common"
+	itemDomains "chico/takeout/domains/item"
+	orderDomains "chico/takeout/domains/order"
+	storeDomains "chico/takeout/domains/store"
+)
+
+const (
+	// forecast from tomorrow
+	forecastDays = 14
+	// orders of these weeks until yesterday are used
+	forecastHistoryWeeks = 8
+	// suggestion = estimate x (1 + margin)
+	forecastSafetyMargin = 0.2
+)
+
+type DemandForecastData struct {
+	StartDate        string
+	EndDate          string
+	HistoryStartDate string
+	HistoryEndDate   string
+	Foods            []FoodDemandForecast
+	Stocks           []StockDemandForecast
+}
+
+type FoodDemandForecast struct {
+	ItemId string
+	Name   string
+	Days   []FoodDemandForecastDay
+}
+
+type FoodDemandForecastDay struct {
+	Date     string
+	Estimate float64
+	// quota of the date, or max order per day if not set
+	CurrentQuota   int
+	SuggestedQuota int
+}
+
+type StockDemandForecast struct {
+	ItemId string
+	Name   string
+	// total of the forecast days
+	Estimate float64
+	// remain on stock ledger
+	Remain         int
+	SuggestedStock int
+	// 0 if remain is enough
+	Shortage int
+	Days     []StockDemandForecastDay
+}
+
+type StockDemandForecastDay struct {
+	Date     string
+	Estimate float64
+}
+
+type DemandForecastUseCase struct {
+	service         OrderExportQueryService
+	stockRepository itemDomains.StockItemRepository
+	ledgerService   itemDomains.StockLedgerService
+	foodRepository  itemDomains.FoodItemRepository
+	quotaRepository itemDomains.FoodQuotaRepository
+	hourManagement  storeDomains.BusinessHourManagementService
+	clock           common.Clock
+}
+
+func NewDemandForecastUseCase(queryService OrderExportQueryService,
+	stockRepository itemDomains.StockItemRepository,
+	batchRepository itemDomains.StockBatchRepository,
+	movementRepository itemDomains.StockMovementRepository,
+	foodRepository itemDomains.FoodItemRepository,
+	quotaRepository itemDomains.FoodQuotaRepository,
+	businessHoursRepository storeDomains.BusinessHoursRepository,
+	specialHolidayRepository storeDomains.SpecialHolidayRepository,
+	specialBusinessHourRepository storeDomains.SpecialBusinessHourRepository,
+	clock common.Clock) *DemandForecastUseCase {
+	return &DemandForecastUseCase{
+		service:         queryService,
+		stockRepository: stockRepository,
+		ledgerService:   *itemDomains.NewStockLedgerService(stockRepository, batchRepository, movementRepository),
+		foodRepository:  foodRepository,
+		quotaRepository: quotaRepository,
+		hourManagement:  *storeDomains.NewBusinessHourManagementService(businessHoursRepository, specialHolidayRepository, specialBusinessHourRepository),
+		clock:           clock,
+	}
+}
+
+// estimates demand of enabled items from orders of the same weekday and business hour
+func (d *DemandForecastUseCase) FetchForecast() (*DemandForecastData, error) {
+	today := common.GetDateUntilDay(d.clock.Now())
+	historyStart := today.AddDate(0, 0, -7*forecastHistoryWeeks)
+	historyEnd := today.AddDate(0, 0, -1)
+	forecaster, err := d.newForecaster(historyStart, historyEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	start := today.AddDate(0, 0, 1)
+	end := today.AddDate(0, 0, forecastDays)
+	days, err := d.hourManagement.GetCalendar(start, end)
+	if err != nil {
+		return nil, err
+	}
+	quotas, err := d.quotaRepository.FindByRange(common.ConvertTimeToDateStr(start), common.ConvertTimeToDateStr(end))
+	if err != nil {
+		return nil, err
+	}
+	foods, err := d.forecastFoods(forecaster, days, quotas)
+	if err != nil {
+		return nil, err
+	}
+	stocks, err := d.forecastStocks(forecaster, days)
+	if err != nil {
+		return nil, err
+	}
+	return &DemandForecastData{
+		StartDate:        common.ConvertTimeToDateStr(start),
+		EndDate:          common.ConvertTimeToDateStr(end),
+		HistoryStartDate: common.ConvertTimeToDateStr(historyStart),
+		HistoryEndDate:   common.ConvertTimeToDateStr(historyEnd),
+		Foods:            foods,
+		Stocks:           stocks,
+	}, nil
+}
+
+// saves suggested quotas of the dates with demand. manual quota and quota 0 (sold out) are kept
+func (d *DemandForecastUseCase) ApplyForecast() (*DemandForecastData, error) {
+	data, err := d.FetchForecast()
+	if err != nil {
+		return nil, err
+	}
+	start, end := data.StartDate, data.EndDate
+	quotas, err := d.quotaRepository.FindByRange(start, end)
+	if err != nil {
+		return nil, err
+	}
+	for i := range data.Foods {
+		food := &data.Foods[i]
+		for j := range food.Days {
+			day := &food.Days[j]
+			if day.Estimate == 0 || isKeptQuota(food.ItemId, day.Date, quotas) {
+				continue
+			}
+			quota, err := itemDomains.NewForecastFoodQuota(food.ItemId, day.Date, day.SuggestedQuota)
+			if err != nil {
+				return nil, err
+			}
+			err = d.quotaRepository.Save(quota)
+			if err != nil {
+				return nil, err
+			}
+			day.CurrentQuota = day.SuggestedQuota
+		}
+	}
+	return data, nil
+}
+
+func isKeptQuota(itemId, date string, quotas []itemDomains.FoodQuota) bool {
+	for _, quota := range quotas {
+		if quota.IsWholeDay() && quota.GetItemId() == itemId && quota.GetDate() == date {
+			return quota.IsManual() || quota.GetQuantity() == 0
+		}
+	}
+	return false
+}
+
+// every business hour in the range is a record even if there is no order
+func (d *DemandForecastUseCase) newForecaster(start, end time.Time) (*orderDomains.DemandForecaster, error) {
+	days, err := d.hourManagement.GetCalendar(start, end)
+	if err != nil {
+		return nil, err
+	}
+	hours := &hourFinder{
+		service: d.hourManagement,
+		hours:   map[string][]storeDomains.HourInfo{},
+	}
+	records := []orderDomains.DemandRecord{}
+	index := map[string]int{}
+	for _, day := range days {
+		hours.hours[day.Date] = day.Hours
+		weekday, err := weekdayOf(day.Date)
+		if err != nil {
+			return nil, err
+		}
+		for _, hour := range day.Hours {
+			index[day.Date+"_"+hour.HourTypeId] = len(records)
+			records = append(records, orderDomains.DemandRecord{
+				Date:       day.Date,
+				Weekday:    weekday,
+				HourTypeId: hour.HourTypeId,
+				Special:    day.IsSpecial,
+				Quantities: map[string]int{},
+			})
+		}
+	}
+
+	err = d.service.FetchOrders(DateBasisPickup, start, end, func(data ExportOrderData) error {
+		if data.Canceled {
+			return nil
+		}
+		pickup := data.PickupDateTime
+		hour, weekday, err := hours.find(pickup)
+		if err != nil {
+			return err
+		}
+		if hour == nil {
+			return nil
+		}
+		// hour crossing midnight belongs to previous date
+		date := pickup
+		if weekday != pickup.Weekday() {
+			date = date.AddDate(0, 0, -1)
+		}
+		i, ok := index[common.ConvertTimeToDateStr(date)+"_"+hour.HourTypeId]
+		if !ok {
+			return nil
+		}
+		for _, item := range data.Items {
+			records[i].Quantities[item.ItemId] += item.Quantity
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return orderDomains.NewDemandForecaster(records, orderDomains.DefaultSmoothingFactor)
+}
+
+func (d *DemandForecastUseCase) forecastFoods(forecaster *orderDomains.DemandForecaster, days []storeDomains.CalendarDayInfo, quotas []itemDomains.FoodQuota) ([]FoodDemandForecast, error) {
+	foods, err := d.foodRepository.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	result := []FoodDemandForecast{}
+	for i := range foods {
+		food := &foods[i]
+		if !food.GetEnabled() {
+			continue
+		}
+		forecast := FoodDemandForecast{ItemId: food.GetId(), Name: food.GetName(), Days: []FoodDemandForecastDay{}}
+		for _, day := range days {
+			weekday, err := weekdayOf(day.Date)
+			if err != nil {
+				return nil, err
+			}
+			estimate := 0.0
+			if isAllowedDate(food, day.Date) {
+				for _, hour := range day.Hours {
+					if food.HasScheduleId(hour.HourTypeId) {
+						estimate += forecaster.Estimate(food.GetId(), weekday, hour.HourTypeId, day.IsSpecial)
+					}
+				}
+			}
+			forecast.Days = append(forecast.Days, FoodDemandForecastDay{
+				Date:           day.Date,
+				Estimate:       roundEstimate(estimate),
+				CurrentQuota:   food.GetQuotaAt(day.Date, quotas),
+				SuggestedQuota: suggestedQuantity(estimate, itemDomains.FoodQuotaMaxValue),
+			})
+		}
+		result = append(result, forecast)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+func (d *DemandForecastUseCase) forecastStocks(forecaster *orderDomains.DemandForecaster, days []storeDomains.CalendarDayInfo) ([]StockDemandForecast, error) {
+	stocks, err := d.stockRepository.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	result := []StockDemandForecast{}
+	for i := range stocks {
+		stock := &stocks[i]
+		if !stock.GetEnabled() {
+			continue
+		}
+		remain, err := d.ledgerService.CurrentRemain(stock)
+		if err != nil {
+			return nil, err
+		}
+		forecast := StockDemandForecast{ItemId: stock.GetId(), Name: stock.GetName(), Remain: remain, Days: []StockDemandForecastDay{}}
+		total := 0.0
+		for _, day := range days {
+			weekday, err := weekdayOf(day.Date)
+			if err != nil {
+				return nil, err
+			}
+			estimate := 0.0
+			for _, hour := range day.Hours {
+				estimate += forecaster.Estimate(stock.GetId(), weekday, hour.HourTypeId, day.IsSpecial)
+			}
+			total += estimate
+			forecast.Days = append(forecast.Days, StockDemandForecastDay{Date: day.Date, Estimate: roundEstimate(estimate)})
+		}
+		forecast.Estimate = roundEstimate(total)
+		forecast.SuggestedStock = suggestedQuantity(total, math.MaxInt32)
+		if forecast.SuggestedStock > remain {
+			forecast.Shortage = forecast.SuggestedStock - remain
+		}
+		result = append(result, forecast)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+func weekdayOf(dateStr string) (time.Weekday, error) {
+	date, err := common.ConvertStrToDate(dateStr)
+	if err != nil {
+		return 0, err
+	}
+	return date.Weekday(), nil
+}
+
+// empty allow dates means every date is allowed
+func isAllowedDate(food *itemDomains.FoodItem, date string) bool {
+	dates := food.GetAllowDates()
+	if len(dates) == 0 {
+		return true
+	}
+	for _, allowed := range dates {
+		if allowed == date {
+			return true
+		}
+	}
+	return false
+}
+
+func suggestedQuantity(estimate float64, max int) int {
+	// rounded before ceil to avoid floating error (ex:2.5x1.2=3.0000000000000004)
+	suggested := int(math.Ceil(roundEstimate(estimate * (1 + forecastSafetyMargin))))
+	if suggested > max {
+		return max
+	}
+	return suggested
+}
+
+func roundEstimate(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,182 @@
+package order
+
+import (
+	"testing"
+	"time"
+
+	"chico/takeout/common"
+	itemDomains "chico/takeout/domains/item"
+	"chico/takeout/infrastructures/memory"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDemandForecast(t *testing.T) {
+	stockRepo := memory.NewStockItemMemoryRepository()
+	stocks, _ := stockRepo.FindAll()
+	foodRepo := memory.NewFoodItemMemoryRepository()
+	foods, _ := foodRepo.FindAll()
+	var food1 *itemDomains.FoodItem
+	for i := range foods {
+		if foods[i].GetName() == "food1" {
+			food1 = &foods[i]
+		}
+	}
+	stock1, stock2 := stocks[0], stocks[1]
+
+	service := &fakeExportQueryService{orders: []ExportOrderData{
+		// saturday lunch
+		{Id: "order1", PickupDateTime: parseDateTime("2050/12/10 12:00"), Items: []ExportOrderItemData{
+			{ItemType: "food", ItemId: food1.GetId(), Quantity: 2},
+		}},
+		{Id: "order2", PickupDateTime: parseDateTime("2050/12/17 12:00"), Items: []ExportOrderItemData{
+			{ItemType: "food", ItemId: food1.GetId(), Quantity: 4},
+			{ItemType: "stock", ItemId: stock1.GetId(), Quantity: 3},
+			{ItemType: "stock", ItemId: stock2.GetId(), Quantity: 2},
+		}},
+		// canceled
+		{Id: "order3", PickupDateTime: parseDateTime("2050/12/17 12:30"), Canceled: true, Items: []ExportOrderItemData{
+			{ItemType: "food", ItemId: food1.GetId(), Quantity: 10},
+		}},
+		// out of business hours
+		{Id: "order4", PickupDateTime: parseDateTime("2050/12/17 22:00"), Items: []ExportOrderItemData{
+			{ItemType: "food", ItemId: food1.GetId(), Quantity: 10},
+		}},
+	}}
+	quotaRepo := memory.NewFoodQuotaMemoryRepository()
+	soldOut, _ := itemDomains.NewFoodQuota(food1.GetId(), "2050/12/31", "", 0)
+	quotaRepo.Save(soldOut)
+	// tuesday
+	clock := common.NewFakeClock(time.Date(2050, 12, 20, 10, 0, 0, 0, common.GetStoreLocation()))
+	useCase := NewDemandForecastUseCase(service, stockRepo, memory.NewStockBatchMemoryRepository(), memory.NewStockMovementMemoryRepository(),
+		foodRepo, quotaRepo, memory.NewBusinessHoursMemoryRepository(), memory.NewSpecialHolidayMemoryRepository(), memory.NewSpecialBusinessHourMemoryRepository(), clock)
+
+	data, err := useCase.FetchForecast()
+	assert.Nil(t, err)
+	assert.Equal(t, DateBasisPickup, service.basis)
+	assert.Equal(t, "2050/10/25", common.ConvertTimeToDateStr(service.start))
+	assert.Equal(t, "2050/12/19", common.ConvertTimeToDateStr(service.end))
+	assert.Equal(t, "2050/10/25", data.HistoryStartDate)
+	assert.Equal(t, "2050/12/19", data.HistoryEndDate)
+	assert.Equal(t, "2050/12/21", data.StartDate)
+	assert.Equal(t, "2051/01/03", data.EndDate)
+
+	var forecast *FoodDemandForecast
+	for i := range data.Foods {
+		if data.Foods[i].ItemId == food1.GetId() {
+			forecast = &data.Foods[i]
+		}
+	}
+	assert.NotNil(t, forecast)
+	assert.Equal(t, forecastDays, len(forecast.Days))
+	for _, day := range forecast.Days {
+		switch day.Date {
+		case "2050/12/24":
+			// 6 saturdays without order, then 2 and 4 -> 0.5x2=1 -> 0.5x4+0.5x1=2.5
+			assert.Equal(t, FoodDemandForecastDay{Date: day.Date, Estimate: 2.5, CurrentQuota: food1.GetMaxOrderPerDay(), SuggestedQuota: 3}, day)
+		case "2050/12/31":
+			assert.Equal(t, FoodDemandForecastDay{Date: day.Date, Estimate: 2.5, CurrentQuota: 0, SuggestedQuota: 3}, day)
+		default:
+			assert.Equal(t, FoodDemandForecastDay{Date: day.Date, CurrentQuota: food1.GetMaxOrderPerDay()}, day, day.Date)
+		}
+	}
+
+	assert.Equal(t, 2, len(data.Stocks))
+	for _, stock := range data.Stocks {
+		switch stock.ItemId {
+		case stock1.GetId():
+			// 1.5 x 2 saturdays
+			assert.Equal(t, 3.0, stock.Estimate)
+			assert.Equal(t, 4, stock.SuggestedStock)
+			assert.Equal(t, stock1.GetRemain(), stock.Remain)
+			assert.Equal(t, 0, stock.Shortage)
+		case stock2.GetId():
+			assert.Equal(t, 2.0, stock.Estimate)
+			assert.Equal(t, 3, stock.SuggestedStock)
+			assert.Equal(t, stock2.GetRemain(), stock.Remain)
+			assert.Equal(t, 3-stock2.GetRemain(), stock.Shortage)
+		}
+		assert.Equal(t, forecastDays, len(stock.Days))
+	}
+
+	// sold out is kept
+	_, err = useCase.ApplyForecast()
+	assert.Nil(t, err)
+	quotas, _ := quotaRepo.FindByRange("2050/12/21", "2051/01/03")
+	assert.Equal(t, 3, food1.GetQuotaAt("2050/12/24", quotas))
+	assert.Equal(t, 0, food1.GetQuotaAt("2050/12/31", quotas))
+	for _, quota := range quotas {
+		assert.Equal(t, food1.GetId(), quota.GetItemId())
+	}
+	assert.Equal(t, 2, len(quotas))
+}
+
+func TestDemandForecast_ApplyKeepsManualQuota(t *testing.T) {
+	foodRepo := memory.NewFoodItemMemoryRepository()
+	foods, _ := foodRepo.FindAll()
+	var food1 *itemDomains.FoodItem
+	for i := range foods {
+		if foods[i].GetName() == "food1" {
+			food1 = &foods[i]
+		}
+	}
+	// saturday lunch
+	service := &fakeExportQueryService{orders: []ExportOrderData{
+		{Id: "order1", PickupDateTime: parseDateTime("2050/12/10 12:00"), Items: []ExportOrderItemData{
+			{ItemType: "food", ItemId: food1.GetId(), Quantity: 2},
+		}},
+		{Id: "order2", PickupDateTime: parseDateTime("2050/12/17 12:00"), Items: []ExportOrderItemData{
+			{ItemType: "food", ItemId: food1.GetId(), Quantity: 4},
+		}},
+	}}
+	quotaRepo := memory.NewFoodQuotaMemoryRepository()
+	manual, _ := itemDomains.NewFoodQuota(food1.GetId(), "2050/12/24", "", 10)
+	quotaRepo.Save(manual)
+	forecast, _ := itemDomains.NewForecastFoodQuota(food1.GetId(), "2050/12/31", 1)
+	quotaRepo.Save(forecast)
+	clock := common.NewFakeClock(time.Date(2050, 12, 20, 10, 0, 0, 0, common.GetStoreLocation()))
+	useCase := NewDemandForecastUseCase(service, memory.NewStockItemMemoryRepository(), memory.NewStockBatchMemoryRepository(), memory.NewStockMovementMemoryRepository(),
+		foodRepo, quotaRepo, memory.NewBusinessHoursMemoryRepository(), memory.NewSpecialHolidayMemoryRepository(), memory.NewSpecialBusinessHourMemoryRepository(), clock)
+
+	data, err := useCase.ApplyForecast()
+	assert.Nil(t, err)
+	for _, food := range data.Foods {
+		if food.ItemId != food1.GetId() {
+			continue
+		}
+		for _, day := range food.Days {
+			switch day.Date {
+			case "2050/12/24":
+				assert.Equal(t, 10, day.CurrentQuota)
+			case "2050/12/31":
+				assert.Equal(t, 3, day.CurrentQuota)
+			}
+		}
+	}
+
+	// manual override survives, previous forecast is overwritten
+	quotas, _ := quotaRepo.FindByRange("2050/12/21", "2051/01/03")
+	assert.Equal(t, 2, len(quotas))
+	assert.Equal(t, 10, food1.GetQuotaAt("2050/12/24", quotas))
+	assert.Equal(t, itemDomains.FoodQuotaManual, quotas[0].GetSource())
+	assert.Equal(t, 3, food1.GetQuotaAt("2050/12/31", quotas))
+	assert.Equal(t, itemDomains.FoodQuotaForecast, quotas[1].GetSource())
+}
+
+func TestDemandForecast_NoHistory(t *testing.T) {
+	quotaRepo := memory.NewFoodQuotaMemoryRepository()
+	clock := common.NewFakeClock(time.Date(2050, 12, 20, 10, 0, 0, 0, common.GetStoreLocation()))
+	useCase := NewDemandForecastUseCase(&fakeExportQueryService{}, memory.NewStockItemMemoryRepository(), memory.NewStockBatchMemoryRepository(), memory.NewStockMovementMemoryRepository(),
+		memory.NewFoodItemMemoryRepository(), quotaRepo, memory.NewBusinessHoursMemoryRepository(), memory.NewSpecialHolidayMemoryRepository(), memory.NewSpecialBusinessHourMemoryRepository(), clock)
+
+	data, err := useCase.ApplyForecast()
+	assert.Nil(t, err)
+	for _, food := range data.Foods {
+		for _, day := range food.Days {
+			assert.Equal(t, 0.0, day.Estimate)
+			assert.Equal(t, 0, day.SuggestedQuota)
+		}
+	}
+	quotas, _ := quotaRepo.FindByRange("2050/12/21", "2051/01/03")
+	assert.Equal(t, 0, len(quotas))
+}
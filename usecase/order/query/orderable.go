@@ -12,11 +12,13 @@ type OrderableInfoQueryService interface {
 
 type OrderQueryUseCase struct {
 	service OrderableInfoQueryService
+	clock   common.Clock
 }
 
-func NewOrderQueryUseCase(queryService OrderableInfoQueryService) *OrderQueryUseCase {
+func NewOrderQueryUseCase(queryService OrderableInfoQueryService, clock common.Clock) *OrderQueryUseCase {
 	return &OrderQueryUseCase{
 		service: queryService,
+		clock:   clock,
 	}
 }
 
@@ -26,6 +28,7 @@ type OrderableInfo struct {
 	PerDayInfo []PerDayOrderableInfo
 }
 
+// EndTime before StartTime means the hour ends at next date
 type PerDayOrderableInfo struct {
 	Date       string
 	HourTypeId string
@@ -38,11 +41,13 @@ type OrderableItemInfo struct {
 	Id       string
 	ItemType string
 	Remain   int
+	// 0 means lead time of business hour is used
+	LeadTimeHours uint
 }
 
 func (o *OrderQueryUseCase) FetchOrderableInfo() (*OrderableInfo, error) {
 	// start is now
-	start := common.GetNowDateUntilDay()
+	start := common.GetDateUntilDay(o.clock.Now())
 	// 30 days
 	end := start.AddDate(0, 0, 30)
 	return o.service.FetchByDate(*start, end)
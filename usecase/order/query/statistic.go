@@ -2,20 +2,16 @@ package order
 
 import (
 	"fmt"
-	"time"
+	"sort"
 
 	"chico/takeout/common"
 )
 
-type OrderStatisticQueryService interface {
-	FetchMonthlyStatistic(startMonth, endMonth time.Time) (*MonthlyStatisticData, error)
-}
-
 type OrderStatisticUseCase struct {
-	service OrderStatisticQueryService
+	service OrderExportQueryService
 }
 
-func NewOrderStatisticUseCase(queryService OrderStatisticQueryService) *OrderStatisticUseCase {
+func NewOrderStatisticUseCase(queryService OrderExportQueryService) *OrderStatisticUseCase {
 	return &OrderStatisticUseCase{
 		service: queryService,
 	}
@@ -24,17 +20,29 @@ func NewOrderStatisticUseCase(queryService OrderStatisticQueryService) *OrderSta
 type MonthlyStatisticRequestModel struct {
 	Start string
 	End string
+	// order (default) or pickup
+	Basis string
 }
 
 type MonthlyStatisticData struct {
 	Data []MonthlyData
 }
 
+// canceled orders are excluded. option prices are included in money total
 type MonthlyData struct {
 	Month         string
 	OrderTotal    int
 	QuantityTotal int
 	MoneyTotal    int
+	// breakdown by order source (web, phone, walk_in)
+	Sources []MonthlySourceData
+}
+
+type MonthlySourceData struct {
+	Source        string
+	OrderTotal    int
+	QuantityTotal int
+	MoneyTotal    int
 }
 
 func (o *OrderStatisticUseCase) FetchMonthlyData(req MonthlyStatisticRequestModel) (*MonthlyStatisticData, error) {
@@ -57,5 +65,71 @@ func (o *OrderStatisticUseCase) FetchMonthlyData(req MonthlyStatisticRequestMode
 	if end.After(limit) {
 		return nil, common.NewValidationError("Start, End", fmt.Sprintf("start should within 1year from end. start%s, end:%s", *start, *end))
 	}
-	return o.service.FetchMonthlyStatistic(*start, *end)
+	basis, err := NewDateBasis(req.Basis)
+	if err != nil {
+		return nil, err
+	}
+
+	months := []string{}
+	monthly := map[string]*MonthlyData{}
+	sources := map[string]map[string]*MonthlySourceData{}
+	for month := *start; !month.After(*end); month = month.AddDate(0, 1, 0) {
+		monthStr := common.ConvertTimeToMonthStr(month)
+		months = append(months, monthStr)
+		monthly[monthStr] = &MonthlyData{Month: monthStr}
+		sources[monthStr] = map[string]*MonthlySourceData{}
+	}
+
+	// end date is the last day of end month
+	err = o.service.FetchOrders(basis, *start, end.AddDate(0, 1, -1), func(data ExportOrderData) error {
+		if data.Canceled {
+			return nil
+		}
+		basisDateTime := data.OrderDateTime
+		if basis == DateBasisPickup {
+			basisDateTime = data.PickupDateTime
+		}
+		monthStr := common.ConvertTimeToMonthStr(basisDateTime)
+		m, ok := monthly[monthStr]
+		if !ok {
+			return nil
+		}
+		source, ok := sources[monthStr][data.Source]
+		if !ok {
+			source = &MonthlySourceData{Source: data.Source}
+			sources[monthStr][data.Source] = source
+		}
+		quantity, money := 0, 0
+		for _, item := range data.Items {
+			quantity += item.Quantity
+			money += subtotalOf(item)
+		}
+		m.OrderTotal++
+		m.QuantityTotal += quantity
+		m.MoneyTotal += money
+		source.OrderTotal++
+		source.QuantityTotal += quantity
+		source.MoneyTotal += money
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// months without order are included as 0
+	models := []MonthlyData{}
+	for _, monthStr := range months {
+		m := *monthly[monthStr]
+		m.Sources = []MonthlySourceData{}
+		for _, source := range sources[monthStr] {
+			m.Sources = append(m.Sources, *source)
+		}
+		sort.Slice(m.Sources, func(i, j int) bool {
+			return m.Sources[i].Source < m.Sources[j].Source
+		})
+		models = append(models, m)
+	}
+	return &MonthlyStatisticData{
+		Data: models,
+	}, nil
 }
@@ -0,0 +1,72 @@
+package order
+
+import (
+	"testing"
+
+	"chico/takeout/common"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newStatisticOrders() []ExportOrderData {
+	return []ExportOrderData{
+		// two identical lines in one order are both counted
+		{Id: "order1", OrderDateTime: parseDateTime("2050/11/30 10:00"), PickupDateTime: parseDateTime("2050/12/01 12:00"), Source: "web", Items: []ExportOrderItemData{
+			{ItemType: "food", ItemId: "food1", Name: "curry", Price: 800, Quantity: 1, Options: []ExportOptionData{}},
+			{ItemType: "food", ItemId: "food1", Name: "curry", Price: 800, Quantity: 1, Options: []ExportOptionData{}},
+		}},
+		// option price x option quantity is added per item
+		{Id: "order2", OrderDateTime: parseDateTime("2050/11/20 11:00"), PickupDateTime: parseDateTime("2050/11/20 12:00"), Source: "phone", Items: []ExportOrderItemData{
+			{ItemType: "food", ItemId: "food1", Name: "curry", Price: 800, Quantity: 2, Options: []ExportOptionData{
+				{Id: "opt1", Name: "large", Price: 100, Quantity: 1},
+				{Id: "opt2", Name: "cheese", Price: 50, Quantity: 2},
+			}},
+			{ItemType: "stock", ItemId: "stock1", Name: "lassi", Price: 300, Quantity: 1, Options: []ExportOptionData{}},
+		}},
+		{Id: "order3", OrderDateTime: parseDateTime("2050/11/21 12:00"), PickupDateTime: parseDateTime("2050/11/21 13:00"), Source: "web", Canceled: true, Items: []ExportOrderItemData{
+			{ItemType: "stock", ItemId: "stock1", Name: "lassi", Price: 300, Quantity: 1, Options: []ExportOptionData{}},
+		}},
+	}
+}
+
+func TestFetchMonthlyData(t *testing.T) {
+	service := &fakeExportQueryService{orders: newStatisticOrders()}
+	useCase := NewOrderStatisticUseCase(service)
+
+	data, err := useCase.FetchMonthlyData(MonthlyStatisticRequestModel{Start: "2050/10", End: "2050/12"})
+	assert.Nil(t, err)
+	assert.Equal(t, []MonthlyData{
+		{Month: "2050/10", Sources: []MonthlySourceData{}},
+		{Month: "2050/11", OrderTotal: 2, QuantityTotal: 5, MoneyTotal: 3900, Sources: []MonthlySourceData{
+			{Source: "phone", OrderTotal: 1, QuantityTotal: 3, MoneyTotal: 2300},
+			{Source: "web", OrderTotal: 1, QuantityTotal: 2, MoneyTotal: 1600},
+		}},
+		{Month: "2050/12", Sources: []MonthlySourceData{}},
+	}, data.Data)
+	assert.Equal(t, DateBasisOrder, service.basis)
+	assert.Equal(t, "2050/10/01", common.ConvertTimeToDateStr(service.start))
+	assert.Equal(t, "2050/12/31", common.ConvertTimeToDateStr(service.end))
+}
+
+func TestFetchMonthlyData_PickupBasis(t *testing.T) {
+	service := &fakeExportQueryService{orders: newStatisticOrders()}
+	useCase := NewOrderStatisticUseCase(service)
+
+	data, err := useCase.FetchMonthlyData(MonthlyStatisticRequestModel{Start: "2050/11", End: "2050/12", Basis: "pickup"})
+	assert.Nil(t, err)
+	assert.Equal(t, DateBasisPickup, service.basis)
+	assert.Equal(t, 2, len(data.Data))
+	assert.Equal(t, 1, data.Data[0].OrderTotal)
+	assert.Equal(t, 2300, data.Data[0].MoneyTotal)
+	// order1 is ordered in 11 but picked up in 12
+	assert.Equal(t, 1, data.Data[1].OrderTotal)
+	assert.Equal(t, 1600, data.Data[1].MoneyTotal)
+}
+
+func TestFetchMonthlyData_InvalidBasis(t *testing.T) {
+	useCase := NewOrderStatisticUseCase(&fakeExportQueryService{})
+
+	_, err := useCase.FetchMonthlyData(MonthlyStatisticRequestModel{Start: "2050/11", End: "2050/12", Basis: "cancel"})
+	_, ok := err.(*common.ValidationError)
+	assert.True(t, ok)
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"chico/takeout/common"
+	idomains "chico/takeout/domains/item"
 	domains "chico/takeout/domains/order"
 	storeDomains "chico/takeout/domains/store"
 )
@@ -12,12 +13,15 @@ import (
 type OrderTaskUseCase interface {
 	NotifyDailyOrder(start time.Time) error
 	NotifyOrderByHour(currentTime time.Time) error
+	// alerts of the date are sent as one mail
+	NotifyDailyItemAlert(date time.Time) error
 }
 
 type orderTaskUseCase struct {
 	filter        domains.OrderFilter
 	mailerService SendOrderMailService
 	mngService    storeDomains.BusinessHourManagementService
+	alertRepo     idomains.ItemAlertRepository
 }
 
 func NewOrderTaskUseCase(
@@ -25,14 +29,34 @@ func NewOrderTaskUseCase(
 	mailerService SendOrderMailService,
 	businessHoursRepository storeDomains.BusinessHoursRepository,
 	specialHolidayRepository storeDomains.SpecialHolidayRepository,
-	specialBusinessHourRepository storeDomains.SpecialBusinessHourRepository) OrderTaskUseCase {
+	specialBusinessHourRepository storeDomains.SpecialBusinessHourRepository,
+	alertRepo idomains.ItemAlertRepository) OrderTaskUseCase {
 	return &orderTaskUseCase{
 		filter:        *domains.NewOrderFilter(orderRepos),
 		mailerService: mailerService,
 		mngService:    *storeDomains.NewBusinessHourManagementService(businessHoursRepository, specialHolidayRepository, specialBusinessHourRepository),
+		alertRepo:     alertRepo,
 	}
 }
 
+// digest is not sent if no alert
+func (o *orderTaskUseCase) NotifyDailyItemAlert(date time.Time) error {
+	dateStr := common.ConvertTimeToDateStr(date)
+	alerts, err := o.alertRepo.FindByTargetDate(dateStr)
+	if err != nil {
+		return err
+	}
+	if len(alerts) == 0 {
+		return nil
+	}
+	cfg := common.GetConfig().Mail
+	mailData, err := NewItemAlertDigestMailData(alerts, cfg.From, cfg.Admin, dateStr)
+	if err != nil {
+		return err
+	}
+	return o.mailerService.SendItemAlert(*mailData)
+}
+
 func (o *orderTaskUseCase) NotifyDailyOrder(start time.Time) error {
 	orders, err := o.filter.GetActiveOrderOfSpecifiedDay(start)
 	if err != nil {
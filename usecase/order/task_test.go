@@ -17,6 +17,9 @@ import (
 
 var jst = time.FixedZone("Asia/Tokyo", 9*60*60)
 
+// orders in tests are created at this time
+var orderedAt = time.Date(2050, 12, 1, 10, 0, 0, 0, jst)
+
 func TestNotifyDailyOrder_NoOrderDate(t *testing.T) {
 	setUpEnv(t)
 	useCase, mail := setUpUseCase()
@@ -481,7 +484,7 @@ func setUpUseCase() (order.OrderTaskUseCase, *memory.MemorySendOrderMail) {
 
 	// morning
 	stockOrders1 := []domains.OrderStockItem{}
-	order1, err := domains.NewOrderInfo("user2", "ユーザー2", "user2@hoge.com", "123456789", "memo1", "2050/12/10 9:10", stockOrders1, foodOrders1)
+	order1, err := domains.NewOrderInfo("user2", "ユーザー2", "user2@hoge.com", "123456789", "memo1", "2050/12/10 9:10", stockOrders1, foodOrders1, orderedAt)
 	if err != nil {
 		fmt.Println(err)
 		panic("failed to create stock order")
@@ -489,21 +492,21 @@ func setUpUseCase() (order.OrderTaskUseCase, *memory.MemorySendOrderMail) {
 	orders[order1.GetId()] = order1
 
 	// create additional canceled order
-	order2, err := domains.NewOrderInfo("user2", "ユーザー2", "user2@hoge.com", "123456789", "memo1", "2050/12/10 9:00", stockOrders1, foodOrders1)
+	order2, err := domains.NewOrderInfo("user2", "ユーザー2", "user2@hoge.com", "123456789", "memo1", "2050/12/10 9:00", stockOrders1, foodOrders1, orderedAt)
 	if err != nil {
 		fmt.Println(err)
 		panic("failed to create stock order")
 	}
-	order2.SetCancel()
+	order2.SetCancel("")
 	orders[order2.GetId()] = order2
 
 	// lunch and cancel
-	order3, err := domains.NewOrderInfo("user2", "ユーザー2", "user2@hoge.com", "123456789", "memo1", "2050/12/11 12:10", stockOrders1, foodOrders1)
+	order3, err := domains.NewOrderInfo("user2", "ユーザー2", "user2@hoge.com", "123456789", "memo1", "2050/12/11 12:10", stockOrders1, foodOrders1, orderedAt)
 	if err != nil {
 		fmt.Println(err)
 		panic("failed to create stock order")
 	}
-	order3.SetCancel()
+	order3.SetCancel("")
 	orders[order3.GetId()] = order3
 
 	// morning, _ := NewBusinessHour("morning", "07:00", "09:30", []Weekday{Tuesday, Wednesday, Friday, Saturday, Sunday})
@@ -512,7 +515,9 @@ func setUpUseCase() (order.OrderTaskUseCase, *memory.MemorySendOrderMail) {
 
 	businessHourRepo := memory.NewBusinessHoursMemoryRepository()
 	spBusinessHourRepo := memory.NewSpecialBusinessHourMemoryRepository()
+	spBusinessHourRepo.Reset()
 	holidayRepo := memory.NewSpecialHolidayMemoryRepository()
+	holidayRepo.Reset()
 
 	holiday1, err := stDomains.NewSpecialHoliday("おやすみXX", "2050/11/20", "2050/12/08")
 	if err != nil {
@@ -551,7 +556,7 @@ func setUpUseCase() (order.OrderTaskUseCase, *memory.MemorySendOrderMail) {
 	}
 	spBusinessHourRepo.Create(spHour4)
 
-	orderSp, err := domains.NewOrderInfo("user2", "ユーザー2", "user2@hoge.com", "123456789", "memo1", "2050/12/19 9:10", stockOrders1, foodOrders1)
+	orderSp, err := domains.NewOrderInfo("user2", "ユーザー2", "user2@hoge.com", "123456789", "memo1", "2050/12/19 9:10", stockOrders1, foodOrders1, orderedAt)
 	if err != nil {
 		fmt.Println(err)
 		panic("failed to create stock order")
@@ -559,7 +564,7 @@ func setUpUseCase() (order.OrderTaskUseCase, *memory.MemorySendOrderMail) {
 	orders[orderSp.GetId()] = orderSp
 
 	mail := memory.NewMemorySendOrderMail()
-	useCase := order.NewOrderTaskUseCase(repo, mail, businessHourRepo, holidayRepo, spBusinessHourRepo)
+	useCase := order.NewOrderTaskUseCase(repo, mail, businessHourRepo, holidayRepo, spBusinessHourRepo, memory.NewItemAlertMemoryRepository())
 
 	return useCase, mail
 }
@@ -578,7 +583,7 @@ func setUpUseCaseWithMultipleOrders() (order.OrderTaskUseCase, *memory.MemorySen
 
 	// morning
 	stockOrders1 := []domains.OrderStockItem{}
-	order1, err := domains.NewOrderInfo("user2", "ユーザー2", "user2@hoge.com", "123456789", "memo1", "2050/12/10 7:00", stockOrders1, foodOrders1)
+	order1, err := domains.NewOrderInfo("user2", "ユーザー2", "user2@hoge.com", "123456789", "memo1", "2050/12/10 7:00", stockOrders1, foodOrders1, orderedAt)
 	if err != nil {
 		fmt.Println(err)
 		panic("failed to create stock order")
@@ -607,7 +612,7 @@ func setUpUseCaseWithMultipleOrders() (order.OrderTaskUseCase, *memory.MemorySen
 		panic("failed to create stock order")
 	}
 	stockOrders2 = append(stockOrders2, *stockOrder2)
-	order2, err := domains.NewOrderInfo("user3", "ユーザー3", "user3@hoge.com", "123456789", "memo1", "2050/12/10 9:00", stockOrders2, foodOrders2)
+	order2, err := domains.NewOrderInfo("user3", "ユーザー3", "user3@hoge.com", "123456789", "memo1", "2050/12/10 9:00", stockOrders2, foodOrders2, orderedAt)
 	if err != nil {
 		fmt.Println(err)
 		panic("failed to create stock order")
@@ -615,12 +620,12 @@ func setUpUseCaseWithMultipleOrders() (order.OrderTaskUseCase, *memory.MemorySen
 	orders[order2.GetId()] = order2
 
 	// morning and cancel
-	order3, err := domains.NewOrderInfo("user2", "ユーザー2", "user2@hoge.com", "123456789", "memo1", "2050/12/10 8:30", stockOrders1, foodOrders1)
+	order3, err := domains.NewOrderInfo("user2", "ユーザー2", "user2@hoge.com", "123456789", "memo1", "2050/12/10 8:30", stockOrders1, foodOrders1, orderedAt)
 	if err != nil {
 		fmt.Println(err)
 		panic("failed to create stock order")
 	}
-	order3.SetCancel()
+	order3.SetCancel("")
 	orders[order3.GetId()] = order3
 
 	// morning, _ := NewBusinessHour("morning", "07:00", "09:30", []Weekday{Tuesday, Wednesday, Friday, Saturday, Sunday})
@@ -632,7 +637,7 @@ func setUpUseCaseWithMultipleOrders() (order.OrderTaskUseCase, *memory.MemorySen
 	holidayRepo := memory.NewSpecialHolidayMemoryRepository()
 
 	mail := memory.NewMemorySendOrderMail()
-	useCase := order.NewOrderTaskUseCase(repo, mail, businessHourRepo, holidayRepo, spBusinessHourRepo)
+	useCase := order.NewOrderTaskUseCase(repo, mail, businessHourRepo, holidayRepo, spBusinessHourRepo, memory.NewItemAlertMemoryRepository())
 
 	return useCase, mail
 }
@@ -1,6 +1,8 @@
 package store
 
 import (
+	"chico/takeout/common"
+	idomains "chico/takeout/domains/item"
 	domains "chico/takeout/domains/store"
 )
 
@@ -18,13 +20,23 @@ func newBusinessHoursModel(item *domains.BusinessHours) *BusinessHoursModel {
 	}
 }
 
+type BusinessHoursCreateModel struct {
+	Name          string
+	Start         string
+	End           string
+	Weekdays      []Weekday
+	OffsetHour    uint
+	WeekdayShifts []WeekdayShiftModel
+}
+
 type BusinessHoursUpdateModel struct {
-	Id         string
-	Name       string
-	Start      string
-	End        string
-	Weekdays   []Weekday
-	OffsetHour uint
+	Id            string
+	Name          string
+	Start         string
+	End           string
+	Weekdays      []Weekday
+	OffsetHour    uint
+	WeekdayShifts []WeekdayShiftModel
 }
 
 type BusinessHoursEnabledUpdateModel struct {
@@ -33,13 +45,21 @@ type BusinessHoursEnabledUpdateModel struct {
 }
 
 type BusinessHourModel struct {
-	Id         string
-	Name       string
-	Start      string
-	End        string
-	Weekdays   []Weekday
-	Enabled    bool
-	OffsetHour uint
+	Id            string
+	Name          string
+	Start         string
+	End           string
+	Weekdays      []Weekday
+	Enabled       bool
+	OffsetHour    uint
+	WeekdayShifts []WeekdayShiftModel
+}
+
+// time of the weekday which overrides Start and End
+type WeekdayShiftModel struct {
+	Weekday Weekday
+	Start   string
+	End     string
 }
 
 func newBusinessHourModel(item domains.BusinessHour) *BusinessHourModel {
@@ -47,16 +67,37 @@ func newBusinessHourModel(item domains.BusinessHour) *BusinessHourModel {
 	for _, week := range item.GetWeekdays() {
 		weekdays = append(weekdays, newWeekday(week))
 	}
+	shifts := []WeekdayShiftModel{}
+	for _, ws := range item.GetWeekdayShifts() {
+		shifts = append(shifts, WeekdayShiftModel{
+			Weekday: newWeekday(ws.GetWeekday()),
+			Start:   ws.GetStart(),
+			End:     ws.GetEnd(),
+		})
+	}
 
 	return &BusinessHourModel{
-		Id:         item.GetId(),
-		Name:       item.GetName(),
-		Start:      item.GetStart(),
-		End:        item.GetEnd(),
-		Weekdays:   weekdays,
-		Enabled:    item.GetEnabled(),
-		OffsetHour: item.GetHourOffset(),
+		Id:            item.GetId(),
+		Name:          item.GetName(),
+		Start:         item.GetStart(),
+		End:           item.GetEnd(),
+		Weekdays:      weekdays,
+		Enabled:       item.GetEnabled(),
+		OffsetHour:    item.GetHourOffset(),
+		WeekdayShifts: shifts,
+	}
+}
+
+func toDomainWeekdayShifts(models []WeekdayShiftModel) ([]domains.WeekdayShift, error) {
+	shifts := []domains.WeekdayShift{}
+	for _, model := range models {
+		shift, err := domains.NewWeekdayShift(domains.Weekday(model.Weekday), model.Start, model.End)
+		if err != nil {
+			return nil, err
+		}
+		shifts = append(shifts, *shift)
 	}
+	return shifts, nil
 }
 
 type Weekday int
@@ -119,7 +160,9 @@ func toDomainWeekday(weekdays []Weekday) []domains.Weekday {
 
 type BusinessHoursUseCase interface {
 	GetAll() (*BusinessHoursModel, error)
+	Create(model *BusinessHoursCreateModel) (string, error)
 	Update(model *BusinessHoursUpdateModel) error
+	Delete(id string) error
 	UpdateEnabled(model *BusinessHoursEnabledUpdateModel) error
 	InitIfNotExists() error
 }
@@ -127,14 +170,18 @@ type BusinessHoursUseCase interface {
 type businessHoursUseCase struct {
 	businessHoursRepository domains.BusinessHoursRepository
 	businessHoursService    domains.BusinessHoursService
+	removeService           domains.BusinessHourRemoveService
 }
 
 func NewBusinessHoursUseCase(
 	businessHoursRepository domains.BusinessHoursRepository,
-	specialBusinessHourRepository domains.SpecialBusinessHourRepository) BusinessHoursUseCase {
+	specialBusinessHourRepository domains.SpecialBusinessHourRepository,
+	foodRepository idomains.FoodItemRepository,
+	clock common.Clock) BusinessHoursUseCase {
 	return &businessHoursUseCase{
 		businessHoursRepository: businessHoursRepository,
 		businessHoursService:    *domains.NewBusinessHoursService(businessHoursRepository),
+		removeService:           *domains.NewBusinessHourRemoveService(businessHoursRepository, specialBusinessHourRepository, foodRepository, clock),
 	}
 }
 
@@ -154,16 +201,51 @@ func (b *businessHoursUseCase) InitIfNotExists() error {
 	return nil
 }
 
+func (b *businessHoursUseCase) Create(model *BusinessHoursCreateModel) (string, error) {
+	businessHours, err := b.businessHoursService.FetchBusinessHours()
+	if err != nil {
+		return "", err
+	}
+
+	shifts, err := toDomainWeekdayShifts(model.WeekdayShifts)
+	if err != nil {
+		return "", err
+	}
+	new, id, err := businessHours.Add(model.Name, model.Start, model.End, toDomainWeekday(model.Weekdays), model.OffsetHour)
+	if err != nil {
+		return "", err
+	}
+	new, err = new.UpdateWeekdayShifts(id, shifts)
+	if err != nil {
+		return "", err
+	}
+
+	err = b.businessHoursRepository.Update(new)
+	if err != nil {
+		return "", err
+	}
+
+	return id, nil
+}
+
 func (b *businessHoursUseCase) Update(model *BusinessHoursUpdateModel) error {
 	businessHours, err := b.businessHoursService.FetchBusinessHours()
 	if err != nil {
 		return err
 	}
 
+	shifts, err := toDomainWeekdayShifts(model.WeekdayShifts)
+	if err != nil {
+		return err
+	}
 	new, err := businessHours.Update(model.Id, model.Name, model.Start, model.End, toDomainWeekday(model.Weekdays), model.OffsetHour)
 	if err != nil {
 		return err
 	}
+	new, err = new.UpdateWeekdayShifts(model.Id, shifts)
+	if err != nil {
+		return err
+	}
 
 	err = b.businessHoursRepository.Update(new)
 	if err != nil {
@@ -191,3 +273,7 @@ func (b *businessHoursUseCase) UpdateEnabled(model *BusinessHoursEnabledUpdateMo
 
 	return nil
 }
+
+func (b *businessHoursUseCase) Delete(id string) error {
+	return b.removeService.Remove(id)
+}
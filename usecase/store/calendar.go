@@ -0,0 +1,103 @@
+package store
+
+import (
+	"fmt"
+
+	"chico/takeout/common"
+	domains "chico/takeout/domains/store"
+)
+
+const CalendarDefaultDays = 30
+
+type StoreCalendarModel struct {
+	From string
+	To   string
+	Days []CalendarDayModel
+}
+
+type CalendarDayModel struct {
+	Date        string
+	IsHoliday   bool
+	HolidayName string
+	IsSpecial   bool
+	Hours       []CalendarHourModel
+}
+
+type CalendarHourModel struct {
+	HourTypeId string
+	Name       string
+	StartTime  string
+	EndTime    string
+}
+
+func newCalendarDayModel(item domains.CalendarDayInfo) *CalendarDayModel {
+	hours := []CalendarHourModel{}
+	for _, hour := range item.Hours {
+		hours = append(hours, CalendarHourModel{
+			HourTypeId: hour.HourTypeId,
+			Name:       hour.Name,
+			StartTime:  hour.StartTime,
+			EndTime:    hour.EndTime,
+		})
+	}
+	return &CalendarDayModel{
+		Date:        item.Date,
+		IsHoliday:   item.IsHoliday(),
+		HolidayName: item.HolidayName,
+		IsSpecial:   item.IsSpecial,
+		Hours:       hours,
+	}
+}
+
+type StoreCalendarUseCase interface {
+	// date format is yyyy-MM-dd. empty from is today, empty to is 30 days after from.
+	Fetch(from, to string) (*StoreCalendarModel, error)
+}
+
+type storeCalendarUseCase struct {
+	managementService domains.BusinessHourManagementService
+	clock             common.Clock
+}
+
+func NewStoreCalendarUseCase(businessHoursRepository domains.BusinessHoursRepository,
+	specialHolidayRepository domains.SpecialHolidayRepository,
+	specialBusinessHourRepository domains.SpecialBusinessHourRepository,
+	clock common.Clock) StoreCalendarUseCase {
+	return &storeCalendarUseCase{
+		managementService: *domains.NewBusinessHourManagementService(businessHoursRepository, specialHolidayRepository, specialBusinessHourRepository),
+		clock:             clock,
+	}
+}
+
+func (s *storeCalendarUseCase) Fetch(from, to string) (*StoreCalendarModel, error) {
+	start := common.GetDateUntilDay(s.clock.Now())
+	if from != "" {
+		converted, err := common.ConvertHyphenStrToDate(from)
+		if err != nil {
+			return nil, common.NewValidationError("from", fmt.Sprintf("not allowed date format:%s", from))
+		}
+		start = converted
+	}
+	end := start.AddDate(0, 0, CalendarDefaultDays-1)
+	if to != "" {
+		converted, err := common.ConvertHyphenStrToDate(to)
+		if err != nil {
+			return nil, common.NewValidationError("to", fmt.Sprintf("not allowed date format:%s", to))
+		}
+		end = *converted
+	}
+
+	days, err := s.managementService.GetCalendar(*start, end)
+	if err != nil {
+		return nil, err
+	}
+	models := []CalendarDayModel{}
+	for _, day := range days {
+		models = append(models, *newCalendarDayModel(day))
+	}
+	return &StoreCalendarModel{
+		From: common.ConvertTimeToDateStr(*start),
+		To:   common.ConvertTimeToDateStr(end),
+		Days: models,
+	}, nil
+}
@@ -0,0 +1,165 @@
+package store
+
+import (
+	"fmt"
+
+	"chico/takeout/common"
+	domains "chico/takeout/domains/store"
+)
+
+const (
+	// import as special holiday (store is closed)
+	HolidayImportModeClosed = "closed"
+	// import as special business hour
+	HolidayImportModeSpecial = "special"
+)
+
+type HolidayImportEntryModel struct {
+	// yyyy/MM/dd
+	Date string
+	Name string
+}
+
+type HolidayImportModel struct {
+	Entries []HolidayImportEntryModel
+	Mode    string
+	// below are used only special mode
+	BusinessHourId string
+	Start          string
+	End            string
+	OffsetHour     uint
+}
+
+type HolidayImportSkippedModel struct {
+	Date   string
+	Name   string
+	Reason string
+}
+
+type HolidayImportResultModel struct {
+	Imported []HolidayImportEntryModel
+	Skipped  []HolidayImportSkippedModel
+}
+
+func (h *HolidayImportResultModel) skip(entry HolidayImportEntryModel, reason string) {
+	h.Skipped = append(h.Skipped, HolidayImportSkippedModel{Date: entry.Date, Name: entry.Name, Reason: reason})
+}
+
+type HolidayImportUseCase interface {
+	// past, invalid or overlapped entries are skipped
+	Import(model *HolidayImportModel) (*HolidayImportResultModel, error)
+}
+
+type holidayImportUseCase struct {
+	holidayRepository             domains.SpecialHolidayRepository
+	specialBusinessHourRepository domains.SpecialBusinessHourRepository
+	holidayService                domains.HolidayService
+	businessHoursService          domains.BusinessHoursService
+	clock                         common.Clock
+}
+
+func NewHolidayImportUseCase(
+	holidayRepository domains.SpecialHolidayRepository,
+	specialBusinessHourRepository domains.SpecialBusinessHourRepository,
+	businessHoursRepository domains.BusinessHoursRepository,
+	clock common.Clock) HolidayImportUseCase {
+	return &holidayImportUseCase{
+		holidayRepository:             holidayRepository,
+		specialBusinessHourRepository: specialBusinessHourRepository,
+		holidayService:                *domains.NewHolidayService(holidayRepository),
+		businessHoursService:          *domains.NewBusinessHoursService(businessHoursRepository),
+		clock:                         clock,
+	}
+}
+
+func (h *holidayImportUseCase) Import(model *HolidayImportModel) (*HolidayImportResultModel, error) {
+	if len(model.Entries) == 0 {
+		return nil, common.NewValidationError("entries", "no holiday exists")
+	}
+	switch model.Mode {
+	case HolidayImportModeClosed:
+		return h.importAsHoliday(model)
+	case HolidayImportModeSpecial:
+		return h.importAsSpecialHour(model)
+	default:
+		return nil, common.NewValidationError("mode", fmt.Sprintf("not allowed value:%s", model.Mode))
+	}
+}
+
+func (h *holidayImportUseCase) importAsHoliday(model *HolidayImportModel) (*HolidayImportResultModel, error) {
+	result := HolidayImportResultModel{Imported: []HolidayImportEntryModel{}, Skipped: []HolidayImportSkippedModel{}}
+	for _, entry := range h.filterFuture(model.Entries, &result) {
+		item, err := domains.NewSpecialHoliday(entry.Name, entry.Date, entry.Date)
+		if err != nil {
+			result.skip(entry, err.Error())
+			continue
+		}
+		isOverwrap, err := h.holidayService.CheckOverWrap(item)
+		if err != nil {
+			return nil, err
+		}
+		if isOverwrap {
+			result.skip(entry, "date is overwrapped")
+			continue
+		}
+		_, err = h.holidayRepository.Create(item)
+		if err != nil {
+			return nil, err
+		}
+		result.Imported = append(result.Imported, entry)
+	}
+	return &result, nil
+}
+
+func (h *holidayImportUseCase) importAsSpecialHour(model *HolidayImportModel) (*HolidayImportResultModel, error) {
+	exists, err := h.businessHoursService.ExistsBusinessHour(model.BusinessHourId)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, common.NewValidationError("businessHourId", fmt.Sprintf("not exists:%s", model.BusinessHourId))
+	}
+	specialHours, err := h.specialBusinessHourRepository.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	result := HolidayImportResultModel{Imported: []HolidayImportEntryModel{}, Skipped: []HolidayImportSkippedModel{}}
+	for _, entry := range h.filterFuture(model.Entries, &result) {
+		item, err := domains.NewSpecialBusinessHour(entry.Name, entry.Date, model.Start, model.End, model.BusinessHourId, model.OffsetHour)
+		if err != nil {
+			result.skip(entry, err.Error())
+			continue
+		}
+		err = domains.NewSpecialBusinessHourSpecification(specialHours).Validate(item)
+		if err != nil {
+			result.skip(entry, err.Error())
+			continue
+		}
+		_, err = h.specialBusinessHourRepository.Create(item)
+		if err != nil {
+			return nil, err
+		}
+		specialHours = append(specialHours, *item)
+		result.Imported = append(result.Imported, entry)
+	}
+	return &result, nil
+}
+
+// past holidays are not needed (national holiday file usually contains from 1955)
+func (h *holidayImportUseCase) filterFuture(entries []HolidayImportEntryModel, result *HolidayImportResultModel) []HolidayImportEntryModel {
+	today := common.GetDateUntilDay(h.clock.Now())
+	filtered := []HolidayImportEntryModel{}
+	for _, entry := range entries {
+		date, err := common.ConvertStrToDate(entry.Date)
+		if err != nil {
+			result.skip(entry, fmt.Sprintf("not allowed date format:%s", entry.Date))
+			continue
+		}
+		if date.Before(*today) {
+			continue
+		}
+		filtered = append(filtered, entry)
+	}
+	return filtered
+}
@@ -6,32 +6,58 @@ import (
 )
 
 type SpecialHolidayModel struct {
-	Id    string
-	Name  string
-	Start string
-	End   string
+	Id         string
+	Name       string
+	Start      string
+	End        string
+	Recurrence HolidayRecurrenceModel
+}
+
+// empty Type is not recurring
+type HolidayRecurrenceModel struct {
+	Type     string
+	Weekdays []Weekday
+	Nth      int
+	Month    int
 }
 
 func newSpecialHolidayModel(item *domains.SpecialHoliday) *SpecialHolidayModel {
+	recurrence := item.GetRecurrence()
+	weekdays := []Weekday{}
+	for _, weekday := range recurrence.GetWeekdays() {
+		weekdays = append(weekdays, newWeekday(weekday))
+	}
 	return &SpecialHolidayModel{
 		Id:    item.GetId(),
 		Name:  item.GetName(),
 		Start: item.GetStart(),
 		End:   item.GetEnd(),
+		Recurrence: HolidayRecurrenceModel{
+			Type:     recurrence.GetType(),
+			Weekdays: weekdays,
+			Nth:      recurrence.GetNth(),
+			Month:    recurrence.GetMonth(),
+		},
 	}
 }
 
+func (h *HolidayRecurrenceModel) toDomain() (*domains.HolidayRecurrence, error) {
+	return domains.NewHolidayRecurrence(h.Type, toDomainWeekday(h.Weekdays), h.Nth, h.Month)
+}
+
 type SpecialHolidayCreateModel struct {
-	Name  string
-	Start string
-	End   string
+	Name       string
+	Start      string
+	End        string
+	Recurrence HolidayRecurrenceModel
 }
 
 type SpecialHolidayUpdateModel struct {
-	Id    string
-	Name  string
-	Start string
-	End   string
+	Id         string
+	Name       string
+	Start      string
+	End        string
+	Recurrence HolidayRecurrenceModel
 }
 
 type SpecialHolidayUseCase interface {
@@ -83,7 +109,11 @@ func (i *specialHolidayUseCase) FindAll() ([]SpecialHolidayModel, error) {
 }
 
 func (s *specialHolidayUseCase) Create(model *SpecialHolidayCreateModel) (string, error) {
-	item, err := domains.NewSpecialHoliday(model.Name, model.Start, model.End)
+	recurrence, err := model.Recurrence.toDomain()
+	if err != nil {
+		return "", err
+	}
+	item, err := domains.NewRecurringSpecialHoliday(model.Name, model.Start, model.End, *recurrence)
 	if err != nil {
 		return "", err
 	}
@@ -104,7 +134,11 @@ func (s *specialHolidayUseCase) Update(model *SpecialHolidayUpdateModel) error {
 		return common.NewUpdateTargetNotFoundError(model.Id)
 	}
 
-	err = item.Set(model.Name, model.Start, model.End)
+	recurrence, err := model.Recurrence.toDomain()
+	if err != nil {
+		return err
+	}
+	err = item.SetWithRecurrence(model.Name, model.Start, model.End, *recurrence)
 	if err != nil {
 		return err
 	}